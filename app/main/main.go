@@ -2,14 +2,36 @@ package main
 
 import (
 	"database/sql"
+	"errors"
 	"flag"
+	"fmt"
 	"github.com/TechnoHandOver/backend/config"
 	AdsDelivery "github.com/TechnoHandOver/backend/internal/ad/delivery"
 	AdsRepository "github.com/TechnoHandOver/backend/internal/ad/repository"
+	AdsScheduler "github.com/TechnoHandOver/backend/internal/ad/scheduler"
 	AdsUsecase "github.com/TechnoHandOver/backend/internal/ad/usecase"
+	LocalBlobStore "github.com/TechnoHandOver/backend/internal/blobstore/local"
+	ChatDelivery "github.com/TechnoHandOver/backend/internal/chat/delivery"
+	ChatRepository "github.com/TechnoHandOver/backend/internal/chat/repository"
+	ChatUsecase "github.com/TechnoHandOver/backend/internal/chat/usecase"
 	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	NotificationDelivery "github.com/TechnoHandOver/backend/internal/notification/delivery"
 	NotificationRepository "github.com/TechnoHandOver/backend/internal/notification/repository"
+	NotificationScheduler "github.com/TechnoHandOver/backend/internal/notification/scheduler"
 	NotificationUsecase "github.com/TechnoHandOver/backend/internal/notification/usecase"
+	NotificationWorker "github.com/TechnoHandOver/backend/internal/notification/worker"
+	"github.com/TechnoHandOver/backend/internal/notifier"
+	EmailNotifier "github.com/TechnoHandOver/backend/internal/notifier/email"
+	LogNotifier "github.com/TechnoHandOver/backend/internal/notifier/logsink"
+	VkBotNotifier "github.com/TechnoHandOver/backend/internal/notifier/vkbot"
+	WebhookNotifier "github.com/TechnoHandOver/backend/internal/notifier/webhook"
+	PlaceDelivery "github.com/TechnoHandOver/backend/internal/place/delivery"
+	PlaceRepository "github.com/TechnoHandOver/backend/internal/place/repository"
+	PlaceUsecase "github.com/TechnoHandOver/backend/internal/place/usecase"
+	ReportDelivery "github.com/TechnoHandOver/backend/internal/report/delivery"
+	ReportRepository "github.com/TechnoHandOver/backend/internal/report/repository"
+	ReportUsecase "github.com/TechnoHandOver/backend/internal/report/usecase"
 	SessionDelivery "github.com/TechnoHandOver/backend/internal/session/delivery"
 	SessionRepository "github.com/TechnoHandOver/backend/internal/session/repository"
 	SessionUsecase "github.com/TechnoHandOver/backend/internal/session/usecase"
@@ -69,18 +91,50 @@ func main() {
 	sessionRepository := SessionRepository.NewSessionRepositoryImpl()
 	userRepository := UserRepository.NewUserRepositoryImpl(db)
 	notificationRepository := NotificationRepository.NewNotificationRepositoryImpl(db)
+	placeRepository := PlaceRepository.NewPlaceRepositoryImpl(db)
+	reportRepository := ReportRepository.NewReportRepositoryImpl(db)
+	chatRepository := ChatRepository.NewChatRepositoryImpl(db)
+	blobStore := LocalBlobStore.NewLocalBlobStore(config_.GetBlobStoreDir())
 
-	notificationUsecase := NotificationUsecase.NewNotificationUsecaseImpl(notificationRepository)
-	adsUsecase := AdsUsecase.NewAdUsecaseImpl(adsRepository, notificationUsecase)
+	notifiers, fallbackNotifier, err := newNotifiers(config_)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	notificationUsecase := NotificationUsecase.NewNotificationUsecaseImpl(notificationRepository, notifiers,
+		fallbackNotifier)
+	adsUsecase := AdsUsecase.NewAdUsecaseImpl(adsRepository, notificationUsecase, blobStore)
 	userUsecase := UserUsecase.NewUserUsecaseImpl(userRepository)
 	sessionUsecase := SessionUsecase.NewSessionUsecaseImpl(sessionRepository)
+	placeUsecase := PlaceUsecase.NewPlaceUsecaseImpl(placeRepository)
+	reportUsecase := ReportUsecase.NewReportUsecaseImpl(reportRepository)
+	chatUsecase := ChatUsecase.NewChatUsecaseImpl(chatRepository)
+
+	adsScheduler := AdsScheduler.NewAdScheduler(adsUsecase, config_.GetAdExpirySweepInterval(),
+		config_.GetAdExpiryGracePeriod(), config_.GetAdTemplateHorizon())
+	adsScheduler.Start()
+	defer adsScheduler.Stop()
+
+	notificationWorker := NotificationWorker.NewNotificationWorker(notificationUsecase,
+		config_.GetNotificationOutboxPollInterval(), config_.GetNotificationOutboxPoolSize())
+	notificationWorker.Start()
+	defer notificationWorker.Stop()
+
+	notificationScheduler := NotificationScheduler.NewNotificationScheduler(notificationUsecase,
+		config_.GetNotificationDigestSweepInterval())
+	notificationScheduler.Start()
+	defer notificationScheduler.Stop()
 
 	adsDelivery := AdsDelivery.NewAdDelivery(adsUsecase)
 	sessionDelivery := SessionDelivery.NewSessionDelivery(sessionUsecase, userUsecase)
 	userDelivery := UserDelivery.NewUserDelivery(userUsecase)
+	placeDelivery := PlaceDelivery.NewPlaceDelivery(placeUsecase)
+	reportDelivery := ReportDelivery.NewReportDelivery(reportUsecase)
+	chatDelivery := ChatDelivery.NewChatDelivery(chatUsecase)
+	notificationDelivery := NotificationDelivery.NewNotificationDelivery(notificationUsecase)
 
 	recoverMiddleware := middlewares.NewRecoverMiddleware()
-	authMiddleware := middlewares.NewAuthMiddleware(sessionUsecase, userUsecase)
+	authMiddleware := middlewares.NewAuthMiddleware(sessionUsecase, userUsecase, config_.GetAdminVkIds())
 	middlewaresManager := middlewares.NewManager(recoverMiddleware, authMiddleware)
 
 	echo_ := echo.New()
@@ -94,8 +148,46 @@ func main() {
 	adsDelivery.Configure(echo_, middlewaresManager)
 	sessionDelivery.Configure(echo_, middlewaresManager)
 	userDelivery.Configure(echo_, middlewaresManager)
+	placeDelivery.Configure(echo_, middlewaresManager)
+	reportDelivery.Configure(echo_, middlewaresManager)
+	chatDelivery.Configure(echo_, middlewaresManager)
+	notificationDelivery.Configure(echo_, middlewaresManager)
 
 	if err := echo_.Start(config_.GetServerConfigString()); err != nil {
 		log.Fatal(err)
 	}
 }
+
+func newNotifiers(config_ *config.Config) (map[models.NotificationChannelKind]notifier.Notifier, notifier.Notifier,
+	error) {
+	notifiers := make(map[models.NotificationChannelKind]notifier.Notifier)
+	for _, channel := range config_.GetNotificationsChannels() {
+		switch channel := models.NotificationChannelKind(channel); channel {
+		case models.NotificationChannelKindVkBot:
+			notifiers[channel] = VkBotNotifier.NewVkBotNotifier(config_.GetNotificationsVkBotUrl())
+		case models.NotificationChannelKindWebhook:
+			//the receivers could not tell the signed requests from the forged ones
+			if config_.GetNotificationsWebhookSecret() == "" {
+				return nil, nil, errors.New("notifications webhook secret is not set")
+			}
+			notifiers[channel] = WebhookNotifier.NewWebhookNotifier(config_.GetNotificationsWebhookSecret(),
+				WebhookNotifier.NewClient())
+		case models.NotificationChannelKindEmail:
+			notifiers[channel] = EmailNotifier.NewEmailNotifier(config_.Notifications.Email.Host,
+				config_.GetNotificationsEmailPort(), config_.Notifications.Email.Username,
+				config_.Notifications.Email.Password, config_.Notifications.Email.From)
+		case models.NotificationChannelKindLog:
+			notifiers[channel] = LogNotifier.NewLogNotifier(log.Default())
+		default:
+			return nil, nil, fmt.Errorf("unknown notifications channel: %s", channel)
+		}
+	}
+
+	fallbackNotifier, ok := notifiers[models.NotificationChannelKind(config_.GetNotificationsFallbackChannel())]
+	if !ok {
+		return nil, nil, fmt.Errorf("notifications fallback channel is not enabled: %s",
+			config_.GetNotificationsFallbackChannel())
+	}
+
+	return notifiers, fallbackNotifier, nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Duration time.Duration
+
+func (duration *Duration) UnmarshalJSON(bytes []byte) error {
+	var durationString string
+	if err := json.Unmarshal(bytes, &durationString); err != nil {
+		return err
+	}
+
+	duration_, err := time.ParseDuration(durationString)
+	if err != nil {
+		return err
+	}
+
+	*duration = Duration(duration_)
+	return nil
+}
+
+func (duration Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(duration).String())
+}
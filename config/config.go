@@ -4,6 +4,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
+)
+
+const (
+	defaultAdExpirySweepInterval                  = 5 * time.Minute
+	defaultAdExpiryGracePeriod                    = 12 * time.Hour
+	defaultAdTemplateHorizon                      = 7 * 24 * time.Hour
+	defaultNotificationOutboxPollInterval         = 10 * time.Second
+	defaultNotificationOutboxPoolSize      uint32 = 4
+	defaultNotificationDigestSweepInterval        = time.Minute
+	defaultBlobStoreDir                           = "blobs"
+	defaultNotificationsFallbackChannel           = "vk_bot"
+	defaultNotificationsVkBotUrl                  = "https://handover.space/bot"
+	defaultNotificationsEmailPort          uint16 = 587
 )
 
 type Config struct {
@@ -18,9 +32,42 @@ type Config struct {
 		Host string `json:"host"`
 		Port uint16 `json:"port"`
 	} `json:"server"`
+	Scheduler struct {
+		AdExpirySweepInterval           Duration `json:"adExpirySweepInterval"`
+		AdExpiryGracePeriod             Duration `json:"adExpiryGracePeriod"`
+		AdTemplateHorizon               Duration `json:"adTemplateHorizon"`
+		NotificationOutboxPollInterval  Duration `json:"notificationOutboxPollInterval"`
+		NotificationOutboxPoolSize      uint32   `json:"notificationOutboxPoolSize"`
+		NotificationDigestSweepInterval Duration `json:"notificationDigestSweepInterval"`
+	} `json:"scheduler"`
+	BlobStore struct {
+		Dir string `json:"dir"`
+	} `json:"blobStore"`
+	Notifications Notifications `json:"notifications"`
+	Admin         struct {
+		VkIds []uint32 `json:"vkIds"`
+	} `json:"admin"`
 	Properties `json:"properties"`
 }
 
+type Notifications struct {
+	Channels        []string `json:"channels"`
+	FallbackChannel string   `json:"fallbackChannel"`
+	VkBot           struct {
+		Url string `json:"url"`
+	} `json:"vkBot"`
+	Webhook struct {
+		Secret string `json:"secret"`
+	} `json:"webhook"`
+	Email struct {
+		Host     string `json:"host"`
+		Port     uint16 `json:"port"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+		From     string `json:"from"`
+	} `json:"email"`
+}
+
 type Properties struct {
 	Debug bool `json:"debug"`
 }
@@ -36,6 +83,93 @@ func (config *Config) GetServerConfigString() string {
 	return fmt.Sprintf("%s:%d", config.Server.Host, config.Server.Port)
 }
 
+func (config *Config) GetAdExpirySweepInterval() time.Duration {
+	if config.Scheduler.AdExpirySweepInterval == 0 {
+		return defaultAdExpirySweepInterval
+	}
+	return time.Duration(config.Scheduler.AdExpirySweepInterval)
+}
+
+func (config *Config) GetAdExpiryGracePeriod() time.Duration {
+	if config.Scheduler.AdExpiryGracePeriod == 0 {
+		return defaultAdExpiryGracePeriod
+	}
+	return time.Duration(config.Scheduler.AdExpiryGracePeriod)
+}
+
+func (config *Config) GetAdTemplateHorizon() time.Duration {
+	if config.Scheduler.AdTemplateHorizon == 0 {
+		return defaultAdTemplateHorizon
+	}
+	return time.Duration(config.Scheduler.AdTemplateHorizon)
+}
+
+func (config *Config) GetNotificationOutboxPollInterval() time.Duration {
+	if config.Scheduler.NotificationOutboxPollInterval == 0 {
+		return defaultNotificationOutboxPollInterval
+	}
+	return time.Duration(config.Scheduler.NotificationOutboxPollInterval)
+}
+
+func (config *Config) GetNotificationOutboxPoolSize() uint32 {
+	if config.Scheduler.NotificationOutboxPoolSize == 0 {
+		return defaultNotificationOutboxPoolSize
+	}
+	return config.Scheduler.NotificationOutboxPoolSize
+}
+
+func (config *Config) GetNotificationDigestSweepInterval() time.Duration {
+	if config.Scheduler.NotificationDigestSweepInterval == 0 {
+		return defaultNotificationDigestSweepInterval
+	}
+	return time.Duration(config.Scheduler.NotificationDigestSweepInterval)
+}
+
+func (config *Config) GetBlobStoreDir() string {
+	if config.BlobStore.Dir == "" {
+		return defaultBlobStoreDir
+	}
+	return config.BlobStore.Dir
+}
+
+// GetNotificationsChannels lists the enabled channels; the users, who chose any other one, are notified via the
+// fallback channel
+func (config *Config) GetNotificationsChannels() []string {
+	if len(config.Notifications.Channels) == 0 {
+		return []string{config.GetNotificationsFallbackChannel()}
+	}
+	return config.Notifications.Channels
+}
+
+func (config *Config) GetNotificationsFallbackChannel() string {
+	if config.Notifications.FallbackChannel == "" {
+		return defaultNotificationsFallbackChannel
+	}
+	return config.Notifications.FallbackChannel
+}
+
+func (config *Config) GetNotificationsVkBotUrl() string {
+	if config.Notifications.VkBot.Url == "" {
+		return defaultNotificationsVkBotUrl
+	}
+	return config.Notifications.VkBot.Url
+}
+
+func (config *Config) GetNotificationsWebhookSecret() string {
+	return config.Notifications.Webhook.Secret
+}
+
+func (config *Config) GetNotificationsEmailPort() uint16 {
+	if config.Notifications.Email.Port == 0 {
+		return defaultNotificationsEmailPort
+	}
+	return config.Notifications.Email.Port
+}
+
+func (config *Config) GetAdminVkIds() []uint32 {
+	return config.Admin.VkIds
+}
+
 func LoadConfigFile(filename string) (*Config, error) {
 	file, err := os.Open(filename)
 	if err != nil {
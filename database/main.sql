@@ -2,11 +2,33 @@
 
 --CREATE TYPE DAY_OF_WEEK AS ENUM (1, 2, 3, 4, 5, 6, 7); --TODO: может всё-таки есть какой-то встроенный тип?
 
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
 CREATE TABLE user_ (
     id SERIAL PRIMARY KEY,
     vk_id INT NOT NULL UNIQUE,
     name VARCHAR(100) NOT NULL CHECK (length(name) >= 2),
-    avatar VARCHAR(500) NOT NULL
+    avatar VARCHAR(500) NOT NULL,
+    rating REAL NOT NULL DEFAULT 0,
+    rating_count INT NOT NULL DEFAULT 0,
+    suspended BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE user_block (
+    user_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_blocked_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, user_blocked_id),
+    CHECK (user_id != user_blocked_id)
+);
+
+CREATE TABLE place (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE CHECK (length(name) >= 2),
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('building', 'dorm', 'metro')),
+    point POINT NOT NULL
 );
 
 CREATE TABLE ad (
@@ -21,7 +43,17 @@ CREATE TABLE ad (
     date_time_arr TIMESTAMP NOT NULL,
     item VARCHAR(50) NOT NULL CHECK (length(item) >= 3),
     min_price INT NOT NULL CHECK (min_price >= 0),
-    comment VARCHAR(100) NOT NULL
+    comment VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'assigned', 'picked_up', 'delivered',
+                                                                 'confirmed', 'cancelled', 'expired')),
+    loc_dep_point POINT DEFAULT NULL,
+    loc_arr_point POINT DEFAULT NULL,
+    loc_dep_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    loc_arr_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    date_time_dep TIMESTAMP DEFAULT NULL CHECK (date_time_dep <= date_time_arr),
+    version INT NOT NULL DEFAULT 1,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    hidden_by_suspension BOOLEAN NOT NULL DEFAULT FALSE
 );
 
 CREATE TABLE ad_user_execution (
@@ -29,12 +61,175 @@ CREATE TABLE ad_user_execution (
     user_executor_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE
 );
 
+CREATE TABLE ad_handover (
+    ad_id INT NOT NULL PRIMARY KEY REFERENCES ad_user_execution (ad_id) ON DELETE CASCADE,
+    code CHAR(6) NOT NULL,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE ad_offer (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_executor_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_executor_vk_id INT NOT NULL,
+    user_executor_name VARCHAR(100) NOT NULL,
+    user_executor_avatar VARCHAR(500) NOT NULL,
+    user_executor_rating REAL NOT NULL DEFAULT 0,
+    price INT NOT NULL CHECK (price >= 0),
+    comment VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected', 'withdrawn'))
+);
+
+CREATE TABLE ad_review (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_target_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    rating INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment VARCHAR(300) NOT NULL,
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (ad_id, user_author_id)
+);
+
+CREATE TABLE report (
+    id SERIAL PRIMARY KEY,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    ad_id INT DEFAULT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_target_id INT DEFAULT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('scam', 'offensive', 'spam', 'prohibited', 'other')),
+    comment VARCHAR(300) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'resolved', 'dismissed')),
+    action VARCHAR(20) DEFAULT NULL CHECK (action IN ('hide_ad', 'suspend_user')),
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK ((ad_id IS NULL) != (user_target_id IS NULL))
+);
+
+CREATE TABLE ad_thread (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_executor_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    archived BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE ad_message (
+    id SERIAL PRIMARY KEY,
+    ad_thread_id INT NOT NULL REFERENCES ad_thread (id) ON DELETE CASCADE,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    text VARCHAR(1000) NOT NULL CHECK (length(text) >= 1),
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    date_time_read TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE notification (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('route_match', 'saved_search_match', 'ad_expired', 'digest')),
+    payload JSONB NOT NULL,
+    read BOOLEAN NOT NULL DEFAULT FALSE,
+    date_time TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE notification_channel (
+    user_id INT PRIMARY KEY REFERENCES user_ (id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL DEFAULT 'vk_bot' CHECK (channel IN ('vk_bot', 'webhook', 'email')),
+    address VARCHAR(500) DEFAULT NULL,
+    CHECK ((channel = 'vk_bot') = (address IS NULL))
+);
+
+CREATE TABLE notification_settings (
+    user_id INT PRIMARY KEY REFERENCES user_ (id) ON DELETE CASCADE,
+    timezone VARCHAR(50) NOT NULL DEFAULT 'Europe/Moscow',
+    quiet_hours_from TIMESTAMP DEFAULT NULL,
+    quiet_hours_to TIMESTAMP DEFAULT NULL,
+    max_per_hour INT DEFAULT NULL CHECK (max_per_hour >= 1),
+    min_price INT DEFAULT NULL CHECK (min_price >= 0),
+    digest_interval INT DEFAULT NULL CHECK (digest_interval >= 1),
+    digest_times INT[] NOT NULL DEFAULT '{}', --minutes since midnight in the timezone
+    date_time_digest_next TIMESTAMP DEFAULT NULL,
+    CHECK ((quiet_hours_from IS NULL) = (quiet_hours_to IS NULL)),
+    CHECK (digest_interval IS NULL OR cardinality(digest_times) = 0),
+    CHECK ((date_time_digest_next IS NULL) = (digest_interval IS NULL AND cardinality(digest_times) = 0))
+);
+
+CREATE TABLE notification_outbox (
+    id SERIAL PRIMARY KEY,
+    notification_id INT NOT NULL UNIQUE REFERENCES notification (id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts INT NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    last_error VARCHAR(500) DEFAULT NULL,
+    date_time_next_attempt TIMESTAMP NOT NULL DEFAULT now(),
+    date_time_sent TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE notification_digest_item (
+    notification_id INT PRIMARY KEY REFERENCES notification (id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE
+);
+
+CREATE TABLE ad_notification_pending (
+    ad_id INT PRIMARY KEY REFERENCES ad (id) ON DELETE CASCADE,
+    date_time TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE ad_photo (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    content_type VARCHAR(50) NOT NULL,
+    size INT NOT NULL CHECK (size > 0),
+    blob_key VARCHAR(200) NOT NULL UNIQUE,
+    thumbnail_blob_key VARCHAR(200) NOT NULL UNIQUE
+);
+
+CREATE TABLE ad_revision (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL,
+    user_id INT DEFAULT NULL REFERENCES user_ (id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('insert', 'update', 'delete', 'execution')),
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    diff JSONB NOT NULL
+);
+
+CREATE TABLE ad_template (
+    id SERIAL PRIMARY KEY,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    loc_dep VARCHAR(100) NOT NULL CHECK (length(loc_dep) >= 2),
+    loc_arr VARCHAR(100) NOT NULL CHECK (length(loc_arr) >= 2),
+    item VARCHAR(50) NOT NULL CHECK (length(item) >= 3),
+    min_price INT NOT NULL CHECK (min_price >= 0),
+    comment VARCHAR(100) NOT NULL,
+    even_week BOOLEAN NOT NULL,
+    odd_week BOOLEAN NOT NULL,
+    days_of_week INT[] NOT NULL CHECK (cardinality(days_of_week) > 0 AND days_of_week <@ '{1, 2, 3, 4, 5, 6, 7}'),
+    time_dep TIMESTAMP DEFAULT NULL CHECK (time_dep::time <= time_arr::time),
+    time_arr TIMESTAMP NOT NULL,
+    loc_dep_point POINT DEFAULT NULL,
+    loc_arr_point POINT DEFAULT NULL,
+    loc_dep_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    loc_arr_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    paused BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE ad_template_occurrence (
+    ad_template_id INT NOT NULL REFERENCES ad_template (id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    ad_id INT DEFAULT NULL REFERENCES ad (id) ON DELETE SET NULL,
+    PRIMARY KEY (ad_template_id, date)
+);
+
 CREATE TABLE route (
     id SERIAL PRIMARY KEY,
     user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
     loc_dep VARCHAR(100) NOT NULL,
     loc_arr VARCHAR(100) NOT NULL,
-    min_price INT NOT NULL CHECK (min_price >= 0)
+    min_price INT NOT NULL CHECK (min_price >= 0),
+    loc_dep_point POINT DEFAULT NULL,
+    loc_arr_point POINT DEFAULT NULL,
+    loc_dep_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    loc_arr_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    version INT NOT NULL DEFAULT 1
 );
 
 CREATE TABLE route_tmp (
@@ -52,17 +247,36 @@ CREATE TABLE route_perm (
     time_arr TIMESTAMP NOT NULL
 );
 
-CREATE VIEW view_route_tmp (id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr)
+CREATE TABLE route_mute (
+    route_id INT PRIMARY KEY REFERENCES route (id) ON DELETE CASCADE
+);
+
+CREATE TABLE saved_search (
+    id SERIAL PRIMARY KEY,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL CHECK (length(name) >= 1),
+    loc_dep VARCHAR(100) DEFAULT NULL,
+    loc_arr VARCHAR(100) DEFAULT NULL,
+    max_price INT DEFAULT NULL CHECK (max_price >= 0),
+    order_ INT DEFAULT NULL,
+    UNIQUE (user_author_id, name)
+);
+
+CREATE VIEW view_route_tmp (id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr,
+                            loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version)
     AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_tmp.date_time_dep,
-              route_tmp.date_time_arr
+              route_tmp.date_time_arr, route.loc_dep_point, route.loc_arr_point, route.loc_dep_place_id,
+              route.loc_arr_place_id, route.version
     FROM route
         JOIN route_tmp ON route.id = route_tmp.id
     ORDER BY route_tmp.date_time_dep, route_tmp.date_time_arr, route.min_price DESC, route.id;
 
 CREATE VIEW view_route_perm (id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week,
-                             time_dep, time_arr)
+                             time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id,
+                             version)
     AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_perm.even_week,
-              route_perm.odd_week, route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr
+              route_perm.odd_week, route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr,
+              route.loc_dep_point, route.loc_arr_point, route.loc_dep_place_id, route.loc_arr_place_id, route.version
     FROM route
         JOIN route_perm ON route.id = route_perm.id
     ORDER BY route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr, route.min_price DESC,
@@ -72,8 +286,20 @@ CREATE FUNCTION user__update()
     RETURNS TRIGGER
 AS $$
 BEGIN
-    UPDATE ad SET user_author_name = new.name, user_author_avatar = new.avatar
-    WHERE user_author_id = new.id;
+    IF new.name != old.name OR new.avatar != old.avatar THEN
+        UPDATE ad SET user_author_name = new.name, user_author_avatar = new.avatar
+        WHERE user_author_id = new.id;
+    END IF;
+    UPDATE ad_offer SET user_executor_name = new.name, user_executor_avatar = new.avatar,
+                        user_executor_rating = new.rating
+    WHERE user_executor_id = new.id;
+    IF new.suspended AND NOT old.suspended THEN
+        UPDATE ad SET hidden = TRUE, hidden_by_suspension = TRUE
+        WHERE user_author_id = new.id AND status = 'open' AND NOT hidden;
+    ELSIF NOT new.suspended AND old.suspended THEN
+        UPDATE ad SET hidden = FALSE, hidden_by_suspension = FALSE
+        WHERE user_author_id = new.id AND hidden_by_suspension;
+    END IF;
     RETURN new;
 END;
 $$ LANGUAGE plpgsql;
@@ -88,10 +314,12 @@ CREATE FUNCTION ad_insert()
 AS $$
 DECLARE user__ user_%ROWTYPE;
 BEGIN
-    SELECT INTO user__ id, vk_id, name, avatar FROM user_ WHERE id = new.user_author_id; --TODO: возможны ли оптимизации?
+    SELECT INTO user__ * FROM user_ WHERE id = new.user_author_id; --TODO: возможны ли оптимизации?
     new.user_author_vk_id = user__.vk_id;
     new.user_author_name = user__.name;
     new.user_author_avatar = user__.avatar;
+    new.hidden = user__.suspended;
+    new.hidden_by_suspension = user__.suspended;
     RETURN new;
 END;
 $$ LANGUAGE plpgsql;
@@ -101,12 +329,113 @@ CREATE TRIGGER ad_insert BEFORE INSERT
     FOR EACH ROW
 EXECUTE FUNCTION ad_insert();
 
+CREATE FUNCTION loc_place_fill()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF new.loc_dep_place_id IS NOT NULL THEN
+        SELECT INTO new.loc_dep, new.loc_dep_point name, point FROM place WHERE id = new.loc_dep_place_id;
+        IF NOT FOUND THEN
+            RAISE foreign_key_violation USING MESSAGE = 'Place of departure does not exist';
+        END IF;
+    END IF;
+    IF new.loc_arr_place_id IS NOT NULL THEN
+        SELECT INTO new.loc_arr, new.loc_arr_point name, point FROM place WHERE id = new.loc_arr_place_id;
+        IF NOT FOUND THEN
+            RAISE foreign_key_violation USING MESSAGE = 'Place of arrival does not exist';
+        END IF;
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_loc_place_fill BEFORE INSERT OR UPDATE OF loc_dep, loc_arr, loc_dep_place_id, loc_arr_place_id
+    ON ad
+    FOR EACH ROW
+EXECUTE FUNCTION loc_place_fill();
+
+CREATE TRIGGER route_loc_place_fill BEFORE INSERT OR UPDATE OF loc_dep, loc_arr, loc_dep_place_id, loc_arr_place_id
+    ON route
+    FOR EACH ROW
+EXECUTE FUNCTION loc_place_fill();
+
+CREATE TRIGGER ad_template_loc_place_fill BEFORE INSERT OR UPDATE OF loc_dep, loc_arr, loc_dep_place_id, loc_arr_place_id
+    ON ad_template
+    FOR EACH ROW
+EXECUTE FUNCTION loc_place_fill();
+
+CREATE FUNCTION version_increment()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    new.version := old.version + 1;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_version_increment BEFORE UPDATE
+    ON ad
+    FOR EACH ROW
+EXECUTE FUNCTION version_increment();
+
+CREATE TRIGGER route_version_increment BEFORE UPDATE
+    ON route
+    FOR EACH ROW
+EXECUTE FUNCTION version_increment();
+
+CREATE FUNCTION ad_handover_code()
+    RETURNS CHAR(6)
+AS $$
+BEGIN
+    RETURN lpad(((('x' || encode(gen_random_bytes(4), 'hex'))::bit(32)::bigint) % 1000000)::text, 6, '0');
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION user_blocked(user_id_1 INT, user_id_2 INT)
+    RETURNS BOOLEAN
+AS $$
+BEGIN
+    RETURN EXISTS (SELECT FROM user_block
+                   WHERE user_id = user_id_1 AND user_blocked_id = user_id_2 OR
+                         user_id = user_id_2 AND user_blocked_id = user_id_1);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+CREATE FUNCTION user_executor_block_check()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF user_blocked(new.user_executor_id, (SELECT ad.user_author_id FROM ad WHERE ad.id = new.ad_id)) THEN
+        RAISE insufficient_privilege USING MESSAGE = 'Author of ad and executor have blocked each other';
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_user_execution_block_check BEFORE INSERT
+    ON ad_user_execution
+    FOR EACH ROW
+EXECUTE FUNCTION user_executor_block_check();
+
+CREATE TRIGGER ad_offer_block_check BEFORE INSERT
+    ON ad_offer
+    FOR EACH ROW
+EXECUTE FUNCTION user_executor_block_check();
+
 CREATE FUNCTION ad_user_execution_insert()
     RETURNS TRIGGER
 AS $$
 BEGIN
-    UPDATE ad SET user_executor_vk_id = (SELECT user_.vk_id FROM user_ WHERE user_.id = new.user_executor_id)
-    WHERE id = new.ad_id;
+    UPDATE ad SET user_executor_vk_id = (SELECT user_.vk_id FROM user_ WHERE user_.id = new.user_executor_id),
+                  status = 'assigned'
+    WHERE id = new.ad_id AND status = 'open';
+    IF NOT FOUND THEN
+        RAISE object_not_in_prerequisite_state USING MESSAGE = 'Ad is not open';
+    END IF;
+    INSERT INTO ad_handover (ad_id, code)
+    VALUES (new.ad_id, ad_handover_code());
+    INSERT INTO ad_thread (ad_id, user_author_id, user_executor_id)
+    SELECT ad.id, ad.user_author_id, new.user_executor_id FROM ad WHERE ad.id = new.ad_id;
     RETURN new;
 END;
 $$ LANGUAGE plpgsql;
@@ -120,8 +449,13 @@ CREATE FUNCTION ad_user_execution_delete()
     RETURNS TRIGGER
 AS $$
 BEGIN
-    UPDATE ad SET user_executor_vk_id = NULL
+    UPDATE ad SET user_executor_vk_id = NULL,
+                  status = CASE WHEN status = 'assigned' THEN 'open' ELSE status END
     WHERE id = old.ad_id;
+    UPDATE ad_offer SET status = 'withdrawn'
+    WHERE ad_id = old.ad_id AND status = 'accepted';
+    UPDATE ad_thread SET archived = TRUE
+    WHERE ad_id = old.ad_id AND NOT archived;
     RETURN old;
 END;
 $$ LANGUAGE plpgsql;
@@ -131,14 +465,87 @@ CREATE TRIGGER ad_user_execution_delete AFTER DELETE
     FOR EACH ROW
 EXECUTE FUNCTION ad_user_execution_delete();
 
+CREATE FUNCTION ad_thread_archive()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad_thread SET archived = TRUE
+    WHERE ad_id = new.id AND NOT archived;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_thread_archive AFTER UPDATE OF status
+    ON ad
+    FOR EACH ROW
+    WHEN (new.status IN ('confirmed', 'cancelled', 'expired') AND old.status != new.status)
+EXECUTE FUNCTION ad_thread_archive();
+
+CREATE FUNCTION ad_offer_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE user__ user_%ROWTYPE;
+BEGIN
+    SELECT INTO user__ id, vk_id, name, avatar, rating FROM user_ WHERE id = new.user_executor_id;
+    new.user_executor_vk_id = user__.vk_id;
+    new.user_executor_name = user__.name;
+    new.user_executor_avatar = user__.avatar;
+    new.user_executor_rating = user__.rating;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_offer_insert BEFORE INSERT
+    ON ad_offer
+    FOR EACH ROW
+EXECUTE FUNCTION ad_offer_insert();
+
+CREATE FUNCTION ad_offer_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF new.status = 'accepted' AND old.status != 'accepted' THEN
+        INSERT INTO ad_user_execution (ad_id, user_executor_id)
+        VALUES (new.ad_id, new.user_executor_id);
+        UPDATE ad_offer SET status = 'rejected'
+        WHERE ad_id = new.ad_id AND id != new.id AND status = 'pending';
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_offer_update AFTER UPDATE
+    ON ad_offer
+    FOR EACH ROW
+EXECUTE FUNCTION ad_offer_update();
+
+CREATE FUNCTION ad_review_insert()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE user_ SET rating = (rating * rating_count + new.rating) / (rating_count + 1),
+                     rating_count = rating_count + 1
+    WHERE id = new.user_target_id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_review_insert AFTER INSERT
+    ON ad_review
+    FOR EACH ROW
+EXECUTE FUNCTION ad_review_insert();
+
 CREATE FUNCTION view_route_tmp_insert()
     RETURNS TRIGGER
 AS $$
 DECLARE id_ route.id%TYPE;
 BEGIN
-    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price)
-    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price)
-    RETURNING id INTO id_;
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point, loc_dep_place_id,
+                       loc_arr_place_id)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point,
+            new.loc_dep_place_id, new.loc_arr_place_id)
+    RETURNING id, loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO id_, new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
     INSERT INTO route_tmp (id, date_time_dep, date_time_arr)
     SELECT id_, new.date_time_dep, new.date_time_arr;
     new.id := id_;
@@ -158,8 +565,12 @@ BEGIN
     IF old.user_author_id != new.user_author_id THEN
         RAISE 'It is forbidden to update author of temporary route';
     END IF;
-    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price
-    WHERE id = new.id AND user_author_id = new.user_author_id;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point,
+                     loc_dep_place_id = new.loc_dep_place_id, loc_arr_place_id = new.loc_arr_place_id
+    WHERE id = new.id AND user_author_id = new.user_author_id
+    RETURNING loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
     UPDATE route_tmp SET date_time_dep = new.date_time_dep, date_time_arr = new.date_time_arr
     WHERE id = new.id;
     RETURN new;
@@ -190,9 +601,12 @@ CREATE FUNCTION view_route_perm_insert()
 AS $$
 DECLARE id_ route.id%TYPE;
 BEGIN
-    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price)
-    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price)
-    RETURNING id INTO id_;
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point, loc_dep_place_id,
+                       loc_arr_place_id)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point,
+            new.loc_dep_place_id, new.loc_arr_place_id)
+    RETURNING id, loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO id_, new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
     INSERT INTO route_perm (id, even_week, odd_week, day_of_week, time_dep, time_arr)
     SELECT id_, new.even_week, new.odd_week, new.day_of_week, new.time_dep, new.time_arr;
     new.id := id_;
@@ -212,8 +626,12 @@ BEGIN
     IF old.user_author_id != new.user_author_id THEN
         RAISE 'It is forbidden to update author of permanent route';
     END IF;
-    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price
-    WHERE id = new.id AND user_author_id = new.user_author_id;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point,
+                     loc_dep_place_id = new.loc_dep_place_id, loc_arr_place_id = new.loc_arr_place_id
+    WHERE id = new.id AND user_author_id = new.user_author_id
+    RETURNING loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
     UPDATE route_perm SET even_week = new.even_week, odd_week = new.odd_week, day_of_week = new.day_of_week,
                           time_dep = new.time_dep, time_arr = new.time_arr
     WHERE id = new.id;
@@ -245,11 +663,50 @@ CREATE INDEX ON user_ USING hash (vk_id);
 CREATE INDEX ON ad USING hash (id);
 CREATE INDEX ON ad USING hash (user_author_id);
 CREATE INDEX ON ad (date_time_arr, min_price);
+CREATE INDEX ON ad USING hash (status);
+CREATE INDEX ON ad USING hash (loc_dep_place_id);
+CREATE INDEX ON ad USING hash (loc_arr_place_id);
+CREATE INDEX ON ad (date_time_arr) WHERE status = 'open';
+
+CREATE INDEX ON user_block USING hash (user_blocked_id);
 
 CREATE INDEX ON ad_user_execution USING hash (ad_id);
+CREATE INDEX ON ad_user_execution USING hash (user_executor_id);
+
+CREATE INDEX ON ad_thread USING hash (ad_id);
+CREATE UNIQUE INDEX ON ad_thread (ad_id) WHERE NOT archived;
+CREATE INDEX ON ad_message (ad_thread_id, id);
+
+CREATE INDEX ON notification (user_id, id);
+CREATE INDEX ON notification USING hash (user_id) WHERE NOT read;
+CREATE UNIQUE INDEX ON notification (user_id, ((payload->>'adId')::int)) WHERE type IN ('route_match', 'saved_search_match');
+CREATE INDEX ON notification_outbox (date_time_next_attempt) WHERE status = 'pending';
+CREATE INDEX ON notification_settings (date_time_digest_next) WHERE date_time_digest_next IS NOT NULL;
+CREATE INDEX ON notification_digest_item USING hash (user_id);
+CREATE INDEX ON ad_notification_pending (date_time);
+
+CREATE INDEX ON ad_offer USING hash (ad_id);
+CREATE UNIQUE INDEX ON ad_offer (ad_id) WHERE status = 'accepted';
+CREATE UNIQUE INDEX ON ad_offer (ad_id, user_executor_id) WHERE status IN ('pending', 'accepted', 'rejected');
+
+CREATE INDEX ON ad_review USING hash (user_target_id);
+
+CREATE INDEX ON report USING hash (status);
+CREATE UNIQUE INDEX ON report (user_author_id, ad_id) WHERE status = 'pending';
+CREATE UNIQUE INDEX ON report (user_author_id, user_target_id) WHERE status = 'pending';
+
+CREATE INDEX ON ad_photo USING hash (ad_id);
+
+CREATE INDEX ON ad_revision USING hash (ad_id);
+
+CREATE INDEX ON ad_template USING hash (user_author_id);
 
 CREATE INDEX ON route USING hash (user_author_id);
+CREATE INDEX ON route USING hash (loc_dep_place_id);
+CREATE INDEX ON route USING hash (loc_arr_place_id);
 
 CREATE INDEX ON route_tmp (date_time_dep, date_time_arr);
 
 CREATE INDEX ON route_perm (time_dep, time_arr);
+
+CREATE INDEX ON saved_search USING hash (user_author_id);
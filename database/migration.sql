@@ -1 +1,1055 @@
---TODO
+\c handover;
+
+ALTER TABLE ad ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'assigned', 'picked_up', 'delivered', 'confirmed', 'cancelled', 'expired'));
+UPDATE ad SET status = 'assigned' WHERE user_executor_vk_id IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION ad_user_execution_insert()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_executor_vk_id = (SELECT user_.vk_id FROM user_ WHERE user_.id = new.user_executor_id),
+                  status = 'assigned'
+    WHERE id = new.ad_id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION ad_user_execution_delete()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_executor_vk_id = NULL,
+                  status = CASE WHEN status = 'assigned' THEN 'open' ELSE status END
+    WHERE id = old.ad_id;
+    RETURN old;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX ON ad USING hash (status);
+
+CREATE TABLE ad_offer (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_executor_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_executor_vk_id INT NOT NULL,
+    user_executor_name VARCHAR(100) NOT NULL,
+    user_executor_avatar VARCHAR(500) NOT NULL,
+    price INT NOT NULL CHECK (price >= 0),
+    comment VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected', 'withdrawn')),
+    UNIQUE (ad_id, user_executor_id)
+);
+
+CREATE OR REPLACE FUNCTION user__update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_author_name = new.name, user_author_avatar = new.avatar
+    WHERE user_author_id = new.id;
+    UPDATE ad_offer SET user_executor_name = new.name, user_executor_avatar = new.avatar
+    WHERE user_executor_id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION ad_user_execution_delete()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_executor_vk_id = NULL,
+                  status = CASE WHEN status = 'assigned' THEN 'open' ELSE status END
+    WHERE id = old.ad_id;
+    UPDATE ad_offer SET status = 'withdrawn'
+    WHERE ad_id = old.ad_id AND status = 'accepted';
+    RETURN old;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION ad_offer_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE user__ user_%ROWTYPE;
+BEGIN
+    SELECT INTO user__ id, vk_id, name, avatar FROM user_ WHERE id = new.user_executor_id;
+    new.user_executor_vk_id = user__.vk_id;
+    new.user_executor_name = user__.name;
+    new.user_executor_avatar = user__.avatar;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_offer_insert BEFORE INSERT
+    ON ad_offer
+    FOR EACH ROW
+EXECUTE FUNCTION ad_offer_insert();
+
+CREATE FUNCTION ad_offer_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF new.status = 'accepted' AND old.status != 'accepted' THEN
+        INSERT INTO ad_user_execution (ad_id, user_executor_id)
+        VALUES (new.ad_id, new.user_executor_id);
+        UPDATE ad_offer SET status = 'rejected'
+        WHERE ad_id = new.ad_id AND id != new.id AND status = 'pending';
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_offer_update AFTER UPDATE
+    ON ad_offer
+    FOR EACH ROW
+EXECUTE FUNCTION ad_offer_update();
+
+CREATE INDEX ON ad_offer USING hash (ad_id);
+CREATE UNIQUE INDEX ON ad_offer (ad_id) WHERE status = 'accepted';
+
+CREATE INDEX ON ad (date_time_arr) WHERE status = 'open';
+
+CREATE TABLE ad_revision (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL,
+    user_id INT DEFAULT NULL REFERENCES user_ (id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('insert', 'update', 'delete', 'execution')),
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    diff JSONB NOT NULL
+);
+
+CREATE INDEX ON ad_revision USING hash (ad_id);
+
+CREATE TABLE ad_photo (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    content_type VARCHAR(50) NOT NULL,
+    size INT NOT NULL CHECK (size > 0),
+    blob_key VARCHAR(200) NOT NULL UNIQUE,
+    thumbnail_blob_key VARCHAR(200) NOT NULL UNIQUE
+);
+
+CREATE INDEX ON ad_photo USING hash (ad_id);
+
+ALTER TABLE ad ADD COLUMN loc_dep_point POINT DEFAULT NULL;
+ALTER TABLE ad ADD COLUMN loc_arr_point POINT DEFAULT NULL;
+
+ALTER TABLE route ADD COLUMN loc_dep_point POINT DEFAULT NULL;
+ALTER TABLE route ADD COLUMN loc_arr_point POINT DEFAULT NULL;
+
+DROP VIEW view_route_tmp;
+DROP VIEW view_route_perm;
+
+CREATE VIEW view_route_tmp (id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr,
+                            loc_dep_point, loc_arr_point)
+    AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_tmp.date_time_dep,
+              route_tmp.date_time_arr, route.loc_dep_point, route.loc_arr_point
+    FROM route
+        JOIN route_tmp ON route.id = route_tmp.id
+    ORDER BY route_tmp.date_time_dep, route_tmp.date_time_arr, route.min_price DESC, route.id;
+
+CREATE VIEW view_route_perm (id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week,
+                             time_dep, time_arr, loc_dep_point, loc_arr_point)
+    AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_perm.even_week,
+              route_perm.odd_week, route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr,
+              route.loc_dep_point, route.loc_arr_point
+    FROM route
+        JOIN route_perm ON route.id = route_perm.id
+    ORDER BY route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr, route.min_price DESC,
+             route_perm.odd_week DESC, route_perm.even_week DESC, route.id;
+
+CREATE OR REPLACE FUNCTION view_route_tmp_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE id_ route.id%TYPE;
+BEGIN
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point)
+    RETURNING id INTO id_;
+    INSERT INTO route_tmp (id, date_time_dep, date_time_arr)
+    SELECT id_, new.date_time_dep, new.date_time_arr;
+    new.id := id_;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_tmp_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF old.user_author_id != new.user_author_id THEN
+        RAISE 'It is forbidden to update author of temporary route';
+    END IF;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point
+    WHERE id = new.id AND user_author_id = new.user_author_id;
+    UPDATE route_tmp SET date_time_dep = new.date_time_dep, date_time_arr = new.date_time_arr
+    WHERE id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_perm_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE id_ route.id%TYPE;
+BEGIN
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point)
+    RETURNING id INTO id_;
+    INSERT INTO route_perm (id, even_week, odd_week, day_of_week, time_dep, time_arr)
+    SELECT id_, new.even_week, new.odd_week, new.day_of_week, new.time_dep, new.time_arr;
+    new.id := id_;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_perm_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF old.user_author_id != new.user_author_id THEN
+        RAISE 'It is forbidden to update author of permanent route';
+    END IF;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point
+    WHERE id = new.id AND user_author_id = new.user_author_id;
+    UPDATE route_perm SET even_week = new.even_week, odd_week = new.odd_week, day_of_week = new.day_of_week,
+                          time_dep = new.time_dep, time_arr = new.time_arr
+    WHERE id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER view_route_tmp_insert INSTEAD OF INSERT
+    ON view_route_tmp
+    FOR EACH ROW
+    EXECUTE FUNCTION view_route_tmp_insert();
+
+CREATE TRIGGER view_route_tmp_update INSTEAD OF UPDATE
+    ON view_route_tmp
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_tmp_update();
+
+CREATE TRIGGER view_route_tmp_delete INSTEAD OF DELETE
+    ON view_route_tmp
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_tmp_delete();
+
+CREATE TRIGGER view_route_perm_insert INSTEAD OF INSERT
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_insert();
+
+CREATE TRIGGER view_route_perm_update INSTEAD OF UPDATE
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_update();
+
+CREATE TRIGGER view_route_perm_delete INSTEAD OF DELETE
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_delete();
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE place (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE CHECK (length(name) >= 2),
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('building', 'dorm', 'metro')),
+    point POINT NOT NULL
+);
+
+ALTER TABLE ad ADD COLUMN loc_dep_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL;
+ALTER TABLE ad ADD COLUMN loc_arr_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL;
+
+ALTER TABLE route ADD COLUMN loc_dep_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL;
+ALTER TABLE route ADD COLUMN loc_arr_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL;
+
+CREATE FUNCTION loc_place_fill()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF new.loc_dep_place_id IS NOT NULL THEN
+        SELECT INTO new.loc_dep, new.loc_dep_point name, point FROM place WHERE id = new.loc_dep_place_id;
+        IF NOT FOUND THEN
+            RAISE foreign_key_violation USING MESSAGE = 'Place of departure does not exist';
+        END IF;
+    END IF;
+    IF new.loc_arr_place_id IS NOT NULL THEN
+        SELECT INTO new.loc_arr, new.loc_arr_point name, point FROM place WHERE id = new.loc_arr_place_id;
+        IF NOT FOUND THEN
+            RAISE foreign_key_violation USING MESSAGE = 'Place of arrival does not exist';
+        END IF;
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_loc_place_fill BEFORE INSERT OR UPDATE OF loc_dep, loc_arr, loc_dep_place_id, loc_arr_place_id
+    ON ad
+    FOR EACH ROW
+EXECUTE FUNCTION loc_place_fill();
+
+CREATE TRIGGER route_loc_place_fill BEFORE INSERT OR UPDATE OF loc_dep, loc_arr, loc_dep_place_id, loc_arr_place_id
+    ON route
+    FOR EACH ROW
+EXECUTE FUNCTION loc_place_fill();
+
+DROP VIEW view_route_tmp;
+DROP VIEW view_route_perm;
+
+CREATE VIEW view_route_tmp (id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr,
+                            loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id)
+    AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_tmp.date_time_dep,
+              route_tmp.date_time_arr, route.loc_dep_point, route.loc_arr_point, route.loc_dep_place_id,
+              route.loc_arr_place_id
+    FROM route
+        JOIN route_tmp ON route.id = route_tmp.id
+    ORDER BY route_tmp.date_time_dep, route_tmp.date_time_arr, route.min_price DESC, route.id;
+
+CREATE VIEW view_route_perm (id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week,
+                             time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id)
+    AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_perm.even_week,
+              route_perm.odd_week, route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr,
+              route.loc_dep_point, route.loc_arr_point, route.loc_dep_place_id, route.loc_arr_place_id
+    FROM route
+        JOIN route_perm ON route.id = route_perm.id
+    ORDER BY route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr, route.min_price DESC,
+             route_perm.odd_week DESC, route_perm.even_week DESC, route.id;
+
+CREATE OR REPLACE FUNCTION view_route_tmp_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE id_ route.id%TYPE;
+BEGIN
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point, loc_dep_place_id,
+                       loc_arr_place_id)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point,
+            new.loc_dep_place_id, new.loc_arr_place_id)
+    RETURNING id, loc_dep, loc_arr, loc_dep_point, loc_arr_point
+        INTO id_, new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point;
+    INSERT INTO route_tmp (id, date_time_dep, date_time_arr)
+    SELECT id_, new.date_time_dep, new.date_time_arr;
+    new.id := id_;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_tmp_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF old.user_author_id != new.user_author_id THEN
+        RAISE 'It is forbidden to update author of temporary route';
+    END IF;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point,
+                     loc_dep_place_id = new.loc_dep_place_id, loc_arr_place_id = new.loc_arr_place_id
+    WHERE id = new.id AND user_author_id = new.user_author_id
+    RETURNING loc_dep, loc_arr, loc_dep_point, loc_arr_point
+        INTO new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point;
+    UPDATE route_tmp SET date_time_dep = new.date_time_dep, date_time_arr = new.date_time_arr
+    WHERE id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_perm_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE id_ route.id%TYPE;
+BEGIN
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point, loc_dep_place_id,
+                       loc_arr_place_id)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point,
+            new.loc_dep_place_id, new.loc_arr_place_id)
+    RETURNING id, loc_dep, loc_arr, loc_dep_point, loc_arr_point
+        INTO id_, new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point;
+    INSERT INTO route_perm (id, even_week, odd_week, day_of_week, time_dep, time_arr)
+    SELECT id_, new.even_week, new.odd_week, new.day_of_week, new.time_dep, new.time_arr;
+    new.id := id_;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_perm_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF old.user_author_id != new.user_author_id THEN
+        RAISE 'It is forbidden to update author of permanent route';
+    END IF;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point,
+                     loc_dep_place_id = new.loc_dep_place_id, loc_arr_place_id = new.loc_arr_place_id
+    WHERE id = new.id AND user_author_id = new.user_author_id
+    RETURNING loc_dep, loc_arr, loc_dep_point, loc_arr_point
+        INTO new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point;
+    UPDATE route_perm SET even_week = new.even_week, odd_week = new.odd_week, day_of_week = new.day_of_week,
+                          time_dep = new.time_dep, time_arr = new.time_arr
+    WHERE id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER view_route_tmp_insert INSTEAD OF INSERT
+    ON view_route_tmp
+    FOR EACH ROW
+    EXECUTE FUNCTION view_route_tmp_insert();
+
+CREATE TRIGGER view_route_tmp_update INSTEAD OF UPDATE
+    ON view_route_tmp
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_tmp_update();
+
+CREATE TRIGGER view_route_tmp_delete INSTEAD OF DELETE
+    ON view_route_tmp
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_tmp_delete();
+
+CREATE TRIGGER view_route_perm_insert INSTEAD OF INSERT
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_insert();
+
+CREATE TRIGGER view_route_perm_update INSTEAD OF UPDATE
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_update();
+
+CREATE TRIGGER view_route_perm_delete INSTEAD OF DELETE
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_delete();
+
+CREATE INDEX ON ad USING hash (loc_dep_place_id);
+CREATE INDEX ON ad USING hash (loc_arr_place_id);
+
+CREATE INDEX ON route USING hash (loc_dep_place_id);
+CREATE INDEX ON route USING hash (loc_arr_place_id);
+
+CREATE TABLE saved_search (
+    id SERIAL PRIMARY KEY,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL CHECK (length(name) >= 1),
+    loc_dep VARCHAR(100) DEFAULT NULL,
+    loc_arr VARCHAR(100) DEFAULT NULL,
+    max_price INT DEFAULT NULL CHECK (max_price >= 0),
+    order_ INT DEFAULT NULL,
+    UNIQUE (user_author_id, name)
+);
+
+CREATE INDEX ON saved_search USING hash (user_author_id);
+
+ALTER TABLE ad ADD COLUMN date_time_dep TIMESTAMP DEFAULT NULL CHECK (date_time_dep <= date_time_arr);
+
+CREATE TABLE ad_template (
+    id SERIAL PRIMARY KEY,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    loc_dep VARCHAR(100) NOT NULL CHECK (length(loc_dep) >= 2),
+    loc_arr VARCHAR(100) NOT NULL CHECK (length(loc_arr) >= 2),
+    item VARCHAR(50) NOT NULL CHECK (length(item) >= 3),
+    min_price INT NOT NULL CHECK (min_price >= 0),
+    comment VARCHAR(100) NOT NULL,
+    even_week BOOLEAN NOT NULL,
+    odd_week BOOLEAN NOT NULL,
+    days_of_week INT[] NOT NULL CHECK (cardinality(days_of_week) > 0 AND days_of_week <@ '{1, 2, 3, 4, 5, 6, 7}'),
+    time_dep TIMESTAMP DEFAULT NULL CHECK (time_dep::time <= time_arr::time),
+    time_arr TIMESTAMP NOT NULL,
+    loc_dep_point POINT DEFAULT NULL,
+    loc_arr_point POINT DEFAULT NULL,
+    loc_dep_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    loc_arr_place_id INT DEFAULT NULL REFERENCES place (id) ON DELETE SET NULL,
+    paused BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE ad_template_occurrence (
+    ad_template_id INT NOT NULL REFERENCES ad_template (id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    ad_id INT DEFAULT NULL REFERENCES ad (id) ON DELETE SET NULL,
+    PRIMARY KEY (ad_template_id, date)
+);
+
+CREATE TRIGGER ad_template_loc_place_fill BEFORE INSERT OR UPDATE OF loc_dep, loc_arr, loc_dep_place_id, loc_arr_place_id
+    ON ad_template
+    FOR EACH ROW
+EXECUTE FUNCTION loc_place_fill();
+
+CREATE INDEX ON ad_template USING hash (user_author_id);
+
+ALTER TABLE ad ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE route ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+CREATE FUNCTION version_increment()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    new.version := old.version + 1;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_version_increment BEFORE UPDATE
+    ON ad
+    FOR EACH ROW
+EXECUTE FUNCTION version_increment();
+
+CREATE TRIGGER route_version_increment BEFORE UPDATE
+    ON route
+    FOR EACH ROW
+EXECUTE FUNCTION version_increment();
+
+DROP VIEW view_route_tmp;
+DROP VIEW view_route_perm;
+
+CREATE VIEW view_route_tmp (id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr,
+                            loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version)
+    AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_tmp.date_time_dep,
+              route_tmp.date_time_arr, route.loc_dep_point, route.loc_arr_point, route.loc_dep_place_id,
+              route.loc_arr_place_id, route.version
+    FROM route
+        JOIN route_tmp ON route.id = route_tmp.id
+    ORDER BY route_tmp.date_time_dep, route_tmp.date_time_arr, route.min_price DESC, route.id;
+
+CREATE VIEW view_route_perm (id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week,
+                             time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id,
+                             version)
+    AS SELECT route.id, route.user_author_id, route.loc_dep, route.loc_arr, route.min_price, route_perm.even_week,
+              route_perm.odd_week, route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr,
+              route.loc_dep_point, route.loc_arr_point, route.loc_dep_place_id, route.loc_arr_place_id, route.version
+    FROM route
+        JOIN route_perm ON route.id = route_perm.id
+    ORDER BY route_perm.day_of_week, route_perm.time_dep, route_perm.time_arr, route.min_price DESC,
+             route_perm.odd_week DESC, route_perm.even_week DESC, route.id;
+
+CREATE OR REPLACE FUNCTION view_route_tmp_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE id_ route.id%TYPE;
+BEGIN
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point, loc_dep_place_id,
+                       loc_arr_place_id)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point,
+            new.loc_dep_place_id, new.loc_arr_place_id)
+    RETURNING id, loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO id_, new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
+    INSERT INTO route_tmp (id, date_time_dep, date_time_arr)
+    SELECT id_, new.date_time_dep, new.date_time_arr;
+    new.id := id_;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_tmp_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF old.user_author_id != new.user_author_id THEN
+        RAISE 'It is forbidden to update author of temporary route';
+    END IF;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point,
+                     loc_dep_place_id = new.loc_dep_place_id, loc_arr_place_id = new.loc_arr_place_id
+    WHERE id = new.id AND user_author_id = new.user_author_id
+    RETURNING loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
+    UPDATE route_tmp SET date_time_dep = new.date_time_dep, date_time_arr = new.date_time_arr
+    WHERE id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_perm_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE id_ route.id%TYPE;
+BEGIN
+    INSERT INTO route (user_author_id, loc_dep, loc_arr, min_price, loc_dep_point, loc_arr_point, loc_dep_place_id,
+                       loc_arr_place_id)
+    VALUES (new.user_author_id, new.loc_dep, new.loc_arr, new.min_price, new.loc_dep_point, new.loc_arr_point,
+            new.loc_dep_place_id, new.loc_arr_place_id)
+    RETURNING id, loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO id_, new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
+    INSERT INTO route_perm (id, even_week, odd_week, day_of_week, time_dep, time_arr)
+    SELECT id_, new.even_week, new.odd_week, new.day_of_week, new.time_dep, new.time_arr;
+    new.id := id_;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION view_route_perm_update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF old.user_author_id != new.user_author_id THEN
+        RAISE 'It is forbidden to update author of permanent route';
+    END IF;
+    UPDATE route SET loc_dep = new.loc_dep, loc_arr = new.loc_arr, min_price = new.min_price,
+                     loc_dep_point = new.loc_dep_point, loc_arr_point = new.loc_arr_point,
+                     loc_dep_place_id = new.loc_dep_place_id, loc_arr_place_id = new.loc_arr_place_id
+    WHERE id = new.id AND user_author_id = new.user_author_id
+    RETURNING loc_dep, loc_arr, loc_dep_point, loc_arr_point, version
+        INTO new.loc_dep, new.loc_arr, new.loc_dep_point, new.loc_arr_point, new.version;
+    UPDATE route_perm SET even_week = new.even_week, odd_week = new.odd_week, day_of_week = new.day_of_week,
+                          time_dep = new.time_dep, time_arr = new.time_arr
+    WHERE id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER view_route_tmp_insert INSTEAD OF INSERT
+    ON view_route_tmp
+    FOR EACH ROW
+    EXECUTE FUNCTION view_route_tmp_insert();
+
+CREATE TRIGGER view_route_tmp_update INSTEAD OF UPDATE
+    ON view_route_tmp
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_tmp_update();
+
+CREATE TRIGGER view_route_tmp_delete INSTEAD OF DELETE
+    ON view_route_tmp
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_tmp_delete();
+
+CREATE TRIGGER view_route_perm_insert INSTEAD OF INSERT
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_insert();
+
+CREATE TRIGGER view_route_perm_update INSTEAD OF UPDATE
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_update();
+
+CREATE TRIGGER view_route_perm_delete INSTEAD OF DELETE
+    ON view_route_perm
+    FOR EACH ROW
+EXECUTE FUNCTION view_route_perm_delete();
+
+CREATE INDEX ON ad_user_execution USING hash (user_executor_id);
+
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE ad_handover (
+    ad_id INT NOT NULL PRIMARY KEY REFERENCES ad_user_execution (ad_id) ON DELETE CASCADE,
+    code CHAR(6) NOT NULL,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP DEFAULT NULL
+);
+
+CREATE FUNCTION ad_handover_code()
+    RETURNS CHAR(6)
+AS $$
+BEGIN
+    RETURN lpad(((('x' || encode(gen_random_bytes(4), 'hex'))::bit(32)::bigint) % 1000000)::text, 6, '0');
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION ad_user_execution_insert()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_executor_vk_id = (SELECT user_.vk_id FROM user_ WHERE user_.id = new.user_executor_id),
+                  status = 'assigned'
+    WHERE id = new.ad_id;
+    INSERT INTO ad_handover (ad_id, code)
+    VALUES (new.ad_id, ad_handover_code());
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+INSERT INTO ad_handover (ad_id, code)
+SELECT ad_user_execution.ad_id, ad_handover_code()
+FROM ad_user_execution
+    JOIN ad ON ad.id = ad_user_execution.ad_id
+WHERE ad.status IN ('assigned', 'picked_up', 'delivered');
+
+ALTER TABLE user_
+    ADD COLUMN rating REAL NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE ad_offer
+    ADD COLUMN user_executor_rating REAL NOT NULL DEFAULT 0;
+
+CREATE TABLE ad_review (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_target_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    rating INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment VARCHAR(300) NOT NULL,
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (ad_id, user_author_id)
+);
+
+CREATE OR REPLACE FUNCTION user__update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF new.name != old.name OR new.avatar != old.avatar THEN
+        UPDATE ad SET user_author_name = new.name, user_author_avatar = new.avatar
+        WHERE user_author_id = new.id;
+    END IF;
+    UPDATE ad_offer SET user_executor_name = new.name, user_executor_avatar = new.avatar,
+                        user_executor_rating = new.rating
+    WHERE user_executor_id = new.id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION ad_offer_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE user__ user_%ROWTYPE;
+BEGIN
+    SELECT INTO user__ id, vk_id, name, avatar, rating FROM user_ WHERE id = new.user_executor_id;
+    new.user_executor_vk_id = user__.vk_id;
+    new.user_executor_name = user__.name;
+    new.user_executor_avatar = user__.avatar;
+    new.user_executor_rating = user__.rating;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION ad_review_insert()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE user_ SET rating = (rating * rating_count + new.rating) / (rating_count + 1),
+                     rating_count = rating_count + 1
+    WHERE id = new.user_target_id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_review_insert AFTER INSERT
+    ON ad_review
+    FOR EACH ROW
+EXECUTE FUNCTION ad_review_insert();
+
+CREATE INDEX ON ad_review USING hash (user_target_id);
+
+ALTER TABLE user_
+    ADD COLUMN suspended BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE ad
+    ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE report (
+    id SERIAL PRIMARY KEY,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    ad_id INT DEFAULT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_target_id INT DEFAULT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('scam', 'offensive', 'spam', 'prohibited', 'other')),
+    comment VARCHAR(300) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'resolved', 'dismissed')),
+    action VARCHAR(20) DEFAULT NULL CHECK (action IN ('hide_ad', 'suspend_user')),
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK ((ad_id IS NULL) != (user_target_id IS NULL))
+);
+
+CREATE OR REPLACE FUNCTION user__update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF new.name != old.name OR new.avatar != old.avatar THEN
+        UPDATE ad SET user_author_name = new.name, user_author_avatar = new.avatar
+        WHERE user_author_id = new.id;
+    END IF;
+    UPDATE ad_offer SET user_executor_name = new.name, user_executor_avatar = new.avatar,
+                        user_executor_rating = new.rating
+    WHERE user_executor_id = new.id;
+    IF new.suspended AND NOT old.suspended THEN
+        UPDATE ad SET hidden = TRUE
+        WHERE user_author_id = new.id AND status = 'open';
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION ad_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE user__ user_%ROWTYPE;
+BEGIN
+    SELECT INTO user__ * FROM user_ WHERE id = new.user_author_id; --TODO: возможны ли оптимизации?
+    new.user_author_vk_id = user__.vk_id;
+    new.user_author_name = user__.name;
+    new.user_author_avatar = user__.avatar;
+    new.hidden = user__.suspended;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX ON report USING hash (status);
+CREATE UNIQUE INDEX ON report (user_author_id, ad_id) WHERE status = 'pending';
+CREATE UNIQUE INDEX ON report (user_author_id, user_target_id) WHERE status = 'pending';
+
+CREATE TABLE user_block (
+    user_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_blocked_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, user_blocked_id),
+    CHECK (user_id != user_blocked_id)
+);
+
+CREATE FUNCTION user_blocked(user_id_1 INT, user_id_2 INT)
+    RETURNS BOOLEAN
+AS $$
+BEGIN
+    RETURN EXISTS (SELECT FROM user_block
+                   WHERE user_id = user_id_1 AND user_blocked_id = user_id_2 OR
+                         user_id = user_id_2 AND user_blocked_id = user_id_1);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+CREATE FUNCTION user_executor_block_check()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF user_blocked(new.user_executor_id, (SELECT ad.user_author_id FROM ad WHERE ad.id = new.ad_id)) THEN
+        RAISE insufficient_privilege USING MESSAGE = 'Author of ad and executor have blocked each other';
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_user_execution_block_check BEFORE INSERT
+    ON ad_user_execution
+    FOR EACH ROW
+EXECUTE FUNCTION user_executor_block_check();
+
+CREATE TRIGGER ad_offer_block_check BEFORE INSERT
+    ON ad_offer
+    FOR EACH ROW
+EXECUTE FUNCTION user_executor_block_check();
+
+CREATE INDEX ON user_block USING hash (user_blocked_id);
+
+CREATE TABLE ad_thread (
+    id SERIAL PRIMARY KEY,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    user_executor_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    archived BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE ad_message (
+    id SERIAL PRIMARY KEY,
+    ad_thread_id INT NOT NULL REFERENCES ad_thread (id) ON DELETE CASCADE,
+    user_author_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    text VARCHAR(1000) NOT NULL CHECK (length(text) >= 1),
+    date_time TIMESTAMP NOT NULL DEFAULT now(),
+    date_time_read TIMESTAMP DEFAULT NULL
+);
+
+INSERT INTO ad_thread (ad_id, user_author_id, user_executor_id, archived)
+SELECT ad.id, ad.user_author_id, ad_user_execution.user_executor_id, ad.status IN ('confirmed', 'cancelled', 'expired')
+FROM ad_user_execution
+    JOIN ad ON ad.id = ad_user_execution.ad_id;
+
+CREATE OR REPLACE FUNCTION ad_user_execution_insert()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_executor_vk_id = (SELECT user_.vk_id FROM user_ WHERE user_.id = new.user_executor_id),
+                  status = 'assigned'
+    WHERE id = new.ad_id;
+    INSERT INTO ad_handover (ad_id, code)
+    VALUES (new.ad_id, ad_handover_code());
+    INSERT INTO ad_thread (ad_id, user_author_id, user_executor_id)
+    SELECT ad.id, ad.user_author_id, new.user_executor_id FROM ad WHERE ad.id = new.ad_id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION ad_user_execution_delete()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_executor_vk_id = NULL,
+                  status = CASE WHEN status = 'assigned' THEN 'open' ELSE status END
+    WHERE id = old.ad_id;
+    UPDATE ad_offer SET status = 'withdrawn'
+    WHERE ad_id = old.ad_id AND status = 'accepted';
+    UPDATE ad_thread SET archived = TRUE
+    WHERE ad_id = old.ad_id AND NOT archived;
+    RETURN old;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION ad_thread_archive()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad_thread SET archived = TRUE
+    WHERE ad_id = new.id AND NOT archived;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ad_thread_archive AFTER UPDATE OF status
+    ON ad
+    FOR EACH ROW
+    WHEN (new.status IN ('confirmed', 'cancelled', 'expired') AND old.status != new.status)
+EXECUTE FUNCTION ad_thread_archive();
+
+CREATE INDEX ON ad_thread USING hash (ad_id);
+CREATE UNIQUE INDEX ON ad_thread (ad_id) WHERE NOT archived;
+CREATE INDEX ON ad_message (ad_thread_id, id);
+
+CREATE TABLE notification (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('route_match', 'saved_search_match', 'ad_expired')),
+    payload JSONB NOT NULL,
+    read BOOLEAN NOT NULL DEFAULT FALSE,
+    date_time TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON notification (user_id, id);
+CREATE INDEX ON notification USING hash (user_id) WHERE NOT read;
+
+CREATE TABLE notification_outbox (
+    id SERIAL PRIMARY KEY,
+    notification_id INT NOT NULL UNIQUE REFERENCES notification (id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts INT NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    last_error VARCHAR(500) DEFAULT NULL,
+    date_time_next_attempt TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON notification_outbox (date_time_next_attempt) WHERE status = 'pending';
+
+CREATE TABLE notification_channel (
+    user_id INT PRIMARY KEY REFERENCES user_ (id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL DEFAULT 'vk_bot' CHECK (channel IN ('vk_bot', 'webhook', 'email')),
+    address VARCHAR(500) DEFAULT NULL,
+    CHECK ((channel = 'vk_bot') = (address IS NULL))
+);
+
+CREATE TABLE notification_settings (
+    user_id INT PRIMARY KEY REFERENCES user_ (id) ON DELETE CASCADE,
+    timezone VARCHAR(50) NOT NULL DEFAULT 'Europe/Moscow',
+    quiet_hours_from TIMESTAMP DEFAULT NULL,
+    quiet_hours_to TIMESTAMP DEFAULT NULL,
+    max_per_hour INT DEFAULT NULL CHECK (max_per_hour >= 1),
+    min_price INT DEFAULT NULL CHECK (min_price >= 0),
+    CHECK ((quiet_hours_from IS NULL) = (quiet_hours_to IS NULL))
+);
+
+CREATE TABLE route_mute (
+    route_id INT PRIMARY KEY REFERENCES route (id) ON DELETE CASCADE
+);
+
+ALTER TABLE notification_outbox ADD COLUMN date_time_sent TIMESTAMP DEFAULT NULL;
+
+ALTER TABLE notification DROP CONSTRAINT notification_type_check;
+ALTER TABLE notification
+    ADD CONSTRAINT notification_type_check CHECK (type IN ('route_match', 'saved_search_match', 'ad_expired', 'digest'));
+
+ALTER TABLE notification_settings
+    ADD COLUMN digest_interval INT DEFAULT NULL CHECK (digest_interval >= 1),
+    ADD COLUMN digest_times INT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN date_time_digest_next TIMESTAMP DEFAULT NULL,
+    ADD CHECK (digest_interval IS NULL OR cardinality(digest_times) = 0),
+    ADD CHECK ((date_time_digest_next IS NULL) = (digest_interval IS NULL AND cardinality(digest_times) = 0));
+
+CREATE INDEX ON notification_settings (date_time_digest_next) WHERE date_time_digest_next IS NOT NULL;
+
+CREATE TABLE notification_digest_item (
+    notification_id INT PRIMARY KEY REFERENCES notification (id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES user_ (id) ON DELETE CASCADE,
+    ad_id INT NOT NULL REFERENCES ad (id) ON DELETE CASCADE
+);
+
+CREATE INDEX ON notification_digest_item USING hash (user_id);
+
+ALTER TABLE ad_offer DROP CONSTRAINT ad_offer_ad_id_user_executor_id_key;
+
+CREATE UNIQUE INDEX ON ad_offer (ad_id, user_executor_id) WHERE status IN ('pending', 'accepted');
+
+CREATE TABLE ad_notification_pending (
+    ad_id INT PRIMARY KEY REFERENCES ad (id) ON DELETE CASCADE,
+    date_time TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON ad_notification_pending (date_time);
+
+DROP INDEX ad_offer_ad_id_user_executor_id_idx;
+
+CREATE UNIQUE INDEX ON ad_offer (ad_id, user_executor_id) WHERE status IN ('pending', 'accepted', 'rejected');
+
+CREATE OR REPLACE FUNCTION ad_user_execution_insert()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    UPDATE ad SET user_executor_vk_id = (SELECT user_.vk_id FROM user_ WHERE user_.id = new.user_executor_id),
+                  status = 'assigned'
+    WHERE id = new.ad_id AND status = 'open';
+    IF NOT FOUND THEN
+        RAISE object_not_in_prerequisite_state USING MESSAGE = 'Ad is not open';
+    END IF;
+    INSERT INTO ad_handover (ad_id, code)
+    VALUES (new.ad_id, ad_handover_code());
+    INSERT INTO ad_thread (ad_id, user_author_id, user_executor_id)
+    SELECT ad.id, ad.user_author_id, new.user_executor_id FROM ad WHERE ad.id = new.ad_id;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE ad
+    ADD COLUMN hidden_by_suspension BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE ad SET hidden_by_suspension = TRUE
+FROM user_
+WHERE user_.id = ad.user_author_id AND user_.suspended AND ad.hidden;
+
+CREATE OR REPLACE FUNCTION user__update()
+    RETURNS TRIGGER
+AS $$
+BEGIN
+    IF new.name != old.name OR new.avatar != old.avatar THEN
+        UPDATE ad SET user_author_name = new.name, user_author_avatar = new.avatar
+        WHERE user_author_id = new.id;
+    END IF;
+    UPDATE ad_offer SET user_executor_name = new.name, user_executor_avatar = new.avatar,
+                        user_executor_rating = new.rating
+    WHERE user_executor_id = new.id;
+    IF new.suspended AND NOT old.suspended THEN
+        UPDATE ad SET hidden = TRUE, hidden_by_suspension = TRUE
+        WHERE user_author_id = new.id AND status = 'open' AND NOT hidden;
+    ELSIF NOT new.suspended AND old.suspended THEN
+        UPDATE ad SET hidden = FALSE, hidden_by_suspension = FALSE
+        WHERE user_author_id = new.id AND hidden_by_suspension;
+    END IF;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION ad_insert()
+    RETURNS TRIGGER
+AS $$
+DECLARE user__ user_%ROWTYPE;
+BEGIN
+    SELECT INTO user__ * FROM user_ WHERE id = new.user_author_id; --TODO: возможны ли оптимизации?
+    new.user_author_vk_id = user__.vk_id;
+    new.user_author_name = user__.name;
+    new.user_author_avatar = user__.avatar;
+    new.hidden = user__.suspended;
+    new.hidden_by_suspension = user__.suspended;
+    RETURN new;
+END;
+$$ LANGUAGE plpgsql;
+
+DELETE FROM notification
+USING notification AS notification_
+WHERE notification.type IN ('route_match', 'saved_search_match') AND
+      notification_.type IN ('route_match', 'saved_search_match') AND
+      notification.user_id = notification_.user_id AND
+      notification.payload->>'adId' = notification_.payload->>'adId' AND
+      notification.id > notification_.id;
+
+CREATE UNIQUE INDEX ON notification (user_id, ((payload->>'adId')::int)) WHERE type IN ('route_match', 'saved_search_match');
@@ -1,6 +1,7 @@
 package delivery
 
 import (
+	"errors"
 	"github.com/TechnoHandOver/backend/internal/ad"
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/middlewares"
@@ -10,8 +11,17 @@ import (
 	"github.com/TechnoHandOver/backend/internal/tools/response"
 	"github.com/TechnoHandOver/backend/internal/tools/responser"
 	"github.com/labstack/echo/v4"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
 )
 
+// adPhotosMaxBodySize leaves 1 MiB over the photos for the multipart headers and boundaries
+const adPhotosMaxBodySize = models.AdPhotosMaxCount*models.AdPhotoMaxSize + 1<<20
+
+var errBodyTooLarge = errors.New("Request body too large\n")
+
 type AdDelivery struct {
 	adUsecase ad.Usecase
 }
@@ -26,21 +36,50 @@ func (adDelivery *AdDelivery) Configure(echo_ *echo.Echo, middlewaresManager *mi
 	echo_.POST("/api/ads", adDelivery.HandlerAdCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.GET("/api/ads/:id", adDelivery.HandlerAdGet())
 	echo_.PUT("/api/ads/:id", adDelivery.HandlerAdUpdate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.PATCH("/api/ads/:id", adDelivery.HandlerAdPatch(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.DELETE("/api/ads/:id", adDelivery.HandlerAdDelete(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.GET("/api/ads/list", adDelivery.HandlerAdsList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/executing", adDelivery.HandlerAdsExecuting(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.GET("/api/ads/search", adDelivery.HandlerAdsSearch(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.POST("/api/ads/:id/execution", adDelivery.HandlerAdExecutionCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.DELETE("/api/ads/:id/execution", adDelivery.HandlerAdExecutionDelete(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/offers", adDelivery.HandlerAdOfferCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/:id/offers", adDelivery.HandlerAdOffersList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/:id/history", adDelivery.HandlerAdHistory(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/photos", adDelivery.HandlerAdPhotosCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/:id/photos/:photoId", adDelivery.HandlerAdPhotoGet(false), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/:id/photos/:photoId/thumbnail", adDelivery.HandlerAdPhotoGet(true), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/offers/:offerId/acceptance", adDelivery.HandlerAdOfferAccept(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/offers/:offerId/rejection", adDelivery.HandlerAdOfferReject(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/pickup", adDelivery.HandlerAdPickUp(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/delivery", adDelivery.HandlerAdDeliver(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/confirmation", adDelivery.HandlerAdConfirm(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/cancellation", adDelivery.HandlerAdCancel(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/reviews", adDelivery.HandlerAdReviewCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/:id/handover", adDelivery.HandlerAdHandoverGet(false), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/:id/handover/qr", adDelivery.HandlerAdHandoverGet(true), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/confirm", adDelivery.HandlerAdHandoverConfirm(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/templates", adDelivery.HandlerAdTemplateCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/templates/list", adDelivery.HandlerAdTemplatesList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.DELETE("/api/ads/templates/:id", adDelivery.HandlerAdTemplateDelete(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/templates/:id/pause", adDelivery.HandlerAdTemplatePause(true), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.DELETE("/api/ads/templates/:id/pause", adDelivery.HandlerAdTemplatePause(false), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/templates/:id/skips", adDelivery.HandlerAdTemplateSkipCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
 }
 
 func (adDelivery *AdDelivery) HandlerAdCreate() echo.HandlerFunc {
 	type AdCreateRequest struct {
-		LocDep      *string   `json:"locDep" validate:"required,gte=2,lte=100"`
-		LocArr      *string   `json:"locArr" validate:"required,gte=2,lte=100"`
-		DateTimeArr *DateTime `json:"dateTimeArr" validate:"required"`
-		Item        *string   `json:"item" validate:"required,gte=3,lte=50"`
-		MinPrice    *uint32   `json:"minPrice" validate:"required"`
-		Comment     *string   `json:"comment" validate:"required,lte=100"`
+		LocDep        *string          `json:"locDep" validate:"required_without=LocDepPlaceId,omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"required_without=LocArrPlaceId,omitempty,gte=2,lte=100"`
+		DateTimeDep   *DateTime        `json:"dateTimeDep" validate:"omitempty"`
+		DateTimeArr   *DateTime        `json:"dateTimeArr" validate:"required"`
+		Item          *string          `json:"item" validate:"required,gte=3,lte=50"`
+		MinPrice      *uint32          `json:"minPrice" validate:"required"`
+		Comment       *string          `json:"comment" validate:"required,lte=100"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
 	}
 
 	return func(context echo.Context) error {
@@ -50,13 +89,18 @@ func (adDelivery *AdDelivery) HandlerAdCreate() echo.HandlerFunc {
 		}
 
 		ad_ := &models.Ad{
-			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
-			LocDep:       *adCreateRequest.LocDep,
-			LocArr:       *adCreateRequest.LocArr,
-			DateTimeArr:  *adCreateRequest.DateTimeArr,
-			Item:         *adCreateRequest.Item,
-			MinPrice:     *adCreateRequest.MinPrice,
-			Comment:      *adCreateRequest.Comment,
+			UserAuthorId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			LocDep:        parser.GetOrDefault(adCreateRequest.LocDep, "").(string),
+			LocArr:        parser.GetOrDefault(adCreateRequest.LocArr, "").(string),
+			DateTimeDep:   adCreateRequest.DateTimeDep,
+			DateTimeArr:   *adCreateRequest.DateTimeArr,
+			Item:          *adCreateRequest.Item,
+			MinPrice:      *adCreateRequest.MinPrice,
+			Comment:       *adCreateRequest.Comment,
+			LocDepPoint:   adCreateRequest.LocDepPoint,
+			LocArrPoint:   adCreateRequest.LocArrPoint,
+			LocDepPlaceId: adCreateRequest.LocDepPlaceId,
+			LocArrPlaceId: adCreateRequest.LocArrPlaceId,
 		}
 
 		return responser.Respond(context, adDelivery.adUsecase.Create(ad_))
@@ -82,13 +126,18 @@ func (adDelivery *AdDelivery) HandlerAdGet() echo.HandlerFunc {
 
 func (adDelivery *AdDelivery) HandlerAdUpdate() echo.HandlerFunc {
 	type AdUpdateRequest struct {
-		Id          *uint32   `param:"id" validate:"required"`
-		LocDep      *string   `json:"locDep" validate:"required,gte=2,lte=100"`
-		LocArr      *string   `json:"locArr" validate:"required,gte=2,lte=100"`
-		DateTimeArr *DateTime `json:"dateTimeArr" validate:"required"`
-		Item        *string   `json:"item" validate:"required,gte=3,lte=50"`
-		MinPrice    *uint32   `json:"minPrice" validate:"required"`
-		Comment     *string   `json:"comment" validate:"required,lte=100"`
+		Id            *uint32          `param:"id" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"required_without=LocDepPlaceId,omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"required_without=LocArrPlaceId,omitempty,gte=2,lte=100"`
+		DateTimeDep   *DateTime        `json:"dateTimeDep" validate:"omitempty"`
+		DateTimeArr   *DateTime        `json:"dateTimeArr" validate:"required"`
+		Item          *string          `json:"item" validate:"required,gte=3,lte=50"`
+		MinPrice      *uint32          `json:"minPrice" validate:"required"`
+		Comment       *string          `json:"comment" validate:"required,lte=100"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
 	}
 
 	return func(context echo.Context) error {
@@ -97,21 +146,102 @@ func (adDelivery *AdDelivery) HandlerAdUpdate() echo.HandlerFunc {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
 		ad_ := &models.Ad{
-			Id:           *adUpdateRequest.Id,
-			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
-			LocDep:       *adUpdateRequest.LocDep,
-			LocArr:       *adUpdateRequest.LocArr,
-			DateTimeArr:  *adUpdateRequest.DateTimeArr,
-			Item:         *adUpdateRequest.Item,
-			MinPrice:     *adUpdateRequest.MinPrice,
-			Comment:      *adUpdateRequest.Comment,
+			Id:            *adUpdateRequest.Id,
+			UserAuthorId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			LocDep:        parser.GetOrDefault(adUpdateRequest.LocDep, "").(string),
+			LocArr:        parser.GetOrDefault(adUpdateRequest.LocArr, "").(string),
+			DateTimeDep:   adUpdateRequest.DateTimeDep,
+			DateTimeArr:   *adUpdateRequest.DateTimeArr,
+			Item:          *adUpdateRequest.Item,
+			MinPrice:      *adUpdateRequest.MinPrice,
+			Comment:       *adUpdateRequest.Comment,
+			LocDepPoint:   adUpdateRequest.LocDepPoint,
+			LocArrPoint:   adUpdateRequest.LocArrPoint,
+			LocDepPlaceId: adUpdateRequest.LocDepPlaceId,
+			LocArrPlaceId: adUpdateRequest.LocArrPlaceId,
+			Version:       version,
 		}
 
 		return responser.Respond(context, adDelivery.adUsecase.Update(ad_))
 	}
 }
 
+func (adDelivery *AdDelivery) HandlerAdPatch() echo.HandlerFunc {
+	type AdPatchRequest struct {
+		Id            *uint32          `param:"id" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"omitempty,gte=2,lte=100"`
+		DateTimeDep   *DateTime        `json:"dateTimeDep" validate:"omitempty"`
+		DateTimeArr   *DateTime        `json:"dateTimeArr" validate:"omitempty"`
+		Item          *string          `json:"item" validate:"omitempty,gte=3,lte=50"`
+		MinPrice      *uint32          `json:"minPrice" validate:"omitempty"`
+		Comment       *string          `json:"comment" validate:"omitempty,lte=100"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
+	}
+
+	return func(context echo.Context) error {
+		adPatchRequest := new(AdPatchRequest)
+		document, err := parser.ParseMergePatchRequest(context, adPatchRequest)
+		if err != nil {
+			if err == parser.ErrUnsupportedMediaType {
+				return responser.Respond(context, response.NewEmptyResponse(consts.UnsupportedMediaType))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+		if document.IsAnyNull("locDep", "locArr", "dateTimeArr", "item", "minPrice", "comment") {
+			return responser.Respond(context, response.NewEmptyResponse(consts.BadRequest))
+		}
+
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
+		//typed location without place id detaches ad from previous place, otherwise it would be overwritten by place
+		adPatch := &models.AdPatch{
+			Id:                *adPatchRequest.Id,
+			UserAuthorId:      context.Get(consts.EchoContextKeyUserId).(uint32),
+			Version:           version,
+			LocDep:            adPatchRequest.LocDep,
+			LocArr:            adPatchRequest.LocArr,
+			DateTimeDep:       adPatchRequest.DateTimeDep,
+			DateTimeDepNull:   document.IsNull("dateTimeDep"),
+			DateTimeArr:       adPatchRequest.DateTimeArr,
+			Item:              adPatchRequest.Item,
+			MinPrice:          adPatchRequest.MinPrice,
+			Comment:           adPatchRequest.Comment,
+			LocDepPoint:       adPatchRequest.LocDepPoint,
+			LocDepPointNull:   document.IsNull("locDepPoint"),
+			LocArrPoint:       adPatchRequest.LocArrPoint,
+			LocArrPointNull:   document.IsNull("locArrPoint"),
+			LocDepPlaceId:     adPatchRequest.LocDepPlaceId,
+			LocDepPlaceIdNull: document.IsNullOrSuperseded("locDepPlaceId", "locDep", "locDepPoint"),
+			LocArrPlaceId:     adPatchRequest.LocArrPlaceId,
+			LocArrPlaceIdNull: document.IsNullOrSuperseded("locArrPlaceId", "locArr", "locArrPoint"),
+		}
+
+		return responser.Respond(context, adDelivery.adUsecase.Patch(adPatch))
+	}
+}
+
 func (adDelivery *AdDelivery) HandlerAdDelete() echo.HandlerFunc {
 	type AdDeleteRequest struct {
 		Id *uint32 `param:"id" validate:"required"`
@@ -123,18 +253,72 @@ func (adDelivery *AdDelivery) HandlerAdDelete() echo.HandlerFunc {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
 		id := *adDeleteRequest.Id
 		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
 
-		return responser.Respond(context, adDelivery.adUsecase.Delete(userId, id))
+		return responser.Respond(context, adDelivery.adUsecase.Delete(userId, id, version))
 	}
 }
 
 func (adDelivery *AdDelivery) HandlerAdsList() echo.HandlerFunc {
+	type AdsListRequest struct {
+		Status *models.AdStatus        `query:"status" validate:"omitempty,eq=open|eq=assigned|eq=picked_up|eq=delivered|eq=confirmed|eq=cancelled|eq=expired"`
+		Order  *models.AdsSearchOrder  `query:"order" validate:"omitempty"`
+		Cursor *models.AdsSearchCursor `query:"cursor" validate:"omitempty"`
+		Limit  *uint32                 `query:"limit" validate:"omitempty,min=1,max=100"`
+	}
+
 	return func(context echo.Context) error {
+		adsListRequest := new(AdsListRequest)
+		if err := parser.ParseRequest(context, adsListRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
 		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
 		adsSearch := &models.AdsSearch{
 			UserAuthorId: &userId,
+			Status:       adsListRequest.Status,
+			Order:        adsListRequest.Order,
+			Cursor:       adsListRequest.Cursor,
+			Limit:        adsListRequest.Limit,
+		}
+
+		return responser.Respond(context, adDelivery.adUsecase.Search(adsSearch))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdsExecuting() echo.HandlerFunc {
+	type AdsExecutingRequest struct {
+		Active *bool                   `query:"active" validate:"omitempty"`
+		Status *models.AdStatus        `query:"status" validate:"omitempty,eq=assigned|eq=picked_up|eq=delivered|eq=confirmed|eq=cancelled"`
+		Order  *models.AdsSearchOrder  `query:"order" validate:"omitempty"`
+		Cursor *models.AdsSearchCursor `query:"cursor" validate:"omitempty"`
+		Limit  *uint32                 `query:"limit" validate:"omitempty,min=1,max=100"`
+	}
+
+	return func(context echo.Context) error {
+		adsExecutingRequest := new(AdsExecutingRequest)
+		if err := parser.ParseRequest(context, adsExecutingRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+		adsSearch := &models.AdsSearch{
+			UserExecutorId: &userId,
+			Status:         adsExecutingRequest.Status,
+			Active:         adsExecutingRequest.Active,
+			Order:          adsExecutingRequest.Order,
+			Cursor:         adsExecutingRequest.Cursor,
+			Limit:          adsExecutingRequest.Limit,
 		}
 
 		return responser.Respond(context, adDelivery.adUsecase.Search(adsSearch))
@@ -143,11 +327,20 @@ func (adDelivery *AdDelivery) HandlerAdsList() echo.HandlerFunc {
 
 func (adDelivery *AdDelivery) HandlerAdsSearch() echo.HandlerFunc {
 	type AdsSearchRequest struct {
-		LocDep         *string                `query:"loc_dep" validate:"omitempty,lte=100"`
-		LocArr         *string                `query:"loc_arr" validate:"omitempty,lte=100"`
-		MinDateTimeArr *DateTime              `query:"min_date_time_arr" validate:"omitempty"` //TODO: а точно нужен поиск по дате? как он будет работать?
-		MaxPrice       *uint32                `query:"max_price" validate:"omitempty"`
-		Order          *models.AdsSearchOrder `query:"order" validate:"omitempty"`
+		LocDep         *string                 `query:"loc_dep" validate:"omitempty,lte=100"`
+		LocArr         *string                 `query:"loc_arr" validate:"omitempty,lte=100"`
+		LocDepPlaceId  *uint32                 `query:"loc_dep_place_id" validate:"omitempty"`
+		LocArrPlaceId  *uint32                 `query:"loc_arr_place_id" validate:"omitempty"`
+		MinDateTimeArr *DateTime               `query:"min_date_time_arr" validate:"omitempty"` //TODO: а точно нужен поиск по дате? как он будет работать?
+		MaxPrice       *uint32                 `query:"max_price" validate:"omitempty"`
+		MinRating      *float32                `query:"min_rating" validate:"omitempty,min=1,max=5"`
+		NearDep        *models.GeoPoint        `query:"near_dep" validate:"omitempty"`
+		NearArr        *models.GeoPoint        `query:"near_arr" validate:"omitempty"`
+		RadiusM        *uint32                 `query:"radius_m" validate:"omitempty,min=1,max=50000"`
+		Order          *models.AdsSearchOrder  `query:"order" validate:"omitempty"`
+		Status         *models.AdStatus        `query:"status" validate:"omitempty,eq=open|eq=assigned|eq=picked_up|eq=delivered|eq=confirmed|eq=cancelled|eq=expired"`
+		Cursor         *models.AdsSearchCursor `query:"cursor" validate:"omitempty"`
+		Limit          *uint32                 `query:"limit" validate:"omitempty,min=1,max=100"`
 	}
 
 	return func(context echo.Context) error {
@@ -158,12 +351,22 @@ func (adDelivery *AdDelivery) HandlerAdsSearch() echo.HandlerFunc {
 
 		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
 		adsSearch := &models.AdsSearch{
-			NotUserAuthorId: &userId,
-			LocDep:          adsSearchRequest.LocDep,
-			LocArr:          adsSearchRequest.LocArr,
-			MinDateTimeArr:  adsSearchRequest.MinDateTimeArr,
-			MaxPrice:        adsSearchRequest.MaxPrice,
-			Order:           adsSearchRequest.Order,
+			NotUserAuthorId:     &userId,
+			NotBlockedUserId:    &userId,
+			LocDep:              adsSearchRequest.LocDep,
+			LocArr:              adsSearchRequest.LocArr,
+			LocDepPlaceId:       adsSearchRequest.LocDepPlaceId,
+			LocArrPlaceId:       adsSearchRequest.LocArrPlaceId,
+			MinDateTimeArr:      adsSearchRequest.MinDateTimeArr,
+			MaxPrice:            adsSearchRequest.MaxPrice,
+			MinUserAuthorRating: adsSearchRequest.MinRating,
+			NearDep:             adsSearchRequest.NearDep,
+			NearArr:             adsSearchRequest.NearArr,
+			RadiusM:             adsSearchRequest.RadiusM,
+			Order:               adsSearchRequest.Order,
+			Status:              adsSearchRequest.Status,
+			Cursor:              adsSearchRequest.Cursor,
+			Limit:               adsSearchRequest.Limit,
 		}
 
 		return responser.Respond(context, adDelivery.adUsecase.Search(adsSearch))
@@ -205,3 +408,478 @@ func (adDelivery *AdDelivery) HandlerAdExecutionDelete() echo.HandlerFunc {
 		return responser.Respond(context, adDelivery.adUsecase.UnsetAdUserExecutor(userId, adId))
 	}
 }
+
+func (adDelivery *AdDelivery) HandlerAdOfferCreate() echo.HandlerFunc {
+	type AdOfferCreateRequest struct {
+		AdId    *uint32 `param:"id" validate:"required"`
+		Price   *uint32 `json:"price" validate:"required"`
+		Comment *string `json:"comment" validate:"omitempty,lte=100"`
+	}
+
+	return func(context echo.Context) error {
+		adOfferCreateRequest := new(AdOfferCreateRequest)
+		if err := parser.ParseRequest(context, adOfferCreateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adOffer := &models.AdOffer{
+			AdId:           *adOfferCreateRequest.AdId,
+			UserExecutorId: context.Get(consts.EchoContextKeyUserId).(uint32),
+			Price:          *adOfferCreateRequest.Price,
+			Comment:        parser.GetOrDefault(adOfferCreateRequest.Comment, "").(string),
+		}
+
+		return responser.Respond(context, adDelivery.adUsecase.CreateAdOffer(adOffer))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdOffersList() echo.HandlerFunc {
+	type AdOffersListRequest struct {
+		AdId      *uint32                     `param:"id" validate:"required"`
+		MinRating *float32                    `query:"min_rating" validate:"omitempty,min=1,max=5"`
+		Order     *models.AdOffersSearchOrder `query:"order" validate:"omitempty"`
+	}
+
+	return func(context echo.Context) error {
+		adOffersListRequest := new(AdOffersListRequest)
+		if err := parser.ParseRequest(context, adOffersListRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+		adOffersSearch := &models.AdOffersSearch{
+			AdId:                  *adOffersListRequest.AdId,
+			MinUserExecutorRating: adOffersListRequest.MinRating,
+			Order:                 adOffersListRequest.Order,
+		}
+
+		return responser.Respond(context, adDelivery.adUsecase.ListAdOffers(userId, adOffersSearch))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdHistory() echo.HandlerFunc {
+	type AdHistoryRequest struct {
+		AdId *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adHistoryRequest := new(AdHistoryRequest)
+		if err := parser.ParseRequest(context, adHistoryRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adHistoryRequest.AdId
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.ListAdRevisions(userId, adId))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdPhotosCreate() echo.HandlerFunc {
+	type AdPhotosCreateRequest struct {
+		AdId *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		//binding parses the form too, so the body is limited before it
+		request := context.Request()
+		if request.ContentLength > adPhotosMaxBodySize {
+			return responser.Respond(context, response.NewEmptyResponse(consts.PayloadTooLarge))
+		}
+		body := &limitedBody{ReadCloser: request.Body, remaining: adPhotosMaxBodySize}
+		request.Body = body
+
+		adPhotosCreateRequest := new(AdPhotosCreateRequest)
+		if err := parser.ParseRequest(context, adPhotosCreateRequest); err != nil {
+			if body.exceeded {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PayloadTooLarge))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		form, err := context.MultipartForm()
+		if err != nil {
+			if body.exceeded {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PayloadTooLarge))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		fileHeaders := form.File["photos"]
+		if len(fileHeaders) > models.AdPhotosMaxCount {
+			return responser.Respond(context, response.NewEmptyResponse(consts.BadRequest))
+		}
+
+		photos := make([][]byte, len(fileHeaders))
+		for i, fileHeader := range fileHeaders {
+			if fileHeader.Size > models.AdPhotoMaxSize {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PayloadTooLarge))
+			}
+
+			if photos[i], err = readMultipartFile(fileHeader); err != nil {
+				return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+			}
+		}
+
+		adId := *adPhotosCreateRequest.AdId
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.CreateAdPhotos(userId, adId, photos))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdPhotoGet(thumbnail bool) echo.HandlerFunc {
+	type AdPhotoGetRequest struct {
+		AdId      *uint32 `param:"id" validate:"required"`
+		AdPhotoId *uint32 `param:"photoId" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adPhotoGetRequest := new(AdPhotoGetRequest)
+		if err := parser.ParseRequest(context, adPhotoGetRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		response_ := adDelivery.adUsecase.GetAdPhoto(*adPhotoGetRequest.AdId, *adPhotoGetRequest.AdPhotoId, thumbnail)
+		if response_.Code != consts.OK {
+			return responser.Respond(context, response_)
+		}
+
+		adPhotoContent := response_.Data.(*models.AdPhotoContent)
+		context.Response().Header().Set("Cache-Control", "private, max-age=86400")
+		return context.Blob(http.StatusOK, adPhotoContent.ContentType, adPhotoContent.Data)
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdOfferAccept() echo.HandlerFunc {
+	type AdOfferAcceptRequest struct {
+		AdId *uint32 `param:"id" validate:"required"`
+		Id   *uint32 `param:"offerId" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adOfferAcceptRequest := new(AdOfferAcceptRequest)
+		if err := parser.ParseRequest(context, adOfferAcceptRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adOfferAcceptRequest.AdId
+		id := *adOfferAcceptRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.AcceptAdOffer(userId, adId, id))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdOfferReject() echo.HandlerFunc {
+	type AdOfferRejectRequest struct {
+		AdId *uint32 `param:"id" validate:"required"`
+		Id   *uint32 `param:"offerId" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adOfferRejectRequest := new(AdOfferRejectRequest)
+		if err := parser.ParseRequest(context, adOfferRejectRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adOfferRejectRequest.AdId
+		id := *adOfferRejectRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.RejectAdOffer(userId, adId, id))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdPickUp() echo.HandlerFunc {
+	type AdPickUpRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adPickUpRequest := new(AdPickUpRequest)
+		if err := parser.ParseRequest(context, adPickUpRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adPickUpRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.PickUp(userId, adId))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdDeliver() echo.HandlerFunc {
+	type AdDeliverRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adDeliverRequest := new(AdDeliverRequest)
+		if err := parser.ParseRequest(context, adDeliverRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adDeliverRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.Deliver(userId, adId))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdConfirm() echo.HandlerFunc {
+	type AdConfirmRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adConfirmRequest := new(AdConfirmRequest)
+		if err := parser.ParseRequest(context, adConfirmRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adConfirmRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.Confirm(userId, adId))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdReviewCreate() echo.HandlerFunc {
+	type AdReviewCreateRequest struct {
+		AdId    *uint32 `param:"id" validate:"required"`
+		Rating  *uint32 `json:"rating" validate:"required,min=1,max=5"`
+		Comment *string `json:"comment" validate:"omitempty,lte=300"`
+	}
+
+	return func(context echo.Context) error {
+		adReviewCreateRequest := new(AdReviewCreateRequest)
+		if err := parser.ParseRequest(context, adReviewCreateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adReview := &models.AdReview{
+			AdId:         *adReviewCreateRequest.AdId,
+			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
+			Rating:       *adReviewCreateRequest.Rating,
+			Comment:      parser.GetOrDefault(adReviewCreateRequest.Comment, "").(string),
+		}
+
+		return responser.Respond(context, adDelivery.adUsecase.CreateAdReview(adReview))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdHandoverGet(qr bool) echo.HandlerFunc {
+	type AdHandoverGetRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adHandoverGetRequest := new(AdHandoverGetRequest)
+		if err := parser.ParseRequest(context, adHandoverGetRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adHandoverGetRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.GetAdHandover(userId, adId, qr))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdHandoverConfirm() echo.HandlerFunc {
+	type AdHandoverConfirmRequest struct {
+		Id   *uint32 `param:"id" validate:"required"`
+		Code *string `json:"code" validate:"required,len=6,numeric"`
+	}
+
+	return func(context echo.Context) error {
+		adHandoverConfirmRequest := new(AdHandoverConfirmRequest)
+		if err := parser.ParseRequest(context, adHandoverConfirmRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adHandoverConfirmRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.ConfirmAdHandover(userId, adId, *adHandoverConfirmRequest.Code))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdCancel() echo.HandlerFunc {
+	type AdCancelRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adCancelRequest := new(AdCancelRequest)
+		if err := parser.ParseRequest(context, adCancelRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adId := *adCancelRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.Cancel(userId, adId))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdTemplateCreate() echo.HandlerFunc {
+	type AdTemplateCreateRequest struct {
+		LocDep        *string          `json:"locDep" validate:"required_without=LocDepPlaceId,omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"required_without=LocArrPlaceId,omitempty,gte=2,lte=100"`
+		Item          *string          `json:"item" validate:"required,gte=3,lte=50"`
+		MinPrice      *uint32          `json:"minPrice" validate:"required"`
+		Comment       *string          `json:"comment" validate:"required,lte=100"`
+		EvenWeek      *bool            `json:"evenWeek" validate:"required"`
+		OddWeek       *bool            `json:"oddWeek" validate:"required"`
+		DaysOfWeek    []DayOfWeek      `json:"daysOfWeek" validate:"required,min=1,max=7,dive,eq=Mon|eq=Tue|eq=Wed|eq=Thu|eq=Fri|eq=Sat|eq=Sun"`
+		TimeDep       *Time            `json:"timeDep" validate:"omitempty"`
+		TimeArr       *Time            `json:"timeArr" validate:"required"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
+	}
+
+	return func(context echo.Context) error {
+		adTemplateCreateRequest := new(AdTemplateCreateRequest)
+		if err := parser.ParseRequest(context, adTemplateCreateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		daysOfWeek := make([]uint32, len(adTemplateCreateRequest.DaysOfWeek))
+		for i, dayOfWeek := range adTemplateCreateRequest.DaysOfWeek {
+			dayOfWeek_, err := dayOfWeek.ToUint32()
+			if err != nil {
+				return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+			}
+			daysOfWeek[i] = dayOfWeek_
+		}
+		adTemplate := &models.AdTemplate{
+			UserAuthorId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			LocDep:        parser.GetOrDefault(adTemplateCreateRequest.LocDep, "").(string),
+			LocArr:        parser.GetOrDefault(adTemplateCreateRequest.LocArr, "").(string),
+			Item:          *adTemplateCreateRequest.Item,
+			MinPrice:      *adTemplateCreateRequest.MinPrice,
+			Comment:       *adTemplateCreateRequest.Comment,
+			EvenWeek:      *adTemplateCreateRequest.EvenWeek,
+			OddWeek:       *adTemplateCreateRequest.OddWeek,
+			DaysOfWeek:    daysOfWeek,
+			TimeDep:       adTemplateCreateRequest.TimeDep,
+			TimeArr:       *adTemplateCreateRequest.TimeArr,
+			LocDepPoint:   adTemplateCreateRequest.LocDepPoint,
+			LocArrPoint:   adTemplateCreateRequest.LocArrPoint,
+			LocDepPlaceId: adTemplateCreateRequest.LocDepPlaceId,
+			LocArrPlaceId: adTemplateCreateRequest.LocArrPlaceId,
+		}
+
+		return responser.Respond(context, adDelivery.adUsecase.CreateAdTemplate(adTemplate))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdTemplatesList() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.ListAdTemplates(userId))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdTemplateDelete() echo.HandlerFunc {
+	type AdTemplateDeleteRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adTemplateDeleteRequest := new(AdTemplateDeleteRequest)
+		if err := parser.ParseRequest(context, adTemplateDeleteRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		id := *adTemplateDeleteRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.DeleteAdTemplate(userId, id))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdTemplatePause(paused bool) echo.HandlerFunc {
+	type AdTemplatePauseRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adTemplatePauseRequest := new(AdTemplatePauseRequest)
+		if err := parser.ParseRequest(context, adTemplatePauseRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		id := *adTemplatePauseRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.SetAdTemplatePaused(userId, id, paused))
+	}
+}
+
+func (adDelivery *AdDelivery) HandlerAdTemplateSkipCreate() echo.HandlerFunc {
+	type AdTemplateSkipCreateRequest struct {
+		Id   *uint32 `param:"id" validate:"required"`
+		Date *Date   `json:"date" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adTemplateSkipCreateRequest := new(AdTemplateSkipCreateRequest)
+		if err := parser.ParseRequest(context, adTemplateSkipCreateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		id := *adTemplateSkipCreateRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, adDelivery.adUsecase.SkipAdTemplateOccurrence(userId, id,
+			*adTemplateSkipCreateRequest.Date))
+	}
+}
+
+func readMultipartFile(fileHeader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return ioutil.ReadAll(io.LimitReader(file, models.AdPhotoMaxSize+1))
+}
+
+// limitedBody fails the reads past the remaining bytes and remembers it, as the multipart parser drops the error
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+func (body *limitedBody) Read(p []byte) (int, error) {
+	if body.exceeded {
+		return 0, errBodyTooLarge
+	}
+
+	if int64(len(p)) > body.remaining+1 {
+		p = p[:body.remaining+1]
+	}
+
+	n, err := body.ReadCloser.Read(p)
+	if int64(n) <= body.remaining {
+		body.remaining -= int64(n)
+		return n, err
+	}
+
+	n = int(body.remaining)
+	body.remaining = 0
+	body.exceeded = true
+	return n, errBodyTooLarge
+}
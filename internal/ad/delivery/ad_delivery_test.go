@@ -1,6 +1,7 @@
 package delivery_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"github.com/TechnoHandOver/backend/internal/ad/delivery"
 	"github.com/TechnoHandOver/backend/internal/ad/mock_ad"
@@ -8,6 +9,8 @@ import (
 	"github.com/TechnoHandOver/backend/internal/middlewares"
 	"github.com/TechnoHandOver/backend/internal/models"
 	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+	"github.com/TechnoHandOver/backend/internal/tools/mergepatch"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
 	"github.com/TechnoHandOver/backend/internal/tools/responser"
 	HandoverValidator "github.com/TechnoHandOver/backend/internal/tools/validator"
@@ -15,7 +18,9 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/openlyinc/pointy"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -33,12 +38,15 @@ func TestAdDelivery_HandlerAdCreate(t *testing.T) {
 	echo_.Validator = HandoverValidator.NewRequestValidator()
 	adDelivery.Configure(echo_, &middlewares.Manager{})
 
+	dateTimeDep, err := timestamps.NewDateTime("27.10.2021 18:00")
+	assert.Nil(t, err)
 	dateTimeArr, err := timestamps.NewDateTime("27.10.2021 19:31")
 	assert.Nil(t, err)
 	ad := &models.Ad{
 		UserAuthorId: 101,
 		LocDep:       "Общежитие №10",
 		LocArr:       "УЛК",
+		DateTimeDep:  dateTimeDep,
 		DateTimeArr:  *dateTimeArr,
 		Item:         "Зачётная книжка",
 		MinPrice:     500,
@@ -50,6 +58,7 @@ func TestAdDelivery_HandlerAdCreate(t *testing.T) {
 		UserAuthorVkId: 201,
 		LocDep:         ad.LocDep,
 		LocArr:         ad.LocArr,
+		DateTimeDep:    ad.DateTimeDep,
 		DateTimeArr:    ad.DateTimeArr,
 		Item:           ad.Item,
 		MinPrice:       ad.MinPrice,
@@ -92,6 +101,107 @@ func TestAdDelivery_HandlerAdCreate(t *testing.T) {
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
 
+func TestAdDelivery_HandlerAdCreate_placeIds(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	dateTimeArr, err := timestamps.NewDateTime("27.10.2021 19:31")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		UserAuthorId:  101,
+		DateTimeArr:   *dateTimeArr,
+		Item:          "Зачётная книжка",
+		MinPrice:      500,
+		Comment:       "Поеду на велосипеде",
+		LocDepPlaceId: pointy.Uint32(1),
+		LocArrPlaceId: pointy.Uint32(2),
+	}
+	expectedAd := &models.Ad{
+		Id:             1,
+		UserAuthorId:   ad.UserAuthorId,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    ad.DateTimeArr,
+		Item:           ad.Item,
+		MinPrice:       ad.MinPrice,
+		Comment:        ad.Comment,
+		LocDepPoint:    &models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		LocArrPoint:    &models.GeoPoint{Lat: 55.765, Lon: 37.685},
+		LocDepPlaceId:  ad.LocDepPlaceId,
+		LocArrPlaceId:  ad.LocArrPlaceId,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		Create(gomock.Eq(ad)).
+		Return(response.NewResponse(consts.Created, expectedAd))
+
+	jsonRequest, err := json.Marshal(struct {
+		DateTimeArr   *timestamps.DateTime `json:"dateTimeArr"`
+		Item          string               `json:"item"`
+		MinPrice      uint32               `json:"minPrice"`
+		Comment       string               `json:"comment"`
+		LocDepPlaceId uint32               `json:"locDepPlaceId"`
+		LocArrPlaceId uint32               `json:"locArrPlaceId"`
+	}{&ad.DateTimeArr, ad.Item, ad.MinPrice, ad.Comment, *ad.LocDepPlaceId, *ad.LocArrPlaceId})
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAd,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/api/ads", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, ad.UserAuthorId)
+
+	handler := adDelivery.HandlerAdCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdCreate_noLocDep(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/ads", strings.NewReader(
+		`{"locArr":"УЛК","dateTimeArr":"27.10.2021 19:31","item":"Зачётная книжка","minPrice":500,"comment":""}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := adDelivery.HandlerAdCreate()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
 func TestAdDelivery_HandlerAdGet(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -114,12 +224,13 @@ func TestAdDelivery_HandlerAdGet(t *testing.T) {
 		Item:           "Зачётная книжка",
 		MinPrice:       500,
 		Comment:        "Поеду на велосипеде",
+		Version:        2,
 	}
 
 	mockAdUsecase.
 		EXPECT().
 		Get(gomock.Eq(expectedAd.Id)).
-		Return(response.NewResponse(consts.OK, expectedAd))
+		Return(response.NewVersionedResponse(consts.OK, expectedAd, expectedAd.Version))
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
 		Data: expectedAd,
@@ -140,6 +251,7 @@ func TestAdDelivery_HandlerAdGet(t *testing.T) {
 	err = handler(context)
 	assert.Nil(t, err)
 	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, etag.Format(expectedAd.Version), recorder.Header().Get(etag.HeaderETag))
 
 	responseBody, err := ioutil.ReadAll(recorder.Body)
 	assert.Nil(t, err)
@@ -167,6 +279,7 @@ func TestAdDelivery_HandlerAdUpdate(t *testing.T) {
 		Item:         "Зачётная книжка",
 		MinPrice:     500,
 		Comment:      "Поеду на велосипеде",
+		Version:      2,
 	}
 	expectedAd := &models.Ad{
 		Id:             ad.Id,
@@ -199,6 +312,7 @@ func TestAdDelivery_HandlerAdUpdate(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(jsonRequest)))
 	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(ad.Version))
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
@@ -218,7 +332,7 @@ func TestAdDelivery_HandlerAdUpdate(t *testing.T) {
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
 
-func TestAdDelivery_HandlerAdDelete(t *testing.T) {
+func TestAdDelivery_HandlerAdPatch(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
@@ -228,23 +342,32 @@ func TestAdDelivery_HandlerAdDelete(t *testing.T) {
 	echo_.Validator = HandoverValidator.NewRequestValidator()
 	adDelivery.Configure(echo_, &middlewares.Manager{})
 
-	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
+	dateTimeArr, err := timestamps.NewDateTime("27.10.2021 19:50")
 	assert.Nil(t, err)
+	adPatch := &models.AdPatch{
+		Id:                1,
+		UserAuthorId:      101,
+		Version:           2,
+		LocDep:            pointy.String("Общежитие №9"),
+		DateTimeDepNull:   true,
+		MinPrice:          pointy.Uint32(600),
+		LocDepPlaceIdNull: true,
+	}
 	expectedAd := &models.Ad{
-		Id:             1,
-		UserAuthorId:   101,
+		Id:             adPatch.Id,
+		UserAuthorId:   adPatch.UserAuthorId,
 		UserAuthorVkId: 201,
-		LocDep:         "Общежитие №10",
+		LocDep:         *adPatch.LocDep,
 		LocArr:         "УЛК",
 		DateTimeArr:    *dateTimeArr,
 		Item:           "Зачётная книжка",
-		MinPrice:       500,
+		MinPrice:       *adPatch.MinPrice,
 		Comment:        "Поеду на велосипеде",
 	}
 
 	mockAdUsecase.
 		EXPECT().
-		Delete(gomock.Eq(expectedAd.UserAuthorId), gomock.Eq(expectedAd.Id)).
+		Patch(gomock.Eq(adPatch)).
 		Return(response.NewResponse(consts.OK, expectedAd))
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
@@ -253,16 +376,19 @@ func TestAdDelivery_HandlerAdDelete(t *testing.T) {
 	assert.Nil(t, err)
 	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
 
-	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+	request := httptest.NewRequest(http.MethodPatch, "/",
+		strings.NewReader(`{"locDep": "Общежитие №9", "dateTimeDep": null, "minPrice": 600}`))
+	request.Header.Set(echo.HeaderContentType, mergepatch.MIMEApplicationMergePatchJSON)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(adPatch.Version))
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
 	context.SetPath("/api/ads/:id")
 	context.SetParamNames("id")
-	context.SetParamValues(strconv.FormatUint(uint64(expectedAd.Id), 10))
-	context.Set(consts.EchoContextKeyUserId, expectedAd.UserAuthorId)
+	context.SetParamValues(strconv.FormatUint(uint64(adPatch.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, adPatch.UserAuthorId)
 
-	handler := adDelivery.HandlerAdDelete()
+	handler := adDelivery.HandlerAdPatch()
 
 	err = handler(context)
 	assert.Nil(t, err)
@@ -273,7 +399,7 @@ func TestAdDelivery_HandlerAdDelete(t *testing.T) {
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
 
-func TestAdDelivery_HandlerAdsList(t *testing.T) {
+func TestAdDelivery_HandlerAdPatch_nullRequired(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
@@ -283,73 +409,51 @@ func TestAdDelivery_HandlerAdsList(t *testing.T) {
 	echo_.Validator = HandoverValidator.NewRequestValidator()
 	adDelivery.Configure(echo_, &middlewares.Manager{})
 
-	dateTimeArr1, err := timestamps.NewDateTime("04.11.2021 19:40")
-	assert.Nil(t, err)
-	dateTimeArr2, err := timestamps.NewDateTime("04.11.2021 19:45")
-	assert.Nil(t, err)
-	const userAuthorId uint32 = 101
-	const userAuthorVkId uint32 = 201
-	expectedAds := &models.Ads{
-		&models.Ad{
-			Id:             1,
-			UserAuthorId:   userAuthorId,
-			UserAuthorVkId: userAuthorVkId,
-			LocDep:         "Общежитие №10",
-			LocArr:         "УЛК",
-			DateTimeArr:    *dateTimeArr1,
-			Item:           "Тубус",
-			MinPrice:       500,
-			Comment:        "Поеду на коньках",
-		},
-		&models.Ad{
-			Id:             2,
-			UserAuthorId:   userAuthorId,
-			UserAuthorVkId: userAuthorVkId,
-			LocDep:         "Общежитие №9",
-			LocArr:         "СК",
-			DateTimeArr:    *dateTimeArr2,
-			Item:           "Спортивная форма",
-			MinPrice:       600,
-			Comment:        "Поеду на роликах :)",
-		},
-	}
-	adsSearch := &models.AdsSearch{
-		UserAuthorId: pointy.Uint32(userAuthorId),
-	}
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"item": null}`))
+	request.Header.Set(echo.HeaderContentType, mergepatch.MIMEApplicationMergePatchJSON)
 
-	mockAdUsecase.
-		EXPECT().
-		Search(gomock.Eq(adsSearch)).
-		Return(response.NewResponse(consts.OK, expectedAds))
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
 
-	jsonRequest, err := json.Marshal(adsSearch)
-	assert.Nil(t, err)
+	handler := adDelivery.HandlerAdPatch()
 
-	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
-		Data: expectedAds,
-	})
+	err := handler(context)
 	assert.Nil(t, err)
-	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
 
-	request := httptest.NewRequest(http.MethodGet, "/api/ads/list", strings.NewReader(string(jsonRequest)))
-	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+func TestAdDelivery_HandlerAdPatch_unsupportedMediaType(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"minPrice": 600}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
-	context.Set(consts.EchoContextKeyUserId, userAuthorId)
-
-	handler := adDelivery.HandlerAdsList()
+	context.SetPath("/api/ads/:id")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
 
-	err = handler(context)
-	assert.Nil(t, err)
-	assert.Equal(t, http.StatusOK, recorder.Code)
+	handler := adDelivery.HandlerAdPatch()
 
-	responseBody, err := ioutil.ReadAll(recorder.Body)
+	err := handler(context)
 	assert.Nil(t, err)
-	assert.Equal(t, jsonExpectedResponse, responseBody)
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
 }
 
-func TestAdDelivery_HandlerAdsSearch(t *testing.T) {
+func TestAdDelivery_HandlerAdPatch_preconditionRequired(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
@@ -359,75 +463,52 @@ func TestAdDelivery_HandlerAdsSearch(t *testing.T) {
 	echo_.Validator = HandoverValidator.NewRequestValidator()
 	adDelivery.Configure(echo_, &middlewares.Manager{})
 
-	var userId uint32 = 101
-	var userVkId uint32 = 201
-	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
-	assert.Nil(t, err)
-	adsSearch := &models.AdsSearch{
-		NotUserAuthorId: &userId,
-		LocDep:          pointy.String("Общежитие"),
-		LocArr:          pointy.String("СК"),
-		MinDateTimeArr:  dateTimeArr,
-		MaxPrice:        pointy.Uint32(1000),
-	}
-	expectedAds := &models.Ads{
-		&models.Ad{
-			Id:             1,
-			UserAuthorId:   userId,
-			UserAuthorVkId: userVkId,
-			LocDep:         "Общежитие №10",
-			LocArr:         "УЛК",
-			DateTimeArr:    *dateTimeArr,
-			Item:           "Тубус",
-			MinPrice:       500,
-			Comment:        "Поеду на коньках",
-		},
-		&models.Ad{
-			Id:             2,
-			UserAuthorId:   userId,
-			UserAuthorVkId: userVkId,
-			LocDep:         "Общежитие №9",
-			LocArr:         "СК",
-			DateTimeArr:    *dateTimeArr,
-			Item:           "Спортивная форма",
-			MinPrice:       600,
-			Comment:        "Поеду на роликах :)",
-		},
-	}
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"minPrice": 600}`))
+	request.Header.Set(echo.HeaderContentType, mergepatch.MIMEApplicationMergePatchJSON)
 
-	mockAdUsecase.
-		EXPECT().
-		Search(gomock.Eq(adsSearch)).
-		Return(response.NewResponse(consts.OK, expectedAds))
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
 
-	jsonRequest, err := json.Marshal(adsSearch)
-	assert.Nil(t, err)
+	handler := adDelivery.HandlerAdPatch()
 
-	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
-		Data: expectedAds,
-	})
+	err := handler(context)
 	assert.Nil(t, err)
-	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+	assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+}
 
-	request := httptest.NewRequest(http.MethodGet, "/api/ads/search", strings.NewReader(string(jsonRequest)))
-	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+func TestAdDelivery_HandlerAdPatch_badIfMatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"minPrice": 600}`))
+	request.Header.Set(echo.HeaderContentType, mergepatch.MIMEApplicationMergePatchJSON)
+	request.Header.Set(etag.HeaderIfMatch, "W/\"2\"")
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
-	context.Set(consts.EchoContextKeyUserId, userId)
-
-	handler := adDelivery.HandlerAdsSearch()
+	context.SetPath("/api/ads/:id")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
 
-	err = handler(context)
-	assert.Nil(t, err)
-	assert.Equal(t, http.StatusOK, recorder.Code)
+	handler := adDelivery.HandlerAdPatch()
 
-	responseBody, err := ioutil.ReadAll(recorder.Body)
+	err := handler(context)
 	assert.Nil(t, err)
-	assert.Equal(t, jsonExpectedResponse, responseBody)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
 }
 
-func TestAdDelivery_HandlerAdExecutionCreate(t *testing.T) {
+func TestAdDelivery_HandlerAdDelete(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
@@ -437,8 +518,7 @@ func TestAdDelivery_HandlerAdExecutionCreate(t *testing.T) {
 	echo_.Validator = HandoverValidator.NewRequestValidator()
 	adDelivery.Configure(echo_, &middlewares.Manager{})
 
-	const userId uint32 = 102
-	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 19:50")
+	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
 	assert.Nil(t, err)
 	expectedAd := &models.Ad{
 		Id:             1,
@@ -450,12 +530,13 @@ func TestAdDelivery_HandlerAdExecutionCreate(t *testing.T) {
 		Item:           "Зачётная книжка",
 		MinPrice:       500,
 		Comment:        "Поеду на велосипеде",
+		Version:        2,
 	}
 
 	mockAdUsecase.
 		EXPECT().
-		SetAdUserExecutor(gomock.Eq(userId), gomock.Eq(expectedAd.Id)).
-		Return(response.NewResponse(consts.Created, expectedAd))
+		Delete(gomock.Eq(expectedAd.UserAuthorId), gomock.Eq(expectedAd.Id), gomock.Eq(expectedAd.Version)).
+		Return(response.NewResponse(consts.OK, expectedAd))
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
 		Data: expectedAd,
@@ -463,27 +544,28 @@ func TestAdDelivery_HandlerAdExecutionCreate(t *testing.T) {
 	assert.Nil(t, err)
 	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
 
-	request := httptest.NewRequest(http.MethodPost, "/", nil)
+	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(expectedAd.Version))
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
-	context.SetPath("/api/ads/:id/execution")
+	context.SetPath("/api/ads/:id")
 	context.SetParamNames("id")
 	context.SetParamValues(strconv.FormatUint(uint64(expectedAd.Id), 10))
-	context.Set(consts.EchoContextKeyUserId, userId)
+	context.Set(consts.EchoContextKeyUserId, expectedAd.UserAuthorId)
 
-	handler := adDelivery.HandlerAdExecutionCreate()
+	handler := adDelivery.HandlerAdDelete()
 
 	err = handler(context)
 	assert.Nil(t, err)
-	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, http.StatusOK, recorder.Code)
 
 	responseBody, err := ioutil.ReadAll(recorder.Body)
 	assert.Nil(t, err)
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
 
-func TestAdDelivery_HandlerAdExecutionDelete(t *testing.T) {
+func TestAdDelivery_HandlerAdDelete_anyVersion(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
@@ -493,17 +575,365 @@ func TestAdDelivery_HandlerAdExecutionDelete(t *testing.T) {
 	echo_.Validator = HandoverValidator.NewRequestValidator()
 	adDelivery.Configure(echo_, &middlewares.Manager{})
 
-	const userId uint32 = 102
-	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:35")
-	assert.Nil(t, err)
-	expectedAd := &models.Ad{
-		Id:               1,
-		UserAuthorId:     101,
-		UserAuthorVkId:   201,
-		UserExecutorVkId: pointy.Uint32(userId),
-		LocDep:           "Общежитие №10",
-		LocArr:           "УЛК",
-		DateTimeArr:      *dateTimeArr,
+	const userId uint32 = 101
+	const id uint32 = 1
+
+	mockAdUsecase.
+		EXPECT().
+		Delete(gomock.Eq(userId), gomock.Eq(id), gomock.Eq(etag.AnyVersion)).
+		Return(response.NewEmptyResponse(consts.NotFound))
+
+	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+	request.Header.Set(etag.HeaderIfMatch, "*")
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(id), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdDelete()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestAdDelivery_HandlerAdDelete_weakAndStrongIfMatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	const id uint32 = 1
+
+	//the weak entity tag never matches, so only the strong one is compared
+	mockAdUsecase.
+		EXPECT().
+		Delete(gomock.Eq(userId), gomock.Eq(id), gomock.Eq(uint32(3))).
+		Return(response.NewEmptyResponse(consts.PreconditionFailed))
+
+	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+	request.Header.Set(etag.HeaderIfMatch, `W/"2", "3"`)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(id), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdDelete()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+}
+
+func TestAdDelivery_HandlerAdsList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	dateTimeArr1, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	dateTimeArr2, err := timestamps.NewDateTime("04.11.2021 19:45")
+	assert.Nil(t, err)
+	const userAuthorId uint32 = 101
+	const userAuthorVkId uint32 = 201
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:             1,
+			UserAuthorId:   userAuthorId,
+			UserAuthorVkId: userAuthorVkId,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr1,
+			Item:           "Тубус",
+			MinPrice:       500,
+			Comment:        "Поеду на коньках",
+		},
+		&models.Ad{
+			Id:             2,
+			UserAuthorId:   userAuthorId,
+			UserAuthorVkId: userAuthorVkId,
+			LocDep:         "Общежитие №9",
+			LocArr:         "СК",
+			DateTimeArr:    *dateTimeArr2,
+			Item:           "Спортивная форма",
+			MinPrice:       600,
+			Comment:        "Поеду на роликах :)",
+		},
+	}
+	adsSearch := &models.AdsSearch{
+		UserAuthorId: pointy.Uint32(userAuthorId),
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		Search(gomock.Eq(adsSearch)).
+		Return(response.NewResponse(consts.OK, expectedAds))
+
+	jsonRequest, err := json.Marshal(adsSearch)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAds,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/api/ads/list", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, userAuthorId)
+
+	handler := adDelivery.HandlerAdsList()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdsExecuting(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	const userExecutorId uint32 = 102
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     101,
+			UserAuthorVkId:   201,
+			UserExecutorVkId: pointy.Uint32(202),
+			LocDep:           "Общежитие №10",
+			LocArr:           "УЛК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Тубус",
+			MinPrice:         500,
+			Comment:          "Поеду на коньках",
+			Status:           models.AdStatusAssigned,
+		},
+	}
+	order := models.AdsSearchOrderDateTimeArrAsc
+	adsSearch := &models.AdsSearch{
+		UserExecutorId: pointy.Uint32(userExecutorId),
+		Active:         pointy.Bool(true),
+		Order:          &order,
+		Limit:          pointy.Uint32(10),
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		Search(gomock.Eq(adsSearch)).
+		Return(response.NewPageResponse(consts.OK, expectedAds, ""))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAds,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/api/ads/executing?active=true&order="+strconv.Itoa(int(order))+"&limit=10", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, userExecutorId)
+
+	handler := adDelivery.HandlerAdsExecuting()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdsSearch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	var userId uint32 = 101
+	var userVkId uint32 = 201
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId:  &userId,
+		NotBlockedUserId: &userId,
+		LocDep:           pointy.String("Общежитие"),
+		LocArr:           pointy.String("СК"),
+		MinDateTimeArr:   dateTimeArr,
+		MaxPrice:         pointy.Uint32(1000),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:             1,
+			UserAuthorId:   userId,
+			UserAuthorVkId: userVkId,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Тубус",
+			MinPrice:       500,
+			Comment:        "Поеду на коньках",
+		},
+		&models.Ad{
+			Id:             2,
+			UserAuthorId:   userId,
+			UserAuthorVkId: userVkId,
+			LocDep:         "Общежитие №9",
+			LocArr:         "СК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Спортивная форма",
+			MinPrice:       600,
+			Comment:        "Поеду на роликах :)",
+		},
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		Search(gomock.Eq(adsSearch)).
+		Return(response.NewResponse(consts.OK, expectedAds))
+
+	jsonRequest, err := json.Marshal(adsSearch)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAds,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/api/ads/search", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdsSearch()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdExecutionCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 102
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	expectedAd := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusAssigned,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		SetAdUserExecutor(gomock.Eq(userId), gomock.Eq(expectedAd.Id)).
+		Return(response.NewResponse(consts.OK, expectedAd))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAd,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/execution")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedAd.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdExecutionCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdExecutionDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 102
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:35")
+	assert.Nil(t, err)
+	expectedAd := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(userId),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
 		Item:             "Зачётная книжка",
 		MinPrice:         500,
 		Comment:          "Поеду на велосипеде",
@@ -511,25 +941,859 @@ func TestAdDelivery_HandlerAdExecutionDelete(t *testing.T) {
 
 	mockAdUsecase.
 		EXPECT().
-		UnsetAdUserExecutor(gomock.Eq(userId), gomock.Eq(expectedAd.Id)).
-		Return(response.NewResponse(consts.OK, expectedAd))
+		UnsetAdUserExecutor(gomock.Eq(userId), gomock.Eq(expectedAd.Id)).
+		Return(response.NewResponse(consts.OK, expectedAd))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAd,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/execution")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedAd.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdExecutionDelete()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdPickUp(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 102
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:35")
+	assert.Nil(t, err)
+	expectedAd := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusPickedUp,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		PickUp(gomock.Eq(userId), gomock.Eq(expectedAd.Id)).
+		Return(response.NewResponse(consts.OK, expectedAd))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAd,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/pickup")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedAd.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdPickUp()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdHandoverGet_qr(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	const adId uint32 = 1
+	expectedAdHandoverQr := &models.AdHandoverQr{
+		Payload: "handover:1:042519",
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		GetAdHandover(gomock.Eq(userId), gomock.Eq(adId), gomock.Eq(true)).
+		Return(response.NewResponse(consts.OK, expectedAdHandoverQr))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdHandoverQr,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/handover/qr")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdHandoverGet(true)
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdHandoverConfirm(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 102
+	const code = "042519"
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:35")
+	assert.Nil(t, err)
+	expectedAd := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusConfirmed,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		ConfirmAdHandover(gomock.Eq(userId), gomock.Eq(expectedAd.Id), gomock.Eq(code)).
+		Return(response.NewResponse(consts.OK, expectedAd))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAd,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"`+code+`"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/confirm")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedAd.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdHandoverConfirm()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdHandoverConfirm_badCode(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"12ab"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/confirm")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(102))
+
+	handler := adDelivery.HandlerAdHandoverConfirm()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestAdDelivery_HandlerAdCancel_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	const adId uint32 = 1
+
+	mockAdUsecase.
+		EXPECT().
+		Cancel(gomock.Eq(userId), gomock.Eq(adId)).
+		Return(response.NewEmptyResponse(consts.Conflict))
+
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/cancellation")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdCancel()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestAdDelivery_HandlerAdOfferCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	adOffer := &models.AdOffer{
+		AdId:           1,
+		UserExecutorId: 102,
+		Price:          450,
+		Comment:        "Могу через час",
+	}
+	expectedAdOffer := &models.AdOffer{
+		Id:               1,
+		AdId:             adOffer.AdId,
+		UserExecutorId:   adOffer.UserExecutorId,
+		UserExecutorVkId: 202,
+		Price:            adOffer.Price,
+		Comment:          adOffer.Comment,
+		Status:           models.AdOfferStatusPending,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		CreateAdOffer(gomock.Eq(adOffer)).
+		Return(response.NewResponse(consts.Created, expectedAdOffer))
+
+	jsonRequest, err := json.Marshal(adOffer)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdOffer,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/offers")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adOffer.AdId), 10))
+	context.Set(consts.EchoContextKeyUserId, adOffer.UserExecutorId)
+
+	handler := adDelivery.HandlerAdOfferCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdOffersList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	order := models.AdOffersSearchOrderRatingDesc
+	adOffersSearch := &models.AdOffersSearch{
+		AdId:                  1,
+		MinUserExecutorRating: pointy.Float32(4.5),
+		Order:                 &order,
+	}
+	expectedAdOffers := &models.AdOffers{
+		&models.AdOffer{
+			Id:                 1,
+			AdId:               adOffersSearch.AdId,
+			UserExecutorId:     102,
+			UserExecutorVkId:   202,
+			UserExecutorRating: 4.8,
+			Price:              450,
+			Status:             models.AdOfferStatusPending,
+		},
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		ListAdOffers(gomock.Eq(userId), gomock.Eq(adOffersSearch)).
+		Return(response.NewResponse(consts.OK, expectedAdOffers))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdOffers,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/?min_rating=4.5&order=1", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/offers")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adOffersSearch.AdId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdOffersList()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdReviewCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	adReview := &models.AdReview{
+		AdId:         1,
+		UserAuthorId: 101,
+		Rating:       5,
+		Comment:      "Доставил вовремя",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedAdReview := &models.AdReview{
+		Id:           1,
+		AdId:         adReview.AdId,
+		UserAuthorId: adReview.UserAuthorId,
+		UserTargetId: 102,
+		Rating:       adReview.Rating,
+		Comment:      adReview.Comment,
+		DateTime:     *dateTime,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		CreateAdReview(gomock.Eq(adReview)).
+		Return(response.NewResponse(consts.Created, expectedAdReview))
+
+	jsonRequest, err := json.Marshal(adReview)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdReview,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/reviews")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adReview.AdId), 10))
+	context.Set(consts.EchoContextKeyUserId, adReview.UserAuthorId)
+
+	handler := adDelivery.HandlerAdReviewCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdReviewCreate_badRating(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"rating":6}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/reviews")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := adDelivery.HandlerAdReviewCreate()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestAdDelivery_HandlerAdOfferAccept(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	expectedAdOffer := &models.AdOffer{
+		Id:               2,
+		AdId:             1,
+		UserExecutorId:   102,
+		UserExecutorVkId: 202,
+		Price:            450,
+		Status:           models.AdOfferStatusAccepted,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		AcceptAdOffer(gomock.Eq(userId), gomock.Eq(expectedAdOffer.AdId), gomock.Eq(expectedAdOffer.Id)).
+		Return(response.NewResponse(consts.OK, expectedAdOffer))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdOffer,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/offers/:offerId/acceptance")
+	context.SetParamNames("id", "offerId")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedAdOffer.AdId), 10),
+		strconv.FormatUint(uint64(expectedAdOffer.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdOfferAccept()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdPhotosCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	const adId uint32 = 1
+	photos := [][]byte{[]byte("first photo"), []byte("second photo")}
+	expectedAdPhotos := &models.AdPhotos{
+		&models.AdPhoto{
+			Id:          1,
+			AdId:        adId,
+			ContentType: "image/png",
+			Size:        uint32(len(photos[0])),
+		},
+		&models.AdPhoto{
+			Id:          2,
+			AdId:        adId,
+			ContentType: "image/jpeg",
+			Size:        uint32(len(photos[1])),
+		},
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		CreateAdPhotos(gomock.Eq(userId), gomock.Eq(adId), gomock.Eq(photos)).
+		Return(response.NewResponse(consts.Created, expectedAdPhotos))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdPhotos,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for i, photo := range photos {
+		part, err := writer.CreateFormFile("photos", "photo"+strconv.Itoa(i))
+		assert.Nil(t, err)
+		_, err = part.Write(photo)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+
+	request := httptest.NewRequest(http.MethodPost, "/", body)
+	request.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/photos")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdPhotosCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+	assert.Contains(t, string(responseBody), `"thumbnailUrl":"/api/ads/1/photos/2/thumbnail"`)
+}
+
+func TestAdDelivery_HandlerAdPhotosCreate_tooLarge(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	const adId uint32 = 1
+
+	//streamed without a content length, so only reading the body trips the limit
+	pipeReader, pipeWriter := io.Pipe()
+	defer func() {
+		_ = pipeReader.Close()
+	}()
+	writer := multipart.NewWriter(pipeWriter)
+	go func() {
+		photo := make([]byte, models.AdPhotoMaxSize)
+		for i := 0; i <= models.AdPhotosMaxCount; i++ {
+			part, err := writer.CreateFormFile("photos", "photo"+strconv.Itoa(i))
+			if err != nil {
+				return
+			}
+			if _, err := part.Write(photo); err != nil {
+				return
+			}
+		}
+		_ = writer.Close()
+		_ = pipeWriter.Close()
+	}()
+
+	request := httptest.NewRequest(http.MethodPost, "/", pipeReader)
+	request.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/photos")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdPhotosCreate()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+}
+
+func TestAdDelivery_HandlerAdPhotoGet(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const adId uint32 = 1
+	const adPhotoId uint32 = 2
+	adPhotoContent := &models.AdPhotoContent{
+		ContentType: "image/png",
+		Data:        []byte{0x89, 0x50, 0x4e, 0x47},
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		GetAdPhoto(gomock.Eq(adId), gomock.Eq(adPhotoId), gomock.Eq(false)).
+		Return(response.NewResponse(consts.OK, adPhotoContent))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/photos/:photoId")
+	context.SetParamNames("id", "photoId")
+	context.SetParamValues(strconv.FormatUint(uint64(adId), 10), strconv.FormatUint(uint64(adPhotoId), 10))
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := adDelivery.HandlerAdPhotoGet(false)
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, adPhotoContent.ContentType, recorder.Header().Get(echo.HeaderContentType))
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, adPhotoContent.Data, responseBody)
+}
+
+func TestAdDelivery_HandlerAdHistory(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	const adId uint32 = 1
+	dateTime, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	expectedAdRevisions := &models.AdRevisions{
+		&models.AdRevision{
+			Id:       2,
+			AdId:     adId,
+			UserId:   pointy.Uint32(userId),
+			UserVkId: pointy.Uint32(201),
+			Action:   models.AdRevisionActionUpdate,
+			DateTime: *dateTime,
+			Diff: models.AdRevisionDiff{
+				"locArr": &models.AdRevisionChange{
+					Old: json.RawMessage(`"УЛК"`),
+					New: json.RawMessage(`"СК"`),
+				},
+			},
+		},
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		ListAdRevisions(gomock.Eq(userId), gomock.Eq(adId)).
+		Return(response.NewResponse(consts.OK, expectedAdRevisions))
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
-		Data: expectedAd,
+		Data: expectedAdRevisions,
 	})
 	assert.Nil(t, err)
 	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
 
-	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
-	context.SetPath("/api/ads/:id/execution")
+	context.SetPath("/api/ads/:id/history")
 	context.SetParamNames("id")
-	context.SetParamValues(strconv.FormatUint(uint64(expectedAd.Id), 10))
+	context.SetParamValues(strconv.FormatUint(uint64(adId), 10))
 	context.Set(consts.EchoContextKeyUserId, userId)
 
-	handler := adDelivery.HandlerAdExecutionDelete()
+	handler := adDelivery.HandlerAdHistory()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdsSearch_cursor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	var userId uint32 = 101
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	cursor := &models.AdsSearchCursor{
+		Order: models.AdsSearchOrderMinPriceAsc,
+		Id:    2,
+	}
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId:  &userId,
+		NotBlockedUserId: &userId,
+		Order:            &cursor.Order,
+		Cursor:           cursor,
+		Limit:            pointy.Uint32(1),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:             3,
+			UserAuthorId:   102,
+			UserAuthorVkId: 202,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Тубус",
+			MinPrice:       500,
+			Comment:        "Поеду на коньках",
+			Status:         models.AdStatusOpen,
+		},
+	}
+	expectedNextCursor := models.NewAdsSearchCursor(cursor.Order, (*expectedAds)[0]).String()
+
+	mockAdUsecase.
+		EXPECT().
+		Search(gomock.Eq(adsSearch)).
+		Return(response.NewPageResponse(consts.OK, expectedAds, expectedNextCursor))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data:       expectedAds,
+		NextCursor: expectedNextCursor,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/api/ads/search?order=3&limit=1&cursor="+cursor.String(), nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdsSearch()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdsSearch_near(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	var userId uint32 = 101
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId:  &userId,
+		NotBlockedUserId: &userId,
+		NearDep:          &models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		NearArr:          &models.GeoPoint{Lat: 55.765, Lon: 37.685},
+		RadiusM:          pointy.Uint32(700),
+	}
+	expectedAds := &models.Ads{}
+
+	mockAdUsecase.
+		EXPECT().
+		Search(gomock.Eq(adsSearch)).
+		Return(response.NewPageResponse(consts.OK, expectedAds, ""))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAds,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet,
+		"/api/ads/search?near_dep=55.752,37.681&near_arr=55.765,37.685&radius_m=700", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdsSearch()
 
 	err = handler(context)
 	assert.Nil(t, err)
@@ -539,3 +1803,145 @@ func TestAdDelivery_HandlerAdExecutionDelete(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
+
+func TestAdDelivery_HandlerAdsSearch_nearInvalid(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/ads/search?near_dep=95.752,37.681", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := adDelivery.HandlerAdsSearch()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestAdDelivery_HandlerAdTemplateCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	timeArr, err := timestamps.NewTime("09:30")
+	assert.Nil(t, err)
+	adTemplate := &models.AdTemplate{
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		Item:         "Набор для лабораторной",
+		MinPrice:     300,
+		Comment:      "Каждый вторник",
+		EvenWeek:     true,
+		OddWeek:      false,
+		DaysOfWeek:   []uint32{2, 4},
+		TimeArr:      *timeArr,
+	}
+	expectedAdTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: adTemplate.UserAuthorId,
+		LocDep:       adTemplate.LocDep,
+		LocArr:       adTemplate.LocArr,
+		Item:         adTemplate.Item,
+		MinPrice:     adTemplate.MinPrice,
+		Comment:      adTemplate.Comment,
+		EvenWeek:     adTemplate.EvenWeek,
+		OddWeek:      adTemplate.OddWeek,
+		DaysOfWeek:   adTemplate.DaysOfWeek,
+		TimeArr:      adTemplate.TimeArr,
+	}
+
+	mockAdUsecase.
+		EXPECT().
+		CreateAdTemplate(gomock.Eq(adTemplate)).
+		Return(response.NewResponse(consts.Created, expectedAdTemplate))
+
+	jsonRequest, err := json.Marshal(struct {
+		LocDep     string                 `json:"locDep"`
+		LocArr     string                 `json:"locArr"`
+		Item       string                 `json:"item"`
+		MinPrice   uint32                 `json:"minPrice"`
+		Comment    string                 `json:"comment"`
+		EvenWeek   bool                   `json:"evenWeek"`
+		OddWeek    bool                   `json:"oddWeek"`
+		DaysOfWeek []timestamps.DayOfWeek `json:"daysOfWeek"`
+		TimeArr    *timestamps.Time       `json:"timeArr"`
+	}{adTemplate.LocDep, adTemplate.LocArr, adTemplate.Item, adTemplate.MinPrice, adTemplate.Comment,
+		adTemplate.EvenWeek, adTemplate.OddWeek,
+		[]timestamps.DayOfWeek{timestamps.DayOfWeekTuesday, timestamps.DayOfWeekThursday}, &adTemplate.TimeArr})
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdTemplate,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/api/ads/templates", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, adTemplate.UserAuthorId)
+
+	handler := adDelivery.HandlerAdTemplateCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestAdDelivery_HandlerAdTemplateSkipCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	adDelivery := delivery.NewAdDelivery(mockAdUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	adDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	const adTemplateId uint32 = 1
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+
+	mockAdUsecase.
+		EXPECT().
+		SkipAdTemplateOccurrence(gomock.Eq(userId), gomock.Eq(adTemplateId), gomock.Eq(*date)).
+		Return(response.NewEmptyResponse(consts.Created))
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"date":"23.11.2021"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/templates/:id/skips")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adTemplateId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := adDelivery.HandlerAdTemplateSkipCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
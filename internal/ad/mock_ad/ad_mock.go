@@ -6,8 +6,10 @@ package mock_ad
 
 import (
 	reflect "reflect"
+	time "time"
 
 	models "github.com/TechnoHandOver/backend/internal/models"
+	timestamps "github.com/TechnoHandOver/backend/internal/models/timestamps"
 	response "github.com/TechnoHandOver/backend/internal/tools/response"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,62 @@ func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
 	return m.recorder
 }
 
+// AcceptAdOffer mocks base method.
+func (m *MockUsecase) AcceptAdOffer(arg0, arg1, arg2 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptAdOffer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// AcceptAdOffer indicates an expected call of AcceptAdOffer.
+func (mr *MockUsecaseMockRecorder) AcceptAdOffer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptAdOffer", reflect.TypeOf((*MockUsecase)(nil).AcceptAdOffer), arg0, arg1, arg2)
+}
+
+// Cancel mocks base method.
+func (m *MockUsecase) Cancel(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockUsecaseMockRecorder) Cancel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockUsecase)(nil).Cancel), arg0, arg1)
+}
+
+// Confirm mocks base method.
+func (m *MockUsecase) Confirm(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockUsecaseMockRecorder) Confirm(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockUsecase)(nil).Confirm), arg0, arg1)
+}
+
+// ConfirmAdHandover mocks base method.
+func (m *MockUsecase) ConfirmAdHandover(arg0, arg1 uint32, arg2 string) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmAdHandover", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ConfirmAdHandover indicates an expected call of ConfirmAdHandover.
+func (mr *MockUsecaseMockRecorder) ConfirmAdHandover(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmAdHandover", reflect.TypeOf((*MockUsecase)(nil).ConfirmAdHandover), arg0, arg1, arg2)
+}
+
 // Create mocks base method.
 func (m *MockUsecase) Create(arg0 *models.Ad) *response.Response {
 	m.ctrl.T.Helper()
@@ -49,18 +107,116 @@ func (mr *MockUsecaseMockRecorder) Create(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUsecase)(nil).Create), arg0)
 }
 
+// CreateAdOffer mocks base method.
+func (m *MockUsecase) CreateAdOffer(arg0 *models.AdOffer) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdOffer", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// CreateAdOffer indicates an expected call of CreateAdOffer.
+func (mr *MockUsecaseMockRecorder) CreateAdOffer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdOffer", reflect.TypeOf((*MockUsecase)(nil).CreateAdOffer), arg0)
+}
+
+// CreateAdPhotos mocks base method.
+func (m *MockUsecase) CreateAdPhotos(arg0, arg1 uint32, arg2 [][]byte) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdPhotos", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// CreateAdPhotos indicates an expected call of CreateAdPhotos.
+func (mr *MockUsecaseMockRecorder) CreateAdPhotos(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdPhotos", reflect.TypeOf((*MockUsecase)(nil).CreateAdPhotos), arg0, arg1, arg2)
+}
+
+// CreateAdReview mocks base method.
+func (m *MockUsecase) CreateAdReview(arg0 *models.AdReview) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdReview", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// CreateAdReview indicates an expected call of CreateAdReview.
+func (mr *MockUsecaseMockRecorder) CreateAdReview(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdReview", reflect.TypeOf((*MockUsecase)(nil).CreateAdReview), arg0)
+}
+
+// CreateAdTemplate mocks base method.
+func (m *MockUsecase) CreateAdTemplate(arg0 *models.AdTemplate) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdTemplate", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// CreateAdTemplate indicates an expected call of CreateAdTemplate.
+func (mr *MockUsecaseMockRecorder) CreateAdTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdTemplate", reflect.TypeOf((*MockUsecase)(nil).CreateAdTemplate), arg0)
+}
+
 // Delete mocks base method.
-func (m *MockUsecase) Delete(arg0, arg1 uint32) *response.Response {
+func (m *MockUsecase) Delete(arg0, arg1, arg2 uint32) *response.Response {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*response.Response)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUsecaseMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockUsecaseMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUsecase)(nil).Delete), arg0, arg1, arg2)
+}
+
+// DeleteAdTemplate mocks base method.
+func (m *MockUsecase) DeleteAdTemplate(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAdTemplate", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// DeleteAdTemplate indicates an expected call of DeleteAdTemplate.
+func (mr *MockUsecaseMockRecorder) DeleteAdTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdTemplate", reflect.TypeOf((*MockUsecase)(nil).DeleteAdTemplate), arg0, arg1)
+}
+
+// Deliver mocks base method.
+func (m *MockUsecase) Deliver(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliver", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// Deliver indicates an expected call of Deliver.
+func (mr *MockUsecaseMockRecorder) Deliver(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliver", reflect.TypeOf((*MockUsecase)(nil).Deliver), arg0, arg1)
+}
+
+// Expire mocks base method.
+func (m *MockUsecase) Expire(arg0 time.Duration) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockUsecaseMockRecorder) Expire(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUsecase)(nil).Delete), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockUsecase)(nil).Expire), arg0)
 }
 
 // Get mocks base method.
@@ -77,6 +233,146 @@ func (mr *MockUsecaseMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUsecase)(nil).Get), arg0)
 }
 
+// GetAdHandover mocks base method.
+func (m *MockUsecase) GetAdHandover(arg0, arg1 uint32, arg2 bool) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdHandover", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// GetAdHandover indicates an expected call of GetAdHandover.
+func (mr *MockUsecaseMockRecorder) GetAdHandover(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdHandover", reflect.TypeOf((*MockUsecase)(nil).GetAdHandover), arg0, arg1, arg2)
+}
+
+// GetAdPhoto mocks base method.
+func (m *MockUsecase) GetAdPhoto(arg0, arg1 uint32, arg2 bool) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdPhoto", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// GetAdPhoto indicates an expected call of GetAdPhoto.
+func (mr *MockUsecaseMockRecorder) GetAdPhoto(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdPhoto", reflect.TypeOf((*MockUsecase)(nil).GetAdPhoto), arg0, arg1, arg2)
+}
+
+// ListAdOffers mocks base method.
+func (m *MockUsecase) ListAdOffers(arg0 uint32, arg1 *models.AdOffersSearch) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdOffers", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListAdOffers indicates an expected call of ListAdOffers.
+func (mr *MockUsecaseMockRecorder) ListAdOffers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdOffers", reflect.TypeOf((*MockUsecase)(nil).ListAdOffers), arg0, arg1)
+}
+
+// ListAdRevisions mocks base method.
+func (m *MockUsecase) ListAdRevisions(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdRevisions", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListAdRevisions indicates an expected call of ListAdRevisions.
+func (mr *MockUsecaseMockRecorder) ListAdRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdRevisions", reflect.TypeOf((*MockUsecase)(nil).ListAdRevisions), arg0, arg1)
+}
+
+// ListAdTemplates mocks base method.
+func (m *MockUsecase) ListAdTemplates(arg0 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdTemplates", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListAdTemplates indicates an expected call of ListAdTemplates.
+func (mr *MockUsecaseMockRecorder) ListAdTemplates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdTemplates", reflect.TypeOf((*MockUsecase)(nil).ListAdTemplates), arg0)
+}
+
+// MaterializeAdTemplates mocks base method.
+func (m *MockUsecase) MaterializeAdTemplates(arg0 time.Duration) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaterializeAdTemplates", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// MaterializeAdTemplates indicates an expected call of MaterializeAdTemplates.
+func (mr *MockUsecaseMockRecorder) MaterializeAdTemplates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaterializeAdTemplates", reflect.TypeOf((*MockUsecase)(nil).MaterializeAdTemplates), arg0)
+}
+
+// NotifyPending mocks base method.
+func (m *MockUsecase) NotifyPending(arg0 time.Duration) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyPending", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// NotifyPending indicates an expected call of NotifyPending.
+func (mr *MockUsecaseMockRecorder) NotifyPending(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyPending", reflect.TypeOf((*MockUsecase)(nil).NotifyPending), arg0)
+}
+
+// Patch mocks base method.
+func (m *MockUsecase) Patch(arg0 *models.AdPatch) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockUsecaseMockRecorder) Patch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockUsecase)(nil).Patch), arg0)
+}
+
+// PickUp mocks base method.
+func (m *MockUsecase) PickUp(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickUp", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// PickUp indicates an expected call of PickUp.
+func (mr *MockUsecaseMockRecorder) PickUp(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickUp", reflect.TypeOf((*MockUsecase)(nil).PickUp), arg0, arg1)
+}
+
+// RejectAdOffer mocks base method.
+func (m *MockUsecase) RejectAdOffer(arg0, arg1, arg2 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectAdOffer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// RejectAdOffer indicates an expected call of RejectAdOffer.
+func (mr *MockUsecaseMockRecorder) RejectAdOffer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectAdOffer", reflect.TypeOf((*MockUsecase)(nil).RejectAdOffer), arg0, arg1, arg2)
+}
+
 // Search mocks base method.
 func (m *MockUsecase) Search(arg0 *models.AdsSearch) *response.Response {
 	m.ctrl.T.Helper()
@@ -91,6 +387,20 @@ func (mr *MockUsecaseMockRecorder) Search(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUsecase)(nil).Search), arg0)
 }
 
+// SetAdTemplatePaused mocks base method.
+func (m *MockUsecase) SetAdTemplatePaused(arg0, arg1 uint32, arg2 bool) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdTemplatePaused", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// SetAdTemplatePaused indicates an expected call of SetAdTemplatePaused.
+func (mr *MockUsecaseMockRecorder) SetAdTemplatePaused(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdTemplatePaused", reflect.TypeOf((*MockUsecase)(nil).SetAdTemplatePaused), arg0, arg1, arg2)
+}
+
 // SetAdUserExecutor mocks base method.
 func (m *MockUsecase) SetAdUserExecutor(arg0, arg1 uint32) *response.Response {
 	m.ctrl.T.Helper()
@@ -105,6 +415,20 @@ func (mr *MockUsecaseMockRecorder) SetAdUserExecutor(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdUserExecutor", reflect.TypeOf((*MockUsecase)(nil).SetAdUserExecutor), arg0, arg1)
 }
 
+// SkipAdTemplateOccurrence mocks base method.
+func (m *MockUsecase) SkipAdTemplateOccurrence(arg0, arg1 uint32, arg2 timestamps.Date) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SkipAdTemplateOccurrence", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// SkipAdTemplateOccurrence indicates an expected call of SkipAdTemplateOccurrence.
+func (mr *MockUsecaseMockRecorder) SkipAdTemplateOccurrence(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SkipAdTemplateOccurrence", reflect.TypeOf((*MockUsecase)(nil).SkipAdTemplateOccurrence), arg0, arg1, arg2)
+}
+
 // UnsetAdUserExecutor mocks base method.
 func (m *MockUsecase) UnsetAdUserExecutor(arg0, arg1 uint32) *response.Response {
 	m.ctrl.T.Helper()
@@ -157,18 +481,47 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(arg0 uint32) (*models.Ad, error) {
+func (m *MockRepository) Delete(arg0, arg1 uint32) (*models.Ad, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(*models.Ad)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1)
+}
+
+// DeleteAdNotificationPending mocks base method.
+func (m *MockRepository) DeleteAdNotificationPending(arg0 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAdNotificationPending", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAdNotificationPending indicates an expected call of DeleteAdNotificationPending.
+func (mr *MockRepositoryMockRecorder) DeleteAdNotificationPending(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdNotificationPending", reflect.TypeOf((*MockRepository)(nil).DeleteAdNotificationPending), arg0)
+}
+
+// DeleteAdTemplate mocks base method.
+func (m *MockRepository) DeleteAdTemplate(arg0 uint32) (*models.AdTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAdTemplate", arg0)
+	ret0, _ := ret[0].(*models.AdTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAdTemplate indicates an expected call of DeleteAdTemplate.
+func (mr *MockRepositoryMockRecorder) DeleteAdTemplate(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdTemplate", reflect.TypeOf((*MockRepository)(nil).DeleteAdTemplate), arg0)
 }
 
 // DeleteAdUserExecution mocks base method.
@@ -201,19 +554,123 @@ func (mr *MockRepositoryMockRecorder) Insert(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockRepository)(nil).Insert), arg0)
 }
 
-// InsertAdUserExecution mocks base method.
-func (m *MockRepository) InsertAdUserExecution(arg0 *models.AdUserExecution) (*models.AdUserExecution, error) {
+// InsertAdOffer mocks base method.
+func (m *MockRepository) InsertAdOffer(arg0 *models.AdOffer) (*models.AdOffer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertAdUserExecution", arg0)
-	ret0, _ := ret[0].(*models.AdUserExecution)
+	ret := m.ctrl.Call(m, "InsertAdOffer", arg0)
+	ret0, _ := ret[0].(*models.AdOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAdOffer indicates an expected call of InsertAdOffer.
+func (mr *MockRepositoryMockRecorder) InsertAdOffer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAdOffer", reflect.TypeOf((*MockRepository)(nil).InsertAdOffer), arg0)
+}
+
+// InsertAdOfferAccepted mocks base method.
+func (m *MockRepository) InsertAdOfferAccepted(arg0 *models.AdOffer) (*models.AdOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAdOfferAccepted", arg0)
+	ret0, _ := ret[0].(*models.AdOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAdOfferAccepted indicates an expected call of InsertAdOfferAccepted.
+func (mr *MockRepositoryMockRecorder) InsertAdOfferAccepted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAdOfferAccepted", reflect.TypeOf((*MockRepository)(nil).InsertAdOfferAccepted), arg0)
+}
+
+// InsertAdPhotoArray mocks base method.
+func (m *MockRepository) InsertAdPhotoArray(arg0 *models.AdPhotos) (*models.AdPhotos, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAdPhotoArray", arg0)
+	ret0, _ := ret[0].(*models.AdPhotos)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAdPhotoArray indicates an expected call of InsertAdPhotoArray.
+func (mr *MockRepositoryMockRecorder) InsertAdPhotoArray(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAdPhotoArray", reflect.TypeOf((*MockRepository)(nil).InsertAdPhotoArray), arg0)
+}
+
+// InsertAdReview mocks base method.
+func (m *MockRepository) InsertAdReview(arg0 *models.AdReview) (*models.AdReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAdReview", arg0)
+	ret0, _ := ret[0].(*models.AdReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAdReview indicates an expected call of InsertAdReview.
+func (mr *MockRepositoryMockRecorder) InsertAdReview(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAdReview", reflect.TypeOf((*MockRepository)(nil).InsertAdReview), arg0)
+}
+
+// InsertAdTemplate mocks base method.
+func (m *MockRepository) InsertAdTemplate(arg0 *models.AdTemplate) (*models.AdTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAdTemplate", arg0)
+	ret0, _ := ret[0].(*models.AdTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAdTemplate indicates an expected call of InsertAdTemplate.
+func (mr *MockRepositoryMockRecorder) InsertAdTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAdTemplate", reflect.TypeOf((*MockRepository)(nil).InsertAdTemplate), arg0)
+}
+
+// InsertAdTemplateSkip mocks base method.
+func (m *MockRepository) InsertAdTemplateSkip(arg0 uint32, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAdTemplateSkip", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAdTemplateSkip indicates an expected call of InsertAdTemplateSkip.
+func (mr *MockRepositoryMockRecorder) InsertAdTemplateSkip(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAdTemplateSkip", reflect.TypeOf((*MockRepository)(nil).InsertAdTemplateSkip), arg0, arg1)
+}
+
+// InsertByAdTemplateOccurrence mocks base method.
+func (m *MockRepository) InsertByAdTemplateOccurrence(arg0 *models.Ad, arg1 *models.AdTemplateOccurrence) (*models.Ad, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertByAdTemplateOccurrence", arg0, arg1)
+	ret0, _ := ret[0].(*models.Ad)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertByAdTemplateOccurrence indicates an expected call of InsertByAdTemplateOccurrence.
+func (mr *MockRepositoryMockRecorder) InsertByAdTemplateOccurrence(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertByAdTemplateOccurrence", reflect.TypeOf((*MockRepository)(nil).InsertByAdTemplateOccurrence), arg0, arg1)
+}
+
+// Patch mocks base method.
+func (m *MockRepository) Patch(arg0 *models.AdPatch) (*models.Ad, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0)
+	ret0, _ := ret[0].(*models.Ad)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertAdUserExecution indicates an expected call of InsertAdUserExecution.
-func (mr *MockRepositoryMockRecorder) InsertAdUserExecution(arg0 interface{}) *gomock.Call {
+// Patch indicates an expected call of Patch.
+func (mr *MockRepositoryMockRecorder) Patch(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAdUserExecution", reflect.TypeOf((*MockRepository)(nil).InsertAdUserExecution), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockRepository)(nil).Patch), arg0)
 }
 
 // Select mocks base method.
@@ -231,6 +688,156 @@ func (mr *MockRepositoryMockRecorder) Select(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Select", reflect.TypeOf((*MockRepository)(nil).Select), arg0)
 }
 
+// SelectAdArrayNotificationPending mocks base method.
+func (m *MockRepository) SelectAdArrayNotificationPending(arg0 time.Time) (*models.Ads, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdArrayNotificationPending", arg0)
+	ret0, _ := ret[0].(*models.Ads)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdArrayNotificationPending indicates an expected call of SelectAdArrayNotificationPending.
+func (mr *MockRepositoryMockRecorder) SelectAdArrayNotificationPending(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdArrayNotificationPending", reflect.TypeOf((*MockRepository)(nil).SelectAdArrayNotificationPending), arg0)
+}
+
+// SelectAdHandover mocks base method.
+func (m *MockRepository) SelectAdHandover(arg0 uint32) (*models.AdHandover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdHandover", arg0)
+	ret0, _ := ret[0].(*models.AdHandover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdHandover indicates an expected call of SelectAdHandover.
+func (mr *MockRepositoryMockRecorder) SelectAdHandover(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdHandover", reflect.TypeOf((*MockRepository)(nil).SelectAdHandover), arg0)
+}
+
+// SelectAdOffer mocks base method.
+func (m *MockRepository) SelectAdOffer(arg0 uint32) (*models.AdOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdOffer", arg0)
+	ret0, _ := ret[0].(*models.AdOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdOffer indicates an expected call of SelectAdOffer.
+func (mr *MockRepositoryMockRecorder) SelectAdOffer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdOffer", reflect.TypeOf((*MockRepository)(nil).SelectAdOffer), arg0)
+}
+
+// SelectAdOfferArray mocks base method.
+func (m *MockRepository) SelectAdOfferArray(arg0 *models.AdOffersSearch) (*models.AdOffers, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdOfferArray", arg0)
+	ret0, _ := ret[0].(*models.AdOffers)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdOfferArray indicates an expected call of SelectAdOfferArray.
+func (mr *MockRepositoryMockRecorder) SelectAdOfferArray(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdOfferArray", reflect.TypeOf((*MockRepository)(nil).SelectAdOfferArray), arg0)
+}
+
+// SelectAdPhoto mocks base method.
+func (m *MockRepository) SelectAdPhoto(arg0 uint32) (*models.AdPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdPhoto", arg0)
+	ret0, _ := ret[0].(*models.AdPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdPhoto indicates an expected call of SelectAdPhoto.
+func (mr *MockRepositoryMockRecorder) SelectAdPhoto(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdPhoto", reflect.TypeOf((*MockRepository)(nil).SelectAdPhoto), arg0)
+}
+
+// SelectAdPhotoArrayByAdIds mocks base method.
+func (m *MockRepository) SelectAdPhotoArrayByAdIds(arg0 []uint32) (*models.AdPhotos, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdPhotoArrayByAdIds", arg0)
+	ret0, _ := ret[0].(*models.AdPhotos)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdPhotoArrayByAdIds indicates an expected call of SelectAdPhotoArrayByAdIds.
+func (mr *MockRepositoryMockRecorder) SelectAdPhotoArrayByAdIds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdPhotoArrayByAdIds", reflect.TypeOf((*MockRepository)(nil).SelectAdPhotoArrayByAdIds), arg0)
+}
+
+// SelectAdRevisionArrayByAdId mocks base method.
+func (m *MockRepository) SelectAdRevisionArrayByAdId(arg0 uint32) (*models.AdRevisions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdRevisionArrayByAdId", arg0)
+	ret0, _ := ret[0].(*models.AdRevisions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdRevisionArrayByAdId indicates an expected call of SelectAdRevisionArrayByAdId.
+func (mr *MockRepositoryMockRecorder) SelectAdRevisionArrayByAdId(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdRevisionArrayByAdId", reflect.TypeOf((*MockRepository)(nil).SelectAdRevisionArrayByAdId), arg0)
+}
+
+// SelectAdTemplate mocks base method.
+func (m *MockRepository) SelectAdTemplate(arg0 uint32) (*models.AdTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdTemplate", arg0)
+	ret0, _ := ret[0].(*models.AdTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdTemplate indicates an expected call of SelectAdTemplate.
+func (mr *MockRepositoryMockRecorder) SelectAdTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdTemplate", reflect.TypeOf((*MockRepository)(nil).SelectAdTemplate), arg0)
+}
+
+// SelectAdTemplateArrayByUserAuthorId mocks base method.
+func (m *MockRepository) SelectAdTemplateArrayByUserAuthorId(arg0 uint32) (*models.AdTemplates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdTemplateArrayByUserAuthorId", arg0)
+	ret0, _ := ret[0].(*models.AdTemplates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdTemplateArrayByUserAuthorId indicates an expected call of SelectAdTemplateArrayByUserAuthorId.
+func (mr *MockRepositoryMockRecorder) SelectAdTemplateArrayByUserAuthorId(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdTemplateArrayByUserAuthorId", reflect.TypeOf((*MockRepository)(nil).SelectAdTemplateArrayByUserAuthorId), arg0)
+}
+
+// SelectAdTemplateOccurrenceArrayDue mocks base method.
+func (m *MockRepository) SelectAdTemplateOccurrenceArrayDue(arg0, arg1 time.Time) (*models.AdTemplateOccurrences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdTemplateOccurrenceArrayDue", arg0, arg1)
+	ret0, _ := ret[0].(*models.AdTemplateOccurrences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdTemplateOccurrenceArrayDue indicates an expected call of SelectAdTemplateOccurrenceArrayDue.
+func (mr *MockRepositoryMockRecorder) SelectAdTemplateOccurrenceArrayDue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdTemplateOccurrenceArrayDue", reflect.TypeOf((*MockRepository)(nil).SelectAdTemplateOccurrenceArrayDue), arg0, arg1)
+}
+
 // SelectAdUserExecution mocks base method.
 func (m *MockRepository) SelectAdUserExecution(arg0 uint32) (*models.AdUserExecution, error) {
 	m.ctrl.T.Helper()
@@ -275,3 +882,78 @@ func (mr *MockRepositoryMockRecorder) Update(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), arg0)
 }
+
+// UpdateAdHandoverAttempt mocks base method.
+func (m *MockRepository) UpdateAdHandoverAttempt(arg0 uint32, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdHandoverAttempt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdHandoverAttempt indicates an expected call of UpdateAdHandoverAttempt.
+func (mr *MockRepositoryMockRecorder) UpdateAdHandoverAttempt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdHandoverAttempt", reflect.TypeOf((*MockRepository)(nil).UpdateAdHandoverAttempt), arg0, arg1, arg2)
+}
+
+// UpdateAdOfferStatus mocks base method.
+func (m *MockRepository) UpdateAdOfferStatus(arg0 uint32, arg1, arg2 models.AdOfferStatus) (*models.AdOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdOfferStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.AdOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdOfferStatus indicates an expected call of UpdateAdOfferStatus.
+func (mr *MockRepositoryMockRecorder) UpdateAdOfferStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdOfferStatus", reflect.TypeOf((*MockRepository)(nil).UpdateAdOfferStatus), arg0, arg1, arg2)
+}
+
+// UpdateAdTemplatePaused mocks base method.
+func (m *MockRepository) UpdateAdTemplatePaused(arg0 uint32, arg1 bool) (*models.AdTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAdTemplatePaused", arg0, arg1)
+	ret0, _ := ret[0].(*models.AdTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAdTemplatePaused indicates an expected call of UpdateAdTemplatePaused.
+func (mr *MockRepositoryMockRecorder) UpdateAdTemplatePaused(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAdTemplatePaused", reflect.TypeOf((*MockRepository)(nil).UpdateAdTemplatePaused), arg0, arg1)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockRepository) UpdateStatus(arg0 uint32, arg1, arg2 models.AdStatus, arg3 uint32) (*models.Ad, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Ad)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockRepositoryMockRecorder) UpdateStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockRepository)(nil).UpdateStatus), arg0, arg1, arg2, arg3)
+}
+
+// UpdateStatusExpired mocks base method.
+func (m *MockRepository) UpdateStatusExpired(arg0 time.Time) (*models.Ads, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusExpired", arg0)
+	ret0, _ := ret[0].(*models.Ads)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatusExpired indicates an expected call of UpdateStatusExpired.
+func (mr *MockRepositoryMockRecorder) UpdateStatusExpired(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusExpired", reflect.TypeOf((*MockRepository)(nil).UpdateStatusExpired), arg0)
+}
@@ -1,14 +1,41 @@
 package ad
 
-import "github.com/TechnoHandOver/backend/internal/models"
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"time"
+)
 
 type Repository interface {
 	Insert(ad_ *models.Ad) (*models.Ad, error)
 	Select(id uint32) (*models.Ad, error)
 	Update(ad_ *models.Ad) (*models.Ad, error)
+	Patch(adPatch *models.AdPatch) (*models.Ad, error)
 	SelectArray(adsSearch *models.AdsSearch) (*models.Ads, error)
-	Delete(id uint32) (*models.Ad, error)
-	InsertAdUserExecution(adUserExecution *models.AdUserExecution) (*models.AdUserExecution, error)
+	Delete(id uint32, version uint32) (*models.Ad, error)
+	UpdateStatus(id uint32, status models.AdStatus, newStatus models.AdStatus, userId uint32) (*models.Ad, error)
+	UpdateStatusExpired(maxDateTimeArr time.Time) (*models.Ads, error)
 	SelectAdUserExecution(adId uint32) (*models.AdUserExecution, error)
 	DeleteAdUserExecution(adId uint32) (*models.AdUserExecution, error)
+	SelectAdHandover(adId uint32) (*models.AdHandover, error)
+	UpdateAdHandoverAttempt(adId uint32, code string, now time.Time) (bool, error)
+	InsertAdReview(adReview *models.AdReview) (*models.AdReview, error)
+	InsertAdOffer(adOffer *models.AdOffer) (*models.AdOffer, error)
+	SelectAdOffer(id uint32) (*models.AdOffer, error)
+	SelectAdOfferArray(adOffersSearch *models.AdOffersSearch) (*models.AdOffers, error)
+	UpdateAdOfferStatus(id uint32, status models.AdOfferStatus, newStatus models.AdOfferStatus) (*models.AdOffer, error)
+	InsertAdOfferAccepted(adOffer *models.AdOffer) (*models.AdOffer, error)
+	InsertAdPhotoArray(adPhotos *models.AdPhotos) (*models.AdPhotos, error)
+	SelectAdPhoto(id uint32) (*models.AdPhoto, error)
+	SelectAdPhotoArrayByAdIds(adIds []uint32) (*models.AdPhotos, error)
+	SelectAdRevisionArrayByAdId(adId uint32) (*models.AdRevisions, error)
+	InsertAdTemplate(adTemplate *models.AdTemplate) (*models.AdTemplate, error)
+	SelectAdTemplate(id uint32) (*models.AdTemplate, error)
+	SelectAdTemplateArrayByUserAuthorId(userAuthorId uint32) (*models.AdTemplates, error)
+	UpdateAdTemplatePaused(id uint32, paused bool) (*models.AdTemplate, error)
+	DeleteAdTemplate(id uint32) (*models.AdTemplate, error)
+	InsertAdTemplateSkip(adTemplateId uint32, date time.Time) error
+	SelectAdTemplateOccurrenceArrayDue(minDateTimeArr time.Time, maxDate time.Time) (*models.AdTemplateOccurrences, error)
+	InsertByAdTemplateOccurrence(ad_ *models.Ad, adTemplateOccurrence *models.AdTemplateOccurrence) (*models.Ad, error)
+	SelectAdArrayNotificationPending(maxDateTime time.Time) (*models.Ads, error)
+	DeleteAdNotificationPending(adId uint32) error
 }
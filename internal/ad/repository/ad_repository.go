@@ -5,6 +5,8 @@ import (
 	"github.com/TechnoHandOver/backend/internal/ad"
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+	"github.com/TechnoHandOver/backend/internal/tools/geo"
 	"github.com/lib/pq"
 	"strconv"
 	"time"
@@ -21,15 +23,19 @@ func NewAdRepositoryImpl(db *sql.DB) ad.Repository {
 }
 
 func (adsRepository *AdRepository) Insert(ad_ *models.Ad) (*models.Ad, error) {
-	const query = `
-INSERT INTO ad (user_author_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, loc_dep, loc_arr, date_time_arr, item, min_price, comment`
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := insertAd(tx, ad_); err != nil {
+		return nil, err
+	}
 
-	if err := adsRepository.db.QueryRow(query, ad_.UserAuthorId, ad_.LocDep, ad_.LocArr, time.Time(ad_.DateTimeArr),
-		ad_.Item, ad_.MinPrice, ad_.Comment).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName,
-		&ad_.UserAuthorAvatar, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice,
-		&ad_.Comment); err != nil {
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -38,7 +44,7 @@ RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_a
 
 func (adsRepository *AdRepository) Select(id uint32) (*models.Ad, error) {
 	const query = `
-SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment
+SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version
 FROM ad
 WHERE id = $1`
 
@@ -46,7 +52,8 @@ WHERE id = $1`
 	var userExecutorVkId sql.NullInt32
 	if err := adsRepository.db.QueryRow(query, id).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId,
 		&ad_.UserAuthorName, &ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr,
-		&ad_.Item, &ad_.MinPrice, &ad_.Comment); err != nil {
+		&ad_.Item, &ad_.MinPrice, &ad_.Comment, &ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId,
+		&ad_.LocArrPlaceId, &ad_.DateTimeDep, &ad_.Version); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
@@ -63,16 +70,178 @@ WHERE id = $1`
 
 func (adsRepository *AdRepository) Update(ad_ *models.Ad) (*models.Ad, error) {
 	const query = `
-UPDATE ad SET loc_dep = $2, loc_arr = $3, date_time_arr = $4, item = $5, min_price = $6, comment = $7
-WHERE id = $1
-RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment`
+UPDATE ad SET loc_dep = $2, loc_arr = $3, date_time_arr = $4, item = $5, min_price = $6, comment = $7,
+              loc_dep_point = $8, loc_arr_point = $9, loc_dep_place_id = $10, loc_arr_place_id = $11,
+              date_time_dep = $12
+WHERE id = $1 AND (version = $13 OR $13 = 0)
+RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version`
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingAd, err := selectAdForUpdate(tx, ad_.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	//the ad may be taken since the usecase checked it
+	if existingAd.Status != models.AdStatusOpen {
+		return nil, consts.RepErrConflict
+	}
+
+	var userExecutorVkId sql.NullInt32
+	if err := tx.QueryRow(query, ad_.Id, ad_.LocDep, ad_.LocArr, time.Time(ad_.DateTimeArr), ad_.Item, ad_.MinPrice,
+		ad_.Comment, ad_.LocDepPoint, ad_.LocArrPoint, ad_.LocDepPlaceId, ad_.LocArrPlaceId,
+		(*time.Time)(ad_.DateTimeDep), ad_.Version).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId,
+		&ad_.UserAuthorName, &ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr,
+		&ad_.Item, &ad_.MinPrice, &ad_.Comment, &ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId,
+		&ad_.LocArrPlaceId, &ad_.DateTimeDep, &ad_.Version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrPreconditionFailed
+		}
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+	if userExecutorVkId.Valid {
+		ad_.UserExecutorVkId = new(uint32)
+		*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+	}
+
+	if err := insertAdRevision(tx, ad_.Id, &ad_.UserAuthorId, models.AdRevisionActionUpdate, existingAd,
+		ad_); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ad_, nil
+}
+
+func (adsRepository *AdRepository) Patch(adPatch *models.AdPatch) (*models.Ad, error) {
+	const queryStart = "UPDATE ad SET "
+	const queryLocDep = "loc_dep"
+	const queryLocArr = "loc_arr"
+	const queryDateTimeDep = "date_time_dep"
+	const queryDateTimeArr = "date_time_arr"
+	const queryItem = "item"
+	const queryMinPrice = "min_price"
+	const queryComment = "comment"
+	const queryLocDepPoint = "loc_dep_point"
+	const queryLocArrPoint = "loc_arr_point"
+	const queryLocDepPlaceId = "loc_dep_place_id"
+	const queryLocArrPlaceId = "loc_arr_place_id"
+	const queryEquals = " = $"
+	const queryComma = ", "
+	const queryEnd = `
+WHERE id = $1 AND (version = $2 OR $2 = 0)
+RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version`
+
+	query := queryStart
+	queryArgs := make([]interface{}, 0)
+	queryArgs = append(queryArgs, adPatch.Id, adPatch.Version)
+	set := func(queryColumn string, queryArg interface{}) {
+		query += queryColumn + queryEquals + strconv.Itoa(len(queryArgs)+1) + queryComma
+		queryArgs = append(queryArgs, queryArg)
+	}
+
+	if adPatch.LocDep != nil {
+		set(queryLocDep, *adPatch.LocDep)
+	}
+	if adPatch.LocArr != nil {
+		set(queryLocArr, *adPatch.LocArr)
+	}
+	if adPatch.DateTimeDepNull {
+		set(queryDateTimeDep, nil)
+	} else if adPatch.DateTimeDep != nil {
+		set(queryDateTimeDep, time.Time(*adPatch.DateTimeDep))
+	}
+	if adPatch.DateTimeArr != nil {
+		set(queryDateTimeArr, time.Time(*adPatch.DateTimeArr))
+	}
+	if adPatch.Item != nil {
+		set(queryItem, *adPatch.Item)
+	}
+	if adPatch.MinPrice != nil {
+		set(queryMinPrice, *adPatch.MinPrice)
+	}
+	if adPatch.Comment != nil {
+		set(queryComment, *adPatch.Comment)
+	}
+	if adPatch.LocDepPointNull {
+		set(queryLocDepPoint, nil)
+	} else if adPatch.LocDepPoint != nil {
+		set(queryLocDepPoint, *adPatch.LocDepPoint)
+	}
+	if adPatch.LocArrPointNull {
+		set(queryLocArrPoint, nil)
+	} else if adPatch.LocArrPoint != nil {
+		set(queryLocArrPoint, *adPatch.LocArrPoint)
+	}
+	if adPatch.LocDepPlaceIdNull {
+		set(queryLocDepPlaceId, nil)
+	} else if adPatch.LocDepPlaceId != nil {
+		set(queryLocDepPlaceId, *adPatch.LocDepPlaceId)
+	}
+	if adPatch.LocArrPlaceIdNull {
+		set(queryLocArrPlaceId, nil)
+	} else if adPatch.LocArrPlaceId != nil {
+		set(queryLocArrPlaceId, *adPatch.LocArrPlaceId)
+	}
+
+	//an empty merge patch changes nothing, but it is still conditional
+	if len(queryArgs) == 2 {
+		ad_, err := adsRepository.Select(adPatch.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !etag.Matches(adPatch.Version, ad_.Version) {
+			return nil, consts.RepErrPreconditionFailed
+		}
+
+		return ad_, nil
+	}
+
+	query = query[:len(query)-2] + queryEnd
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingAd, err := selectAdForUpdate(tx, adPatch.Id)
+	if err != nil {
+		return nil, err
+	}
 
+	//the ad may be taken since the usecase checked it
+	if existingAd.Status != models.AdStatusOpen {
+		return nil, consts.RepErrConflict
+	}
+
+	ad_ := new(models.Ad)
 	var userExecutorVkId sql.NullInt32
-	if err := adsRepository.db.QueryRow(query, ad_.Id, ad_.LocDep, ad_.LocArr, time.Time(ad_.DateTimeArr), ad_.Item,
-		ad_.MinPrice, ad_.Comment).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName,
-		&ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item,
-		&ad_.MinPrice, &ad_.Comment); err != nil {
+	if err := tx.QueryRow(query, queryArgs...).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId,
+		&ad_.UserAuthorName, &ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr,
+		&ad_.Item, &ad_.MinPrice, &ad_.Comment, &ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId,
+		&ad_.LocArrPlaceId, &ad_.DateTimeDep, &ad_.Version); err != nil {
 		if err == sql.ErrNoRows {
+			return nil, consts.RepErrPreconditionFailed
+		}
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
 			return nil, consts.RepErrNotFound
 		}
 
@@ -83,22 +252,50 @@ RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_a
 		*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
 	}
 
+	if err := insertAdRevision(tx, ad_.Id, &adPatch.UserAuthorId, models.AdRevisionActionUpdate, existingAd,
+		ad_); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return ad_, nil
 }
 
-func (adsRepository *AdRepository) Delete(id uint32) (*models.Ad, error) {
+func (adsRepository *AdRepository) Delete(id uint32, version uint32) (*models.Ad, error) {
 	const query = `
 DELETE FROM ad
-WHERE id = $1
-RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment`
+WHERE id = $1 AND (version = $2 OR $2 = 0)
+RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version`
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingAd, err := selectAdForUpdate(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	//the execution, the handover code and the thread would be gone with the cascade in the middle of the delivery
+	if existingAd.Status != models.AdStatusOpen {
+		return nil, consts.RepErrConflict
+	}
 
 	ad_ := new(models.Ad)
 	var userExecutorVkId sql.NullInt32
-	if err := adsRepository.db.QueryRow(query, id).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId,
+	if err := tx.QueryRow(query, id, version).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId,
 		&ad_.UserAuthorName, &ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr,
-		&ad_.Item, &ad_.MinPrice, &ad_.Comment); err != nil {
+		&ad_.Item, &ad_.MinPrice, &ad_.Comment, &ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId,
+		&ad_.LocArrPlaceId, &ad_.DateTimeDep, &ad_.Version); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, consts.RepErrNotFound
+			return nil, consts.RepErrPreconditionFailed
 		}
 
 		return nil, err
@@ -108,32 +305,72 @@ RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_a
 		*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
 	}
 
+	if err := insertAdRevision(tx, ad_.Id, &ad_.UserAuthorId, models.AdRevisionActionDelete, ad_, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return ad_, nil
 }
 
 func (adsRepository *AdRepository) SelectArray(adsSearch *models.AdsSearch) (*models.Ads, error) { //TODO: назвать здесь константы SQL-запроса чуть более подходящими названиями...
-	const queryStart = "SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment FROM ad"
+	const queryStart = "SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version FROM ad"
+	const queryJoinUserExecution = " JOIN ad_user_execution ON ad_user_execution.ad_id = ad.id"
 	const queryWhere = " WHERE "
+	const queryNotHidden = "NOT hidden"
 	const queryUserAuthorId = "user_author_id = $"
 	const queryNotUserAuthorId = "user_author_id != $"
-	const queryUserExecutorVkIdIsNull = "user_executor_vk_id IS NULL"
+	const queryUserExecutorId = "ad_user_execution.user_executor_id = $"
+	const queryStatus = "status = $"
+	const queryStatusActive = "status IN ('assigned', 'picked_up', 'delivered')"
+	const queryStatusNotActive = "status NOT IN ('assigned', 'picked_up', 'delivered')"
 	const queryLocDep1 = "to_tsvector('russian', loc_dep) @@ plainto_tsquery('russian', $"
 	const queryLocDep2 = ")"
 	const queryLocArr1 = "to_tsvector('russian', loc_arr) @@ plainto_tsquery('russian', $"
 	const queryLocArr2 = ")"
+	const queryLocDepPlaceId = "loc_dep_place_id = $"
+	const queryLocArrPlaceId = "loc_arr_place_id = $"
 	const queryDateTimeArr = "date_time_arr >= $"
 	const queryMinPrice = "min_price <= $"
+	const queryMinUserAuthorRating1 = "user_author_id IN (SELECT user_.id FROM user_ WHERE user_.rating >= $"
+	const queryMinUserAuthorRating2 = ")"
+	const queryNotBlockedUserId1 = "NOT user_blocked($"
+	const queryNotBlockedUserId2 = ", ad.user_author_id)"
+	const queryLocDepPoint = "loc_dep_point"
+	const queryLocArrPoint = "loc_arr_point"
+	const queryPoint1 = "$"
+	const queryPoint2 = "::point"
+	const queryDistance = " <= $"
+	const queryCursor1 = "("
+	const queryCursorLess = " < $"
+	const queryCursorGreater = " > $"
+	const queryCursor2 = " OR "
+	const queryCursor3 = " = $"
+	const queryCursor4 = " AND id < $"
+	const queryCursor5 = ")"
 	const queryAnd = " AND "
 	const queryOrderBy = " ORDER BY "
 	const queryOrderByDateTimeArr = "date_time_arr"
 	const queryOrderByMinPrice = "min_price"
 	const queryOrderByDesc = " DESC"
 	const queryEnd = ", id DESC"
+	const queryLimit = " LIMIT $"
 
-	var whereClause = false
-	query := queryStart + queryWhere
+	query := queryStart
+	if adsSearch.UserExecutorId != nil {
+		query += queryJoinUserExecution
+	}
+	query += queryWhere + queryNotHidden + queryAnd
 	queryArgs := make([]interface{}, 0)
 
+	var order = models.AdsSearchOrderDateTimeArrDesc
+	if adsSearch.Order != nil {
+		order = *adsSearch.Order
+	}
+
 	if adsSearch.UserAuthorId != nil {
 		query += queryUserAuthorId + strconv.Itoa(len(queryArgs)+1) + queryAnd
 		queryArgs = append(queryArgs, adsSearch.UserAuthorId)
@@ -144,9 +381,27 @@ func (adsRepository *AdRepository) SelectArray(adsSearch *models.AdsSearch) (*mo
 		queryArgs = append(queryArgs, adsSearch.NotUserAuthorId)
 	}
 
-	if adsSearch.NullUserExecutorVkId != nil && *adsSearch.NullUserExecutorVkId == true {
-		query += queryUserExecutorVkIdIsNull + queryAnd
-		whereClause = true
+	if adsSearch.NotBlockedUserId != nil {
+		query += queryNotBlockedUserId1 + strconv.Itoa(len(queryArgs)+1) + queryNotBlockedUserId2 + queryAnd
+		queryArgs = append(queryArgs, adsSearch.NotBlockedUserId)
+	}
+
+	if adsSearch.UserExecutorId != nil {
+		query += queryUserExecutorId + strconv.Itoa(len(queryArgs)+1) + queryAnd
+		queryArgs = append(queryArgs, adsSearch.UserExecutorId)
+	}
+
+	if adsSearch.Status != nil {
+		query += queryStatus + strconv.Itoa(len(queryArgs)+1) + queryAnd
+		queryArgs = append(queryArgs, adsSearch.Status)
+	}
+
+	if adsSearch.Active != nil {
+		if *adsSearch.Active {
+			query += queryStatusActive + queryAnd
+		} else {
+			query += queryStatusNotActive + queryAnd
+		}
 	}
 
 	if adsSearch.LocDep != nil {
@@ -159,6 +414,16 @@ func (adsRepository *AdRepository) SelectArray(adsSearch *models.AdsSearch) (*mo
 		queryArgs = append(queryArgs, adsSearch.LocArr)
 	}
 
+	if adsSearch.LocDepPlaceId != nil {
+		query += queryLocDepPlaceId + strconv.Itoa(len(queryArgs)+1) + queryAnd
+		queryArgs = append(queryArgs, adsSearch.LocDepPlaceId)
+	}
+
+	if adsSearch.LocArrPlaceId != nil {
+		query += queryLocArrPlaceId + strconv.Itoa(len(queryArgs)+1) + queryAnd
+		queryArgs = append(queryArgs, adsSearch.LocArrPlaceId)
+	}
+
 	if adsSearch.MinDateTimeArr != nil {
 		query += queryDateTimeArr + strconv.Itoa(len(queryArgs)+1) + queryAnd
 		queryArgs = append(queryArgs, time.Time(*adsSearch.MinDateTimeArr))
@@ -169,17 +434,54 @@ func (adsRepository *AdRepository) SelectArray(adsSearch *models.AdsSearch) (*mo
 		queryArgs = append(queryArgs, adsSearch.MaxPrice)
 	}
 
-	if len(queryArgs) > 0 || whereClause {
-		query = query[:len(query)-len(queryAnd)]
-	} else {
-		query = query[:len(query)-len(queryWhere)]
+	if adsSearch.MinUserAuthorRating != nil {
+		query += queryMinUserAuthorRating1 + strconv.Itoa(len(queryArgs)+1) + queryMinUserAuthorRating2 + queryAnd
+		queryArgs = append(queryArgs, adsSearch.MinUserAuthorRating)
 	}
 
-	var order = models.AdsSearchOrderDateTimeArrDesc
-	if adsSearch.Order != nil {
-		order = *adsSearch.Order
+	if adsSearch.NearDep != nil {
+		query += geo.DistanceExpression(queryLocDepPoint, queryPoint1+strconv.Itoa(len(queryArgs)+1)+queryPoint2) +
+			queryDistance + strconv.Itoa(len(queryArgs)+2) + queryAnd
+		queryArgs = append(queryArgs, adsSearch.NearDep, adsSearch.RadiusM)
+	}
+
+	if adsSearch.NearArr != nil {
+		query += geo.DistanceExpression(queryLocArrPoint, queryPoint1+strconv.Itoa(len(queryArgs)+1)+queryPoint2) +
+			queryDistance + strconv.Itoa(len(queryArgs)+2) + queryAnd
+		queryArgs = append(queryArgs, adsSearch.NearArr, adsSearch.RadiusM)
 	}
 
+	if adsSearch.Cursor != nil {
+		var queryCursorColumn, queryCursorComparison string
+		var queryCursorValue interface{}
+		switch order {
+		case models.AdsSearchOrderDateTimeArrAsc:
+			queryCursorColumn, queryCursorComparison = queryOrderByDateTimeArr, queryCursorGreater
+			queryCursorValue = adsSearch.Cursor.DateTimeArr
+			break
+		case models.AdsSearchOrderDateTimeArrDesc:
+			queryCursorColumn, queryCursorComparison = queryOrderByDateTimeArr, queryCursorLess
+			queryCursorValue = adsSearch.Cursor.DateTimeArr
+			break
+		case models.AdsSearchOrderMinPriceAsc:
+			queryCursorColumn, queryCursorComparison = queryOrderByMinPrice, queryCursorGreater
+			queryCursorValue = adsSearch.Cursor.MinPrice
+			break
+		case models.AdsSearchOrderMinPriceDesc:
+			queryCursorColumn, queryCursorComparison = queryOrderByMinPrice, queryCursorLess
+			queryCursorValue = adsSearch.Cursor.MinPrice
+			break
+		}
+
+		queryCursorValueNumber := strconv.Itoa(len(queryArgs) + 1)
+		query += queryCursor1 + queryCursorColumn + queryCursorComparison + queryCursorValueNumber + queryCursor2 +
+			queryCursorColumn + queryCursor3 + queryCursorValueNumber + queryCursor4 + strconv.Itoa(len(queryArgs)+2) +
+			queryCursor5 + queryAnd
+		queryArgs = append(queryArgs, queryCursorValue, adsSearch.Cursor.Id)
+	}
+
+	query = query[:len(query)-len(queryAnd)]
+
 	query += queryOrderBy
 	switch order {
 	case models.AdsSearchOrderDateTimeArrAsc:
@@ -197,6 +499,11 @@ func (adsRepository *AdRepository) SelectArray(adsSearch *models.AdsSearch) (*mo
 	}
 	query += queryEnd
 
+	if adsSearch.Limit != nil {
+		query += queryLimit + strconv.Itoa(len(queryArgs)+1)
+		queryArgs = append(queryArgs, adsSearch.Limit)
+	}
+
 	rows, err := adsRepository.db.Query(query, queryArgs...)
 	if err != nil {
 		return nil, err
@@ -210,8 +517,9 @@ func (adsRepository *AdRepository) SelectArray(adsSearch *models.AdsSearch) (*mo
 		ad_ := new(models.Ad)
 		var userExecutorVkId sql.NullInt32
 		if err := rows.Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName, &ad_.UserAuthorAvatar,
-			&userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice,
-			&ad_.Comment); err != nil {
+			&userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice, &ad_.Comment,
+			&ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId, &ad_.LocArrPlaceId,
+			&ad_.DateTimeDep, &ad_.Version); err != nil {
 			return nil, err
 		}
 		if userExecutorVkId.Valid {
@@ -225,22 +533,129 @@ func (adsRepository *AdRepository) SelectArray(adsSearch *models.AdsSearch) (*mo
 	return &ads, nil
 }
 
-func (adsRepository *AdRepository) InsertAdUserExecution(adUserExecution *models.AdUserExecution) (*models.AdUserExecution, error) {
+func (adsRepository *AdRepository) UpdateStatus(id uint32, status models.AdStatus, newStatus models.AdStatus,
+	userId uint32) (*models.Ad, error) {
 	const query = `
-INSERT INTO ad_user_execution (ad_id, user_executor_id)
-VALUES ($1, $2)
-RETURNING ad_id, user_executor_id`
+UPDATE ad SET status = $3
+WHERE id = $1 AND status = $2
+RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version`
 
-	if err := adsRepository.db.QueryRow(query, adUserExecution.AdId,
-		adUserExecution.UserExecutorId).Scan(&adUserExecution.AdId, &adUserExecution.UserExecutorId); err != nil {
-		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingAd, err := selectAdForUpdate(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ad_ := new(models.Ad)
+	var userExecutorVkId sql.NullInt32
+	if err := tx.QueryRow(query, id, status, newStatus).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId,
+		&ad_.UserAuthorName, &ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr,
+		&ad_.Item, &ad_.MinPrice, &ad_.Comment, &ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId,
+		&ad_.LocArrPlaceId, &ad_.DateTimeDep, &ad_.Version); err != nil {
+		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
 
 		return nil, err
 	}
+	if userExecutorVkId.Valid {
+		ad_.UserExecutorVkId = new(uint32)
+		*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+	}
+
+	if err := insertAdRevision(tx, ad_.Id, &userId, models.AdRevisionActionExecution, existingAd,
+		ad_); err != nil {
+		return nil, err
+	}
 
-	return adUserExecution, nil
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ad_, nil
+}
+
+func (adsRepository *AdRepository) UpdateStatusExpired(maxDateTimeArr time.Time) (*models.Ads, error) {
+	const query = `
+UPDATE ad SET status = 'expired'
+WHERE id = ANY($1)
+RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version`
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingAds, err := selectAdArrayExpiredForUpdate(tx, maxDateTimeArr)
+	if err != nil {
+		return nil, err
+	}
+
+	ads := make(models.Ads, 0, len(existingAds))
+	if len(existingAds) == 0 {
+		return &ads, nil
+	}
+
+	ids := make([]int64, len(existingAds))
+	for i, existingAd := range existingAds {
+		ids[i] = int64(existingAd.Id)
+	}
+
+	rows, err := tx.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		ad_ := new(models.Ad)
+		var userExecutorVkId sql.NullInt32
+		if err := rows.Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName, &ad_.UserAuthorAvatar,
+			&userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice, &ad_.Comment,
+			&ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId, &ad_.LocArrPlaceId,
+			&ad_.DateTimeDep, &ad_.Version); err != nil {
+			return nil, err
+		}
+		if userExecutorVkId.Valid {
+			ad_.UserExecutorVkId = new(uint32)
+			*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+		}
+
+		ads = append(ads, ad_)
+	}
+	if err := rows.Close(); err != nil { //the connection is busy until the rows are closed
+		return nil, err
+	}
+
+	existingAdsById := make(map[uint32]*models.Ad, len(existingAds))
+	for _, existingAd := range existingAds {
+		existingAdsById[existingAd.Id] = existingAd
+	}
+	for _, ad_ := range ads {
+		//expiry has no actor
+		if err := insertAdRevision(tx, ad_.Id, nil, models.AdRevisionActionExecution, existingAdsById[ad_.Id],
+			ad_); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &ads, nil
 }
 
 func (adsRepository *AdRepository) SelectAdUserExecution(adId uint32) (*models.AdUserExecution, error) {
@@ -268,9 +683,21 @@ DELETE FROM ad_user_execution
 WHERE ad_id = $1
 RETURNING ad_id, user_executor_id`
 
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingAd, err := selectAdForUpdate(tx, adId)
+	if err != nil {
+		return nil, err
+	}
+
 	adUserExecution := new(models.AdUserExecution)
-	if err := adsRepository.db.QueryRow(query, adId).Scan(&adUserExecution.AdId,
-		&adUserExecution.UserExecutorId); err != nil {
+	if err := tx.QueryRow(query, adId).Scan(&adUserExecution.AdId, &adUserExecution.UserExecutorId); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
@@ -278,5 +705,930 @@ RETURNING ad_id, user_executor_id`
 		return nil, err
 	}
 
+	if err := insertAdExecutionRevision(tx, existingAd, adUserExecution.UserExecutorId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return adUserExecution, nil
 }
+
+func (adsRepository *AdRepository) SelectAdHandover(adId uint32) (*models.AdHandover, error) {
+	const query = `
+SELECT ad_id, code, failed_attempts, locked_until
+FROM ad_handover
+WHERE ad_id = $1`
+
+	adHandover := new(models.AdHandover)
+	if err := adsRepository.db.QueryRow(query, adId).Scan(&adHandover.AdId, &adHandover.Code,
+		&adHandover.FailedAttempts, &adHandover.LockedUntil); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return adHandover, nil
+}
+
+func (adsRepository *AdRepository) UpdateAdHandoverAttempt(adId uint32, code string, now time.Time) (bool, error) {
+	const query = `
+UPDATE ad_handover
+SET failed_attempts = CASE WHEN code = $2 OR failed_attempts + 1 >= $3 THEN 0 ELSE failed_attempts + 1 END,
+    locked_until = CASE WHEN code = $2 OR failed_attempts + 1 < $3 THEN NULL ELSE $4::timestamp END
+WHERE ad_id = $1 AND (locked_until IS NULL OR locked_until <= $5)
+RETURNING code = $2`
+
+	var matched bool
+	if err := adsRepository.db.QueryRow(query, adId, code, models.AdHandoverMaxFailedAttempts,
+		now.Add(models.AdHandoverLockout), now).Scan(&matched); err != nil {
+		if err == sql.ErrNoRows {
+			return false, consts.RepErrNotFound
+		}
+
+		return false, err
+	}
+
+	return matched, nil
+}
+
+func (adsRepository *AdRepository) InsertAdReview(adReview *models.AdReview) (*models.AdReview, error) {
+	const query = `
+INSERT INTO ad_review (ad_id, user_author_id, user_target_id, rating, comment)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, ad_id, user_author_id, user_target_id, rating, comment, date_time`
+
+	if err := adsRepository.db.QueryRow(query, adReview.AdId, adReview.UserAuthorId, adReview.UserTargetId,
+		adReview.Rating, adReview.Comment).Scan(&adReview.Id, &adReview.AdId, &adReview.UserAuthorId,
+		&adReview.UserTargetId, &adReview.Rating, &adReview.Comment, &adReview.DateTime); err != nil {
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23505" {
+			return nil, consts.RepErrConflict
+		}
+
+		return nil, err
+	}
+
+	return adReview, nil
+}
+
+func (adsRepository *AdRepository) InsertAdOffer(adOffer *models.AdOffer) (*models.AdOffer, error) {
+	const query = `
+INSERT INTO ad_offer (ad_id, user_executor_id, price, comment)
+VALUES ($1, $2, $3, $4)
+RETURNING id, ad_id, user_executor_id, user_executor_vk_id, user_executor_name, user_executor_avatar, user_executor_rating, price, comment, status`
+
+	if err := adsRepository.db.QueryRow(query, adOffer.AdId, adOffer.UserExecutorId, adOffer.Price,
+		adOffer.Comment).Scan(&adOffer.Id, &adOffer.AdId, &adOffer.UserExecutorId, &adOffer.UserExecutorVkId,
+		&adOffer.UserExecutorName, &adOffer.UserExecutorAvatar, &adOffer.UserExecutorRating, &adOffer.Price,
+		&adOffer.Comment, &adOffer.Status); err != nil {
+		if err_, ok := err.(*pq.Error); ok {
+			switch err_.Code {
+			case "23503":
+				return nil, consts.RepErrNotFound
+			case "23505":
+				return nil, consts.RepErrConflict
+			case "42501":
+				return nil, consts.RepErrForbidden
+			}
+		}
+
+		return nil, err
+	}
+
+	return adOffer, nil
+}
+
+func (adsRepository *AdRepository) SelectAdOffer(id uint32) (*models.AdOffer, error) {
+	const query = `
+SELECT id, ad_id, user_executor_id, user_executor_vk_id, user_executor_name, user_executor_avatar, user_executor_rating, price, comment, status
+FROM ad_offer
+WHERE id = $1`
+
+	adOffer := new(models.AdOffer)
+	if err := adsRepository.db.QueryRow(query, id).Scan(&adOffer.Id, &adOffer.AdId, &adOffer.UserExecutorId,
+		&adOffer.UserExecutorVkId, &adOffer.UserExecutorName, &adOffer.UserExecutorAvatar,
+		&adOffer.UserExecutorRating, &adOffer.Price, &adOffer.Comment, &adOffer.Status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return adOffer, nil
+}
+
+func (adsRepository *AdRepository) SelectAdOfferArray(adOffersSearch *models.AdOffersSearch) (*models.AdOffers, error) {
+	const queryStart = `
+SELECT id, ad_id, user_executor_id, user_executor_vk_id, user_executor_name, user_executor_avatar, user_executor_rating, price, comment, status
+FROM ad_offer
+WHERE ad_id = $1`
+	const queryMinUserExecutorRating = " AND user_executor_rating >= $2"
+	const queryOrderPriceAsc = " ORDER BY price, id"
+	const queryOrderRatingDesc = " ORDER BY user_executor_rating DESC, price, id"
+
+	query := queryStart
+	queryArgs := make([]interface{}, 0)
+	queryArgs = append(queryArgs, adOffersSearch.AdId)
+
+	if adOffersSearch.MinUserExecutorRating != nil {
+		query += queryMinUserExecutorRating
+		queryArgs = append(queryArgs, *adOffersSearch.MinUserExecutorRating)
+	}
+
+	if adOffersSearch.Order != nil && *adOffersSearch.Order == models.AdOffersSearchOrderRatingDesc {
+		query += queryOrderRatingDesc
+	} else {
+		query += queryOrderPriceAsc
+	}
+
+	rows, err := adsRepository.db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adOffers := make(models.AdOffers, 0)
+	for rows.Next() {
+		adOffer := new(models.AdOffer)
+		if err := rows.Scan(&adOffer.Id, &adOffer.AdId, &adOffer.UserExecutorId, &adOffer.UserExecutorVkId,
+			&adOffer.UserExecutorName, &adOffer.UserExecutorAvatar, &adOffer.UserExecutorRating, &adOffer.Price,
+			&adOffer.Comment, &adOffer.Status); err != nil {
+			return nil, err
+		}
+
+		adOffers = append(adOffers, adOffer)
+	}
+
+	return &adOffers, nil
+}
+
+func (adsRepository *AdRepository) UpdateAdOfferStatus(id uint32, status models.AdOfferStatus, newStatus models.AdOfferStatus) (*models.AdOffer, error) {
+	const query = `
+UPDATE ad_offer SET status = $3
+WHERE id = $1 AND status = $2
+RETURNING id, ad_id, user_executor_id, user_executor_vk_id, user_executor_name, user_executor_avatar, user_executor_rating, price, comment, status`
+	const queryAdId = `
+SELECT ad_id
+FROM ad_offer
+WHERE id = $1`
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var existingAd *models.Ad
+	if newStatus == models.AdOfferStatusAccepted {
+		var adId uint32
+		if err := tx.QueryRow(queryAdId, id).Scan(&adId); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, consts.RepErrNotFound
+			}
+
+			return nil, err
+		}
+
+		if existingAd, err = selectAdForUpdate(tx, adId); err != nil {
+			return nil, err
+		}
+
+		//the ad may be taken since the usecase checked it
+		if existingAd.Status != models.AdStatusOpen {
+			return nil, consts.RepErrConflict
+		}
+	}
+
+	adOffer := new(models.AdOffer)
+	if err := tx.QueryRow(query, id, status, newStatus).Scan(&adOffer.Id, &adOffer.AdId, &adOffer.UserExecutorId,
+		&adOffer.UserExecutorVkId, &adOffer.UserExecutorName, &adOffer.UserExecutorAvatar,
+		&adOffer.UserExecutorRating, &adOffer.Price, &adOffer.Comment, &adOffer.Status); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+		if err_, ok := err.(*pq.Error); ok {
+			switch err_.Code {
+			case "23505", "55000":
+				return nil, consts.RepErrConflict
+			case "42501":
+				return nil, consts.RepErrForbidden
+			}
+		}
+
+		return nil, err
+	}
+
+	if existingAd != nil {
+		//ad_offer_update trigger has assigned the executor to the open ad
+		if err := insertAdExecutionRevision(tx, existingAd, existingAd.UserAuthorId); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return adOffer, nil
+}
+
+// InsertAdOfferAccepted inserts the offer and accepts it at once, the ad row is locked to check it is still open
+func (adsRepository *AdRepository) InsertAdOfferAccepted(adOffer *models.AdOffer) (*models.AdOffer, error) {
+	const queryInsert = `
+INSERT INTO ad_offer (ad_id, user_executor_id, price, comment)
+VALUES ($1, $2, $3, $4)
+RETURNING id`
+	const query = `
+UPDATE ad_offer SET status = $2
+WHERE id = $1
+RETURNING id, ad_id, user_executor_id, user_executor_vk_id, user_executor_name, user_executor_avatar, user_executor_rating, price, comment, status`
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingAd, err := selectAdForUpdate(tx, adOffer.AdId)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingAd.Status != models.AdStatusOpen {
+		return nil, consts.RepErrConflict
+	}
+
+	var id uint32
+	if err := tx.QueryRow(queryInsert, adOffer.AdId, adOffer.UserExecutorId, adOffer.Price,
+		adOffer.Comment).Scan(&id); err != nil {
+		if err_, ok := err.(*pq.Error); ok {
+			switch err_.Code {
+			case "23503":
+				return nil, consts.RepErrNotFound
+			case "23505":
+				return nil, consts.RepErrConflict
+			case "42501":
+				return nil, consts.RepErrForbidden
+			}
+		}
+
+		return nil, err
+	}
+
+	//ad_offer_update trigger assigns the executor and rejects the other offers
+	if err := tx.QueryRow(query, id, models.AdOfferStatusAccepted).Scan(&adOffer.Id, &adOffer.AdId,
+		&adOffer.UserExecutorId, &adOffer.UserExecutorVkId, &adOffer.UserExecutorName, &adOffer.UserExecutorAvatar,
+		&adOffer.UserExecutorRating, &adOffer.Price, &adOffer.Comment, &adOffer.Status); err != nil {
+		if err_, ok := err.(*pq.Error); ok {
+			switch err_.Code {
+			case "23505", "55000":
+				return nil, consts.RepErrConflict
+			}
+		}
+
+		return nil, err
+	}
+
+	if err := insertAdExecutionRevision(tx, existingAd, adOffer.UserExecutorId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return adOffer, nil
+}
+
+// InsertAdPhotoArray inserts the photos of one ad, the ad row is locked to keep their count within the limit
+func (adsRepository *AdRepository) InsertAdPhotoArray(adPhotos *models.AdPhotos) (*models.AdPhotos, error) {
+	const queryCount = `
+SELECT (SELECT count(*) FROM ad_photo WHERE ad_id = ad.id)
+FROM ad
+WHERE id = $1
+FOR UPDATE`
+	const query = `
+INSERT INTO ad_photo (ad_id, content_type, size, blob_key, thumbnail_blob_key)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, ad_id, content_type, size, blob_key, thumbnail_blob_key`
+
+	if len(*adPhotos) == 0 {
+		return adPhotos, nil
+	}
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var count int
+	if err := tx.QueryRow(queryCount, (*adPhotos)[0].AdId).Scan(&count); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	if count+len(*adPhotos) > models.AdPhotosMaxCount {
+		return nil, consts.RepErrConflict
+	}
+
+	for _, adPhoto := range *adPhotos {
+		if err := tx.QueryRow(query, adPhoto.AdId, adPhoto.ContentType, adPhoto.Size, adPhoto.BlobKey,
+			adPhoto.ThumbnailBlobKey).Scan(&adPhoto.Id, &adPhoto.AdId, &adPhoto.ContentType, &adPhoto.Size,
+			&adPhoto.BlobKey, &adPhoto.ThumbnailBlobKey); err != nil {
+			if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+				return nil, consts.RepErrNotFound
+			}
+
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return adPhotos, nil
+}
+
+func (adsRepository *AdRepository) SelectAdPhoto(id uint32) (*models.AdPhoto, error) {
+	const query = `
+SELECT id, ad_id, content_type, size, blob_key, thumbnail_blob_key
+FROM ad_photo
+WHERE id = $1`
+
+	adPhoto := new(models.AdPhoto)
+	if err := adsRepository.db.QueryRow(query, id).Scan(&adPhoto.Id, &adPhoto.AdId, &adPhoto.ContentType, &adPhoto.Size,
+		&adPhoto.BlobKey, &adPhoto.ThumbnailBlobKey); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return adPhoto, nil
+}
+
+func (adsRepository *AdRepository) SelectAdPhotoArrayByAdIds(adIds []uint32) (*models.AdPhotos, error) {
+	const query = `
+SELECT id, ad_id, content_type, size, blob_key, thumbnail_blob_key
+FROM ad_photo
+WHERE ad_id = ANY($1)
+ORDER BY ad_id, id`
+
+	adIds_ := make(pq.Int64Array, len(adIds))
+	for i, adId := range adIds {
+		adIds_[i] = int64(adId)
+	}
+
+	rows, err := adsRepository.db.Query(query, adIds_)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adPhotos := make(models.AdPhotos, 0)
+	for rows.Next() {
+		adPhoto := new(models.AdPhoto)
+		if err := rows.Scan(&adPhoto.Id, &adPhoto.AdId, &adPhoto.ContentType, &adPhoto.Size, &adPhoto.BlobKey,
+			&adPhoto.ThumbnailBlobKey); err != nil {
+			return nil, err
+		}
+
+		adPhotos = append(adPhotos, adPhoto)
+	}
+
+	return &adPhotos, nil
+}
+
+func (adsRepository *AdRepository) SelectAdRevisionArrayByAdId(adId uint32) (*models.AdRevisions, error) {
+	const query = `
+SELECT ad_revision.id, ad_revision.ad_id, ad_revision.user_id, user_.vk_id, ad_revision.action, ad_revision.date_time, ad_revision.diff
+FROM ad_revision
+LEFT JOIN user_ ON ad_revision.user_id = user_.id
+WHERE ad_revision.ad_id = $1
+ORDER BY ad_revision.id`
+
+	rows, err := adsRepository.db.Query(query, adId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adRevisions := make(models.AdRevisions, 0)
+	for rows.Next() {
+		adRevision := new(models.AdRevision)
+		var userId, userVkId sql.NullInt32
+		if err := rows.Scan(&adRevision.Id, &adRevision.AdId, &userId, &userVkId, &adRevision.Action,
+			&adRevision.DateTime, &adRevision.Diff); err != nil {
+			return nil, err
+		}
+		if userId.Valid {
+			adRevision.UserId = new(uint32)
+			*adRevision.UserId = uint32(userId.Int32)
+		}
+		if userVkId.Valid {
+			adRevision.UserVkId = new(uint32)
+			*adRevision.UserVkId = uint32(userVkId.Int32)
+		}
+
+		adRevisions = append(adRevisions, adRevision)
+	}
+
+	return &adRevisions, nil
+}
+
+func (adsRepository *AdRepository) InsertAdTemplate(adTemplate *models.AdTemplate) (*models.AdTemplate, error) {
+	const query = `
+INSERT INTO ad_template (user_author_id, loc_dep, loc_arr, item, min_price, comment, even_week, odd_week, days_of_week,
+                         time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING id, user_author_id, loc_dep, loc_arr, item, min_price, comment, even_week, odd_week, days_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, paused`
+
+	var daysOfWeek pq.Int64Array
+	if err := adsRepository.db.QueryRow(query, adTemplate.UserAuthorId, adTemplate.LocDep, adTemplate.LocArr,
+		adTemplate.Item, adTemplate.MinPrice, adTemplate.Comment, adTemplate.EvenWeek, adTemplate.OddWeek,
+		pq.Array(adTemplate.DaysOfWeek), (*time.Time)(adTemplate.TimeDep), time.Time(adTemplate.TimeArr),
+		adTemplate.LocDepPoint, adTemplate.LocArrPoint, adTemplate.LocDepPlaceId,
+		adTemplate.LocArrPlaceId).Scan(&adTemplate.Id, &adTemplate.UserAuthorId, &adTemplate.LocDep, &adTemplate.LocArr,
+		&adTemplate.Item, &adTemplate.MinPrice, &adTemplate.Comment, &adTemplate.EvenWeek, &adTemplate.OddWeek,
+		&daysOfWeek, &adTemplate.TimeDep, &adTemplate.TimeArr, &adTemplate.LocDepPoint, &adTemplate.LocArrPoint,
+		&adTemplate.LocDepPlaceId, &adTemplate.LocArrPlaceId, &adTemplate.Paused); err != nil {
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+	adTemplate.DaysOfWeek = newDaysOfWeek(daysOfWeek)
+
+	return adTemplate, nil
+}
+
+func (adsRepository *AdRepository) SelectAdTemplate(id uint32) (*models.AdTemplate, error) {
+	const query = `
+SELECT id, user_author_id, loc_dep, loc_arr, item, min_price, comment, even_week, odd_week, days_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, paused
+FROM ad_template
+WHERE id = $1`
+
+	adTemplate := new(models.AdTemplate)
+	var daysOfWeek pq.Int64Array
+	if err := adsRepository.db.QueryRow(query, id).Scan(&adTemplate.Id, &adTemplate.UserAuthorId, &adTemplate.LocDep,
+		&adTemplate.LocArr, &adTemplate.Item, &adTemplate.MinPrice, &adTemplate.Comment, &adTemplate.EvenWeek,
+		&adTemplate.OddWeek, &daysOfWeek, &adTemplate.TimeDep, &adTemplate.TimeArr, &adTemplate.LocDepPoint,
+		&adTemplate.LocArrPoint, &adTemplate.LocDepPlaceId, &adTemplate.LocArrPlaceId, &adTemplate.Paused); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+	adTemplate.DaysOfWeek = newDaysOfWeek(daysOfWeek)
+
+	return adTemplate, nil
+}
+
+func (adsRepository *AdRepository) SelectAdTemplateArrayByUserAuthorId(userAuthorId uint32) (*models.AdTemplates, error) {
+	const query = `
+SELECT id, user_author_id, loc_dep, loc_arr, item, min_price, comment, even_week, odd_week, days_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, paused
+FROM ad_template
+WHERE user_author_id = $1
+ORDER BY id`
+
+	rows, err := adsRepository.db.Query(query, userAuthorId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adTemplates := make(models.AdTemplates, 0)
+	for rows.Next() {
+		adTemplate := new(models.AdTemplate)
+		var daysOfWeek pq.Int64Array
+		if err := rows.Scan(&adTemplate.Id, &adTemplate.UserAuthorId, &adTemplate.LocDep, &adTemplate.LocArr,
+			&adTemplate.Item, &adTemplate.MinPrice, &adTemplate.Comment, &adTemplate.EvenWeek, &adTemplate.OddWeek,
+			&daysOfWeek, &adTemplate.TimeDep, &adTemplate.TimeArr, &adTemplate.LocDepPoint, &adTemplate.LocArrPoint,
+			&adTemplate.LocDepPlaceId, &adTemplate.LocArrPlaceId, &adTemplate.Paused); err != nil {
+			return nil, err
+		}
+		adTemplate.DaysOfWeek = newDaysOfWeek(daysOfWeek)
+
+		adTemplates = append(adTemplates, adTemplate)
+	}
+
+	return &adTemplates, nil
+}
+
+func (adsRepository *AdRepository) UpdateAdTemplatePaused(id uint32, paused bool) (*models.AdTemplate, error) {
+	const query = `
+UPDATE ad_template SET paused = $2
+WHERE id = $1
+RETURNING id, user_author_id, loc_dep, loc_arr, item, min_price, comment, even_week, odd_week, days_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, paused`
+
+	adTemplate := new(models.AdTemplate)
+	var daysOfWeek pq.Int64Array
+	if err := adsRepository.db.QueryRow(query, id, paused).Scan(&adTemplate.Id, &adTemplate.UserAuthorId,
+		&adTemplate.LocDep, &adTemplate.LocArr, &adTemplate.Item, &adTemplate.MinPrice, &adTemplate.Comment,
+		&adTemplate.EvenWeek, &adTemplate.OddWeek, &daysOfWeek, &adTemplate.TimeDep, &adTemplate.TimeArr,
+		&adTemplate.LocDepPoint, &adTemplate.LocArrPoint, &adTemplate.LocDepPlaceId, &adTemplate.LocArrPlaceId,
+		&adTemplate.Paused); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+	adTemplate.DaysOfWeek = newDaysOfWeek(daysOfWeek)
+
+	return adTemplate, nil
+}
+
+func (adsRepository *AdRepository) DeleteAdTemplate(id uint32) (*models.AdTemplate, error) {
+	const query = `
+DELETE FROM ad_template
+WHERE id = $1
+RETURNING id, user_author_id, loc_dep, loc_arr, item, min_price, comment, even_week, odd_week, days_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, paused`
+
+	adTemplate := new(models.AdTemplate)
+	var daysOfWeek pq.Int64Array
+	if err := adsRepository.db.QueryRow(query, id).Scan(&adTemplate.Id, &adTemplate.UserAuthorId, &adTemplate.LocDep,
+		&adTemplate.LocArr, &adTemplate.Item, &adTemplate.MinPrice, &adTemplate.Comment, &adTemplate.EvenWeek,
+		&adTemplate.OddWeek, &daysOfWeek, &adTemplate.TimeDep, &adTemplate.TimeArr, &adTemplate.LocDepPoint,
+		&adTemplate.LocArrPoint, &adTemplate.LocDepPlaceId, &adTemplate.LocArrPlaceId, &adTemplate.Paused); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+	adTemplate.DaysOfWeek = newDaysOfWeek(daysOfWeek)
+
+	return adTemplate, nil
+}
+
+func (adsRepository *AdRepository) InsertAdTemplateSkip(adTemplateId uint32, date time.Time) error {
+	//a skipped occurrence is just an occurrence without an ad, so the scheduler never materializes it
+	const query = `
+INSERT INTO ad_template_occurrence (ad_template_id, date)
+VALUES ($1, $2)`
+	const querySelect = `
+SELECT ad_id
+FROM ad_template_occurrence
+WHERE ad_template_id = $1 AND date = $2
+FOR UPDATE`
+	const queryUpdate = `
+UPDATE ad_template_occurrence SET ad_id = NULL
+WHERE ad_template_id = $1 AND date = $2`
+	const queryUpdateAd = `
+UPDATE ad SET status = 'cancelled'
+WHERE id = $1
+RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version`
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var adId sql.NullInt32
+	if err := tx.QueryRow(querySelect, adTemplateId, date).Scan(&adId); err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if _, err := tx.Exec(query, adTemplateId, date); err != nil {
+			if err_, ok := err.(*pq.Error); ok {
+				switch err_.Code {
+				case "23503":
+					return consts.RepErrNotFound
+				case "23505":
+					return consts.RepErrConflict
+				}
+			}
+
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	//the occurrence has already been skipped or its ad has been deleted
+	if !adId.Valid {
+		return consts.RepErrConflict
+	}
+
+	existingAd, err := selectAdForUpdate(tx, uint32(adId.Int32))
+	if err != nil {
+		return err
+	}
+
+	if existingAd.Status != models.AdStatusOpen {
+		return consts.RepErrConflict
+	}
+
+	ad_ := new(models.Ad)
+	var userExecutorVkId sql.NullInt32
+	if err := tx.QueryRow(queryUpdateAd, existingAd.Id).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId,
+		&ad_.UserAuthorName, &ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr,
+		&ad_.Item, &ad_.MinPrice, &ad_.Comment, &ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId,
+		&ad_.LocArrPlaceId, &ad_.DateTimeDep, &ad_.Version); err != nil {
+		return err
+	}
+	if userExecutorVkId.Valid {
+		ad_.UserExecutorVkId = new(uint32)
+		*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+	}
+
+	if err := insertAdRevision(tx, ad_.Id, &ad_.UserAuthorId, models.AdRevisionActionExecution, existingAd,
+		ad_); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(queryUpdate, adTemplateId, date); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (adsRepository *AdRepository) SelectAdTemplateOccurrenceArrayDue(minDateTimeArr time.Time,
+	maxDate time.Time) (*models.AdTemplateOccurrences, error) {
+	//even and odd weeks are told apart by the ISO week number
+	const query = `
+SELECT id, user_author_id, loc_dep, loc_arr, item, min_price, comment, even_week, odd_week, days_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, paused, day
+FROM ad_template
+    CROSS JOIN generate_series(date_trunc('day', $1::timestamp), $2::timestamp, interval '1 day') AS day
+WHERE NOT paused AND
+      extract(ISODOW FROM day) = ANY (days_of_week) AND
+      CASE WHEN extract(WEEK FROM day)::int % 2 = 0 THEN even_week ELSE odd_week END AND
+      day + time_arr::time > $1::timestamp AND
+      NOT EXISTS(SELECT FROM ad_template_occurrence
+                 WHERE ad_template_occurrence.ad_template_id = ad_template.id AND
+                       ad_template_occurrence.date = day::date)
+ORDER BY day, id`
+
+	rows, err := adsRepository.db.Query(query, minDateTimeArr, maxDate)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adTemplateOccurrences := make(models.AdTemplateOccurrences, 0)
+	for rows.Next() {
+		adTemplate := new(models.AdTemplate)
+		adTemplateOccurrence := &models.AdTemplateOccurrence{AdTemplate: adTemplate}
+		var daysOfWeek pq.Int64Array
+		if err := rows.Scan(&adTemplate.Id, &adTemplate.UserAuthorId, &adTemplate.LocDep, &adTemplate.LocArr,
+			&adTemplate.Item, &adTemplate.MinPrice, &adTemplate.Comment, &adTemplate.EvenWeek, &adTemplate.OddWeek,
+			&daysOfWeek, &adTemplate.TimeDep, &adTemplate.TimeArr, &adTemplate.LocDepPoint, &adTemplate.LocArrPoint,
+			&adTemplate.LocDepPlaceId, &adTemplate.LocArrPlaceId, &adTemplate.Paused,
+			&adTemplateOccurrence.Date); err != nil {
+			return nil, err
+		}
+		adTemplate.DaysOfWeek = newDaysOfWeek(daysOfWeek)
+
+		adTemplateOccurrences = append(adTemplateOccurrences, adTemplateOccurrence)
+	}
+
+	return &adTemplateOccurrences, nil
+}
+
+func (adsRepository *AdRepository) InsertByAdTemplateOccurrence(ad_ *models.Ad,
+	adTemplateOccurrence *models.AdTemplateOccurrence) (*models.Ad, error) {
+	const query = `
+INSERT INTO ad_template_occurrence (ad_template_id, date, ad_id)
+VALUES ($1, $2, $3)`
+
+	tx, err := adsRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := insertAd(tx, ad_); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(query, adTemplateOccurrence.AdTemplate.Id, time.Time(adTemplateOccurrence.Date),
+		ad_.Id); err != nil {
+		if err_, ok := err.(*pq.Error); ok {
+			switch err_.Code {
+			case "23503":
+				return nil, consts.RepErrNotFound
+			case "23505":
+				return nil, consts.RepErrConflict
+			}
+		}
+
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ad_, nil
+}
+
+func (adsRepository *AdRepository) SelectAdArrayNotificationPending(maxDateTime time.Time) (*models.Ads, error) {
+	const query = `
+SELECT ad.id, ad.user_author_id, ad.user_author_vk_id, ad.user_author_name, ad.user_author_avatar, ad.user_executor_vk_id, ad.loc_dep, ad.loc_arr, ad.date_time_arr, ad.item, ad.min_price, ad.comment, ad.status, ad.loc_dep_point, ad.loc_arr_point, ad.loc_dep_place_id, ad.loc_arr_place_id, ad.date_time_dep, ad.version
+FROM ad
+JOIN ad_notification_pending ON ad_notification_pending.ad_id = ad.id
+WHERE ad_notification_pending.date_time < $1
+ORDER BY ad_notification_pending.date_time`
+
+	rows, err := adsRepository.db.Query(query, maxDateTime)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	ads := make(models.Ads, 0)
+	for rows.Next() {
+		ad_ := new(models.Ad)
+		var userExecutorVkId sql.NullInt32
+		if err := rows.Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName, &ad_.UserAuthorAvatar,
+			&userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice, &ad_.Comment,
+			&ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId, &ad_.LocArrPlaceId,
+			&ad_.DateTimeDep, &ad_.Version); err != nil {
+			return nil, err
+		}
+		if userExecutorVkId.Valid {
+			ad_.UserExecutorVkId = new(uint32)
+			*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+		}
+
+		ads = append(ads, ad_)
+	}
+
+	return &ads, rows.Err()
+}
+
+func (adsRepository *AdRepository) DeleteAdNotificationPending(adId uint32) error {
+	const query = `
+DELETE FROM ad_notification_pending
+WHERE ad_id = $1`
+
+	_, err := adsRepository.db.Exec(query, adId)
+	return err
+}
+
+func insertAd(tx *sql.Tx, ad_ *models.Ad) error {
+	const queryNotificationPending = `
+INSERT INTO ad_notification_pending (ad_id)
+VALUES ($1)`
+	const query = `
+INSERT INTO ad (user_author_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, loc_dep_point, loc_arr_point,
+                loc_dep_place_id, loc_arr_place_id, date_time_dep)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version`
+
+	if err := tx.QueryRow(query, ad_.UserAuthorId, ad_.LocDep, ad_.LocArr, time.Time(ad_.DateTimeArr), ad_.Item,
+		ad_.MinPrice, ad_.Comment, ad_.LocDepPoint, ad_.LocArrPoint, ad_.LocDepPlaceId, ad_.LocArrPlaceId,
+		(*time.Time)(ad_.DateTimeDep)).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName,
+		&ad_.UserAuthorAvatar, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice, &ad_.Comment,
+		&ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId, &ad_.LocArrPlaceId,
+		&ad_.DateTimeDep, &ad_.Version); err != nil {
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return consts.RepErrNotFound
+		}
+
+		return err
+	}
+
+	if err := insertAdRevision(tx, ad_.Id, &ad_.UserAuthorId, models.AdRevisionActionInsert, nil, ad_); err != nil {
+		return err
+	}
+
+	//the marker is committed with the ad, so its notifications survive the process stopping right after the commit
+	_, err := tx.Exec(queryNotificationPending, ad_.Id)
+	return err
+}
+
+func selectAdForUpdate(tx *sql.Tx, id uint32) (*models.Ad, error) {
+	const query = `
+SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version
+FROM ad
+WHERE id = $1
+FOR UPDATE`
+
+	ad_ := new(models.Ad)
+	var userExecutorVkId sql.NullInt32
+	if err := tx.QueryRow(query, id).Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName,
+		&ad_.UserAuthorAvatar, &userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice,
+		&ad_.Comment, &ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId, &ad_.LocArrPlaceId,
+		&ad_.DateTimeDep, &ad_.Version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+	if userExecutorVkId.Valid {
+		ad_.UserExecutorVkId = new(uint32)
+		*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+	}
+
+	return ad_, nil
+}
+
+func selectAdArrayExpiredForUpdate(tx *sql.Tx, maxDateTimeArr time.Time) (models.Ads, error) {
+	const query = `
+SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version
+FROM ad
+WHERE status = 'open' AND date_time_arr < $1
+FOR UPDATE`
+
+	rows, err := tx.Query(query, maxDateTimeArr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	ads := make(models.Ads, 0)
+	for rows.Next() {
+		ad_ := new(models.Ad)
+		var userExecutorVkId sql.NullInt32
+		if err := rows.Scan(&ad_.Id, &ad_.UserAuthorId, &ad_.UserAuthorVkId, &ad_.UserAuthorName, &ad_.UserAuthorAvatar,
+			&userExecutorVkId, &ad_.LocDep, &ad_.LocArr, &ad_.DateTimeArr, &ad_.Item, &ad_.MinPrice, &ad_.Comment,
+			&ad_.Status, &ad_.LocDepPoint, &ad_.LocArrPoint, &ad_.LocDepPlaceId, &ad_.LocArrPlaceId,
+			&ad_.DateTimeDep, &ad_.Version); err != nil {
+			return nil, err
+		}
+		if userExecutorVkId.Valid {
+			ad_.UserExecutorVkId = new(uint32)
+			*ad_.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+		}
+
+		ads = append(ads, ad_)
+	}
+
+	return ads, rows.Err()
+}
+
+func insertAdExecutionRevision(tx *sql.Tx, existingAd *models.Ad, userId uint32) error {
+	ad_, err := selectAdForUpdate(tx, existingAd.Id)
+	if err != nil {
+		return err
+	}
+
+	return insertAdRevision(tx, ad_.Id, &userId, models.AdRevisionActionExecution, existingAd, ad_)
+}
+
+func insertAdRevision(tx *sql.Tx, adId uint32, userId *uint32, action models.AdRevisionAction, ad_ *models.Ad,
+	newAd *models.Ad) error {
+	const query = `
+INSERT INTO ad_revision (ad_id, user_id, action, diff)
+VALUES ($1, $2, $3, $4)`
+
+	diff, err := models.NewAdRevisionDiff(ad_, newAd)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, adId, userId, action, diff)
+	return err
+}
+
+func newDaysOfWeek(daysOfWeek pq.Int64Array) []uint32 {
+	daysOfWeek_ := make([]uint32, len(daysOfWeek))
+	for i, dayOfWeek := range daysOfWeek {
+		daysOfWeek_[i] = uint32(dayOfWeek)
+	}
+
+	return daysOfWeek_
+}
@@ -2,13 +2,16 @@ package repository_test
 
 import (
 	"database/sql"
+	"encoding/json"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/TechnoHandOver/backend/internal/ad/repository"
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
 	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/lib/pq"
 	"github.com/openlyinc/pointy"
 	"github.com/stretchr/testify/assert"
+	"regexp"
 	"testing"
 	"time"
 )
@@ -22,16 +25,21 @@ func TestAdRepository_Insert(t *testing.T) {
 
 	adRepository := repository.NewAdRepositoryImpl(db)
 
+	dateTimeDep, err := timestamps.NewDateTime("04.11.2021 17:00")
+	assert.Nil(t, err)
 	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
 	assert.Nil(t, err)
 	ad := &models.Ad{
 		UserAuthorId: 101,
 		LocDep:       "Общежитие №10",
 		LocArr:       "УЛК",
+		DateTimeDep:  dateTimeDep,
 		DateTimeArr:  *dateTimeArr,
 		Item:         "Зачётная книжка",
 		MinPrice:     500,
 		Comment:      "Поеду на велосипеде",
+		LocDepPoint:  &models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		LocArrPoint:  &models.GeoPoint{Lat: 55.765, Lon: 37.685},
 	}
 	expectedAd := &models.Ad{
 		Id:               1,
@@ -41,21 +49,38 @@ func TestAdRepository_Insert(t *testing.T) {
 		UserAuthorAvatar: "https://yandex.ru/logo.png",
 		LocDep:           ad.LocDep,
 		LocArr:           ad.LocArr,
+		DateTimeDep:      ad.DateTimeDep,
 		DateTimeArr:      ad.DateTimeArr,
 		Item:             ad.Item,
 		MinPrice:         ad.MinPrice,
 		Comment:          ad.Comment,
+		Status:           models.AdStatusOpen,
+		LocDepPoint:      ad.LocDepPoint,
+		LocArrPoint:      ad.LocArrPoint,
 	}
 
+	sqlmock_.ExpectBegin()
 	sqlmock_.
 		ExpectQuery("INSERT INTO ad").
-		WithArgs(ad.UserAuthorId, ad.LocDep, ad.LocArr, time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice, ad.Comment).
+		WithArgs(ad.UserAuthorId, ad.LocDep, ad.LocArr, time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice, ad.Comment,
+			ad.LocDepPoint, ad.LocArrPoint, ad.LocDepPlaceId, ad.LocArrPlaceId, (*time.Time)(ad.DateTimeDep)).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
-				"user_author_avatar", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price", "comment"}).
+				"user_author_avatar", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price", "comment", "status",
+				"loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"}).
 				AddRow(expectedAd.Id, ad.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
 					expectedAd.UserAuthorAvatar, ad.LocDep, ad.LocArr, time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice,
-					ad.Comment))
+					ad.Comment, expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint,
+					expectedAd.LocDepPlaceId, expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAd.Id, expectedAd.UserAuthorId, models.AdRevisionActionInsert, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_notification_pending").
+		WithArgs(expectedAd.Id).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
 
 	resultAd, resultErr := adRepository.Insert(ad)
 	assert.Nil(t, resultErr)
@@ -91,15 +116,18 @@ func TestAdRepository_Select(t *testing.T) {
 	}
 
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment FROM ad").
+		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version FROM ad").
 		WithArgs(expectedAd.Id).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
 				"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr",
-				"item", "min_price", "comment"}).
+				"item", "min_price", "comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id",
+				"loc_arr_place_id", "date_time_dep", "version"}).
 				AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
 					expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
-					time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment))
+					time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+					expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+					expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version))
 
 	resultAd, resultErr := adRepository.Select(expectedAd.Id)
 	assert.Nil(t, resultErr)
@@ -120,7 +148,7 @@ func TestAdRepository_Select_notFound(t *testing.T) {
 	const id uint32 = 1
 
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment FROM ad").
+		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version FROM ad").
 		WithArgs(id).
 		WillReturnError(sql.ErrNoRows)
 
@@ -164,19 +192,38 @@ func TestAdRepository_Update(t *testing.T) {
 		Item:             ad.Item,
 		MinPrice:         ad.MinPrice,
 		Comment:          ad.Comment,
+		Status:           models.AdStatusOpen,
 	}
 
+	existingAd := *expectedAd
+	existingAd.MinPrice = 400
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAd.Id).
+		WillReturnRows(newAdRows(&existingAd))
 	sqlmock_.
 		ExpectQuery("UPDATE ad").
 		WithArgs(expectedAd.Id, expectedAd.LocDep, expectedAd.LocArr, time.Time(expectedAd.DateTimeArr),
-			expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment).
+			expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment, expectedAd.LocDepPoint, expectedAd.LocArrPoint,
+			expectedAd.LocDepPlaceId, expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep),
+			expectedAd.Version).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
 				"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
-				"comment"}).
+				"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id",
+				"date_time_dep", "version"}).
 				AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
 					expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
-					time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment))
+					time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+					expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+					expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAd.Id, expectedAd.UserAuthorId, models.AdRevisionActionUpdate, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
 
 	resultAd, resultErr := adRepository.Update(expectedAd)
 	assert.Nil(t, resultErr)
@@ -206,10 +253,12 @@ func TestAdRepository_Update_notFound(t *testing.T) {
 		Comment:     "Поеду на велосипеде",
 	}
 
+	sqlmock_.ExpectBegin()
 	sqlmock_.
-		ExpectQuery("UPDATE ad").
-		WithArgs(ad.Id, ad.LocDep, ad.LocArr, time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice, ad.Comment).
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(ad.Id).
 		WillReturnError(sql.ErrNoRows)
+	sqlmock_.ExpectRollback()
 
 	resultAd, resultErr := adRepository.Update(ad)
 	assert.Equal(t, resultErr, consts.RepErrNotFound)
@@ -218,6 +267,220 @@ func TestAdRepository_Update_notFound(t *testing.T) {
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
+func TestAdRepository_Update_preconditionFailed(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:          1,
+		LocDep:      "Общежитие №10",
+		LocArr:      "УЛК",
+		DateTimeArr: *dateTimeArr,
+		Item:        "Зачётная книжка",
+		MinPrice:    500,
+		Comment:     "Поеду на велосипеде",
+		Status:      models.AdStatusOpen,
+		Version:     2,
+	}
+
+	existingAd := *ad
+	existingAd.Version = 3
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(ad.Id).
+		WillReturnRows(newAdRows(&existingAd))
+	sqlmock_.
+		ExpectQuery("UPDATE ad").
+		WithArgs(ad.Id, ad.LocDep, ad.LocArr, time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice, ad.Comment,
+			ad.LocDepPoint, ad.LocArrPoint, ad.LocDepPlaceId, ad.LocArrPlaceId, (*time.Time)(ad.DateTimeDep),
+			ad.Version).
+		WillReturnError(sql.ErrNoRows)
+	sqlmock_.ExpectRollback()
+
+	resultAd, resultErr := adRepository.Update(ad)
+	assert.Equal(t, resultErr, consts.RepErrPreconditionFailed)
+	assert.Nil(t, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_Update_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:          1,
+		LocDep:      "Общежитие №10",
+		LocArr:      "УЛК",
+		DateTimeArr: *dateTimeArr,
+		Item:        "Зачётная книжка",
+		MinPrice:    500,
+		Comment:     "Поеду на велосипеде",
+		Version:     2,
+	}
+
+	existingAd := *ad
+	existingAd.Status = models.AdStatusPickedUp
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(ad.Id).
+		WillReturnRows(newAdRows(&existingAd))
+	sqlmock_.ExpectRollback()
+
+	resultAd, resultErr := adRepository.Update(ad)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_Patch(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	adPatch := &models.AdPatch{
+		Id:                1,
+		UserAuthorId:      101,
+		Version:           2,
+		MinPrice:          pointy.Uint32(500),
+		LocDepPlaceIdNull: true,
+	}
+	expectedAd := &models.Ad{
+		Id:               adPatch.Id,
+		UserAuthorId:     adPatch.UserAuthorId,
+		UserAuthorVkId:   201,
+		UserAuthorName:   "Vasiliy Pupkin",
+		UserAuthorAvatar: "https://yandex.ru/logo.png",
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         *adPatch.MinPrice,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusOpen,
+	}
+
+	existingAd := *expectedAd
+	existingAd.MinPrice = 400
+	existingAd.LocDepPlaceId = pointy.Uint32(3)
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(adPatch.Id).
+		WillReturnRows(newAdRows(&existingAd))
+	sqlmock_.
+		ExpectQuery("UPDATE ad SET min_price = \\$3, loc_dep_place_id = \\$4 WHERE id = \\$1 AND \\(version = \\$2 OR \\$2 = 0\\)").
+		WithArgs(adPatch.Id, adPatch.Version, *adPatch.MinPrice, nil).
+		WillReturnRows(newAdRows(expectedAd))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAd.Id, adPatch.UserAuthorId, models.AdRevisionActionUpdate, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
+
+	resultAd, resultErr := adRepository.Patch(adPatch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAd, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_Patch_empty(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	adPatch := &models.AdPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		Version:      3,
+	}
+	expectedAd := &models.Ad{
+		Id:               adPatch.Id,
+		UserAuthorId:     adPatch.UserAuthorId,
+		UserAuthorVkId:   201,
+		UserAuthorName:   "Vasiliy Pupkin",
+		UserAuthorAvatar: "https://yandex.ru/logo.png",
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Version:          adPatch.Version,
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad").
+		WithArgs(adPatch.Id).
+		WillReturnRows(newAdRows(expectedAd))
+
+	resultAd, resultErr := adRepository.Patch(adPatch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAd, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_Patch_emptyPreconditionFailed(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adPatch := &models.AdPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		Version:      2,
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad").
+		WithArgs(adPatch.Id).
+		WillReturnRows(newAdRows(&models.Ad{Id: adPatch.Id, UserAuthorId: adPatch.UserAuthorId, Version: 3}))
+
+	resultAd, resultErr := adRepository.Patch(adPatch)
+	assert.Equal(t, consts.RepErrPreconditionFailed, resultErr)
+	assert.Nil(t, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
 func TestAdRepository_Delete(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
@@ -241,27 +504,41 @@ func TestAdRepository_Delete(t *testing.T) {
 		Item:             "Зачётная книжка",
 		MinPrice:         500,
 		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusOpen,
 	}
 
+	sqlmock_.ExpectBegin()
 	sqlmock_.
-		ExpectQuery("DELETE FROM ad").
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
 		WithArgs(expectedAd.Id).
+		WillReturnRows(newAdRows(expectedAd))
+	sqlmock_.
+		ExpectQuery("DELETE FROM ad").
+		WithArgs(expectedAd.Id, expectedAd.Version).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
 				"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
-				"comment"}).
+				"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id",
+				"date_time_dep", "version"}).
 				AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
 					expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
-					time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment))
+					time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+					expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+					expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAd.Id, expectedAd.UserAuthorId, models.AdRevisionActionDelete, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
 
-	resultAd, resultErr := adRepository.Delete(expectedAd.Id)
+	resultAd, resultErr := adRepository.Delete(expectedAd.Id, expectedAd.Version)
 	assert.Nil(t, resultErr)
 	assert.Equal(t, expectedAd, resultAd)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestAdRepository_Delete_notFound(t *testing.T) {
+func TestAdRepository_Delete_preconditionFailed(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -271,14 +548,48 @@ func TestAdRepository_Delete_notFound(t *testing.T) {
 	adRepository := repository.NewAdRepositoryImpl(db)
 
 	const id uint32 = 1
+	const version uint32 = 2
 
+	sqlmock_.ExpectBegin()
 	sqlmock_.
-		ExpectQuery("DELETE FROM ad").
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
 		WithArgs(id).
+		WillReturnRows(newAdRows(&models.Ad{Id: id, UserAuthorId: 101, Status: models.AdStatusOpen, Version: 3}))
+	sqlmock_.
+		ExpectQuery("DELETE FROM ad").
+		WithArgs(id, version).
 		WillReturnError(sql.ErrNoRows)
+	sqlmock_.ExpectRollback()
 
-	resultAd, resultErr := adRepository.Delete(id)
-	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	resultAd, resultErr := adRepository.Delete(id, version)
+	assert.Equal(t, resultErr, consts.RepErrPreconditionFailed)
+	assert.Nil(t, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_Delete_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const id uint32 = 1
+	const version uint32 = 2
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(id).
+		WillReturnRows(newAdRows(&models.Ad{Id: id, UserAuthorId: 101, Status: models.AdStatusPickedUp,
+			Version: version}))
+	sqlmock_.ExpectRollback()
+
+	resultAd, resultErr := adRepository.Delete(id, version)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
 	assert.Nil(t, resultAd)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
@@ -333,14 +644,16 @@ func TestAdRepository_SelectArray_1(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
 		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
-		"comment"})
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"})
 	for _, expectedAd := range *expectedAds {
 		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
 			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
-			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment)
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
 	}
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment FROM ad").
+		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version FROM ad").
 		WithArgs(adsSearch.UserAuthorId, adsSearch.LocDep, adsSearch.LocArr, time.Time(*adsSearch.MinDateTimeArr),
 			adsSearch.MaxPrice).
 		WillReturnRows(rows)
@@ -363,8 +676,10 @@ func TestAdRepository_SelectArray_2(t *testing.T) {
 
 	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
 	assert.Nil(t, err)
+	adsStatus := models.AdStatusOpen
 	adsSearch := &models.AdsSearch{
 		NotUserAuthorId: pointy.Uint32(101),
+		Status:          &adsStatus,
 		LocDep:          pointy.String("Общежитие"),
 		LocArr:          pointy.String("СК"),
 		MinDateTimeArr:  dateTimeArr,
@@ -383,6 +698,7 @@ func TestAdRepository_SelectArray_2(t *testing.T) {
 			Item:             "Тубус",
 			MinPrice:         500,
 			Comment:          "Поеду на коньках",
+			Status:           models.AdStatusOpen,
 		},
 		&models.Ad{
 			Id:               3,
@@ -396,21 +712,24 @@ func TestAdRepository_SelectArray_2(t *testing.T) {
 			Item:             "Спортивная форма",
 			MinPrice:         600,
 			Comment:          "Поеду на роликах :)",
+			Status:           models.AdStatusOpen,
 		},
 	}
 
 	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
 		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
-		"comment"})
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"})
 	for _, expectedAd := range *expectedAds {
 		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
 			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
-			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment)
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
 	}
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment FROM ad").
-		WithArgs(adsSearch.NotUserAuthorId, adsSearch.LocDep, adsSearch.LocArr, time.Time(*adsSearch.MinDateTimeArr),
-			adsSearch.MaxPrice).
+		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version FROM ad").
+		WithArgs(adsSearch.NotUserAuthorId, adsSearch.Status, adsSearch.LocDep, adsSearch.LocArr,
+			time.Time(*adsSearch.MinDateTimeArr), adsSearch.MaxPrice).
 		WillReturnRows(rows)
 
 	resultAds, resultErr := adRepository.SelectArray(adsSearch)
@@ -459,14 +778,16 @@ func TestAdRepository_SelectArray_emptySearchQuery(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
 		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
-		"comment"})
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"})
 	for _, expectedAd := range *expectedAds {
 		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
 			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
-			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment)
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
 	}
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment FROM ad").
+		ExpectQuery("SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, version FROM ad").
 		WillReturnRows(rows)
 
 	resultAds, resultErr := adRepository.SelectArray(adsSearch)
@@ -476,7 +797,7 @@ func TestAdRepository_SelectArray_emptySearchQuery(t *testing.T) {
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestAdRepository_InsertAdUserExecution(t *testing.T) {
+func TestAdRepository_SelectArray_cursor(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -485,26 +806,59 @@ func TestAdRepository_InsertAdUserExecution(t *testing.T) {
 
 	adRepository := repository.NewAdRepositoryImpl(db)
 
-	expectedAdUserExecution := &models.AdUserExecution{
-		AdId:           1,
-		UserExecutorId: 101,
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	order := models.AdsSearchOrderMinPriceAsc
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId: pointy.Uint32(101),
+		Order:           &order,
+		Cursor: &models.AdsSearchCursor{
+			Order:    order,
+			MinPrice: 500,
+			Id:       2,
+		},
+		Limit: pointy.Uint32(1),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     102,
+			UserAuthorVkId:   202,
+			UserAuthorName:   "Pupok Vasiliev",
+			UserAuthorAvatar: "https://yandex.ru/logo2.png",
+			LocDep:           "Общежитие №9",
+			LocArr:           "СК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Спортивная форма",
+			MinPrice:         500,
+			Comment:          "Поеду на роликах :)",
+			Status:           models.AdStatusOpen,
+		},
 	}
 
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"})
+	for _, expectedAd := range *expectedAds {
+		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
+			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
+	}
 	sqlmock_.
-		ExpectQuery("INSERT INTO ad_user_execution").
-		WithArgs(expectedAdUserExecution.AdId, expectedAdUserExecution.UserExecutorId).
-		WillReturnRows(
-			sqlmock.NewRows([]string{"ad_id", "user_executor_id"}).
-				AddRow(expectedAdUserExecution.AdId, expectedAdUserExecution.UserExecutorId))
+		ExpectQuery(regexp.QuoteMeta("WHERE NOT hidden AND user_author_id != $1 AND (min_price > $2 OR min_price = $2 AND id < $3) ORDER BY min_price, id DESC LIMIT $4")).
+		WithArgs(adsSearch.NotUserAuthorId, adsSearch.Cursor.MinPrice, adsSearch.Cursor.Id, adsSearch.Limit).
+		WillReturnRows(rows)
 
-	resultAdUserExecution, resultErr := adRepository.InsertAdUserExecution(expectedAdUserExecution)
+	resultAds, resultErr := adRepository.SelectArray(adsSearch)
 	assert.Nil(t, resultErr)
-	assert.Equal(t, expectedAdUserExecution, resultAdUserExecution)
+	assert.Equal(t, expectedAds, resultAds)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestAdRepository_SelectAdUserExecution(t *testing.T) {
+func TestAdRepository_SelectArray_near(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -513,26 +867,1319 @@ func TestAdRepository_SelectAdUserExecution(t *testing.T) {
 
 	adRepository := repository.NewAdRepositoryImpl(db)
 
-	expectedAdUserExecution := &models.AdUserExecution{
-		AdId:           1,
-		UserExecutorId: 101,
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	order := models.AdsSearchOrderMinPriceAsc
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId: pointy.Uint32(101),
+		NearDep:         &models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		RadiusM:         pointy.Uint32(500),
+		Order:           &order,
+		Limit:           pointy.Uint32(10),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     102,
+			UserAuthorVkId:   202,
+			UserAuthorName:   "Pupok Vasiliev",
+			UserAuthorAvatar: "https://yandex.ru/logo2.png",
+			LocDep:           "Общежитие №9",
+			LocArr:           "СК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Спортивная форма",
+			MinPrice:         500,
+			Comment:          "Поеду на роликах :)",
+			Status:           models.AdStatusOpen,
+			LocDepPoint:      &models.GeoPoint{Lat: 55.754, Lon: 37.683},
+		},
 	}
 
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"})
+	for _, expectedAd := range *expectedAds {
+		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
+			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
+	}
 	sqlmock_.
-		ExpectQuery("SELECT ad_id, user_executor_id FROM ad_user_execution").
-		WithArgs(expectedAdUserExecution.AdId).
-		WillReturnRows(
-			sqlmock.NewRows([]string{"ad_id", "user_executor_id"}).
-				AddRow(expectedAdUserExecution.AdId, expectedAdUserExecution.UserExecutorId))
+		ExpectQuery("WHERE NOT hidden AND user_author_id != \\$1 AND \\(2 \\* 6371000 \\* asin\\(.+loc_dep_point.+\\$2::point.+\\) <= \\$3 ORDER BY min_price, id DESC LIMIT \\$4").
+		WithArgs(adsSearch.NotUserAuthorId, adsSearch.NearDep, adsSearch.RadiusM, adsSearch.Limit).
+		WillReturnRows(rows)
 
-	resultAdUserExecution, resultErr := adRepository.SelectAdUserExecution(expectedAdUserExecution.AdId)
+	resultAds, resultErr := adRepository.SelectArray(adsSearch)
 	assert.Nil(t, resultErr)
-	assert.Equal(t, expectedAdUserExecution, resultAdUserExecution)
+	assert.Equal(t, expectedAds, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectArray_placeIds(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	order := models.AdsSearchOrderMinPriceAsc
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId: pointy.Uint32(101),
+		LocDepPlaceId:   pointy.Uint32(1),
+		LocArrPlaceId:   pointy.Uint32(2),
+		Order:           &order,
+		Limit:           pointy.Uint32(10),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     102,
+			UserAuthorVkId:   202,
+			UserAuthorName:   "Pupok Vasiliev",
+			UserAuthorAvatar: "https://yandex.ru/logo2.png",
+			LocDep:           "Общежитие №9",
+			LocArr:           "СК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Спортивная форма",
+			MinPrice:         500,
+			Comment:          "Поеду на роликах :)",
+			Status:           models.AdStatusOpen,
+			LocDepPoint:      &models.GeoPoint{Lat: 55.754, Lon: 37.683},
+			LocArrPoint:      &models.GeoPoint{Lat: 55.765, Lon: 37.685},
+			LocDepPlaceId:    adsSearch.LocDepPlaceId,
+			LocArrPlaceId:    adsSearch.LocArrPlaceId,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"})
+	for _, expectedAd := range *expectedAds {
+		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
+			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, *expectedAd.LocDepPlaceId,
+			*expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
+	}
+	sqlmock_.
+		ExpectQuery("WHERE NOT hidden AND user_author_id != \\$1 AND loc_dep_place_id = \\$2 AND loc_arr_place_id = \\$3 ORDER BY min_price, id DESC LIMIT \\$4").
+		WithArgs(adsSearch.NotUserAuthorId, adsSearch.LocDepPlaceId, adsSearch.LocArrPlaceId, adsSearch.Limit).
+		WillReturnRows(rows)
+
+	resultAds, resultErr := adRepository.SelectArray(adsSearch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAds, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectArray_userExecutorId(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	order := models.AdsSearchOrderDateTimeArrAsc
+	adsSearch := &models.AdsSearch{
+		UserExecutorId: pointy.Uint32(102),
+		Active:         pointy.Bool(true),
+		Order:          &order,
+		Limit:          pointy.Uint32(10),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     101,
+			UserAuthorVkId:   201,
+			UserAuthorName:   "Vasiliy Pupkin",
+			UserAuthorAvatar: "https://yandex.ru/logo.png",
+			UserExecutorVkId: pointy.Uint32(202),
+			LocDep:           "Общежитие №10",
+			LocArr:           "УЛК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Зачётная книжка",
+			MinPrice:         500,
+			Comment:          "Поеду на велосипеде",
+			Status:           models.AdStatusPickedUp,
+			Version:          3,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep",
+		"version"})
+	for _, expectedAd := range *expectedAds {
+		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
+			expectedAd.UserAuthorAvatar, *expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
+	}
+	sqlmock_.
+		ExpectQuery("FROM ad JOIN ad_user_execution ON ad_user_execution.ad_id = ad.id WHERE NOT hidden AND ad_user_execution.user_executor_id = \\$1 AND status IN \\('assigned', 'picked_up', 'delivered'\\) ORDER BY date_time_arr, id DESC LIMIT \\$2").
+		WithArgs(adsSearch.UserExecutorId, adsSearch.Limit).
+		WillReturnRows(rows)
+
+	resultAds, resultErr := adRepository.SelectArray(adsSearch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAds, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectArray_minUserAuthorRating(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	adsStatus := models.AdStatusOpen
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId:     pointy.Uint32(102),
+		Status:              &adsStatus,
+		MinUserAuthorRating: pointy.Float32(4),
+		Limit:               pointy.Uint32(10),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     101,
+			UserAuthorVkId:   201,
+			UserAuthorName:   "Vasiliy Pupkin",
+			UserAuthorAvatar: "https://yandex.ru/logo.png",
+			LocDep:           "Общежитие №10",
+			LocArr:           "УЛК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Зачётная книжка",
+			MinPrice:         500,
+			Comment:          "Поеду на велосипеде",
+			Status:           models.AdStatusOpen,
+			Version:          1,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep",
+		"version"})
+	for _, expectedAd := range *expectedAds {
+		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
+			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
+	}
+	sqlmock_.
+		ExpectQuery("WHERE NOT hidden AND user_author_id != \\$1 AND status = \\$2 AND user_author_id IN \\(SELECT user_.id FROM user_ WHERE user_.rating >= \\$3\\) ORDER BY date_time_arr DESC, id DESC LIMIT \\$4").
+		WithArgs(adsSearch.NotUserAuthorId, adsSearch.Status, adsSearch.MinUserAuthorRating, adsSearch.Limit).
+		WillReturnRows(rows)
+
+	resultAds, resultErr := adRepository.SelectArray(adsSearch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAds, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectArray_notBlockedUserId(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	adsStatus := models.AdStatusOpen
+	adsSearch := &models.AdsSearch{
+		NotUserAuthorId:  pointy.Uint32(102),
+		NotBlockedUserId: pointy.Uint32(102),
+		Status:           &adsStatus,
+		Limit:            pointy.Uint32(10),
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     101,
+			UserAuthorVkId:   201,
+			UserAuthorName:   "Vasiliy Pupkin",
+			UserAuthorAvatar: "https://yandex.ru/logo.png",
+			LocDep:           "Общежитие №10",
+			LocArr:           "УЛК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Зачётная книжка",
+			MinPrice:         500,
+			Comment:          "Поеду на велосипеде",
+			Status:           models.AdStatusOpen,
+			Version:          1,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep",
+		"version"})
+	for _, expectedAd := range *expectedAds {
+		rows.AddRow(expectedAd.Id, expectedAd.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
+			expectedAd.UserAuthorAvatar, expectedAd.UserExecutorVkId, expectedAd.LocDep, expectedAd.LocArr,
+			time.Time(expectedAd.DateTimeArr), expectedAd.Item, expectedAd.MinPrice, expectedAd.Comment,
+			expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint, expectedAd.LocDepPlaceId,
+			expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version)
+	}
+	sqlmock_.
+		ExpectQuery("WHERE NOT hidden AND user_author_id != \\$1 AND NOT user_blocked\\(\\$2, ad.user_author_id\\) AND status = \\$3 ORDER BY date_time_arr DESC, id DESC LIMIT \\$4").
+		WithArgs(adsSearch.NotUserAuthorId, adsSearch.NotBlockedUserId, adsSearch.Status, adsSearch.Limit).
+		WillReturnRows(rows)
+
+	resultAds, resultErr := adRepository.SelectArray(adsSearch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAds, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateStatus(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	expectedAd := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserAuthorName:   "Vasiliy Pupkin",
+		UserAuthorAvatar: "https://yandex.ru/logo.png",
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusPickedUp,
+	}
+
+	const userId uint32 = 102
+	existingAd := *expectedAd
+	existingAd.Status = models.AdStatusAssigned
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAd.Id).
+		WillReturnRows(newAdRows(&existingAd))
+	sqlmock_.
+		ExpectQuery("UPDATE ad SET status").
+		WithArgs(expectedAd.Id, models.AdStatusAssigned, expectedAd.Status).
+		WillReturnRows(newAdRows(expectedAd))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAd.Id, userId, models.AdRevisionActionExecution,
+			`{"status":{"old":"assigned","new":"picked_up"}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
+
+	resultAd, resultErr := adRepository.UpdateStatus(expectedAd.Id, models.AdStatusAssigned, expectedAd.Status,
+		userId)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAd, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateStatus_notFound(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const id uint32 = 1
+	const userId uint32 = 102
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(id).
+		WillReturnRows(newAdRows(&models.Ad{Id: id, Status: models.AdStatusOpen}))
+	sqlmock_.
+		ExpectQuery("UPDATE ad SET status").
+		WithArgs(id, models.AdStatusAssigned, models.AdStatusPickedUp).
+		WillReturnError(sql.ErrNoRows)
+	sqlmock_.ExpectRollback()
+
+	resultAd, resultErr := adRepository.UpdateStatus(id, models.AdStatusAssigned, models.AdStatusPickedUp, userId)
+	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	assert.Nil(t, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateStatusExpired(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	maxDateTimeArr := time.Time(*dateTimeArr).Add(time.Hour)
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:               1,
+			UserAuthorId:     101,
+			UserAuthorVkId:   201,
+			UserAuthorName:   "Tim Cook",
+			UserAuthorAvatar: "https://yandex.ru/logo.png",
+			LocDep:           "Общежитие №10",
+			LocArr:           "УЛК",
+			DateTimeArr:      *dateTimeArr,
+			Item:             "Тубус",
+			MinPrice:         500,
+			Comment:          "Поеду на коньках",
+			Status:           models.AdStatusExpired,
+		},
+	}
+
+	existingAd := *(*expectedAds)[0]
+	existingAd.Status = models.AdStatusOpen
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE status = 'open' AND date_time_arr < (.+) FOR UPDATE").
+		WithArgs(maxDateTimeArr).
+		WillReturnRows(newAdRows(&existingAd))
+	sqlmock_.
+		ExpectQuery("UPDATE ad SET status = 'expired'").
+		WithArgs(pq.Array([]int64{int64(existingAd.Id)})).
+		WillReturnRows(newAdRows((*expectedAds)[0]))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(existingAd.Id, nil, models.AdRevisionActionExecution, `{"status":{"old":"open","new":"expired"}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
+
+	resultAds, resultErr := adRepository.UpdateStatusExpired(maxDateTimeArr)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAds, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateStatusExpired_empty(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	maxDateTimeArr := time.Date(2021, 11, 4, 20, 40, 0, 0, time.UTC)
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE status = 'open' AND date_time_arr < (.+) FOR UPDATE").
+		WithArgs(maxDateTimeArr).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	sqlmock_.ExpectRollback()
+
+	resultAds, resultErr := adRepository.UpdateStatusExpired(maxDateTimeArr)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, &models.Ads{}, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdUserExecution(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	expectedAdUserExecution := &models.AdUserExecution{
+		AdId:           1,
+		UserExecutorId: 101,
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT ad_id, user_executor_id FROM ad_user_execution").
+		WithArgs(expectedAdUserExecution.AdId).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"ad_id", "user_executor_id"}).
+				AddRow(expectedAdUserExecution.AdId, expectedAdUserExecution.UserExecutorId))
+
+	resultAdUserExecution, resultErr := adRepository.SelectAdUserExecution(expectedAdUserExecution.AdId)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdUserExecution, resultAdUserExecution)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdUserExecution_notFound(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const adId uint32 = 1
+
+	sqlmock_.
+		ExpectQuery("SELECT ad_id, user_executor_id FROM ad_user_execution").
+		WithArgs(adId).
+		WillReturnError(sql.ErrNoRows)
+
+	resultAdUserExecution, resultErr := adRepository.SelectAdUserExecution(adId)
+	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	assert.Nil(t, resultAdUserExecution)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdHandover(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	expectedAdHandover := &models.AdHandover{
+		AdId:           1,
+		Code:           "042519",
+		FailedAttempts: 2,
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT ad_id, code, failed_attempts, locked_until FROM ad_handover").
+		WithArgs(expectedAdHandover.AdId).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"ad_id", "code", "failed_attempts", "locked_until"}).
+				AddRow(expectedAdHandover.AdId, expectedAdHandover.Code, expectedAdHandover.FailedAttempts, nil))
+
+	resultAdHandover, resultErr := adRepository.SelectAdHandover(expectedAdHandover.AdId)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdHandover, resultAdHandover)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateAdHandoverAttempt(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const adId uint32 = 1
+	const code = "042519"
+	now := time.Now()
+
+	sqlmock_.
+		ExpectQuery("UPDATE ad_handover SET failed_attempts = .+ WHERE ad_id = \\$1 AND \\(locked_until IS NULL OR locked_until <= \\$5\\) RETURNING code = \\$2").
+		WithArgs(adId, code, models.AdHandoverMaxFailedAttempts, now.Add(models.AdHandoverLockout), now).
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(true))
+
+	resultMatched, resultErr := adRepository.UpdateAdHandoverAttempt(adId, code, now)
+	assert.Nil(t, resultErr)
+	assert.True(t, resultMatched)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateAdHandoverAttempt_locked(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const adId uint32 = 1
+	const code = "042519"
+	now := time.Now()
+
+	sqlmock_.
+		ExpectQuery("UPDATE ad_handover").
+		WithArgs(adId, code, models.AdHandoverMaxFailedAttempts, now.Add(models.AdHandoverLockout), now).
+		WillReturnError(sql.ErrNoRows)
+
+	resultMatched, resultErr := adRepository.UpdateAdHandoverAttempt(adId, code, now)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+	assert.False(t, resultMatched)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_DeleteAdUserExecution(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	expectedAdUserExecution := &models.AdUserExecution{
+		AdId:           1,
+		UserExecutorId: 102,
+	}
+	existingAd := &models.Ad{
+		Id:               expectedAdUserExecution.AdId,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		Status:           models.AdStatusAssigned,
+	}
+	ad := *existingAd
+	ad.UserExecutorVkId = nil
+	ad.Status = models.AdStatusOpen
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAdUserExecution.AdId).
+		WillReturnRows(newAdRows(existingAd))
+	sqlmock_.
+		ExpectQuery("DELETE FROM ad_user_execution").
+		WithArgs(expectedAdUserExecution.AdId).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"ad_id", "user_executor_id"}).
+				AddRow(expectedAdUserExecution.AdId, expectedAdUserExecution.UserExecutorId))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAdUserExecution.AdId).
+		WillReturnRows(newAdRows(&ad))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAdUserExecution.AdId, expectedAdUserExecution.UserExecutorId,
+			models.AdRevisionActionExecution,
+			`{"status":{"old":"assigned","new":"open"},"userExecutorVkId":{"old":202,"new":null}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
+
+	resultAdUserExecution, resultErr := adRepository.DeleteAdUserExecution(expectedAdUserExecution.AdId)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdUserExecution, resultAdUserExecution)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_DeleteAdUserExecution_notFound(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const adId uint32 = 1
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(adId).
+		WillReturnRows(newAdRows(&models.Ad{Id: adId, Status: models.AdStatusOpen}))
+	sqlmock_.
+		ExpectQuery("DELETE FROM ad_user_execution").
+		WithArgs(adId).
+		WillReturnError(sql.ErrNoRows)
+	sqlmock_.ExpectRollback()
+
+	resultAdUserExecution, resultErr := adRepository.DeleteAdUserExecution(adId)
+	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	assert.Nil(t, resultAdUserExecution)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdOffer(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adOffer := &models.AdOffer{
+		AdId:           1,
+		UserExecutorId: 102,
+		Price:          450,
+		Comment:        "Могу через час",
+	}
+	expectedAdOffer := &models.AdOffer{
+		Id:                 1,
+		AdId:               adOffer.AdId,
+		UserExecutorId:     adOffer.UserExecutorId,
+		UserExecutorVkId:   202,
+		UserExecutorName:   "Pupok Vasiliev",
+		UserExecutorAvatar: "https://yandex.ru/logo2.png",
+		UserExecutorRating: 4.5,
+		Price:              adOffer.Price,
+		Comment:            adOffer.Comment,
+		Status:             models.AdOfferStatusPending,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_offer").
+		WithArgs(adOffer.AdId, adOffer.UserExecutorId, adOffer.Price, adOffer.Comment).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ad_id", "user_executor_id", "user_executor_vk_id", "user_executor_name",
+				"user_executor_avatar", "user_executor_rating", "price", "comment", "status"}).
+				AddRow(expectedAdOffer.Id, expectedAdOffer.AdId, expectedAdOffer.UserExecutorId,
+					expectedAdOffer.UserExecutorVkId, expectedAdOffer.UserExecutorName,
+					expectedAdOffer.UserExecutorAvatar, expectedAdOffer.UserExecutorRating, expectedAdOffer.Price,
+					expectedAdOffer.Comment, expectedAdOffer.Status))
+
+	resultAdOffer, resultErr := adRepository.InsertAdOffer(adOffer)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdOffer, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdOffer_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adOffer := &models.AdOffer{
+		AdId:           1,
+		UserExecutorId: 102,
+		Price:          450,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_offer").
+		WithArgs(adOffer.AdId, adOffer.UserExecutorId, adOffer.Price, adOffer.Comment).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	resultAdOffer, resultErr := adRepository.InsertAdOffer(adOffer)
+	assert.Equal(t, resultErr, consts.RepErrConflict)
+	assert.Nil(t, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdOfferArray(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adOffersSearch := &models.AdOffersSearch{
+		AdId: 1,
+	}
+	expectedAdOffers := &models.AdOffers{
+		&models.AdOffer{
+			Id:                 1,
+			AdId:               adOffersSearch.AdId,
+			UserExecutorId:     102,
+			UserExecutorVkId:   202,
+			UserExecutorName:   "Pupok Vasiliev",
+			UserExecutorAvatar: "https://yandex.ru/logo2.png",
+			UserExecutorRating: 4.5,
+			Price:              450,
+			Comment:            "Могу через час",
+			Status:             models.AdOfferStatusPending,
+		},
+		&models.AdOffer{
+			Id:                 2,
+			AdId:               adOffersSearch.AdId,
+			UserExecutorId:     103,
+			UserExecutorVkId:   203,
+			UserExecutorName:   "Vasiliy Pupkin",
+			UserExecutorAvatar: "https://yandex.ru/logo.png",
+			Price:              500,
+			Status:             models.AdOfferStatusRejected,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "ad_id", "user_executor_id", "user_executor_vk_id", "user_executor_name",
+		"user_executor_avatar", "user_executor_rating", "price", "comment", "status"})
+	for _, expectedAdOffer := range *expectedAdOffers {
+		rows.AddRow(expectedAdOffer.Id, expectedAdOffer.AdId, expectedAdOffer.UserExecutorId,
+			expectedAdOffer.UserExecutorVkId, expectedAdOffer.UserExecutorName, expectedAdOffer.UserExecutorAvatar,
+			expectedAdOffer.UserExecutorRating, expectedAdOffer.Price, expectedAdOffer.Comment, expectedAdOffer.Status)
+	}
+	sqlmock_.
+		ExpectQuery("SELECT id, ad_id, user_executor_id, user_executor_vk_id, user_executor_name, user_executor_avatar, user_executor_rating, price, comment, status FROM ad_offer WHERE ad_id = \\$1 ORDER BY price, id").
+		WithArgs(adOffersSearch.AdId).
+		WillReturnRows(rows)
+
+	resultAdOffers, resultErr := adRepository.SelectAdOfferArray(adOffersSearch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdOffers, resultAdOffers)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdOfferArray_minRating(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	order := models.AdOffersSearchOrderRatingDesc
+	adOffersSearch := &models.AdOffersSearch{
+		AdId:                  1,
+		MinUserExecutorRating: pointy.Float32(4),
+		Order:                 &order,
+	}
+	expectedAdOffers := &models.AdOffers{
+		&models.AdOffer{
+			Id:                 1,
+			AdId:               adOffersSearch.AdId,
+			UserExecutorId:     102,
+			UserExecutorVkId:   202,
+			UserExecutorName:   "Pupok Vasiliev",
+			UserExecutorAvatar: "https://yandex.ru/logo2.png",
+			UserExecutorRating: 4.5,
+			Price:              450,
+			Comment:            "Могу через час",
+			Status:             models.AdOfferStatusPending,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "ad_id", "user_executor_id", "user_executor_vk_id", "user_executor_name",
+		"user_executor_avatar", "user_executor_rating", "price", "comment", "status"})
+	for _, expectedAdOffer := range *expectedAdOffers {
+		rows.AddRow(expectedAdOffer.Id, expectedAdOffer.AdId, expectedAdOffer.UserExecutorId,
+			expectedAdOffer.UserExecutorVkId, expectedAdOffer.UserExecutorName, expectedAdOffer.UserExecutorAvatar,
+			expectedAdOffer.UserExecutorRating, expectedAdOffer.Price, expectedAdOffer.Comment, expectedAdOffer.Status)
+	}
+	sqlmock_.
+		ExpectQuery("FROM ad_offer WHERE ad_id = \\$1 AND user_executor_rating >= \\$2 ORDER BY user_executor_rating DESC, price, id").
+		WithArgs(adOffersSearch.AdId, *adOffersSearch.MinUserExecutorRating).
+		WillReturnRows(rows)
+
+	resultAdOffers, resultErr := adRepository.SelectAdOfferArray(adOffersSearch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdOffers, resultAdOffers)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdReview(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adReview := &models.AdReview{
+		AdId:         1,
+		UserAuthorId: 101,
+		UserTargetId: 102,
+		Rating:       5,
+		Comment:      "Доставил вовремя",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedAdReview := &models.AdReview{
+		Id:           1,
+		AdId:         adReview.AdId,
+		UserAuthorId: adReview.UserAuthorId,
+		UserTargetId: adReview.UserTargetId,
+		Rating:       adReview.Rating,
+		Comment:      adReview.Comment,
+		DateTime:     *dateTime,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_review").
+		WithArgs(adReview.AdId, adReview.UserAuthorId, adReview.UserTargetId, adReview.Rating, adReview.Comment).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ad_id", "user_author_id", "user_target_id", "rating", "comment",
+				"date_time"}).
+				AddRow(expectedAdReview.Id, expectedAdReview.AdId, expectedAdReview.UserAuthorId,
+					expectedAdReview.UserTargetId, expectedAdReview.Rating, expectedAdReview.Comment,
+					time.Time(expectedAdReview.DateTime)))
+
+	resultAdReview, resultErr := adRepository.InsertAdReview(adReview)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdReview, resultAdReview)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdReview_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adReview := &models.AdReview{
+		AdId:         1,
+		UserAuthorId: 101,
+		UserTargetId: 102,
+		Rating:       5,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_review").
+		WithArgs(adReview.AdId, adReview.UserAuthorId, adReview.UserTargetId, adReview.Rating, adReview.Comment).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	resultAdReview, resultErr := adRepository.InsertAdReview(adReview)
+	assert.Equal(t, resultErr, consts.RepErrConflict)
+	assert.Nil(t, resultAdReview)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateAdOfferStatus_accepted(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	expectedAdOffer := &models.AdOffer{
+		Id:                 1,
+		AdId:               11,
+		UserExecutorId:     102,
+		UserExecutorVkId:   202,
+		UserExecutorName:   "Tim Cook",
+		UserExecutorAvatar: "https://yandex.ru/logo.png",
+		Price:              500,
+		Status:             models.AdOfferStatusAccepted,
+	}
+	existingAd := &models.Ad{
+		Id:             expectedAdOffer.AdId,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
+	}
+	ad := *existingAd
+	ad.UserExecutorVkId = pointy.Uint32(expectedAdOffer.UserExecutorVkId)
+	ad.Status = models.AdStatusAssigned
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT ad_id FROM ad_offer").
+		WithArgs(expectedAdOffer.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"ad_id"}).AddRow(expectedAdOffer.AdId))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAdOffer.AdId).
+		WillReturnRows(newAdRows(existingAd))
+	sqlmock_.
+		ExpectQuery("UPDATE ad_offer SET status").
+		WithArgs(expectedAdOffer.Id, models.AdOfferStatusPending, models.AdOfferStatusAccepted).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ad_id", "user_executor_id", "user_executor_vk_id", "user_executor_name",
+				"user_executor_avatar", "user_executor_rating", "price", "comment", "status"}).
+				AddRow(expectedAdOffer.Id, expectedAdOffer.AdId, expectedAdOffer.UserExecutorId,
+					expectedAdOffer.UserExecutorVkId, expectedAdOffer.UserExecutorName,
+					expectedAdOffer.UserExecutorAvatar, expectedAdOffer.UserExecutorRating, expectedAdOffer.Price,
+					expectedAdOffer.Comment, expectedAdOffer.Status))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAdOffer.AdId).
+		WillReturnRows(newAdRows(&ad))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAdOffer.AdId, existingAd.UserAuthorId, models.AdRevisionActionExecution,
+			`{"status":{"old":"open","new":"assigned"},"userExecutorVkId":{"old":null,"new":202}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
+
+	resultAdOffer, resultErr := adRepository.UpdateAdOfferStatus(expectedAdOffer.Id, models.AdOfferStatusPending,
+		models.AdOfferStatusAccepted)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdOffer, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateAdOfferStatus_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const id uint32 = 1
+
+	const adId uint32 = 11
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT ad_id FROM ad_offer").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"ad_id"}).AddRow(adId))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(adId).
+		WillReturnRows(newAdRows(&models.Ad{Id: adId, Status: models.AdStatusOpen}))
+	sqlmock_.
+		ExpectQuery("UPDATE ad_offer SET status").
+		WithArgs(id, models.AdOfferStatusPending, models.AdOfferStatusAccepted).
+		WillReturnError(&pq.Error{Code: "23505"})
+	sqlmock_.ExpectRollback()
+
+	resultAdOffer, resultErr := adRepository.UpdateAdOfferStatus(id, models.AdOfferStatusPending,
+		models.AdOfferStatusAccepted)
+	assert.Equal(t, resultErr, consts.RepErrConflict)
+	assert.Nil(t, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_UpdateAdOfferStatus_notOpen(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	const id uint32 = 1
+
+	const adId uint32 = 11
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT ad_id FROM ad_offer").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"ad_id"}).AddRow(adId))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(adId).
+		WillReturnRows(newAdRows(&models.Ad{Id: adId, Status: models.AdStatusCancelled}))
+	sqlmock_.ExpectRollback()
+
+	resultAdOffer, resultErr := adRepository.UpdateAdOfferStatus(id, models.AdOfferStatusPending,
+		models.AdOfferStatusAccepted)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdOfferAccepted(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	expectedAdOffer := &models.AdOffer{
+		Id:                 1,
+		AdId:               11,
+		UserExecutorId:     102,
+		UserExecutorVkId:   202,
+		UserExecutorName:   "Tim Cook",
+		UserExecutorAvatar: "https://yandex.ru/logo.png",
+		Price:              500,
+		Status:             models.AdOfferStatusAccepted,
+	}
+	adOffer := &models.AdOffer{
+		AdId:           expectedAdOffer.AdId,
+		UserExecutorId: expectedAdOffer.UserExecutorId,
+		Price:          expectedAdOffer.Price,
+	}
+	existingAd := &models.Ad{
+		Id:             expectedAdOffer.AdId,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		MinPrice:       expectedAdOffer.Price,
+		Status:         models.AdStatusOpen,
+	}
+	ad := *existingAd
+	ad.UserExecutorVkId = pointy.Uint32(expectedAdOffer.UserExecutorVkId)
+	ad.Status = models.AdStatusAssigned
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAdOffer.AdId).
+		WillReturnRows(newAdRows(existingAd))
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_offer").
+		WithArgs(adOffer.AdId, adOffer.UserExecutorId, adOffer.Price, adOffer.Comment).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedAdOffer.Id))
+	sqlmock_.
+		ExpectQuery("UPDATE ad_offer SET status").
+		WithArgs(expectedAdOffer.Id, models.AdOfferStatusAccepted).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ad_id", "user_executor_id", "user_executor_vk_id", "user_executor_name",
+				"user_executor_avatar", "user_executor_rating", "price", "comment", "status"}).
+				AddRow(expectedAdOffer.Id, expectedAdOffer.AdId, expectedAdOffer.UserExecutorId,
+					expectedAdOffer.UserExecutorVkId, expectedAdOffer.UserExecutorName,
+					expectedAdOffer.UserExecutorAvatar, expectedAdOffer.UserExecutorRating, expectedAdOffer.Price,
+					expectedAdOffer.Comment, expectedAdOffer.Status))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(expectedAdOffer.AdId).
+		WillReturnRows(newAdRows(&ad))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAdOffer.AdId, expectedAdOffer.UserExecutorId, models.AdRevisionActionExecution,
+			`{"status":{"old":"open","new":"assigned"},"userExecutorVkId":{"old":null,"new":202}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
+
+	resultAdOffer, resultErr := adRepository.InsertAdOfferAccepted(adOffer)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdOffer, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdOfferAccepted_notOpen(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adOffer := &models.AdOffer{
+		AdId:           11,
+		UserExecutorId: 102,
+		Price:          500,
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(adOffer.AdId).
+		WillReturnRows(newAdRows(&models.Ad{Id: adOffer.AdId, UserAuthorId: 101, Status: models.AdStatusAssigned}))
+	sqlmock_.ExpectRollback()
+
+	resultAdOffer, resultErr := adRepository.InsertAdOfferAccepted(adOffer)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdOfferAccepted_blocked(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adOffer := &models.AdOffer{
+		AdId:           11,
+		UserExecutorId: 102,
+		Price:          500,
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(adOffer.AdId).
+		WillReturnRows(newAdRows(&models.Ad{Id: adOffer.AdId, UserAuthorId: 101, Status: models.AdStatusOpen}))
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_offer").
+		WithArgs(adOffer.AdId, adOffer.UserExecutorId, adOffer.Price, adOffer.Comment).
+		WillReturnError(&pq.Error{Code: "42501"})
+	sqlmock_.ExpectRollback()
+
+	resultAdOffer, resultErr := adRepository.InsertAdOfferAccepted(adOffer)
+	assert.Equal(t, consts.RepErrForbidden, resultErr)
+	assert.Nil(t, resultAdOffer)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdPhotoArray(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adPhoto := &models.AdPhoto{
+		AdId:             1,
+		ContentType:      "image/png",
+		Size:             1024,
+		BlobKey:          "ads/1/photo",
+		ThumbnailBlobKey: "ads/1/photo_thumbnail",
+	}
+	expectedAdPhotos := &models.AdPhotos{
+		&models.AdPhoto{
+			Id:               1,
+			AdId:             adPhoto.AdId,
+			ContentType:      adPhoto.ContentType,
+			Size:             adPhoto.Size,
+			BlobKey:          adPhoto.BlobKey,
+			ThumbnailBlobKey: adPhoto.ThumbnailBlobKey,
+		},
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(adPhoto.AdId).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(models.AdPhotosMaxCount - 1))
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_photo").
+		WithArgs(adPhoto.AdId, adPhoto.ContentType, adPhoto.Size, adPhoto.BlobKey, adPhoto.ThumbnailBlobKey).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "ad_id", "content_type", "size", "blob_key", "thumbnail_blob_key"}).
+				AddRow((*expectedAdPhotos)[0].Id, adPhoto.AdId, adPhoto.ContentType, adPhoto.Size, adPhoto.BlobKey,
+					adPhoto.ThumbnailBlobKey))
+	sqlmock_.ExpectCommit()
+
+	resultAdPhotos, resultErr := adRepository.InsertAdPhotoArray(&models.AdPhotos{adPhoto})
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdPhotos, resultAdPhotos)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdPhotoArray_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adPhotos := &models.AdPhotos{
+		&models.AdPhoto{
+			AdId:        1,
+			ContentType: "image/png",
+			Size:        1024,
+		},
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs((*adPhotos)[0].AdId).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(models.AdPhotosMaxCount))
+	sqlmock_.ExpectRollback()
+
+	resultAdPhotos, resultErr := adRepository.InsertAdPhotoArray(adPhotos)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultAdPhotos)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestAdRepository_SelectAdUserExecution_notFound(t *testing.T) {
+func TestAdRepository_SelectAdPhotoArrayByAdIds(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	adIds := []uint32{1, 2}
+	expectedAdPhotos := &models.AdPhotos{
+		&models.AdPhoto{
+			Id:               1,
+			AdId:             1,
+			ContentType:      "image/png",
+			Size:             1024,
+			BlobKey:          "ads/1/photo",
+			ThumbnailBlobKey: "ads/1/photo_thumbnail",
+		},
+		&models.AdPhoto{
+			Id:               3,
+			AdId:             2,
+			ContentType:      "image/jpeg",
+			Size:             2048,
+			BlobKey:          "ads/2/photo",
+			ThumbnailBlobKey: "ads/2/photo_thumbnail",
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "ad_id", "content_type", "size", "blob_key", "thumbnail_blob_key"})
+	for _, expectedAdPhoto := range *expectedAdPhotos {
+		rows.AddRow(expectedAdPhoto.Id, expectedAdPhoto.AdId, expectedAdPhoto.ContentType, expectedAdPhoto.Size,
+			expectedAdPhoto.BlobKey, expectedAdPhoto.ThumbnailBlobKey)
+	}
+	sqlmock_.
+		ExpectQuery("SELECT id, ad_id, content_type, size, blob_key, thumbnail_blob_key FROM ad_photo").
+		WithArgs(pq.Int64Array{1, 2}).
+		WillReturnRows(rows)
+
+	resultAdPhotos, resultErr := adRepository.SelectAdPhotoArrayByAdIds(adIds)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdPhotos, resultAdPhotos)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdRevisionArrayByAdId(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -542,20 +2189,131 @@ func TestAdRepository_SelectAdUserExecution_notFound(t *testing.T) {
 	adRepository := repository.NewAdRepositoryImpl(db)
 
 	const adId uint32 = 1
+	dateTime, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	expectedAdRevisions := &models.AdRevisions{
+		&models.AdRevision{
+			Id:       1,
+			AdId:     adId,
+			UserId:   pointy.Uint32(101),
+			UserVkId: pointy.Uint32(201),
+			Action:   models.AdRevisionActionUpdate,
+			DateTime: *dateTime,
+			Diff: models.AdRevisionDiff{
+				"minPrice": &models.AdRevisionChange{
+					Old: json.RawMessage("400"),
+					New: json.RawMessage("500"),
+				},
+			},
+		},
+		&models.AdRevision{
+			Id:       2,
+			AdId:     adId,
+			Action:   models.AdRevisionActionExecution,
+			DateTime: *dateTime,
+			Diff:     models.AdRevisionDiff{},
+		},
+	}
 
+	rows := sqlmock.NewRows([]string{"id", "ad_id", "user_id", "vk_id", "action", "date_time", "diff"}).
+		AddRow(1, adId, 101, 201, models.AdRevisionActionUpdate, time.Time(*dateTime),
+			[]byte(`{"minPrice":{"old":400,"new":500}}`)).
+		AddRow(2, adId, nil, nil, models.AdRevisionActionExecution, time.Time(*dateTime), []byte(`{}`))
 	sqlmock_.
-		ExpectQuery("SELECT ad_id, user_executor_id FROM ad_user_execution").
+		ExpectQuery("FROM ad_revision").
 		WithArgs(adId).
+		WillReturnRows(rows)
+
+	resultAdRevisions, resultErr := adRepository.SelectAdRevisionArrayByAdId(adId)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdRevisions, resultAdRevisions)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdTemplate(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	timeArr, err := timestamps.NewTime("09:30")
+	assert.Nil(t, err)
+	adTemplate := &models.AdTemplate{
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		Item:         "Набор для лабораторной",
+		MinPrice:     300,
+		Comment:      "Каждый вторник",
+		EvenWeek:     true,
+		OddWeek:      true,
+		DaysOfWeek:   []uint32{2},
+		TimeArr:      *timeArr,
+	}
+	expectedAdTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: adTemplate.UserAuthorId,
+		LocDep:       adTemplate.LocDep,
+		LocArr:       adTemplate.LocArr,
+		Item:         adTemplate.Item,
+		MinPrice:     adTemplate.MinPrice,
+		Comment:      adTemplate.Comment,
+		EvenWeek:     adTemplate.EvenWeek,
+		OddWeek:      adTemplate.OddWeek,
+		DaysOfWeek:   adTemplate.DaysOfWeek,
+		TimeArr:      adTemplate.TimeArr,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_template").
+		WithArgs(adTemplate.UserAuthorId, adTemplate.LocDep, adTemplate.LocArr, adTemplate.Item, adTemplate.MinPrice,
+			adTemplate.Comment, adTemplate.EvenWeek, adTemplate.OddWeek, pq.Int64Array{2}, nil,
+			time.Time(adTemplate.TimeArr), adTemplate.LocDepPoint, adTemplate.LocArrPoint, adTemplate.LocDepPlaceId,
+			adTemplate.LocArrPlaceId).
+		WillReturnRows(newAdTemplateRows(expectedAdTemplate))
+
+	resultAdTemplate, resultErr := adRepository.InsertAdTemplate(adTemplate)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdTemplate, resultAdTemplate)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdTemplateSkip_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	var adTemplateId uint32 = 1
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT ad_id FROM ad_template_occurrence (.+) FOR UPDATE").
+		WithArgs(adTemplateId, time.Time(*date)).
 		WillReturnError(sql.ErrNoRows)
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_template_occurrence").
+		WithArgs(adTemplateId, time.Time(*date)).
+		WillReturnError(&pq.Error{Code: "23505"})
+	sqlmock_.ExpectRollback()
 
-	resultAdUserExecution, resultErr := adRepository.SelectAdUserExecution(adId)
-	assert.Equal(t, resultErr, consts.RepErrNotFound)
-	assert.Nil(t, resultAdUserExecution)
+	resultErr := adRepository.InsertAdTemplateSkip(adTemplateId, time.Time(*date))
+	assert.Equal(t, consts.RepErrConflict, resultErr)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestAdRepository_DeleteAdUserExecution(t *testing.T) {
+func TestAdRepository_InsertAdTemplateSkip_materialized(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -564,26 +2322,221 @@ func TestAdRepository_DeleteAdUserExecution(t *testing.T) {
 
 	adRepository := repository.NewAdRepositoryImpl(db)
 
-	expectedAdUserExecution := &models.AdUserExecution{
-		AdId:           1,
-		UserExecutorId: 101,
+	var adTemplateId uint32 = 1
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+	existingAd := &models.Ad{
+		Id:             11,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
 	}
+	ad := *existingAd
+	ad.Status = models.AdStatusCancelled
 
+	sqlmock_.ExpectBegin()
 	sqlmock_.
-		ExpectQuery("DELETE FROM ad_user_execution").
-		WithArgs(expectedAdUserExecution.AdId).
+		ExpectQuery("SELECT ad_id FROM ad_template_occurrence (.+) FOR UPDATE").
+		WithArgs(adTemplateId, time.Time(*date)).
+		WillReturnRows(sqlmock.NewRows([]string{"ad_id"}).AddRow(existingAd.Id))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(existingAd.Id).
+		WillReturnRows(newAdRows(existingAd))
+	sqlmock_.
+		ExpectQuery("UPDATE ad SET status = 'cancelled'").
+		WithArgs(existingAd.Id).
+		WillReturnRows(newAdRows(&ad))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(existingAd.Id, existingAd.UserAuthorId, models.AdRevisionActionExecution,
+			`{"status":{"old":"open","new":"cancelled"}}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.
+		ExpectExec("UPDATE ad_template_occurrence SET ad_id = NULL").
+		WithArgs(adTemplateId, time.Time(*date)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock_.ExpectCommit()
+
+	resultErr := adRepository.InsertAdTemplateSkip(adTemplateId, time.Time(*date))
+	assert.Nil(t, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertAdTemplateSkip_taken(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	var adTemplateId uint32 = 1
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+	existingAd := &models.Ad{
+		Id:               11,
+		UserAuthorId:     101,
+		UserExecutorVkId: pointy.Uint32(202),
+		Status:           models.AdStatusAssigned,
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT ad_id FROM ad_template_occurrence (.+) FOR UPDATE").
+		WithArgs(adTemplateId, time.Time(*date)).
+		WillReturnRows(sqlmock.NewRows([]string{"ad_id"}).AddRow(existingAd.Id))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad WHERE id = (.+) FOR UPDATE").
+		WithArgs(existingAd.Id).
+		WillReturnRows(newAdRows(existingAd))
+	sqlmock_.ExpectRollback()
+
+	resultErr := adRepository.InsertAdTemplateSkip(adTemplateId, time.Time(*date))
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdTemplateOccurrenceArrayDue(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	minDateTimeArr, err := timestamps.NewDateTime("22.11.2021 12:00")
+	assert.Nil(t, err)
+	maxDate, err := timestamps.NewDateTime("29.11.2021 12:00")
+	assert.Nil(t, err)
+	timeDep, err := timestamps.NewTime("08:00")
+	assert.Nil(t, err)
+	timeArr, err := timestamps.NewTime("09:30")
+	assert.Nil(t, err)
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+	expectedAdTemplateOccurrences := &models.AdTemplateOccurrences{
+		&models.AdTemplateOccurrence{
+			AdTemplate: &models.AdTemplate{
+				Id:           1,
+				UserAuthorId: 101,
+				LocDep:       "Общежитие №10",
+				LocArr:       "УЛК",
+				Item:         "Набор для лабораторной",
+				MinPrice:     300,
+				Comment:      "Каждый вторник",
+				EvenWeek:     true,
+				OddWeek:      false,
+				DaysOfWeek:   []uint32{2, 4},
+				TimeDep:      timeDep,
+				TimeArr:      *timeArr,
+			},
+			Date: *date,
+		},
+	}
+
+	adTemplate := (*expectedAdTemplateOccurrences)[0].AdTemplate
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "item", "min_price", "comment",
+		"even_week", "odd_week", "days_of_week", "time_dep", "time_arr", "loc_dep_point", "loc_arr_point",
+		"loc_dep_place_id", "loc_arr_place_id", "paused", "day"}).
+		AddRow(adTemplate.Id, adTemplate.UserAuthorId, adTemplate.LocDep, adTemplate.LocArr, adTemplate.Item,
+			adTemplate.MinPrice, adTemplate.Comment, adTemplate.EvenWeek, adTemplate.OddWeek, pq.Int64Array{2, 4},
+			time.Time(*adTemplate.TimeDep), time.Time(adTemplate.TimeArr), adTemplate.LocDepPoint,
+			adTemplate.LocArrPoint, adTemplate.LocDepPlaceId, adTemplate.LocArrPlaceId, adTemplate.Paused,
+			time.Time(*date))
+	sqlmock_.
+		ExpectQuery(regexp.QuoteMeta("CROSS JOIN generate_series(date_trunc('day', $1::timestamp), $2::timestamp, interval '1 day') AS day")).
+		WithArgs(time.Time(*minDateTimeArr), time.Time(*maxDate)).
+		WillReturnRows(rows)
+
+	resultAdTemplateOccurrences, resultErr := adRepository.SelectAdTemplateOccurrenceArrayDue(
+		time.Time(*minDateTimeArr), time.Time(*maxDate))
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdTemplateOccurrences, resultAdTemplateOccurrences)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_InsertByAdTemplateOccurrence(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("23.11.2021 09:30")
+	assert.Nil(t, err)
+	adTemplateOccurrence := &models.AdTemplateOccurrence{
+		AdTemplate: &models.AdTemplate{Id: 1},
+		Date:       *date,
+	}
+	ad := &models.Ad{
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		DateTimeArr:  *dateTimeArr,
+		Item:         "Набор для лабораторной",
+		MinPrice:     300,
+		Comment:      "Каждый вторник",
+	}
+	expectedAd := &models.Ad{
+		Id:               1,
+		UserAuthorId:     ad.UserAuthorId,
+		UserAuthorVkId:   201,
+		UserAuthorName:   "Vasiliy Pupkin",
+		UserAuthorAvatar: "https://yandex.ru/logo.png",
+		LocDep:           ad.LocDep,
+		LocArr:           ad.LocArr,
+		DateTimeArr:      ad.DateTimeArr,
+		Item:             ad.Item,
+		MinPrice:         ad.MinPrice,
+		Comment:          ad.Comment,
+		Status:           models.AdStatusOpen,
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad").
+		WithArgs(ad.UserAuthorId, ad.LocDep, ad.LocArr, time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice, ad.Comment,
+			ad.LocDepPoint, ad.LocArrPoint, ad.LocDepPlaceId, ad.LocArrPlaceId, (*time.Time)(ad.DateTimeDep)).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"ad_id", "user_executor_id"}).
-				AddRow(expectedAdUserExecution.AdId, expectedAdUserExecution.UserExecutorId))
+			sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+				"user_author_avatar", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price", "comment", "status",
+				"loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"}).
+				AddRow(expectedAd.Id, ad.UserAuthorId, expectedAd.UserAuthorVkId, expectedAd.UserAuthorName,
+					expectedAd.UserAuthorAvatar, ad.LocDep, ad.LocArr, time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice,
+					ad.Comment, expectedAd.Status, expectedAd.LocDepPoint, expectedAd.LocArrPoint,
+					expectedAd.LocDepPlaceId, expectedAd.LocArrPlaceId, (*time.Time)(expectedAd.DateTimeDep), expectedAd.Version))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WithArgs(expectedAd.Id, expectedAd.UserAuthorId, models.AdRevisionActionInsert, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_notification_pending").
+		WithArgs(expectedAd.Id).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_template_occurrence").
+		WithArgs(adTemplateOccurrence.AdTemplate.Id, time.Time(adTemplateOccurrence.Date), expectedAd.Id).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.ExpectCommit()
 
-	resultAdUserExecution, resultErr := adRepository.DeleteAdUserExecution(expectedAdUserExecution.AdId)
+	resultAd, resultErr := adRepository.InsertByAdTemplateOccurrence(ad, adTemplateOccurrence)
 	assert.Nil(t, resultErr)
-	assert.Equal(t, expectedAdUserExecution, resultAdUserExecution)
+	assert.Equal(t, expectedAd, resultAd)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestAdRepository_DeleteAdUserExecution_notFound(t *testing.T) {
+func TestAdRepository_InsertByAdTemplateOccurrence_conflict(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -592,16 +2545,134 @@ func TestAdRepository_DeleteAdUserExecution_notFound(t *testing.T) {
 
 	adRepository := repository.NewAdRepositoryImpl(db)
 
-	const adId uint32 = 1
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("23.11.2021 09:30")
+	assert.Nil(t, err)
+	adTemplateOccurrence := &models.AdTemplateOccurrence{
+		AdTemplate: &models.AdTemplate{Id: 1},
+		Date:       *date,
+	}
+	ad := &models.Ad{
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		DateTimeArr:  *dateTimeArr,
+		Item:         "Набор для лабораторной",
+		MinPrice:     300,
+		Comment:      "Каждый вторник",
+	}
 
+	sqlmock_.ExpectBegin()
 	sqlmock_.
-		ExpectQuery("DELETE FROM ad_user_execution").
-		WithArgs(adId).
-		WillReturnError(sql.ErrNoRows)
+		ExpectQuery("INSERT INTO ad").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+				"user_author_avatar", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price", "comment", "status",
+				"loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "version"}).
+				AddRow(1, ad.UserAuthorId, 201, "Vasiliy Pupkin", "https://yandex.ru/logo.png", ad.LocDep, ad.LocArr,
+					time.Time(ad.DateTimeArr), ad.Item, ad.MinPrice, ad.Comment, models.AdStatusOpen, nil, nil, nil,
+					nil, nil, 1))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_revision").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_notification_pending").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlmock_.
+		ExpectExec("INSERT INTO ad_template_occurrence").
+		WillReturnError(&pq.Error{Code: "23505"})
+	sqlmock_.ExpectRollback()
 
-	resultAdUserExecution, resultErr := adRepository.DeleteAdUserExecution(adId)
-	assert.Equal(t, resultErr, consts.RepErrNotFound)
-	assert.Nil(t, resultAdUserExecution)
+	resultAd, resultErr := adRepository.InsertByAdTemplateOccurrence(ad, adTemplateOccurrence)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultAd)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_SelectAdArrayNotificationPending(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	maxDateTime := time.Date(2021, 11, 4, 12, 0, 0, 0, time.UTC)
+	expectedAd := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserAuthorName:   "Vasiliy Pupkin",
+		UserAuthorAvatar: "https://yandex.ru/logo.png",
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusOpen,
+		Version:          1,
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT .+ FROM ad JOIN ad_notification_pending .+ WHERE ad_notification_pending.date_time < \\$1").
+		WithArgs(maxDateTime).
+		WillReturnRows(newAdRows(expectedAd))
+
+	resultAds, resultErr := adRepository.SelectAdArrayNotificationPending(maxDateTime)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, &models.Ads{expectedAd}, resultAds)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestAdRepository_DeleteAdNotificationPending(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	adRepository := repository.NewAdRepositoryImpl(db)
+
+	sqlmock_.
+		ExpectExec("DELETE FROM ad_notification_pending").
+		WithArgs(uint32(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	resultErr := adRepository.DeleteAdNotificationPending(1)
+	assert.Nil(t, resultErr)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
+
+func newAdRows(ad_ *models.Ad) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_dep", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id",
+		"date_time_dep", "version"}).
+		AddRow(ad_.Id, ad_.UserAuthorId, ad_.UserAuthorVkId, ad_.UserAuthorName, ad_.UserAuthorAvatar,
+			ad_.UserExecutorVkId, ad_.LocDep, ad_.LocArr, time.Time(ad_.DateTimeArr), ad_.Item, ad_.MinPrice,
+			ad_.Comment, ad_.Status, ad_.LocDepPoint, ad_.LocArrPoint, ad_.LocDepPlaceId, ad_.LocArrPlaceId,
+			(*time.Time)(ad_.DateTimeDep), ad_.Version)
+}
+
+func newAdTemplateRows(adTemplate *models.AdTemplate) *sqlmock.Rows {
+	daysOfWeek := make(pq.Int64Array, len(adTemplate.DaysOfWeek))
+	for i, dayOfWeek := range adTemplate.DaysOfWeek {
+		daysOfWeek[i] = int64(dayOfWeek)
+	}
+
+	return sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "item", "min_price", "comment",
+		"even_week", "odd_week", "days_of_week", "time_dep", "time_arr", "loc_dep_point", "loc_arr_point",
+		"loc_dep_place_id", "loc_arr_place_id", "paused"}).
+		AddRow(adTemplate.Id, adTemplate.UserAuthorId, adTemplate.LocDep, adTemplate.LocArr, adTemplate.Item,
+			adTemplate.MinPrice, adTemplate.Comment, adTemplate.EvenWeek, adTemplate.OddWeek, daysOfWeek,
+			(*time.Time)(adTemplate.TimeDep), time.Time(adTemplate.TimeArr), adTemplate.LocDepPoint,
+			adTemplate.LocArrPoint, adTemplate.LocDepPlaceId, adTemplate.LocArrPlaceId, adTemplate.Paused)
+}
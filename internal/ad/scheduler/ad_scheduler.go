@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"github.com/TechnoHandOver/backend/internal/ad"
+	"log"
+	"time"
+)
+
+// notifyGracePeriod leaves the ads, which were just inserted, to the notify right after the insert
+const notifyGracePeriod = time.Minute
+
+type AdScheduler struct {
+	adUsecase       ad.Usecase
+	sweepInterval   time.Duration
+	gracePeriod     time.Duration
+	templateHorizon time.Duration
+	stop            chan struct{}
+	done            chan struct{}
+}
+
+func NewAdScheduler(adUsecase ad.Usecase, sweepInterval time.Duration, gracePeriod time.Duration,
+	templateHorizon time.Duration) *AdScheduler {
+	return &AdScheduler{
+		adUsecase:       adUsecase,
+		sweepInterval:   sweepInterval,
+		gracePeriod:     gracePeriod,
+		templateHorizon: templateHorizon,
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+func (adScheduler *AdScheduler) Start() {
+	go adScheduler.run()
+}
+
+// Stop waits for the running sweep to finish
+func (adScheduler *AdScheduler) Stop() {
+	close(adScheduler.stop)
+	<-adScheduler.done
+}
+
+func (adScheduler *AdScheduler) run() {
+	defer close(adScheduler.done)
+
+	ticker := time.NewTicker(adScheduler.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		adScheduler.expire()
+		adScheduler.materialize()
+		adScheduler.notify()
+
+		select {
+		case <-ticker.C:
+		case <-adScheduler.stop:
+			return
+		}
+	}
+}
+
+func (adScheduler *AdScheduler) expire() {
+	if response_ := adScheduler.adUsecase.Expire(adScheduler.gracePeriod); response_.Error != nil {
+		log.Println(response_.Error)
+	}
+}
+
+func (adScheduler *AdScheduler) materialize() {
+	if response_ := adScheduler.adUsecase.MaterializeAdTemplates(adScheduler.templateHorizon); response_.Error != nil {
+		log.Println(response_.Error)
+	}
+}
+
+func (adScheduler *AdScheduler) notify() {
+	if response_ := adScheduler.adUsecase.NotifyPending(notifyGracePeriod); response_.Error != nil {
+		log.Println(response_.Error)
+	}
+}
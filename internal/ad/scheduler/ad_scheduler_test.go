@@ -0,0 +1,50 @@
+package scheduler_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/ad/mock_ad"
+	"github.com/TechnoHandOver/backend/internal/ad/scheduler"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/golang/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestAdScheduler_Start(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdUsecase := mock_ad.NewMockUsecase(controller)
+	gracePeriod := 2 * time.Hour
+	templateHorizon := 7 * 24 * time.Hour
+	adScheduler := scheduler.NewAdScheduler(mockAdUsecase, time.Millisecond, gracePeriod, templateHorizon)
+
+	expired := make(chan struct{}, 2)
+	mockAdUsecase.
+		EXPECT().
+		Expire(gomock.Eq(gracePeriod)).
+		DoAndReturn(func(gracePeriod time.Duration) *response.Response {
+			select {
+			case expired <- struct{}{}:
+			default:
+			}
+			return response.NewResponse(consts.OK, &models.Ads{})
+		}).
+		MinTimes(2)
+	mockAdUsecase.
+		EXPECT().
+		MaterializeAdTemplates(gomock.Eq(templateHorizon)).
+		Return(response.NewResponse(consts.OK, &models.Ads{})).
+		MinTimes(1)
+	mockAdUsecase.
+		EXPECT().
+		NotifyPending(gomock.Eq(time.Minute)).
+		Return(response.NewResponse(consts.OK, &models.Ads{})).
+		MinTimes(1)
+
+	adScheduler.Start()
+	<-expired
+	<-expired
+	adScheduler.Stop()
+}
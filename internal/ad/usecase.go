@@ -2,15 +2,40 @@ package ad
 
 import (
 	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"time"
 )
 
 type Usecase interface {
 	Create(ad_ *models.Ad) *response.Response
 	Get(id uint32) *response.Response
 	Update(ad_ *models.Ad) *response.Response
-	Delete(userId uint32, id uint32) *response.Response
+	Patch(adPatch *models.AdPatch) *response.Response
+	Delete(userId uint32, id uint32, version uint32) *response.Response
 	Search(adsSearch *models.AdsSearch) *response.Response
 	SetAdUserExecutor(userId uint32, adId uint32) *response.Response
 	UnsetAdUserExecutor(userId uint32, adId uint32) *response.Response
+	CreateAdOffer(adOffer *models.AdOffer) *response.Response
+	ListAdOffers(userId uint32, adOffersSearch *models.AdOffersSearch) *response.Response
+	AcceptAdOffer(userId uint32, adId uint32, adOfferId uint32) *response.Response
+	RejectAdOffer(userId uint32, adId uint32, adOfferId uint32) *response.Response
+	PickUp(userId uint32, adId uint32) *response.Response
+	Deliver(userId uint32, adId uint32) *response.Response
+	Confirm(userId uint32, adId uint32) *response.Response
+	Cancel(userId uint32, adId uint32) *response.Response
+	CreateAdReview(adReview *models.AdReview) *response.Response
+	GetAdHandover(userId uint32, adId uint32, qr bool) *response.Response
+	ConfirmAdHandover(userId uint32, adId uint32, code string) *response.Response
+	Expire(gracePeriod time.Duration) *response.Response
+	ListAdRevisions(userId uint32, adId uint32) *response.Response
+	CreateAdPhotos(userId uint32, adId uint32, photos [][]byte) *response.Response
+	GetAdPhoto(adId uint32, adPhotoId uint32, thumbnail bool) *response.Response
+	CreateAdTemplate(adTemplate *models.AdTemplate) *response.Response
+	ListAdTemplates(userId uint32) *response.Response
+	DeleteAdTemplate(userId uint32, id uint32) *response.Response
+	SetAdTemplatePaused(userId uint32, id uint32, paused bool) *response.Response
+	SkipAdTemplateOccurrence(userId uint32, id uint32, date timestamps.Date) *response.Response
+	MaterializeAdTemplates(horizon time.Duration) *response.Response
+	NotifyPending(gracePeriod time.Duration) *response.Response
 }
@@ -1,37 +1,71 @@
 package usecase
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/TechnoHandOver/backend/internal/ad"
+	"github.com/TechnoHandOver/backend/internal/blobstore"
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
 	"github.com/TechnoHandOver/backend/internal/models/timestamps"
 	"github.com/TechnoHandOver/backend/internal/notification"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
-	"github.com/openlyinc/pointy"
+	"github.com/TechnoHandOver/backend/internal/tools/thumbnail"
+	"github.com/google/uuid"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
 	"time"
 )
 
+const (
+	adsSearchDefaultLimit   uint32 = 20
+	adsSearchDefaultRadiusM uint32 = 1000
+	adPhotoThumbnailSide           = 320
+)
+
+var adPhotoContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
 type AdUsecase struct {
 	adRepository        ad.Repository
 	notificationUsecase notification.Usecase
+	blobStore           blobstore.BlobStore
 }
 
-func NewAdUsecaseImpl(repository ad.Repository, notificationUsecase notification.Usecase) ad.Usecase {
+func NewAdUsecaseImpl(repository ad.Repository, notificationUsecase notification.Usecase,
+	blobStore blobstore.BlobStore) ad.Usecase {
 	return &AdUsecase{
 		adRepository:        repository,
 		notificationUsecase: notificationUsecase,
+		blobStore:           blobStore,
 	}
 }
 
 func (adUsecase *AdUsecase) Create(ad_ *models.Ad) *response.Response {
 	//TODO: assert.IsZero(ad_.Id) ?
+	if !ad_.HasValidTimeWindow() {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
 	ad_, err := adUsecase.adRepository.Insert(ad_)
 	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
-	go adUsecase.notificationUsecase.NotifySuitableUsers(ad_)
+	adUsecase.notify(ad_)
 
 	return response.NewResponse(consts.Created, ad_)
 }
@@ -46,10 +80,18 @@ func (adUsecase *AdUsecase) Get(id uint32) *response.Response {
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
-	return response.NewResponse(consts.OK, ad_)
+	if err := adUsecase.fillAdPhotos(models.Ads{ad_}); err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewVersionedResponse(consts.OK, ad_, ad_.Version)
 }
 
 func (adUsecase *AdUsecase) Update(ad_ *models.Ad) *response.Response {
+	if !ad_.HasValidTimeWindow() {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
 	existingAd, err := adUsecase.adRepository.Select(ad_.Id)
 	if err != nil {
 		if err == consts.RepErrNotFound {
@@ -63,7 +105,34 @@ func (adUsecase *AdUsecase) Update(ad_ *models.Ad) *response.Response {
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
+	//the courier has agreed to the ad as it is
+	if existingAd.Status != models.AdStatusOpen {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	if !etag.Matches(ad_.Version, existingAd.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
 	ad_, err = adUsecase.adRepository.Update(ad_)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewVersionedResponse(consts.OK, ad_, ad_.Version)
+}
+
+func (adUsecase *AdUsecase) Patch(adPatch *models.AdPatch) *response.Response {
+	existingAd, err := adUsecase.adRepository.Select(adPatch.Id)
 	if err != nil {
 		if err == consts.RepErrNotFound {
 			return response.NewEmptyResponse(consts.NotFound)
@@ -72,10 +141,41 @@ func (adUsecase *AdUsecase) Update(ad_ *models.Ad) *response.Response {
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
-	return response.NewResponse(consts.OK, ad_)
+	if adPatch.UserAuthorId != existingAd.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	//the courier has agreed to the ad as it is
+	if existingAd.Status != models.AdStatusOpen {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	if !etag.Matches(adPatch.Version, existingAd.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
+	if !adPatch.Apply(existingAd).HasValidTimeWindow() {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	ad_, err := adUsecase.adRepository.Patch(adPatch)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewVersionedResponse(consts.OK, ad_, ad_.Version)
 }
 
-func (adUsecase *AdUsecase) Delete(userId uint32, id uint32) *response.Response {
+func (adUsecase *AdUsecase) Delete(userId uint32, id uint32, version uint32) *response.Response {
 	existingAd, err := adUsecase.adRepository.Select(id)
 	if err != nil {
 		if err == consts.RepErrNotFound {
@@ -89,34 +189,81 @@ func (adUsecase *AdUsecase) Delete(userId uint32, id uint32) *response.Response
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
-	ad_, err := adUsecase.adRepository.Delete(id)
+	//the ad being executed is cancelled instead, so that the courier learns about it
+	if existingAd.Status != models.AdStatusOpen {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	if !etag.Matches(version, existingAd.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
+	adPhotos, err := adUsecase.adRepository.SelectAdPhotoArrayByAdIds([]uint32{id})
 	if err != nil {
-		if err == consts.RepErrNotFound {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	ad_, err := adUsecase.adRepository.Delete(id, version)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
 			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
 		}
 
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
+	for _, adPhoto := range *adPhotos {
+		adUsecase.deleteAdPhotoBlobs(adPhoto)
+	}
+
 	return response.NewResponse(consts.OK, ad_)
 }
 
 func (adUsecase *AdUsecase) Search(adsSearch *models.AdsSearch) *response.Response {
-	adsSearch.NullUserExecutorVkId = pointy.Bool(true)
-	if adsSearch.MinDateTimeArr == nil {
-		minDateTimeArr := timestamps.DateTime(time.Now().Add(time.Duration(-12) * time.Hour))
-		fmt.Println(minDateTimeArr.String())
-		adsSearch.MinDateTimeArr = &minDateTimeArr
+	if adsSearch.Status == nil && adsSearch.UserExecutorId == nil {
+		adsSearch.Status = new(models.AdStatus)
+		*adsSearch.Status = models.AdStatusOpen
 	}
+	if adsSearch.Order == nil {
+		adsSearch.Order = new(models.AdsSearchOrder)
+		*adsSearch.Order = models.AdsSearchOrderDateTimeArrDesc
+	}
+	if (adsSearch.NearDep != nil || adsSearch.NearArr != nil) && adsSearch.RadiusM == nil {
+		adsSearch.RadiusM = new(uint32)
+		*adsSearch.RadiusM = adsSearchDefaultRadiusM
+	}
+	if adsSearch.Cursor != nil && adsSearch.Cursor.Order != *adsSearch.Order {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	limit := parser.GetOrDefault(adsSearch.Limit, adsSearchDefaultLimit).(uint32)
+	adsSearch.Limit = new(uint32)
+	*adsSearch.Limit = limit + 1
 
 	ads, err := adUsecase.adRepository.SelectArray(adsSearch)
 	if err != nil {
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
-	return response.NewResponse(consts.OK, ads)
+	var nextCursor string
+	if uint32(len(*ads)) > limit {
+		*ads = (*ads)[:limit]
+		nextCursor = models.NewAdsSearchCursor(*adsSearch.Order, (*ads)[limit-1]).String()
+	}
+
+	if err := adUsecase.fillAdPhotos(*ads); err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewPageResponse(consts.OK, ads, nextCursor)
 }
 
+// SetAdUserExecutor takes the ad at its min price through an offer accepted at once
 func (adUsecase *AdUsecase) SetAdUserExecutor(userId uint32, adId uint32) *response.Response {
 	ad_, err := adUsecase.adRepository.Select(adId)
 	if err != nil {
@@ -130,24 +277,30 @@ func (adUsecase *AdUsecase) SetAdUserExecutor(userId uint32, adId uint32) *respo
 	if ad_.UserAuthorId == userId {
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
-	if ad_.UserExecutorVkId != nil {
+	if !ad_.Status.CanTransitTo(models.AdStatusAssigned) {
 		return response.NewEmptyResponse(consts.Conflict)
 	}
 
-	adUserExecution := &models.AdUserExecution{
+	adOffer := &models.AdOffer{
 		AdId:           adId,
 		UserExecutorId: userId,
+		Price:          ad_.MinPrice,
 	}
-	adUserExecution, err = adUsecase.adRepository.InsertAdUserExecution(adUserExecution)
+	adOffer, err = adUsecase.adRepository.InsertAdOfferAccepted(adOffer)
 	if err != nil {
-		if err == consts.RepErrNotFound {
+		switch err {
+		case consts.RepErrNotFound:
 			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		case consts.RepErrForbidden:
+			return response.NewEmptyResponse(consts.Forbidden)
 		}
 
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
-	updatedAd, err := adUsecase.adRepository.Select(adUserExecution.AdId)
+	updatedAd, err := adUsecase.adRepository.Select(adOffer.AdId)
 	if err != nil {
 		if err == consts.RepErrNotFound {
 			return response.NewEmptyResponse(consts.NotFound)
@@ -173,6 +326,19 @@ func (adUsecase *AdUsecase) UnsetAdUserExecutor(userId uint32, adId uint32) *res
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
+	ad_, err := adUsecase.adRepository.Select(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if !ad_.Status.CanTransitTo(models.AdStatusOpen) {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
 	adUserExecution, err = adUsecase.adRepository.DeleteAdUserExecution(adId)
 	if err != nil {
 		if err == consts.RepErrNotFound {
@@ -193,3 +359,704 @@ func (adUsecase *AdUsecase) UnsetAdUserExecutor(userId uint32, adId uint32) *res
 
 	return response.NewResponse(consts.OK, updatedAd)
 }
+
+func (adUsecase *AdUsecase) CreateAdOffer(adOffer *models.AdOffer) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adOffer.AdId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if ad_.UserAuthorId == adOffer.UserExecutorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+	if !ad_.Status.CanTransitTo(models.AdStatusAssigned) {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	adOffer, err = adUsecase.adRepository.InsertAdOffer(adOffer)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		case consts.RepErrForbidden:
+			return response.NewEmptyResponse(consts.Forbidden)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, adOffer)
+}
+
+func (adUsecase *AdUsecase) ListAdOffers(userId uint32, adOffersSearch *models.AdOffersSearch) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adOffersSearch.AdId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if ad_.UserAuthorId != userId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	adOffers, err := adUsecase.adRepository.SelectAdOfferArray(adOffersSearch)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adOffers)
+}
+
+func (adUsecase *AdUsecase) ListAdRevisions(userId uint32, adId uint32) *response.Response {
+	adRevisions, err := adUsecase.adRepository.SelectAdRevisionArrayByAdId(adId)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	//revisions outlive the ad, so its author is taken from them
+	userAuthorId := adRevisions.UserAuthorId()
+	if userAuthorId == nil {
+		ad_, err := adUsecase.adRepository.Select(adId)
+		if err != nil {
+			if err == consts.RepErrNotFound {
+				return response.NewEmptyResponse(consts.NotFound)
+			}
+
+			return response.NewErrorResponse(consts.InternalError, err)
+		}
+
+		userAuthorId = &ad_.UserAuthorId
+	}
+
+	if *userAuthorId != userId {
+		adUserExecution, err := adUsecase.adRepository.SelectAdUserExecution(adId)
+		if err != nil {
+			if err == consts.RepErrNotFound {
+				return response.NewEmptyResponse(consts.Forbidden)
+			}
+
+			return response.NewErrorResponse(consts.InternalError, err)
+		}
+
+		if adUserExecution.UserExecutorId != userId {
+			return response.NewEmptyResponse(consts.Forbidden)
+		}
+	}
+
+	return response.NewResponse(consts.OK, adRevisions)
+}
+
+func (adUsecase *AdUsecase) CreateAdPhotos(userId uint32, adId uint32, photos [][]byte) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if ad_.UserAuthorId != userId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	existingAdPhotos, err := adUsecase.adRepository.SelectAdPhotoArrayByAdIds([]uint32{adId})
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if len(photos) == 0 || len(*existingAdPhotos)+len(photos) > models.AdPhotosMaxCount {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	thumbnails := make([][]byte, len(photos))
+	adPhotos := make(models.AdPhotos, len(photos))
+	for i, photo := range photos {
+		if len(photo) > models.AdPhotoMaxSize {
+			return response.NewEmptyResponse(consts.PayloadTooLarge)
+		}
+
+		contentType := http.DetectContentType(photo)
+		if !adPhotoContentTypes[contentType] {
+			return response.NewEmptyResponse(consts.UnsupportedMediaType)
+		}
+
+		//the header is checked first, a small file may still decode into a huge bitmap
+		imageConfig, _, err := image.DecodeConfig(bytes.NewReader(photo))
+		if err != nil {
+			return response.NewErrorResponse(consts.BadRequest, err)
+		}
+
+		if imageConfig.Width*imageConfig.Height > models.AdPhotoMaxPixels {
+			return response.NewEmptyResponse(consts.PayloadTooLarge)
+		}
+
+		image_, _, err := image.Decode(bytes.NewReader(photo))
+		if err != nil {
+			return response.NewErrorResponse(consts.BadRequest, err)
+		}
+
+		if thumbnails[i], err = thumbnail.Make(image_, adPhotoThumbnailSide); err != nil {
+			return response.NewErrorResponse(consts.InternalError, err)
+		}
+
+		blobKey := fmt.Sprintf("ads/%d/%s", adId, uuid.NewString())
+		adPhotos[i] = &models.AdPhoto{
+			AdId:             adId,
+			ContentType:      contentType,
+			Size:             uint32(len(photo)),
+			BlobKey:          blobKey,
+			ThumbnailBlobKey: blobKey + "_thumbnail",
+		}
+	}
+
+	for i, adPhoto := range adPhotos {
+		err := adUsecase.blobStore.Put(adPhoto.BlobKey, photos[i])
+		if err == nil {
+			err = adUsecase.blobStore.Put(adPhoto.ThumbnailBlobKey, thumbnails[i])
+		}
+		if err != nil {
+			for _, adPhoto := range adPhotos[:i+1] {
+				adUsecase.deleteAdPhotoBlobs(adPhoto)
+			}
+
+			return response.NewErrorResponse(consts.InternalError, err)
+		}
+	}
+
+	if _, err := adUsecase.adRepository.InsertAdPhotoArray(&adPhotos); err != nil {
+		for _, adPhoto := range adPhotos {
+			adUsecase.deleteAdPhotoBlobs(adPhoto)
+		}
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict: //photos have been added concurrently
+			return response.NewEmptyResponse(consts.BadRequest)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, &adPhotos)
+}
+
+func (adUsecase *AdUsecase) GetAdPhoto(adId uint32, adPhotoId uint32, thumbnail bool) *response.Response {
+	adPhoto, err := adUsecase.adRepository.SelectAdPhoto(adPhotoId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if adPhoto.AdId != adId {
+		return response.NewEmptyResponse(consts.NotFound)
+	}
+
+	adPhotoContent := &models.AdPhotoContent{
+		ContentType: adPhoto.ContentType,
+	}
+	blobKey := adPhoto.BlobKey
+	if thumbnail {
+		adPhotoContent.ContentType = "image/jpeg"
+		blobKey = adPhoto.ThumbnailBlobKey
+	}
+
+	if adPhotoContent.Data, err = adUsecase.blobStore.Get(blobKey); err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adPhotoContent)
+}
+
+func (adUsecase *AdUsecase) AcceptAdOffer(userId uint32, adId uint32, adOfferId uint32) *response.Response {
+	return adUsecase.updateAdOfferStatus(userId, adId, adOfferId, models.AdOfferStatusAccepted)
+}
+
+func (adUsecase *AdUsecase) RejectAdOffer(userId uint32, adId uint32, adOfferId uint32) *response.Response {
+	return adUsecase.updateAdOfferStatus(userId, adId, adOfferId, models.AdOfferStatusRejected)
+}
+
+func (adUsecase *AdUsecase) PickUp(userId uint32, adId uint32) *response.Response {
+	return adUsecase.updateStatusByExecutor(userId, adId, models.AdStatusPickedUp)
+}
+
+func (adUsecase *AdUsecase) Deliver(userId uint32, adId uint32) *response.Response {
+	return adUsecase.updateStatusByExecutor(userId, adId, models.AdStatusDelivered)
+}
+
+func (adUsecase *AdUsecase) Confirm(userId uint32, adId uint32) *response.Response {
+	return adUsecase.updateStatusByAuthor(userId, adId, models.AdStatusConfirmed)
+}
+
+func (adUsecase *AdUsecase) Cancel(userId uint32, adId uint32) *response.Response {
+	return adUsecase.updateStatusByAuthor(userId, adId, models.AdStatusCancelled)
+}
+
+func (adUsecase *AdUsecase) CreateAdReview(adReview *models.AdReview) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adReview.AdId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if ad_.Status != models.AdStatusConfirmed {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	adUserExecution, err := adUsecase.adRepository.SelectAdUserExecution(adReview.AdId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	switch adReview.UserAuthorId {
+	case ad_.UserAuthorId:
+		adReview.UserTargetId = adUserExecution.UserExecutorId
+	case adUserExecution.UserExecutorId:
+		adReview.UserTargetId = ad_.UserAuthorId
+	default:
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	adReview, err = adUsecase.adRepository.InsertAdReview(adReview)
+	if err != nil {
+		if err == consts.RepErrConflict {
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, adReview)
+}
+
+func (adUsecase *AdUsecase) GetAdHandover(userId uint32, adId uint32, qr bool) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if ad_.UserAuthorId != userId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+	if !ad_.Status.IsExecuting() {
+		return response.NewEmptyResponse(consts.NotFound)
+	}
+
+	adHandover, err := adUsecase.adRepository.SelectAdHandover(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if qr {
+		return response.NewResponse(consts.OK, adHandover.Qr())
+	}
+
+	return response.NewResponse(consts.OK, adHandover)
+}
+
+func (adUsecase *AdUsecase) ConfirmAdHandover(userId uint32, adId uint32, code string) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	adUserExecution, err := adUsecase.adRepository.SelectAdUserExecution(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.Forbidden)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if adUserExecution.UserExecutorId != userId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+	if ad_.Status != models.AdStatusPickedUp && ad_.Status != models.AdStatusDelivered {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	matched, err := adUsecase.adRepository.UpdateAdHandoverAttempt(adId, code, time.Now())
+	if err != nil {
+		if err == consts.RepErrNotFound { //handover is locked out after too many failed attempts
+			return response.NewEmptyResponse(consts.TooManyRequests)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if !matched {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	updatedAd, err := adUsecase.adRepository.UpdateStatus(adId, ad_.Status, models.AdStatusConfirmed, userId)
+	if err != nil {
+		if err == consts.RepErrNotFound { //status has been changed concurrently
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, updatedAd)
+}
+
+func (adUsecase *AdUsecase) Expire(gracePeriod time.Duration) *response.Response {
+	ads, err := adUsecase.adRepository.UpdateStatusExpired(time.Now().Add(-gracePeriod))
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	for _, ad_ := range *ads {
+		if response_ := adUsecase.notificationUsecase.NotifyAdExpired(ad_); response_.Error != nil {
+			log.Println(response_.Error)
+		}
+	}
+
+	return response.NewResponse(consts.OK, ads)
+}
+
+func (adUsecase *AdUsecase) CreateAdTemplate(adTemplate *models.AdTemplate) *response.Response {
+	if !adTemplate.HasValidTimeWindow() || !adTemplate.EvenWeek && !adTemplate.OddWeek {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	adTemplate, err := adUsecase.adRepository.InsertAdTemplate(adTemplate)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, adTemplate)
+}
+
+func (adUsecase *AdUsecase) ListAdTemplates(userId uint32) *response.Response {
+	adTemplates, err := adUsecase.adRepository.SelectAdTemplateArrayByUserAuthorId(userId)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adTemplates)
+}
+
+func (adUsecase *AdUsecase) DeleteAdTemplate(userId uint32, id uint32) *response.Response {
+	existingAdTemplate, err := adUsecase.adRepository.SelectAdTemplate(id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if userId != existingAdTemplate.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	adTemplate, err := adUsecase.adRepository.DeleteAdTemplate(id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adTemplate)
+}
+
+func (adUsecase *AdUsecase) SetAdTemplatePaused(userId uint32, id uint32, paused bool) *response.Response {
+	existingAdTemplate, err := adUsecase.adRepository.SelectAdTemplate(id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if userId != existingAdTemplate.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	adTemplate, err := adUsecase.adRepository.UpdateAdTemplatePaused(id, paused)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adTemplate)
+}
+
+func (adUsecase *AdUsecase) SkipAdTemplateOccurrence(userId uint32, id uint32, date timestamps.Date) *response.Response {
+	existingAdTemplate, err := adUsecase.adRepository.SelectAdTemplate(id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if userId != existingAdTemplate.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	if !existingAdTemplate.HasOccurrence(time.Time(date)) {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	//the ad of an already materialized occurrence is cancelled, unless it has been taken
+	if err := adUsecase.adRepository.InsertAdTemplateSkip(id, time.Time(date)); err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		default:
+			return response.NewErrorResponse(consts.InternalError, err)
+		}
+	}
+
+	return response.NewEmptyResponse(consts.Created)
+}
+
+func (adUsecase *AdUsecase) MaterializeAdTemplates(horizon time.Duration) *response.Response {
+	now := time.Now()
+	adTemplateOccurrences, err := adUsecase.adRepository.SelectAdTemplateOccurrenceArrayDue(now, now.Add(horizon))
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	ads := make(models.Ads, 0)
+	for _, adTemplateOccurrence := range *adTemplateOccurrences {
+		ad_, err := adUsecase.adRepository.InsertByAdTemplateOccurrence(adTemplateOccurrence.NewAd(),
+			adTemplateOccurrence)
+		if err != nil {
+			//the template was deleted or the occurrence was skipped in the meantime
+			if err == consts.RepErrNotFound || err == consts.RepErrConflict {
+				continue
+			}
+
+			return response.NewErrorResponse(consts.InternalError, err)
+		}
+
+		adUsecase.notify(ad_)
+
+		ads = append(ads, ad_)
+	}
+
+	return response.NewResponse(consts.OK, &ads)
+}
+
+// NotifyPending enqueues the notifications of the ads, which are still pending after the grace period, that is the
+// process stopped between the insert and the notify
+func (adUsecase *AdUsecase) NotifyPending(gracePeriod time.Duration) *response.Response {
+	ads, err := adUsecase.adRepository.SelectAdArrayNotificationPending(time.Now().Add(-gracePeriod))
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	for _, ad_ := range *ads {
+		adUsecase.notify(ad_)
+	}
+
+	return response.NewResponse(consts.OK, ads)
+}
+
+func (adUsecase *AdUsecase) updateStatusByAuthor(userId uint32, adId uint32, newStatus models.AdStatus) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if ad_.UserAuthorId != userId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	return adUsecase.updateStatus(userId, ad_, newStatus)
+}
+
+func (adUsecase *AdUsecase) updateStatusByExecutor(userId uint32, adId uint32, newStatus models.AdStatus) *response.Response {
+	ad_, err := adUsecase.adRepository.Select(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	adUserExecution, err := adUsecase.adRepository.SelectAdUserExecution(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.Forbidden)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if adUserExecution.UserExecutorId != userId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	return adUsecase.updateStatus(userId, ad_, newStatus)
+}
+
+func (adUsecase *AdUsecase) updateStatus(userId uint32, ad_ *models.Ad, newStatus models.AdStatus) *response.Response {
+	if !ad_.Status.CanTransitTo(newStatus) {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	updatedAd, err := adUsecase.adRepository.UpdateStatus(ad_.Id, ad_.Status, newStatus, userId)
+	if err != nil {
+		if err == consts.RepErrNotFound { //status has been changed concurrently
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, updatedAd)
+}
+
+func (adUsecase *AdUsecase) updateAdOfferStatus(userId uint32, adId uint32, adOfferId uint32, newStatus models.AdOfferStatus) *response.Response {
+	adOffer, err := adUsecase.adRepository.SelectAdOffer(adOfferId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if adOffer.AdId != adId {
+		return response.NewEmptyResponse(consts.NotFound)
+	}
+
+	ad_, err := adUsecase.adRepository.Select(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if ad_.UserAuthorId != userId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+	if adOffer.Status != models.AdOfferStatusPending ||
+		newStatus == models.AdOfferStatusAccepted && !ad_.Status.CanTransitTo(models.AdStatusAssigned) {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	adOffer, err = adUsecase.adRepository.UpdateAdOfferStatus(adOfferId, adOffer.Status, newStatus)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound, consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		case consts.RepErrForbidden:
+			return response.NewEmptyResponse(consts.Forbidden)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adOffer)
+}
+
+func (adUsecase *AdUsecase) fillAdPhotos(ads models.Ads) error {
+	if len(ads) == 0 {
+		return nil
+	}
+
+	adIds := make([]uint32, len(ads))
+	adsById := make(map[uint32]*models.Ad, len(ads))
+	for i, ad_ := range ads {
+		adIds[i] = ad_.Id
+		adsById[ad_.Id] = ad_
+	}
+
+	adPhotos, err := adUsecase.adRepository.SelectAdPhotoArrayByAdIds(adIds)
+	if err != nil {
+		return err
+	}
+
+	for _, adPhoto := range *adPhotos {
+		if ad_, ok := adsById[adPhoto.AdId]; ok {
+			ad_.Photos = append(ad_.Photos, adPhoto)
+		}
+	}
+
+	return nil
+}
+
+func (adUsecase *AdUsecase) deleteAdPhotoBlobs(adPhoto *models.AdPhoto) {
+	for _, blobKey := range []string{adPhoto.BlobKey, adPhoto.ThumbnailBlobKey} {
+		if err := adUsecase.blobStore.Delete(blobKey); err != nil && err != consts.RepErrNotFound {
+			log.Println(err)
+		}
+	}
+}
+
+// notify enqueues the notifications of a new ad; its pending marker is dropped only then, otherwise NotifyPending
+// retries them
+func (adUsecase *AdUsecase) notify(ad_ *models.Ad) {
+	//the ad may be taken or cancelled before the sweep gets to it
+	if ad_.Status == models.AdStatusOpen {
+		if response_ := adUsecase.notificationUsecase.NotifyAdMatches(ad_); response_.Error != nil {
+			log.Println(response_.Error)
+			return
+		}
+	}
+
+	if err := adUsecase.adRepository.DeleteAdNotificationPending(ad_.Id); err != nil {
+		log.Println(err)
+	}
+}
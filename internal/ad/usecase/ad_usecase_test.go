@@ -1,16 +1,24 @@
 package usecase_test
 
 import (
+	"bytes"
+	"errors"
 	"github.com/TechnoHandOver/backend/internal/ad/mock_ad"
 	"github.com/TechnoHandOver/backend/internal/ad/usecase"
+	"github.com/TechnoHandOver/backend/internal/blobstore/mock_blobstore"
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
 	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/notification/mock_notification"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
 	"github.com/golang/mock/gomock"
 	"github.com/openlyinc/pointy"
 	"github.com/stretchr/testify/assert"
+	"image"
+	"image/jpeg"
+	"image/png"
 	"testing"
+	"time"
 )
 
 func TestAdUsecase_Create(t *testing.T) {
@@ -18,7 +26,9 @@ func TestAdUsecase_Create(t *testing.T) {
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
 	assert.Nil(t, err)
@@ -41,27 +51,100 @@ func TestAdUsecase_Create(t *testing.T) {
 		Item:           ad.Item,
 		MinPrice:       ad.MinPrice,
 		Comment:        ad.Comment,
+		Status:         models.AdStatusOpen,
 	}
 
-	mockAdRepository.
+	call := mockAdRepository.
 		EXPECT().
 		Insert(gomock.Eq(ad)).
 		DoAndReturn(func(ad *models.Ad) (*models.Ad, error) {
 			ad.Id = expectedAd.Id
 			ad.UserAuthorVkId = expectedAd.UserAuthorVkId
+			ad.Status = expectedAd.Status
 			return ad, nil
 		})
+	call = mockNotificationUsecase.
+		EXPECT().
+		NotifyAdMatches(gomock.Eq(expectedAd)).
+		Return(response.NewEmptyResponse(consts.OK)).
+		After(call)
+	mockAdRepository.
+		EXPECT().
+		DeleteAdNotificationPending(gomock.Eq(expectedAd.Id)).
+		Return(nil).
+		After(call)
 
 	response_ := adUsecase.Create(ad)
 	assert.Equal(t, response.NewResponse(consts.Created, expectedAd), response_)
 }
 
+func TestAdUsecase_Create_placeNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	var locDepPlaceId uint32 = 1000
+	ad := &models.Ad{
+		UserAuthorId:  101,
+		LocArr:        "УЛК",
+		DateTimeArr:   *dateTimeArr,
+		Item:          "Зачётная книжка",
+		MinPrice:      500,
+		Comment:       "Поеду на велосипеде",
+		LocDepPlaceId: &locDepPlaceId,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Insert(gomock.Eq(ad)).
+		Return(nil, consts.RepErrNotFound)
+
+	response_ := adUsecase.Create(ad)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}
+
+func TestAdUsecase_Create_badTimeWindow(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeDep, err := timestamps.NewDateTime("04.11.2021 20:00")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		DateTimeDep:  dateTimeDep,
+		DateTimeArr:  *dateTimeArr,
+		Item:         "Зачётная книжка",
+		MinPrice:     500,
+		Comment:      "Поеду на велосипеде",
+	}
+
+	response_ := adUsecase.Create(ad)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
 func TestAdUsecase_Get(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:20")
 	assert.Nil(t, err)
@@ -75,15 +158,33 @@ func TestAdUsecase_Get(t *testing.T) {
 		Item:           "Зачётная книжка",
 		MinPrice:       500,
 		Comment:        "Поеду на велосипеде",
+		Version:        2,
 	}
 
-	mockAdRepository.
+	adPhotos := &models.AdPhotos{
+		&models.AdPhoto{
+			Id:               1,
+			AdId:             expectedAd.Id,
+			ContentType:      "image/jpeg",
+			Size:             1024,
+			BlobKey:          "ads/1/photo",
+			ThumbnailBlobKey: "ads/1/photo_thumbnail",
+		},
+	}
+
+	call := mockAdRepository.
 		EXPECT().
 		Select(gomock.Eq(expectedAd.Id)).
 		Return(expectedAd, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{expectedAd.Id})).
+		Return(adPhotos, nil).
+		After(call)
 
 	response_ := adUsecase.Get(expectedAd.Id)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, expectedAd, expectedAd.Version), response_)
+	assert.Equal(t, models.AdPhotos(*adPhotos), expectedAd.Photos)
 }
 
 func TestAdUsecase_Get_notFound(t *testing.T) {
@@ -91,7 +192,9 @@ func TestAdUsecase_Get_notFound(t *testing.T) {
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	const id uint32 = 1
 
@@ -109,7 +212,9 @@ func TestAdUsecase_Update(t *testing.T) {
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	dateTimeArr1, err := timestamps.NewDateTime("04.11.2021 19:40")
 	assert.Nil(t, err)
@@ -122,6 +227,7 @@ func TestAdUsecase_Update(t *testing.T) {
 		Item:         "Зачётная книжка",
 		MinPrice:     500,
 		Comment:      "Поеду на велосипеде",
+		Version:      2,
 	}
 	dateTimeArr2, err := timestamps.NewDateTime("04.11.2021 19:45")
 	assert.Nil(t, err)
@@ -135,6 +241,8 @@ func TestAdUsecase_Update(t *testing.T) {
 		Item:           "Спортивная форма",
 		MinPrice:       600,
 		Comment:        "Поеду на роликах :)",
+		Status:         models.AdStatusOpen,
+		Version:        ad.Version,
 	}
 
 	call := mockAdRepository.
@@ -149,7 +257,7 @@ func TestAdUsecase_Update(t *testing.T) {
 		After(call)
 
 	response_ := adUsecase.Update(ad)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, expectedAd, expectedAd.Version), response_)
 }
 
 func TestAdUsecase_Update_forbidden(t *testing.T) {
@@ -157,7 +265,9 @@ func TestAdUsecase_Update_forbidden(t *testing.T) {
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	dateTimeArr1, err := timestamps.NewDateTime("24.11.2021 13:50")
 	assert.Nil(t, err)
@@ -199,7 +309,9 @@ func TestAdUsecase_Update_notFound(t *testing.T) {
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:35")
 	assert.Nil(t, err)
@@ -223,18 +335,96 @@ func TestAdUsecase_Update_notFound(t *testing.T) {
 	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
 }
 
-func TestAdUsecase_Delete(t *testing.T) {
+func TestAdUsecase_Update_preconditionFailed(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
-	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:35")
 	assert.Nil(t, err)
-	expectedAd := &models.Ad{
-		Id:             1,
-		UserAuthorId:   101,
+	ad := &models.Ad{
+		Id:           1,
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		DateTimeArr:  *dateTimeArr,
+		Item:         "Зачётная книжка",
+		MinPrice:     500,
+		Comment:      "Поеду на велосипеде",
+		Version:      2,
+	}
+	existingAd := *ad
+	existingAd.MinPrice = 400
+	existingAd.Status = models.AdStatusOpen
+	existingAd.Version = 3
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(&existingAd, nil)
+
+	response_ := adUsecase.Update(ad)
+	assert.Equal(t, response.NewEmptyResponse(consts.PreconditionFailed), response_)
+}
+
+func TestAdUsecase_Update_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:35")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:           1,
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		DateTimeArr:  *dateTimeArr,
+		Item:         "Зачётная книжка",
+		MinPrice:     500,
+		Comment:      "Поеду на велосипеде",
+		Version:      2,
+	}
+	existingAd := *ad
+	existingAd.MinPrice = 400
+	existingAd.Status = models.AdStatusPickedUp
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(&existingAd, nil)
+
+	response_ := adUsecase.Update(ad)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_Patch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	adPatch := &models.AdPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		MinPrice:     pointy.Uint32(600),
+	}
+	existingAd := &models.Ad{
+		Id:             adPatch.Id,
+		UserAuthorId:   adPatch.UserAuthorId,
 		UserAuthorVkId: 201,
 		LocDep:         "Общежитие №10",
 		LocArr:         "УЛК",
@@ -242,130 +432,156 @@ func TestAdUsecase_Delete(t *testing.T) {
 		Item:           "Зачётная книжка",
 		MinPrice:       500,
 		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
 	}
+	expectedAd := *existingAd
+	expectedAd.MinPrice = *adPatch.MinPrice
 
 	call := mockAdRepository.
 		EXPECT().
-		Select(gomock.Eq(expectedAd.Id)).
-		Return(expectedAd, nil)
+		Select(gomock.Eq(adPatch.Id)).
+		Return(existingAd, nil)
 
 	mockAdRepository.
 		EXPECT().
-		Delete(gomock.Eq(expectedAd.Id)).
-		Return(expectedAd, nil).
+		Patch(gomock.Eq(adPatch)).
+		Return(&expectedAd, nil).
 		After(call)
 
-	response_ := adUsecase.Delete(expectedAd.UserAuthorId, expectedAd.Id)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
+	response_ := adUsecase.Patch(adPatch)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, &expectedAd, expectedAd.Version), response_)
 }
 
-func TestAdUsecase_Delete_forbidden(t *testing.T) {
+func TestAdUsecase_Patch_forbidden(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
-	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
+	dateTimeArr, err := timestamps.NewDateTime("24.11.2021 13:50")
 	assert.Nil(t, err)
-	expectedAd := &models.Ad{
-		Id:             1,
-		UserAuthorId:   101,
-		UserAuthorVkId: 201,
-		LocDep:         "Общежитие №10",
-		LocArr:         "УЛК",
+	adPatch := &models.AdPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		Item:         pointy.String("Спортивная форма"),
+	}
+	existingAd := &models.Ad{
+		Id:             adPatch.Id,
+		UserAuthorId:   102,
+		UserAuthorVkId: 202,
+		LocDep:         "Общежитие №9",
+		LocArr:         "СК",
 		DateTimeArr:    *dateTimeArr,
 		Item:           "Зачётная книжка",
-		MinPrice:       500,
-		Comment:        "Поеду на велосипеде",
+		MinPrice:       600,
+		Comment:        "Поеду на роликах :)",
 	}
 
 	mockAdRepository.
 		EXPECT().
-		Select(gomock.Eq(expectedAd.Id)).
-		Return(expectedAd, nil)
+		Select(gomock.Eq(adPatch.Id)).
+		Return(existingAd, nil)
 
-	response_ := adUsecase.Delete(expectedAd.UserAuthorId+1, expectedAd.Id)
+	response_ := adUsecase.Patch(adPatch)
 	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
 }
 
-func TestAdUsecase_Delete_notFound(t *testing.T) {
+func TestAdUsecase_Patch_badTimeWindow(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
-	const id uint32 = 1
-	const userAuthorId uint32 = 101
+	dateTimeDep, err := timestamps.NewDateTime("24.11.2021 13:00")
+	assert.Nil(t, err)
+	dateTimeArr1, err := timestamps.NewDateTime("24.11.2021 13:50")
+	assert.Nil(t, err)
+	dateTimeArr2, err := timestamps.NewDateTime("24.11.2021 12:50")
+	assert.Nil(t, err)
+	adPatch := &models.AdPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		DateTimeArr:  dateTimeArr2,
+	}
+	existingAd := &models.Ad{
+		Id:             adPatch.Id,
+		UserAuthorId:   adPatch.UserAuthorId,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeDep:    dateTimeDep,
+		DateTimeArr:    *dateTimeArr1,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
 
 	mockAdRepository.
 		EXPECT().
-		Select(gomock.Eq(id)).
-		Return(nil, consts.RepErrNotFound)
+		Select(gomock.Eq(adPatch.Id)).
+		Return(existingAd, nil)
 
-	response_ := adUsecase.Delete(userAuthorId, id)
-	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+	response_ := adUsecase.Patch(adPatch)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
 }
 
-func TestAdUsecase_Search(t *testing.T) {
+func TestAdUsecase_Patch_conflict(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
 	assert.Nil(t, err)
-	adsSearch := &models.AdsSearch{
-		LocDep:         pointy.String("Общежитие"),
-		LocArr:         pointy.String("СК"),
-		MinDateTimeArr: dateTimeArr,
+	adPatch := &models.AdPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		LocArr:       pointy.String("СК"),
 	}
-	expectedAds := &models.Ads{
-		&models.Ad{
-			Id:             1,
-			UserAuthorId:   101,
-			UserAuthorVkId: 201,
-			LocDep:         "Общежитие №10",
-			LocArr:         "УЛК",
-			DateTimeArr:    *dateTimeArr,
-			Item:           "Тубус",
-			MinPrice:       500,
-			Comment:        "Поеду на коньках",
-		},
-		&models.Ad{
-			Id:             2,
-			UserAuthorId:   102,
-			UserAuthorVkId: 202,
-			LocDep:         "Общежитие №9",
-			LocArr:         "СК",
-			DateTimeArr:    *dateTimeArr,
-			Item:           "Спортивная форма",
-			MinPrice:       600,
-			Comment:        "Поеду на роликах :)",
-		},
+	existingAd := &models.Ad{
+		Id:             adPatch.Id,
+		UserAuthorId:   adPatch.UserAuthorId,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusDelivered,
 	}
 
 	mockAdRepository.
 		EXPECT().
-		SelectArray(gomock.Eq(adsSearch)).
-		Return(expectedAds, nil)
+		Select(gomock.Eq(adPatch.Id)).
+		Return(existingAd, nil)
 
-	response_ := adUsecase.Search(adsSearch)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedAds), response_)
+	response_ := adUsecase.Patch(adPatch)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
 }
 
-func TestAdUsecase_SetAdUserExecutor(t *testing.T) {
+func TestAdUsecase_Delete(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
-	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
 	assert.Nil(t, err)
-	ad := &models.Ad{
+	expectedAd := &models.Ad{
 		Id:             1,
 		UserAuthorId:   101,
 		UserAuthorVkId: 201,
@@ -375,53 +591,60 @@ func TestAdUsecase_SetAdUserExecutor(t *testing.T) {
 		Item:           "Зачётная книжка",
 		MinPrice:       500,
 		Comment:        "Поеду на велосипеде",
-	}
-	adUserExecution := &models.AdUserExecution{
-		AdId:           ad.Id,
-		UserExecutorId: ad.UserAuthorId + 1,
-	}
-	expectedAd := &models.Ad{
-		Id:               ad.Id,
-		UserAuthorId:     ad.UserAuthorId,
-		UserAuthorVkId:   ad.UserAuthorVkId,
-		UserExecutorVkId: &adUserExecution.UserExecutorId,
-		LocDep:           ad.LocDep,
-		LocArr:           ad.LocArr,
-		DateTimeArr:      ad.DateTimeArr,
-		Item:             ad.Item,
-		MinPrice:         ad.MinPrice,
-		Comment:          ad.Comment,
+		Status:         models.AdStatusOpen,
 	}
 
-	callSelect1 := mockAdRepository.
+	call := mockAdRepository.
 		EXPECT().
-		Select(gomock.Eq(ad.Id)).
-		Return(ad, nil)
-	callInsertAdUserExecution := mockAdRepository.
+		Select(gomock.Eq(expectedAd.Id)).
+		Return(expectedAd, nil)
+
+	adPhoto := &models.AdPhoto{
+		Id:               1,
+		AdId:             expectedAd.Id,
+		ContentType:      "image/png",
+		Size:             1024,
+		BlobKey:          "ads/1/photo",
+		ThumbnailBlobKey: "ads/1/photo_thumbnail",
+	}
+
+	call = mockAdRepository.
 		EXPECT().
-		InsertAdUserExecution(gomock.Eq(adUserExecution)).
-		Return(adUserExecution, nil).
-		After(callSelect1)
-	mockAdRepository.
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{expectedAd.Id})).
+		Return(&models.AdPhotos{adPhoto}, nil).
+		After(call)
+	call = mockAdRepository.
 		EXPECT().
-		Select(gomock.Eq(ad.Id)).
+		Delete(gomock.Eq(expectedAd.Id), gomock.Eq(expectedAd.Version)).
 		Return(expectedAd, nil).
-		After(callInsertAdUserExecution)
+		After(call)
+	mockBlobStore.
+		EXPECT().
+		Delete(gomock.Eq(adPhoto.BlobKey)).
+		Return(nil).
+		After(call)
+	mockBlobStore.
+		EXPECT().
+		Delete(gomock.Eq(adPhoto.ThumbnailBlobKey)).
+		Return(consts.RepErrNotFound).
+		After(call)
 
-	response_ := adUsecase.SetAdUserExecutor(adUserExecution.UserExecutorId, adUserExecution.AdId)
+	response_ := adUsecase.Delete(expectedAd.UserAuthorId, expectedAd.Id, expectedAd.Version)
 	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
 }
 
-func TestAdUsecase_SetAdUserExecutor_self(t *testing.T) {
+func TestAdUsecase_Delete_forbidden(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
-	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:05")
+	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
 	assert.Nil(t, err)
-	ad := &models.Ad{
+	expectedAd := &models.Ad{
 		Id:             1,
 		UserAuthorId:   101,
 		UserAuthorVkId: 201,
@@ -432,44 +655,260 @@ func TestAdUsecase_SetAdUserExecutor_self(t *testing.T) {
 		MinPrice:       500,
 		Comment:        "Поеду на велосипеде",
 	}
-	adUserExecution := &models.AdUserExecution{
-		AdId:           ad.Id,
-		UserExecutorId: ad.UserAuthorId,
-	}
 
 	mockAdRepository.
 		EXPECT().
-		Select(gomock.Eq(ad.Id)).
-		Return(ad, nil)
+		Select(gomock.Eq(expectedAd.Id)).
+		Return(expectedAd, nil)
 
-	response_ := adUsecase.SetAdUserExecutor(adUserExecution.UserExecutorId, adUserExecution.AdId)
+	response_ := adUsecase.Delete(expectedAd.UserAuthorId+1, expectedAd.Id, expectedAd.Version)
 	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
 }
 
-func TestAdUsecase_SetAdUserExecutor_conflict(t *testing.T) {
+func TestAdUsecase_Delete_notFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	const id uint32 = 1
+	const userAuthorId uint32 = 101
+	const version uint32 = 1
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(id)).
+		Return(nil, consts.RepErrNotFound)
+
+	response_ := adUsecase.Delete(userAuthorId, id, version)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}
+
+func TestAdUsecase_Delete_concurrentlyChanged(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
+	assert.Nil(t, err)
+	existingAd := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+		Version:        2,
+	}
+
+	call := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(existingAd.Id)).
+		Return(existingAd, nil)
+	call = mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{existingAd.Id})).
+		Return(&models.AdPhotos{}, nil).
+		After(call)
+	mockAdRepository.
+		EXPECT().
+		Delete(gomock.Eq(existingAd.Id), gomock.Eq(existingAd.Version)).
+		Return(nil, consts.RepErrPreconditionFailed).
+		After(call)
+
+	response_ := adUsecase.Delete(existingAd.UserAuthorId, existingAd.Id, existingAd.Version)
+	assert.Equal(t, response.NewEmptyResponse(consts.PreconditionFailed), response_)
+}
+
+func TestAdUsecase_Delete_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("22.11.2021 16:55")
+	assert.Nil(t, err)
+	existingAd := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusPickedUp,
+		Version:        2,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(existingAd.Id)).
+		Return(existingAd, nil)
+
+	response_ := adUsecase.Delete(existingAd.UserAuthorId, existingAd.Id, existingAd.Version)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_Search(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	adsSearch := &models.AdsSearch{
+		LocDep:         pointy.String("Общежитие"),
+		LocArr:         pointy.String("СК"),
+		MinDateTimeArr: dateTimeArr,
+	}
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:             1,
+			UserAuthorId:   101,
+			UserAuthorVkId: 201,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Тубус",
+			MinPrice:       500,
+			Comment:        "Поеду на коньках",
+		},
+		&models.Ad{
+			Id:             2,
+			UserAuthorId:   102,
+			UserAuthorVkId: 202,
+			LocDep:         "Общежитие №9",
+			LocArr:         "СК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Спортивная форма",
+			MinPrice:       600,
+			Comment:        "Поеду на роликах :)",
+		},
+	}
+
+	mockAdRepository.
+		EXPECT().
+		SelectArray(gomock.Eq(adsSearch)).
+		Return(expectedAds, nil)
+
+	mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{1, 2})).
+		Return(&models.AdPhotos{}, nil)
+
+	response_ := adUsecase.Search(adsSearch)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAds), response_)
+}
+
+func TestAdUsecase_SetAdUserExecutor(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
-	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:10")
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
 	assert.Nil(t, err)
 	ad := &models.Ad{
-		Id:               1,
-		UserAuthorId:     101,
-		UserAuthorVkId:   201,
-		UserExecutorVkId: pointy.Uint32(202),
-		LocDep:           "Общежитие №10",
-		LocArr:           "УЛК",
-		DateTimeArr:      *dateTimeArr,
-		Item:             "Зачётная книжка",
-		MinPrice:         500,
-		Comment:          "Поеду на велосипеде",
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
 	}
-	adUserExecution := &models.AdUserExecution{
+	adOffer := &models.AdOffer{
 		AdId:           ad.Id,
 		UserExecutorId: ad.UserAuthorId + 1,
+		Price:          ad.MinPrice,
+	}
+	expectedAd := &models.Ad{
+		Id:               ad.Id,
+		UserAuthorId:     ad.UserAuthorId,
+		UserAuthorVkId:   ad.UserAuthorVkId,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           ad.LocDep,
+		LocArr:           ad.LocArr,
+		DateTimeArr:      ad.DateTimeArr,
+		Item:             ad.Item,
+		MinPrice:         ad.MinPrice,
+		Comment:          ad.Comment,
+		Status:           models.AdStatusAssigned,
+	}
+
+	callSelect1 := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	callInsertAdOfferAccepted := mockAdRepository.
+		EXPECT().
+		InsertAdOfferAccepted(gomock.Eq(adOffer)).
+		Return(&models.AdOffer{
+			Id:             1,
+			AdId:           adOffer.AdId,
+			UserExecutorId: adOffer.UserExecutorId,
+			Price:          adOffer.Price,
+			Status:         models.AdOfferStatusAccepted,
+		}, nil).
+		After(callSelect1)
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(expectedAd, nil).
+		After(callInsertAdOfferAccepted)
+
+	response_ := adUsecase.SetAdUserExecutor(adOffer.UserExecutorId, adOffer.AdId)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
+}
+
+func TestAdUsecase_SetAdUserExecutor_self(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
 	}
 
 	mockAdRepository.
@@ -477,20 +916,22 @@ func TestAdUsecase_SetAdUserExecutor_conflict(t *testing.T) {
 		Select(gomock.Eq(ad.Id)).
 		Return(ad, nil)
 
-	response_ := adUsecase.SetAdUserExecutor(adUserExecution.UserExecutorId, adUserExecution.AdId)
-	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+	response_ := adUsecase.SetAdUserExecutor(ad.UserAuthorId, ad.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
 }
 
-func TestAdUsecase_UnsetAdUserExecutor(t *testing.T) {
+func TestAdUsecase_SetAdUserExecutor_conflict(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
 	assert.Nil(t, err)
-	expectedAd := &models.Ad{
+	ad := &models.Ad{
 		Id:             1,
 		UserAuthorId:   101,
 		UserAuthorVkId: 201,
@@ -500,16 +941,78 @@ func TestAdUsecase_UnsetAdUserExecutor(t *testing.T) {
 		Item:           "Зачётная книжка",
 		MinPrice:       500,
 		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
+	adOffer := &models.AdOffer{
+		AdId:           ad.Id,
+		UserExecutorId: ad.UserAuthorId + 1,
+		Price:          ad.MinPrice,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		InsertAdOfferAccepted(gomock.Eq(adOffer)).
+		Return(nil, consts.RepErrConflict).
+		After(callSelect)
+
+	response_ := adUsecase.SetAdUserExecutor(adOffer.UserExecutorId, adOffer.AdId)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_UnsetAdUserExecutor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+	expectedAd := &models.Ad{
+		Id:             ad.Id,
+		UserAuthorId:   ad.UserAuthorId,
+		UserAuthorVkId: ad.UserAuthorVkId,
+		LocDep:         ad.LocDep,
+		LocArr:         ad.LocArr,
+		DateTimeArr:    ad.DateTimeArr,
+		Item:           ad.Item,
+		MinPrice:       ad.MinPrice,
+		Comment:        ad.Comment,
+		Status:         models.AdStatusOpen,
 	}
 	adUserExecution := &models.AdUserExecution{
 		AdId:           expectedAd.Id,
 		UserExecutorId: expectedAd.UserAuthorId + 1,
 	}
 
-	callSelect1 := mockAdRepository.
+	callSelectAdUserExecution := mockAdRepository.
 		EXPECT().
 		SelectAdUserExecution(gomock.Eq(adUserExecution.AdId)).
 		Return(adUserExecution, nil)
+	callSelect1 := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil).
+		After(callSelectAdUserExecution)
 	callInsertAdUserExecution := mockAdRepository.
 		EXPECT().
 		DeleteAdUserExecution(gomock.Eq(adUserExecution.AdId)).
@@ -530,7 +1033,9 @@ func TestAdUsecase_UnsetAdUserExecutor_notSelf(t *testing.T) {
 	defer controller.Finish()
 
 	mockAdRepository := mock_ad.NewMockRepository(controller)
-	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
 
 	const adId uint32 = 1
 	const userAuthorId uint32 = 101
@@ -547,3 +1052,2116 @@ func TestAdUsecase_UnsetAdUserExecutor_notSelf(t *testing.T) {
 	response_ := adUsecase.UnsetAdUserExecutor(adUserExecution.UserExecutorId+1, adUserExecution.AdId)
 	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
 }
+
+func TestAdUsecase_UnsetAdUserExecutor_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusPickedUp,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: ad.UserAuthorId + 1,
+	}
+
+	callSelectAdUserExecution := mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(adUserExecution.AdId)).
+		Return(adUserExecution, nil)
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil).
+		After(callSelectAdUserExecution)
+
+	response_ := adUsecase.UnsetAdUserExecutor(adUserExecution.UserExecutorId, adUserExecution.AdId)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_PickUp(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: ad.UserAuthorId + 1,
+	}
+	expectedAd := &models.Ad{
+		Id:               ad.Id,
+		UserAuthorId:     ad.UserAuthorId,
+		UserAuthorVkId:   ad.UserAuthorVkId,
+		UserExecutorVkId: ad.UserExecutorVkId,
+		LocDep:           ad.LocDep,
+		LocArr:           ad.LocArr,
+		DateTimeArr:      ad.DateTimeArr,
+		Item:             ad.Item,
+		MinPrice:         ad.MinPrice,
+		Comment:          ad.Comment,
+		Status:           models.AdStatusPickedUp,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	callSelectAdUserExecution := mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+	mockAdRepository.
+		EXPECT().
+		UpdateStatus(gomock.Eq(ad.Id), gomock.Eq(models.AdStatusAssigned), gomock.Eq(models.AdStatusPickedUp),
+			gomock.Eq(adUserExecution.UserExecutorId)).
+		Return(expectedAd, nil).
+		After(callSelectAdUserExecution)
+
+	response_ := adUsecase.PickUp(adUserExecution.UserExecutorId, ad.Id)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
+}
+
+func TestAdUsecase_PickUp_notExecutor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: ad.UserAuthorId + 1,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+
+	response_ := adUsecase.PickUp(ad.UserAuthorId, ad.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_Deliver_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: ad.UserAuthorId + 1,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+
+	response_ := adUsecase.Deliver(adUserExecution.UserExecutorId, ad.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_Confirm(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusDelivered,
+	}
+	expectedAd := &models.Ad{
+		Id:               ad.Id,
+		UserAuthorId:     ad.UserAuthorId,
+		UserAuthorVkId:   ad.UserAuthorVkId,
+		UserExecutorVkId: ad.UserExecutorVkId,
+		LocDep:           ad.LocDep,
+		LocArr:           ad.LocArr,
+		DateTimeArr:      ad.DateTimeArr,
+		Item:             ad.Item,
+		MinPrice:         ad.MinPrice,
+		Comment:          ad.Comment,
+		Status:           models.AdStatusConfirmed,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		UpdateStatus(gomock.Eq(ad.Id), gomock.Eq(models.AdStatusDelivered), gomock.Eq(models.AdStatusConfirmed),
+			gomock.Eq(ad.UserAuthorId)).
+		Return(expectedAd, nil).
+		After(callSelect)
+
+	response_ := adUsecase.Confirm(ad.UserAuthorId, ad.Id)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
+}
+
+func TestAdUsecase_Cancel_notAuthor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+
+	response_ := adUsecase.Cancel(ad.UserAuthorId+1, ad.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_Cancel_concurrentlyChanged(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		UpdateStatus(gomock.Eq(ad.Id), gomock.Eq(models.AdStatusOpen), gomock.Eq(models.AdStatusCancelled),
+			gomock.Eq(ad.UserAuthorId)).
+		Return(nil, consts.RepErrNotFound).
+		After(callSelect)
+
+	response_ := adUsecase.Cancel(ad.UserAuthorId, ad.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_CreateAdReview(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusConfirmed,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	adReview := &models.AdReview{
+		AdId:         ad.Id,
+		UserAuthorId: adUserExecution.UserExecutorId,
+		Rating:       5,
+		Comment:      "Всё чётко",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedAdReview := &models.AdReview{
+		Id:           1,
+		AdId:         adReview.AdId,
+		UserAuthorId: adReview.UserAuthorId,
+		UserTargetId: ad.UserAuthorId,
+		Rating:       adReview.Rating,
+		Comment:      adReview.Comment,
+		DateTime:     *dateTime,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	callSelectAdUserExecution := mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+	mockAdRepository.
+		EXPECT().
+		InsertAdReview(gomock.Eq(&models.AdReview{
+			AdId:         adReview.AdId,
+			UserAuthorId: adReview.UserAuthorId,
+			UserTargetId: ad.UserAuthorId,
+			Rating:       adReview.Rating,
+			Comment:      adReview.Comment,
+		})).
+		Return(expectedAdReview, nil).
+		After(callSelectAdUserExecution)
+
+	response_ := adUsecase.CreateAdReview(adReview)
+	assert.Equal(t, response.NewResponse(consts.Created, expectedAdReview), response_)
+}
+
+func TestAdUsecase_CreateAdReview_notCompleted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusDelivered,
+	}
+	adReview := &models.AdReview{
+		AdId:         ad.Id,
+		UserAuthorId: ad.UserAuthorId,
+		Rating:       5,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+
+	response_ := adUsecase.CreateAdReview(adReview)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_CreateAdReview_stranger(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusConfirmed,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	adReview := &models.AdReview{
+		AdId:         ad.Id,
+		UserAuthorId: 103,
+		Rating:       1,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+
+	response_ := adUsecase.CreateAdReview(adReview)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_CreateAdReview_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusConfirmed,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	adReview := &models.AdReview{
+		AdId:         ad.Id,
+		UserAuthorId: ad.UserAuthorId,
+		Rating:       4,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	callSelectAdUserExecution := mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+	mockAdRepository.
+		EXPECT().
+		InsertAdReview(gomock.Eq(&models.AdReview{
+			AdId:         adReview.AdId,
+			UserAuthorId: adReview.UserAuthorId,
+			UserTargetId: adUserExecution.UserExecutorId,
+			Rating:       adReview.Rating,
+		})).
+		Return(nil, consts.RepErrConflict).
+		After(callSelectAdUserExecution)
+
+	response_ := adUsecase.CreateAdReview(adReview)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_GetAdHandover(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+	expectedAdHandover := &models.AdHandover{
+		AdId: ad.Id,
+		Code: "042519",
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdHandover(gomock.Eq(ad.Id)).
+		Return(expectedAdHandover, nil).
+		After(callSelect)
+
+	response_ := adUsecase.GetAdHandover(ad.UserAuthorId, ad.Id, false)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdHandover), response_)
+}
+
+func TestAdUsecase_GetAdHandover_qr(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusPickedUp,
+	}
+	adHandover := &models.AdHandover{
+		AdId: ad.Id,
+		Code: "042519",
+	}
+	expectedAdHandoverQr := &models.AdHandoverQr{
+		Payload: "handover:1:042519",
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdHandover(gomock.Eq(ad.Id)).
+		Return(adHandover, nil).
+		After(callSelect)
+
+	response_ := adUsecase.GetAdHandover(ad.UserAuthorId, ad.Id, true)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdHandoverQr), response_)
+}
+
+func TestAdUsecase_GetAdHandover_notAuthor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+
+	response_ := adUsecase.GetAdHandover(ad.UserAuthorId+1, ad.Id, false)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_ConfirmAdHandover(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusDelivered,
+	}
+	expectedAd := &models.Ad{
+		Id:               ad.Id,
+		UserAuthorId:     ad.UserAuthorId,
+		UserAuthorVkId:   ad.UserAuthorVkId,
+		UserExecutorVkId: ad.UserExecutorVkId,
+		LocDep:           ad.LocDep,
+		LocArr:           ad.LocArr,
+		DateTimeArr:      ad.DateTimeArr,
+		Item:             ad.Item,
+		MinPrice:         ad.MinPrice,
+		Comment:          ad.Comment,
+		Status:           models.AdStatusConfirmed,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	const code = "042519"
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	callSelectAdUserExecution := mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+	callUpdateAdHandoverAttempt := mockAdRepository.
+		EXPECT().
+		UpdateAdHandoverAttempt(gomock.Eq(ad.Id), gomock.Eq(code), gomock.Any()).
+		Return(true, nil).
+		After(callSelectAdUserExecution)
+	mockAdRepository.
+		EXPECT().
+		UpdateStatus(gomock.Eq(ad.Id), gomock.Eq(models.AdStatusDelivered), gomock.Eq(models.AdStatusConfirmed),
+			gomock.Eq(adUserExecution.UserExecutorId)).
+		Return(expectedAd, nil).
+		After(callUpdateAdHandoverAttempt)
+
+	response_ := adUsecase.ConfirmAdHandover(adUserExecution.UserExecutorId, ad.Id, code)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAd), response_)
+}
+
+func TestAdUsecase_ConfirmAdHandover_wrongCode(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusPickedUp,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	const code = "042519"
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	callSelectAdUserExecution := mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+	mockAdRepository.
+		EXPECT().
+		UpdateAdHandoverAttempt(gomock.Eq(ad.Id), gomock.Eq(code), gomock.Any()).
+		Return(false, nil).
+		After(callSelectAdUserExecution)
+
+	response_ := adUsecase.ConfirmAdHandover(adUserExecution.UserExecutorId, ad.Id, code)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_ConfirmAdHandover_locked(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusPickedUp,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	const code = "042519"
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	callSelectAdUserExecution := mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+	mockAdRepository.
+		EXPECT().
+		UpdateAdHandoverAttempt(gomock.Eq(ad.Id), gomock.Eq(code), gomock.Any()).
+		Return(false, consts.RepErrNotFound).
+		After(callSelectAdUserExecution)
+
+	response_ := adUsecase.ConfirmAdHandover(adUserExecution.UserExecutorId, ad.Id, code)
+	assert.Equal(t, response.NewEmptyResponse(consts.TooManyRequests), response_)
+}
+
+func TestAdUsecase_ConfirmAdHandover_notExecutor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusDelivered,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	const code = "042519"
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(callSelect)
+
+	response_ := adUsecase.ConfirmAdHandover(adUserExecution.UserExecutorId+1, ad.Id, code)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_CreateAdOffer(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
+	adOffer := &models.AdOffer{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+		Price:          450,
+		Comment:        "Могу через час",
+	}
+	expectedAdOffer := &models.AdOffer{
+		Id:               1,
+		AdId:             adOffer.AdId,
+		UserExecutorId:   adOffer.UserExecutorId,
+		UserExecutorVkId: 202,
+		Price:            adOffer.Price,
+		Comment:          adOffer.Comment,
+		Status:           models.AdOfferStatusPending,
+	}
+
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		InsertAdOffer(gomock.Eq(adOffer)).
+		Return(expectedAdOffer, nil).
+		After(callSelect)
+
+	response_ := adUsecase.CreateAdOffer(adOffer)
+	assert.Equal(t, response.NewResponse(consts.Created, expectedAdOffer), response_)
+}
+
+func TestAdUsecase_CreateAdOffer_self(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
+	adOffer := &models.AdOffer{
+		AdId:           ad.Id,
+		UserExecutorId: ad.UserAuthorId,
+		Price:          450,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+
+	response_ := adUsecase.CreateAdOffer(adOffer)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_ListAdOffers_notAuthor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+
+	adOffersSearch := &models.AdOffersSearch{
+		AdId: ad.Id,
+	}
+
+	response_ := adUsecase.ListAdOffers(ad.UserAuthorId+1, adOffersSearch)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_ListAdRevisions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(202),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+	adUserExecution := &models.AdUserExecution{
+		AdId:           ad.Id,
+		UserExecutorId: 102,
+	}
+	expectedAdRevisions := &models.AdRevisions{
+		&models.AdRevision{
+			Id:       1,
+			AdId:     ad.Id,
+			UserId:   pointy.Uint32(ad.UserAuthorId),
+			UserVkId: pointy.Uint32(ad.UserAuthorVkId),
+			Action:   models.AdRevisionActionInsert,
+			DateTime: *dateTimeArr,
+		},
+	}
+
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdRevisionArrayByAdId(gomock.Eq(ad.Id)).
+		Return(expectedAdRevisions, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(adUserExecution, nil).
+		After(call)
+
+	response_ := adUsecase.ListAdRevisions(adUserExecution.UserExecutorId, ad.Id)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdRevisions), response_)
+}
+
+func TestAdUsecase_ListAdRevisions_deleted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTime, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	const adId uint32 = 1
+	const userAuthorId uint32 = 101
+	expectedAdRevisions := &models.AdRevisions{
+		&models.AdRevision{
+			Id:       1,
+			AdId:     adId,
+			UserId:   pointy.Uint32(userAuthorId),
+			UserVkId: pointy.Uint32(201),
+			Action:   models.AdRevisionActionInsert,
+			DateTime: *dateTime,
+		},
+		&models.AdRevision{
+			Id:       2,
+			AdId:     adId,
+			UserId:   pointy.Uint32(userAuthorId),
+			UserVkId: pointy.Uint32(201),
+			Action:   models.AdRevisionActionDelete,
+			DateTime: *dateTime,
+		},
+	}
+
+	mockAdRepository.
+		EXPECT().
+		SelectAdRevisionArrayByAdId(gomock.Eq(adId)).
+		Return(expectedAdRevisions, nil)
+
+	response_ := adUsecase.ListAdRevisions(userAuthorId, adId)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdRevisions), response_)
+}
+
+func TestAdUsecase_ListAdRevisions_notFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	const adId uint32 = 1
+
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdRevisionArrayByAdId(gomock.Eq(adId)).
+		Return(&models.AdRevisions{}, nil)
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(adId)).
+		Return(nil, consts.RepErrNotFound).
+		After(call)
+
+	response_ := adUsecase.ListAdRevisions(101, adId)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}
+
+func TestAdUsecase_ListAdRevisions_forbidden(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
+	}
+
+	//the ad predates the revisions, so the author is taken from it
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdRevisionArrayByAdId(gomock.Eq(ad.Id)).
+		Return(&models.AdRevisions{}, nil)
+	call = mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil).
+		After(call)
+	mockAdRepository.
+		EXPECT().
+		SelectAdUserExecution(gomock.Eq(ad.Id)).
+		Return(nil, consts.RepErrNotFound).
+		After(call)
+
+	response_ := adUsecase.ListAdRevisions(ad.UserAuthorId+1, ad.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_CreateAdPhotos(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
+	}
+	buffer := new(bytes.Buffer)
+	assert.Nil(t, png.Encode(buffer, image.NewRGBA(image.Rect(0, 0, 640, 480))))
+	photo := buffer.Bytes()
+	expectedAdPhotos := &models.AdPhotos{
+		&models.AdPhoto{
+			Id:          1,
+			AdId:        ad.Id,
+			ContentType: "image/png",
+			Size:        uint32(len(photo)),
+		},
+	}
+
+	call := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	call = mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{ad.Id})).
+		Return(&models.AdPhotos{}, nil).
+		After(call)
+	call = mockBlobStore.
+		EXPECT().
+		Put(gomock.Any(), gomock.Eq(photo)).
+		DoAndReturn(func(key string, data []byte) error {
+			(*expectedAdPhotos)[0].BlobKey = key
+			(*expectedAdPhotos)[0].ThumbnailBlobKey = key + "_thumbnail"
+			return nil
+		}).
+		After(call)
+	call = mockBlobStore.
+		EXPECT().
+		Put(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(key string, data []byte) error {
+			assert.Equal(t, (*expectedAdPhotos)[0].ThumbnailBlobKey, key)
+			thumbnail, err := jpeg.Decode(bytes.NewReader(data))
+			assert.Nil(t, err)
+			assert.Equal(t, image.Rect(0, 0, 320, 240), thumbnail.Bounds())
+			return nil
+		}).
+		After(call)
+	mockAdRepository.
+		EXPECT().
+		InsertAdPhotoArray(gomock.Any()).
+		DoAndReturn(func(adPhotos *models.AdPhotos) (*models.AdPhotos, error) {
+			(*adPhotos)[0].Id = (*expectedAdPhotos)[0].Id
+			return adPhotos, nil
+		}).
+		After(call)
+
+	response_ := adUsecase.CreateAdPhotos(ad.UserAuthorId, ad.Id, [][]byte{photo})
+	assert.Equal(t, response.NewResponse(consts.Created, expectedAdPhotos), response_)
+}
+
+func TestAdUsecase_CreateAdPhotos_tooManyPixels(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
+	}
+	//only the header of the photo is read, so its pixels are never allocated
+	photo := []byte("GIF89a\x40\x1f\x40\x1f\x00\x00\x00")
+
+	call := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{ad.Id})).
+		Return(&models.AdPhotos{}, nil).
+		After(call)
+
+	response_ := adUsecase.CreateAdPhotos(ad.UserAuthorId, ad.Id, [][]byte{photo})
+	assert.Equal(t, response.NewEmptyResponse(consts.PayloadTooLarge), response_)
+}
+
+func TestAdUsecase_CreateAdPhotos_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
+	}
+	buffer := new(bytes.Buffer)
+	assert.Nil(t, png.Encode(buffer, image.NewRGBA(image.Rect(0, 0, 64, 48))))
+	photo := buffer.Bytes()
+
+	call := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	call = mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{ad.Id})).
+		Return(&models.AdPhotos{}, nil).
+		After(call)
+	call = mockBlobStore.
+		EXPECT().
+		Put(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(2).
+		After(call)
+	call = mockAdRepository.
+		EXPECT().
+		InsertAdPhotoArray(gomock.Any()).
+		Return(nil, consts.RepErrConflict).
+		After(call)
+	mockBlobStore.
+		EXPECT().
+		Delete(gomock.Any()).
+		Return(nil).
+		Times(2).
+		After(call)
+
+	response_ := adUsecase.CreateAdPhotos(ad.UserAuthorId, ad.Id, [][]byte{photo})
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestAdUsecase_CreateAdPhotos_unsupportedMediaType(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
+	}
+
+	call := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+	mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{ad.Id})).
+		Return(&models.AdPhotos{}, nil).
+		After(call)
+
+	response_ := adUsecase.CreateAdPhotos(ad.UserAuthorId, ad.Id, [][]byte{[]byte("%PDF-1.4")})
+	assert.Equal(t, response.NewEmptyResponse(consts.UnsupportedMediaType), response_)
+}
+
+func TestAdUsecase_CreateAdPhotos_notAuthor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		Status:         models.AdStatusOpen,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil)
+
+	response_ := adUsecase.CreateAdPhotos(ad.UserAuthorId+1, ad.Id, [][]byte{{}})
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_GetAdPhoto_thumbnail(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	adPhoto := &models.AdPhoto{
+		Id:               2,
+		AdId:             1,
+		ContentType:      "image/png",
+		Size:             1024,
+		BlobKey:          "ads/1/photo",
+		ThumbnailBlobKey: "ads/1/photo_thumbnail",
+	}
+	expectedAdPhotoContent := &models.AdPhotoContent{
+		ContentType: "image/jpeg",
+		Data:        []byte{0xff, 0xd8, 0xff},
+	}
+
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdPhoto(gomock.Eq(adPhoto.Id)).
+		Return(adPhoto, nil)
+	mockBlobStore.
+		EXPECT().
+		Get(gomock.Eq(adPhoto.ThumbnailBlobKey)).
+		Return(expectedAdPhotoContent.Data, nil).
+		After(call)
+
+	response_ := adUsecase.GetAdPhoto(adPhoto.AdId, adPhoto.Id, true)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdPhotoContent), response_)
+}
+
+func TestAdUsecase_GetAdPhoto_otherAd(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	adPhoto := &models.AdPhoto{
+		Id:   2,
+		AdId: 1,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		SelectAdPhoto(gomock.Eq(adPhoto.Id)).
+		Return(adPhoto, nil)
+
+	response_ := adUsecase.GetAdPhoto(adPhoto.AdId+1, adPhoto.Id, false)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}
+
+func TestAdUsecase_AcceptAdOffer(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:             1,
+		UserAuthorId:   101,
+		UserAuthorVkId: 201,
+		LocDep:         "Общежитие №10",
+		LocArr:         "УЛК",
+		DateTimeArr:    *dateTimeArr,
+		Item:           "Зачётная книжка",
+		MinPrice:       500,
+		Comment:        "Поеду на велосипеде",
+		Status:         models.AdStatusOpen,
+	}
+	adOffer := &models.AdOffer{
+		Id:               1,
+		AdId:             ad.Id,
+		UserExecutorId:   102,
+		UserExecutorVkId: 202,
+		Price:            450,
+		Status:           models.AdOfferStatusPending,
+	}
+	expectedAdOffer := &models.AdOffer{
+		Id:               adOffer.Id,
+		AdId:             adOffer.AdId,
+		UserExecutorId:   adOffer.UserExecutorId,
+		UserExecutorVkId: adOffer.UserExecutorVkId,
+		Price:            adOffer.Price,
+		Status:           models.AdOfferStatusAccepted,
+	}
+
+	callSelectAdOffer := mockAdRepository.
+		EXPECT().
+		SelectAdOffer(gomock.Eq(adOffer.Id)).
+		Return(adOffer, nil)
+	callSelect := mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil).
+		After(callSelectAdOffer)
+	mockAdRepository.
+		EXPECT().
+		UpdateAdOfferStatus(gomock.Eq(adOffer.Id), gomock.Eq(models.AdOfferStatusPending),
+			gomock.Eq(models.AdOfferStatusAccepted)).
+		Return(expectedAdOffer, nil).
+		After(callSelect)
+
+	response_ := adUsecase.AcceptAdOffer(ad.UserAuthorId, ad.Id, adOffer.Id)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdOffer), response_)
+}
+
+func TestAdUsecase_AcceptAdOffer_alreadyAccepted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("05.12.2021 20:00")
+	assert.Nil(t, err)
+	ad := &models.Ad{
+		Id:               1,
+		UserAuthorId:     101,
+		UserAuthorVkId:   201,
+		UserExecutorVkId: pointy.Uint32(203),
+		LocDep:           "Общежитие №10",
+		LocArr:           "УЛК",
+		DateTimeArr:      *dateTimeArr,
+		Item:             "Зачётная книжка",
+		MinPrice:         500,
+		Comment:          "Поеду на велосипеде",
+		Status:           models.AdStatusAssigned,
+	}
+	adOffer := &models.AdOffer{
+		Id:               1,
+		AdId:             ad.Id,
+		UserExecutorId:   102,
+		UserExecutorVkId: 202,
+		Price:            450,
+		Status:           models.AdOfferStatusPending,
+	}
+
+	callSelectAdOffer := mockAdRepository.
+		EXPECT().
+		SelectAdOffer(gomock.Eq(adOffer.Id)).
+		Return(adOffer, nil)
+	mockAdRepository.
+		EXPECT().
+		Select(gomock.Eq(ad.Id)).
+		Return(ad, nil).
+		After(callSelectAdOffer)
+
+	response_ := adUsecase.AcceptAdOffer(ad.UserAuthorId, ad.Id, adOffer.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_RejectAdOffer_otherAd(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	adOffer := &models.AdOffer{
+		Id:               1,
+		AdId:             2,
+		UserExecutorId:   102,
+		UserExecutorVkId: 202,
+		Price:            450,
+		Status:           models.AdOfferStatusPending,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		SelectAdOffer(gomock.Eq(adOffer.Id)).
+		Return(adOffer, nil)
+
+	response_ := adUsecase.RejectAdOffer(101, adOffer.AdId+1, adOffer.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}
+
+func TestAdUsecase_Search_nextCursor(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	order := models.AdsSearchOrderMinPriceAsc
+	adsSearch := &models.AdsSearch{
+		MinDateTimeArr: dateTimeArr,
+		Order:          &order,
+		Limit:          pointy.Uint32(1),
+	}
+	ads := &models.Ads{
+		&models.Ad{
+			Id:             1,
+			UserAuthorId:   101,
+			UserAuthorVkId: 201,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Тубус",
+			MinPrice:       500,
+			Comment:        "Поеду на коньках",
+			Status:         models.AdStatusOpen,
+		},
+		&models.Ad{
+			Id:             2,
+			UserAuthorId:   102,
+			UserAuthorVkId: 202,
+			LocDep:         "Общежитие №9",
+			LocArr:         "СК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Спортивная форма",
+			MinPrice:       600,
+			Comment:        "Поеду на роликах :)",
+			Status:         models.AdStatusOpen,
+		},
+	}
+	expectedAds := &models.Ads{(*ads)[0]}
+	expectedNextCursor := models.NewAdsSearchCursor(order, (*ads)[0]).String()
+
+	mockAdRepository.
+		EXPECT().
+		SelectArray(gomock.Eq(adsSearch)).
+		DoAndReturn(func(adsSearch *models.AdsSearch) (*models.Ads, error) {
+			assert.Equal(t, uint32(2), *adsSearch.Limit)
+			return ads, nil
+		})
+
+	mockAdRepository.
+		EXPECT().
+		SelectAdPhotoArrayByAdIds(gomock.Eq([]uint32{1})).
+		Return(&models.AdPhotos{}, nil)
+
+	response_ := adUsecase.Search(adsSearch)
+	assert.Equal(t, response.NewPageResponse(consts.OK, expectedAds, expectedNextCursor), response_)
+}
+
+func TestAdUsecase_Search_cursorOrderMismatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	adsSearch := &models.AdsSearch{
+		Cursor: &models.AdsSearchCursor{
+			Order:    models.AdsSearchOrderMinPriceAsc,
+			MinPrice: 500,
+			Id:       1,
+		},
+	}
+
+	response_ := adUsecase.Search(adsSearch)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestAdUsecase_Search_nearDefaultRadius(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	adsSearch := &models.AdsSearch{
+		NearArr: &models.GeoPoint{Lat: 55.765, Lon: 37.685},
+	}
+	expectedAds := &models.Ads{}
+
+	mockAdRepository.
+		EXPECT().
+		SelectArray(gomock.Eq(adsSearch)).
+		DoAndReturn(func(adsSearch *models.AdsSearch) (*models.Ads, error) {
+			assert.Equal(t, uint32(1000), *adsSearch.RadiusM)
+			return expectedAds, nil
+		})
+
+	response_ := adUsecase.Search(adsSearch)
+	assert.Equal(t, response.NewPageResponse(consts.OK, expectedAds, ""), response_)
+}
+
+func TestAdUsecase_Search_userExecutorId(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	adsSearch := &models.AdsSearch{
+		UserExecutorId: pointy.Uint32(102),
+		Active:         pointy.Bool(false),
+	}
+	expectedAds := &models.Ads{}
+
+	mockAdRepository.
+		EXPECT().
+		SelectArray(gomock.Eq(adsSearch)).
+		DoAndReturn(func(adsSearch *models.AdsSearch) (*models.Ads, error) {
+			assert.Nil(t, adsSearch.Status)
+			return expectedAds, nil
+		})
+
+	response_ := adUsecase.Search(adsSearch)
+	assert.Equal(t, response.NewPageResponse(consts.OK, expectedAds, ""), response_)
+}
+
+func TestAdUsecase_Expire(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	gracePeriod := 2 * time.Hour
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:             1,
+			UserAuthorId:   101,
+			UserAuthorVkId: 201,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Тубус",
+			MinPrice:       500,
+			Comment:        "Поеду на коньках",
+			Status:         models.AdStatusExpired,
+		},
+	}
+
+	before := time.Now()
+	call := mockAdRepository.
+		EXPECT().
+		UpdateStatusExpired(gomock.Any()).
+		DoAndReturn(func(maxDateTimeArr time.Time) (*models.Ads, error) {
+			assert.False(t, maxDateTimeArr.Before(before.Add(-gracePeriod)))
+			assert.False(t, maxDateTimeArr.After(time.Now().Add(-gracePeriod)))
+			return expectedAds, nil
+		})
+	mockNotificationUsecase.
+		EXPECT().
+		NotifyAdExpired(gomock.Eq((*expectedAds)[0])).
+		Return(response.NewEmptyResponse(consts.OK)).
+		After(call)
+
+	response_ := adUsecase.Expire(gracePeriod)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAds), response_)
+}
+
+func TestAdUsecase_NotifyPending(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	dateTimeArr, err := timestamps.NewDateTime("04.11.2021 19:40")
+	assert.Nil(t, err)
+	gracePeriod := time.Minute
+	expectedAds := &models.Ads{
+		&models.Ad{
+			Id:             1,
+			UserAuthorId:   101,
+			UserAuthorVkId: 201,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Тубус",
+			MinPrice:       500,
+			Comment:        "Поеду на коньках",
+			Status:         models.AdStatusOpen,
+		},
+		&models.Ad{
+			Id:             2,
+			UserAuthorId:   101,
+			UserAuthorVkId: 201,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Зачётная книжка",
+			MinPrice:       300,
+			Comment:        "Поеду на коньках",
+			Status:         models.AdStatusCancelled,
+		},
+		&models.Ad{
+			Id:             3,
+			UserAuthorId:   101,
+			UserAuthorVkId: 201,
+			LocDep:         "Общежитие №10",
+			LocArr:         "УЛК",
+			DateTimeArr:    *dateTimeArr,
+			Item:           "Методичка",
+			MinPrice:       200,
+			Comment:        "Поеду на коньках",
+			Status:         models.AdStatusOpen,
+		},
+	}
+
+	before := time.Now()
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdArrayNotificationPending(gomock.Any()).
+		DoAndReturn(func(maxDateTime time.Time) (*models.Ads, error) {
+			assert.False(t, maxDateTime.Before(before.Add(-gracePeriod)))
+			assert.False(t, maxDateTime.After(time.Now().Add(-gracePeriod)))
+			return expectedAds, nil
+		})
+	call = mockNotificationUsecase.
+		EXPECT().
+		NotifyAdMatches(gomock.Eq((*expectedAds)[0])).
+		Return(response.NewEmptyResponse(consts.OK)).
+		After(call)
+	call = mockAdRepository.
+		EXPECT().
+		DeleteAdNotificationPending(gomock.Eq((*expectedAds)[0].Id)).
+		Return(nil).
+		After(call)
+	//the cancelled ad is not notified, its marker is dropped only
+	call = mockAdRepository.
+		EXPECT().
+		DeleteAdNotificationPending(gomock.Eq((*expectedAds)[1].Id)).
+		Return(nil).
+		After(call)
+	//the failed ad keeps its marker for the next sweep
+	mockNotificationUsecase.
+		EXPECT().
+		NotifyAdMatches(gomock.Eq((*expectedAds)[2])).
+		Return(response.NewErrorResponse(consts.InternalError, errors.New("connection reset"))).
+		After(call)
+
+	response_ := adUsecase.NotifyPending(gracePeriod)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAds), response_)
+}
+
+func TestAdUsecase_CreateAdTemplate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	timeDep, err := timestamps.NewTime("08:00")
+	assert.Nil(t, err)
+	timeArr, err := timestamps.NewTime("09:30")
+	assert.Nil(t, err)
+	adTemplate := &models.AdTemplate{
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		Item:         "Набор для лабораторной",
+		MinPrice:     300,
+		Comment:      "Каждый вторник",
+		EvenWeek:     true,
+		OddWeek:      true,
+		DaysOfWeek:   []uint32{2},
+		TimeDep:      timeDep,
+		TimeArr:      *timeArr,
+	}
+	expectedAdTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: adTemplate.UserAuthorId,
+		LocDep:       adTemplate.LocDep,
+		LocArr:       adTemplate.LocArr,
+		Item:         adTemplate.Item,
+		MinPrice:     adTemplate.MinPrice,
+		Comment:      adTemplate.Comment,
+		EvenWeek:     adTemplate.EvenWeek,
+		OddWeek:      adTemplate.OddWeek,
+		DaysOfWeek:   adTemplate.DaysOfWeek,
+		TimeDep:      adTemplate.TimeDep,
+		TimeArr:      adTemplate.TimeArr,
+	}
+
+	mockAdRepository.
+		EXPECT().
+		InsertAdTemplate(gomock.Eq(adTemplate)).
+		Return(expectedAdTemplate, nil)
+
+	response_ := adUsecase.CreateAdTemplate(adTemplate)
+	assert.Equal(t, response.NewResponse(consts.Created, expectedAdTemplate), response_)
+}
+
+func TestAdUsecase_CreateAdTemplate_noWeeks(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	timeArr, err := timestamps.NewTime("09:30")
+	assert.Nil(t, err)
+	adTemplate := &models.AdTemplate{
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		Item:         "Набор для лабораторной",
+		MinPrice:     300,
+		Comment:      "Каждый вторник",
+		DaysOfWeek:   []uint32{2},
+		TimeArr:      *timeArr,
+	}
+
+	response_ := adUsecase.CreateAdTemplate(adTemplate)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestAdUsecase_SetAdTemplatePaused(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	var userId uint32 = 101
+	existingAdTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: userId,
+		DaysOfWeek:   []uint32{2},
+	}
+	expectedAdTemplate := &models.AdTemplate{
+		Id:           existingAdTemplate.Id,
+		UserAuthorId: existingAdTemplate.UserAuthorId,
+		DaysOfWeek:   existingAdTemplate.DaysOfWeek,
+		Paused:       true,
+	}
+
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdTemplate(gomock.Eq(existingAdTemplate.Id)).
+		Return(existingAdTemplate, nil)
+	mockAdRepository.
+		EXPECT().
+		UpdateAdTemplatePaused(gomock.Eq(existingAdTemplate.Id), gomock.Eq(true)).
+		Return(expectedAdTemplate, nil).
+		After(call)
+
+	response_ := adUsecase.SetAdTemplatePaused(userId, existingAdTemplate.Id, true)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdTemplate), response_)
+}
+
+func TestAdUsecase_SetAdTemplatePaused_forbidden(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	existingAdTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: 101,
+		DaysOfWeek:   []uint32{2},
+	}
+
+	mockAdRepository.
+		EXPECT().
+		SelectAdTemplate(gomock.Eq(existingAdTemplate.Id)).
+		Return(existingAdTemplate, nil)
+
+	response_ := adUsecase.SetAdTemplatePaused(102, existingAdTemplate.Id, true)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestAdUsecase_SkipAdTemplateOccurrence_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	var userId uint32 = 101
+	existingAdTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: userId,
+		OddWeek:      true,
+		DaysOfWeek:   []uint32{2},
+	}
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdTemplate(gomock.Eq(existingAdTemplate.Id)).
+		Return(existingAdTemplate, nil)
+	mockAdRepository.
+		EXPECT().
+		InsertAdTemplateSkip(gomock.Eq(existingAdTemplate.Id), gomock.Eq(time.Time(*date))).
+		Return(consts.RepErrConflict).
+		After(call)
+
+	response_ := adUsecase.SkipAdTemplateOccurrence(userId, existingAdTemplate.Id, *date)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestAdUsecase_SkipAdTemplateOccurrence_notOccurrence(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	var userId uint32 = 101
+	existingAdTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: userId,
+		EvenWeek:     true,
+		DaysOfWeek:   []uint32{2},
+	}
+	//the Tuesday of the odd week
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+
+	mockAdRepository.
+		EXPECT().
+		SelectAdTemplate(gomock.Eq(existingAdTemplate.Id)).
+		Return(existingAdTemplate, nil)
+
+	response_ := adUsecase.SkipAdTemplateOccurrence(userId, existingAdTemplate.Id, *date)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestAdUsecase_MaterializeAdTemplates(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockAdRepository := mock_ad.NewMockRepository(controller)
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	mockBlobStore := mock_blobstore.NewMockBlobStore(controller)
+	adUsecase := usecase.NewAdUsecaseImpl(mockAdRepository, mockNotificationUsecase, mockBlobStore)
+
+	timeDep, err := timestamps.NewTime("08:00")
+	assert.Nil(t, err)
+	timeArr, err := timestamps.NewTime("09:30")
+	assert.Nil(t, err)
+	date, err := timestamps.NewDate("23.11.2021")
+	assert.Nil(t, err)
+	skippedDate, err := timestamps.NewDate("30.11.2021")
+	assert.Nil(t, err)
+	dateTimeDep, err := timestamps.NewDateTime("23.11.2021 08:00")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("23.11.2021 09:30")
+	assert.Nil(t, err)
+	horizon := 7 * 24 * time.Hour
+	adTemplate := &models.AdTemplate{
+		Id:           1,
+		UserAuthorId: 101,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		Item:         "Набор для лабораторной",
+		MinPrice:     300,
+		Comment:      "Каждый вторник",
+		EvenWeek:     true,
+		OddWeek:      true,
+		DaysOfWeek:   []uint32{2},
+		TimeDep:      timeDep,
+		TimeArr:      *timeArr,
+	}
+	adTemplateOccurrences := &models.AdTemplateOccurrences{
+		&models.AdTemplateOccurrence{AdTemplate: adTemplate, Date: *date},
+		&models.AdTemplateOccurrence{AdTemplate: adTemplate, Date: *skippedDate},
+	}
+	ad := &models.Ad{
+		UserAuthorId: adTemplate.UserAuthorId,
+		LocDep:       adTemplate.LocDep,
+		LocArr:       adTemplate.LocArr,
+		DateTimeDep:  dateTimeDep,
+		DateTimeArr:  *dateTimeArr,
+		Item:         adTemplate.Item,
+		MinPrice:     adTemplate.MinPrice,
+		Comment:      adTemplate.Comment,
+	}
+	expectedAd := &models.Ad{
+		Id:             1,
+		UserAuthorId:   ad.UserAuthorId,
+		UserAuthorVkId: 201,
+		LocDep:         ad.LocDep,
+		LocArr:         ad.LocArr,
+		DateTimeDep:    ad.DateTimeDep,
+		DateTimeArr:    ad.DateTimeArr,
+		Item:           ad.Item,
+		MinPrice:       ad.MinPrice,
+		Comment:        ad.Comment,
+		Status:         models.AdStatusOpen,
+	}
+
+	before := time.Now()
+	call := mockAdRepository.
+		EXPECT().
+		SelectAdTemplateOccurrenceArrayDue(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(minDateTimeArr time.Time, maxDate time.Time) (*models.AdTemplateOccurrences, error) {
+			assert.False(t, minDateTimeArr.Before(before))
+			assert.Equal(t, minDateTimeArr.Add(horizon), maxDate)
+			return adTemplateOccurrences, nil
+		})
+	call = mockAdRepository.
+		EXPECT().
+		InsertByAdTemplateOccurrence(gomock.Eq(ad), gomock.Eq((*adTemplateOccurrences)[0])).
+		Return(expectedAd, nil).
+		After(call)
+	mockAdRepository.
+		EXPECT().
+		InsertByAdTemplateOccurrence(gomock.Any(), gomock.Eq((*adTemplateOccurrences)[1])).
+		Return(nil, consts.RepErrConflict).
+		After(call)
+	call = mockNotificationUsecase.
+		EXPECT().
+		NotifyAdMatches(gomock.Eq(expectedAd)).
+		Return(response.NewEmptyResponse(consts.OK)).
+		After(call)
+	mockAdRepository.
+		EXPECT().
+		DeleteAdNotificationPending(gomock.Eq(expectedAd.Id)).
+		Return(nil).
+		After(call)
+
+	response_ := adUsecase.MaterializeAdTemplates(horizon)
+	assert.Equal(t, response.NewResponse(consts.OK, &models.Ads{expectedAd}), response_)
+}
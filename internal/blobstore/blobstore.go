@@ -0,0 +1,7 @@
+package blobstore
+
+type BlobStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+}
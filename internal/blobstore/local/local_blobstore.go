@@ -0,0 +1,96 @@
+package local
+
+import (
+	"errors"
+	"github.com/TechnoHandOver/backend/internal/blobstore"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var errInvalidKey = errors.New("invalid blob key")
+
+type LocalBlobStore struct {
+	dir string
+}
+
+func NewLocalBlobStore(dir string) blobstore.BlobStore {
+	return &LocalBlobStore{
+		dir: dir,
+	}
+}
+
+func (localBlobStore *LocalBlobStore) Put(key string, data []byte) error {
+	path, err := localBlobStore.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (localBlobStore *LocalBlobStore) Get(key string) ([]byte, error) {
+	path, err := localBlobStore.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (localBlobStore *LocalBlobStore) Delete(key string) error {
+	path, err := localBlobStore.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return consts.RepErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (localBlobStore *LocalBlobStore) path(key string) (string, error) {
+	path := filepath.Join(localBlobStore.dir, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(path, filepath.Clean(localBlobStore.dir)+string(filepath.Separator)) {
+		return "", errInvalidKey
+	}
+
+	return path, nil
+}
@@ -0,0 +1,34 @@
+package local_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/blobstore/local"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	blobStore := local.NewLocalBlobStore(t.TempDir())
+
+	const key = "ads/1/photo"
+	data := []byte{0x89, 0x50, 0x4e, 0x47}
+
+	assert.Nil(t, blobStore.Put(key, data))
+
+	resultData, resultErr := blobStore.Get(key)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, data, resultData)
+
+	assert.Nil(t, blobStore.Delete(key))
+
+	resultData, resultErr = blobStore.Get(key)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+	assert.Nil(t, resultData)
+}
+
+func TestLocalBlobStore_invalidKey(t *testing.T) {
+	blobStore := local.NewLocalBlobStore(t.TempDir())
+
+	assert.NotNil(t, blobStore.Put("../photo", []byte{}))
+	assert.NotNil(t, blobStore.Put("", []byte{}))
+}
@@ -0,0 +1,96 @@
+package delivery
+
+import (
+	"github.com/TechnoHandOver/backend/internal/chat"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	"github.com/labstack/echo/v4"
+)
+
+type ChatDelivery struct {
+	chatUsecase chat.Usecase
+}
+
+func NewChatDelivery(chatUsecase chat.Usecase) *ChatDelivery {
+	return &ChatDelivery{
+		chatUsecase: chatUsecase,
+	}
+}
+
+func (chatDelivery *ChatDelivery) Configure(echo_ *echo.Echo, middlewaresManager *middlewares.Manager) {
+	echo_.POST("/api/ads/:id/messages", chatDelivery.HandlerAdMessageCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/:id/messages/list", chatDelivery.HandlerAdMessagesList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/ads/:id/messages/read", chatDelivery.HandlerAdMessagesRead(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/ads/messages/unread", chatDelivery.HandlerAdMessagesUnread(), middlewaresManager.AuthMiddleware.CheckAuth())
+}
+
+func (chatDelivery *ChatDelivery) HandlerAdMessageCreate() echo.HandlerFunc {
+	type AdMessageCreateRequest struct {
+		AdId *uint32 `param:"id" validate:"required"`
+		Text *string `json:"text" validate:"required,gte=1,lte=1000"`
+	}
+
+	return func(context echo.Context) error {
+		adMessageCreateRequest := new(AdMessageCreateRequest)
+		if err := parser.ParseRequest(context, adMessageCreateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		adMessage := &models.AdMessage{
+			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
+			Text:         *adMessageCreateRequest.Text,
+		}
+
+		return responser.Respond(context, chatDelivery.chatUsecase.CreateAdMessage(*adMessageCreateRequest.AdId,
+			adMessage))
+	}
+}
+
+func (chatDelivery *ChatDelivery) HandlerAdMessagesList() echo.HandlerFunc {
+	type AdMessagesListRequest struct {
+		AdId   *uint32                  `param:"id" validate:"required"`
+		Cursor *models.AdMessagesCursor `query:"cursor" validate:"omitempty"`
+		Limit  *uint32                  `query:"limit" validate:"omitempty,min=1,max=100"`
+	}
+
+	return func(context echo.Context) error {
+		adMessagesListRequest := new(AdMessagesListRequest)
+		if err := parser.ParseRequest(context, adMessagesListRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, chatDelivery.chatUsecase.ListAdMessages(userId,
+			*adMessagesListRequest.AdId, adMessagesListRequest.Cursor, adMessagesListRequest.Limit))
+	}
+}
+
+func (chatDelivery *ChatDelivery) HandlerAdMessagesRead() echo.HandlerFunc {
+	type AdMessagesReadRequest struct {
+		AdId *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		adMessagesReadRequest := new(AdMessagesReadRequest)
+		if err := parser.ParseRequest(context, adMessagesReadRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, chatDelivery.chatUsecase.ReadAdMessages(userId, *adMessagesReadRequest.AdId))
+	}
+}
+
+func (chatDelivery *ChatDelivery) HandlerAdMessagesUnread() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, chatDelivery.chatUsecase.GetAdMessagesUnread(userId))
+	}
+}
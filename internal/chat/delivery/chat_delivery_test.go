@@ -0,0 +1,154 @@
+package delivery_test
+
+import (
+	"encoding/json"
+	"github.com/TechnoHandOver/backend/internal/chat/delivery"
+	"github.com/TechnoHandOver/backend/internal/chat/mock_chat"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	HandoverValidator "github.com/TechnoHandOver/backend/internal/tools/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestChatDelivery_HandlerAdMessageCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockChatUsecase := mock_chat.NewMockUsecase(controller)
+	chatDelivery := delivery.NewChatDelivery(mockChatUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	chatDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const adId uint32 = 1
+	adMessage := &models.AdMessage{
+		UserAuthorId: 102,
+		Text:         "Буду у общежития через 10 минут",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	expectedAdMessage := &models.AdMessage{
+		Id:           1,
+		AdThreadId:   2,
+		UserAuthorId: adMessage.UserAuthorId,
+		Text:         adMessage.Text,
+		DateTime:     *dateTime,
+	}
+
+	mockChatUsecase.
+		EXPECT().
+		CreateAdMessage(gomock.Eq(adId), gomock.Eq(adMessage)).
+		Return(response.NewResponse(consts.Created, expectedAdMessage))
+
+	jsonRequest, err := json.Marshal(adMessage)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdMessage,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/messages")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(adId), 10))
+	context.Set(consts.EchoContextKeyUserId, adMessage.UserAuthorId)
+
+	handler := chatDelivery.HandlerAdMessageCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestChatDelivery_HandlerAdMessageCreate_emptyText(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockChatUsecase := mock_chat.NewMockUsecase(controller)
+	chatDelivery := delivery.NewChatDelivery(mockChatUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	chatDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"text":""}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/messages")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(102))
+
+	handler := chatDelivery.HandlerAdMessageCreate()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestChatDelivery_HandlerAdMessagesUnread(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockChatUsecase := mock_chat.NewMockUsecase(controller)
+	chatDelivery := delivery.NewChatDelivery(mockChatUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	chatDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	expectedAdMessagesUnread := &models.AdMessagesUnread{
+		Count: 3,
+	}
+
+	mockChatUsecase.
+		EXPECT().
+		GetAdMessagesUnread(gomock.Eq(userId)).
+		Return(response.NewResponse(consts.OK, expectedAdMessagesUnread))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdMessagesUnread,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/messages/unread")
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := chatDelivery.HandlerAdMessagesUnread()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
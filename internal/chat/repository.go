@@ -0,0 +1,11 @@
+package chat
+
+import "github.com/TechnoHandOver/backend/internal/models"
+
+type Repository interface {
+	SelectAdThreadByAdId(adId uint32) (*models.AdThread, error)
+	InsertAdMessage(adMessage *models.AdMessage) (*models.AdMessage, error)
+	SelectAdMessageArray(adThreadId uint32, cursor *models.AdMessagesCursor, limit uint32) (*models.AdMessages, error)
+	UpdateAdMessagesRead(adThreadId uint32, userId uint32) error
+	SelectAdMessagesUnread(userId uint32) (*models.AdMessagesUnread, error)
+}
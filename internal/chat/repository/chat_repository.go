@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/TechnoHandOver/backend/internal/chat"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+)
+
+type ChatRepository struct {
+	db *sql.DB
+}
+
+func NewChatRepositoryImpl(db *sql.DB) chat.Repository {
+	return &ChatRepository{
+		db: db,
+	}
+}
+
+func (chatRepository *ChatRepository) SelectAdThreadByAdId(adId uint32) (*models.AdThread, error) {
+	//earlier threads stay with their former executors, the ad shows only the latest one
+	const query = `
+SELECT id, ad_id, user_author_id, user_executor_id, archived FROM ad_thread
+WHERE ad_id = $1
+ORDER BY id DESC
+LIMIT 1`
+
+	adThread := new(models.AdThread)
+	if err := chatRepository.db.QueryRow(query, adId).Scan(&adThread.Id, &adThread.AdId, &adThread.UserAuthorId,
+		&adThread.UserExecutorId, &adThread.Archived); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return adThread, nil
+}
+
+func (chatRepository *ChatRepository) InsertAdMessage(adMessage *models.AdMessage) (*models.AdMessage, error) {
+	const query = `
+INSERT INTO ad_message (ad_thread_id, user_author_id, text)
+SELECT id, $2, $3 FROM ad_thread
+WHERE id = $1 AND NOT archived
+RETURNING id, ad_thread_id, user_author_id, text, date_time, date_time_read`
+
+	if err := chatRepository.db.QueryRow(query, adMessage.AdThreadId, adMessage.UserAuthorId,
+		adMessage.Text).Scan(&adMessage.Id, &adMessage.AdThreadId, &adMessage.UserAuthorId, &adMessage.Text,
+		&adMessage.DateTime, &adMessage.DateTimeRead); err != nil {
+		if err == sql.ErrNoRows { //the thread has been archived concurrently
+			return nil, consts.RepErrConflict
+		}
+
+		return nil, err
+	}
+
+	return adMessage, nil
+}
+
+func (chatRepository *ChatRepository) SelectAdMessageArray(adThreadId uint32, cursor *models.AdMessagesCursor, limit uint32) (*models.AdMessages, error) {
+	const query = `
+SELECT id, ad_thread_id, user_author_id, text, date_time, date_time_read FROM ad_message
+WHERE ad_thread_id = $1 AND ($2::int IS NULL OR id < $2)
+ORDER BY id DESC
+LIMIT $3`
+
+	var cursorId interface{}
+	if cursor != nil {
+		cursorId = cursor.Id
+	}
+
+	rows, err := chatRepository.db.Query(query, adThreadId, cursorId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adMessages := make(models.AdMessages, 0)
+	for rows.Next() {
+		adMessage := new(models.AdMessage)
+		if err := rows.Scan(&adMessage.Id, &adMessage.AdThreadId, &adMessage.UserAuthorId, &adMessage.Text,
+			&adMessage.DateTime, &adMessage.DateTimeRead); err != nil {
+			return nil, err
+		}
+
+		adMessages = append(adMessages, adMessage)
+	}
+
+	return &adMessages, nil
+}
+
+func (chatRepository *ChatRepository) UpdateAdMessagesRead(adThreadId uint32, userId uint32) error {
+	const query = `
+UPDATE ad_message SET date_time_read = now()
+WHERE ad_thread_id = $1 AND user_author_id != $2 AND date_time_read IS NULL`
+
+	_, err := chatRepository.db.Exec(query, adThreadId, userId)
+	return err
+}
+
+func (chatRepository *ChatRepository) SelectAdMessagesUnread(userId uint32) (*models.AdMessagesUnread, error) {
+	const query = `
+SELECT count(*) FROM ad_message
+    JOIN ad_thread ON ad_thread.id = ad_message.ad_thread_id
+WHERE $1 IN (ad_thread.user_author_id, ad_thread.user_executor_id) AND
+      NOT ad_thread.archived AND
+      ad_message.user_author_id != $1 AND
+      ad_message.date_time_read IS NULL`
+
+	adMessagesUnread := new(models.AdMessagesUnread)
+	if err := chatRepository.db.QueryRow(query, userId).Scan(&adMessagesUnread.Count); err != nil {
+		return nil, err
+	}
+
+	return adMessagesUnread, nil
+}
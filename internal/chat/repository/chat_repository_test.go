@@ -0,0 +1,175 @@
+package repository_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TechnoHandOver/backend/internal/chat/repository"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestChatRepository_SelectAdThreadByAdId(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	chatRepository := repository.NewChatRepositoryImpl(db)
+
+	expectedAdThread := &models.AdThread{
+		Id:             2,
+		AdId:           1,
+		UserAuthorId:   101,
+		UserExecutorId: 102,
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad_thread WHERE ad_id = \\$1 ORDER BY id DESC LIMIT 1").
+		WithArgs(expectedAdThread.AdId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ad_id", "user_author_id", "user_executor_id", "archived"}).
+			AddRow(expectedAdThread.Id, expectedAdThread.AdId, expectedAdThread.UserAuthorId,
+				expectedAdThread.UserExecutorId, expectedAdThread.Archived))
+
+	resultAdThread, resultErr := chatRepository.SelectAdThreadByAdId(expectedAdThread.AdId)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdThread, resultAdThread)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestChatRepository_InsertAdMessage(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	chatRepository := repository.NewChatRepositoryImpl(db)
+
+	adMessage := &models.AdMessage{
+		AdThreadId:   2,
+		UserAuthorId: 102,
+		Text:         "Буду у общежития через 10 минут",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	expectedAdMessage := &models.AdMessage{
+		Id:           1,
+		AdThreadId:   adMessage.AdThreadId,
+		UserAuthorId: adMessage.UserAuthorId,
+		Text:         adMessage.Text,
+		DateTime:     *dateTime,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_message (.+) SELECT (.+) FROM ad_thread WHERE id = \\$1 AND NOT archived").
+		WithArgs(adMessage.AdThreadId, adMessage.UserAuthorId, adMessage.Text).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ad_thread_id", "user_author_id", "text", "date_time",
+			"date_time_read"}).
+			AddRow(expectedAdMessage.Id, expectedAdMessage.AdThreadId, expectedAdMessage.UserAuthorId,
+				expectedAdMessage.Text, time.Time(expectedAdMessage.DateTime), nil))
+
+	resultAdMessage, resultErr := chatRepository.InsertAdMessage(adMessage)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdMessage, resultAdMessage)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestChatRepository_InsertAdMessage_archived(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	chatRepository := repository.NewChatRepositoryImpl(db)
+
+	adMessage := &models.AdMessage{
+		AdThreadId:   2,
+		UserAuthorId: 102,
+		Text:         "Буду у общежития через 10 минут",
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO ad_message").
+		WithArgs(adMessage.AdThreadId, adMessage.UserAuthorId, adMessage.Text).
+		WillReturnError(sql.ErrNoRows)
+
+	resultAdMessage, resultErr := chatRepository.InsertAdMessage(adMessage)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultAdMessage)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestChatRepository_SelectAdMessageArray_cursor(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	chatRepository := repository.NewChatRepositoryImpl(db)
+
+	const adThreadId, limit uint32 = 2, 10
+	cursor := &models.AdMessagesCursor{
+		Id: 5,
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	dateTimeRead, err := timestamps.NewDateTime("05.12.2021 19:52")
+	assert.Nil(t, err)
+	expectedAdMessages := &models.AdMessages{
+		&models.AdMessage{
+			Id:           4,
+			AdThreadId:   adThreadId,
+			UserAuthorId: 101,
+			Text:         "Жду у входа",
+			DateTime:     *dateTime,
+			DateTimeRead: dateTimeRead,
+		},
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM ad_message WHERE ad_thread_id = \\$1 AND \\(\\$2::int IS NULL OR id < \\$2\\) ORDER BY id DESC LIMIT \\$3").
+		WithArgs(adThreadId, cursor.Id, limit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ad_thread_id", "user_author_id", "text", "date_time",
+			"date_time_read"}).
+			AddRow((*expectedAdMessages)[0].Id, (*expectedAdMessages)[0].AdThreadId,
+				(*expectedAdMessages)[0].UserAuthorId, (*expectedAdMessages)[0].Text,
+				time.Time((*expectedAdMessages)[0].DateTime), time.Time(*(*expectedAdMessages)[0].DateTimeRead)))
+
+	resultAdMessages, resultErr := chatRepository.SelectAdMessageArray(adThreadId, cursor, limit)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdMessages, resultAdMessages)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestChatRepository_UpdateAdMessagesRead(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	chatRepository := repository.NewChatRepositoryImpl(db)
+
+	const adThreadId, userId uint32 = 2, 101
+
+	sqlmock_.
+		ExpectExec("UPDATE ad_message SET date_time_read = now\\(\\) WHERE ad_thread_id = \\$1 AND user_author_id != \\$2 AND date_time_read IS NULL").
+		WithArgs(adThreadId, userId).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	resultErr := chatRepository.UpdateAdMessagesRead(adThreadId, userId)
+	assert.Nil(t, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
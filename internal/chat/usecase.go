@@ -0,0 +1,13 @@
+package chat
+
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+)
+
+type Usecase interface {
+	CreateAdMessage(adId uint32, adMessage *models.AdMessage) *response.Response
+	ListAdMessages(userId uint32, adId uint32, cursor *models.AdMessagesCursor, limit *uint32) *response.Response
+	ReadAdMessages(userId uint32, adId uint32) *response.Response
+	GetAdMessagesUnread(userId uint32) *response.Response
+}
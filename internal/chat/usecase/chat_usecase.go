@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"github.com/TechnoHandOver/backend/internal/chat"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+)
+
+const adMessagesDefaultLimit uint32 = 50
+
+type ChatUsecase struct {
+	chatRepository chat.Repository
+}
+
+func NewChatUsecaseImpl(chatRepository chat.Repository) chat.Usecase {
+	return &ChatUsecase{
+		chatRepository: chatRepository,
+	}
+}
+
+func (chatUsecase *ChatUsecase) CreateAdMessage(adId uint32, adMessage *models.AdMessage) *response.Response {
+	adThread, errResponse := chatUsecase.getAdThread(adMessage.UserAuthorId, adId)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	if adThread.Archived {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+
+	adMessage.AdThreadId = adThread.Id
+	adMessage, err := chatUsecase.chatRepository.InsertAdMessage(adMessage)
+	if err != nil {
+		if err == consts.RepErrConflict {
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, adMessage)
+}
+
+func (chatUsecase *ChatUsecase) ListAdMessages(userId uint32, adId uint32, cursor *models.AdMessagesCursor,
+	limit *uint32) *response.Response {
+	adThread, errResponse := chatUsecase.getAdThread(userId, adId)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	limit_ := parser.GetOrDefault(limit, adMessagesDefaultLimit).(uint32)
+	adMessages, err := chatUsecase.chatRepository.SelectAdMessageArray(adThread.Id, cursor, limit_+1)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	var nextCursor string
+	if uint32(len(*adMessages)) > limit_ {
+		*adMessages = (*adMessages)[:limit_]
+		nextCursor = models.NewAdMessagesCursor((*adMessages)[limit_-1]).String()
+	}
+
+	return response.NewPageResponse(consts.OK, adMessages, nextCursor)
+}
+
+func (chatUsecase *ChatUsecase) ReadAdMessages(userId uint32, adId uint32) *response.Response {
+	adThread, errResponse := chatUsecase.getAdThread(userId, adId)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	if err := chatUsecase.chatRepository.UpdateAdMessagesRead(adThread.Id, userId); err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewEmptyResponse(consts.OK)
+}
+
+func (chatUsecase *ChatUsecase) GetAdMessagesUnread(userId uint32) *response.Response {
+	adMessagesUnread, err := chatUsecase.chatRepository.SelectAdMessagesUnread(userId)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adMessagesUnread)
+}
+
+func (chatUsecase *ChatUsecase) getAdThread(userId uint32, adId uint32) (*models.AdThread, *response.Response) {
+	adThread, err := chatUsecase.chatRepository.SelectAdThreadByAdId(adId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return nil, response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return nil, response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if !adThread.HasParticipant(userId) {
+		return nil, response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	return adThread, nil
+}
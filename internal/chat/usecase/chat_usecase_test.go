@@ -0,0 +1,164 @@
+package usecase_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/chat/mock_chat"
+	"github.com/TechnoHandOver/backend/internal/chat/usecase"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/golang/mock/gomock"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChatUsecase_CreateAdMessage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockChatRepository := mock_chat.NewMockRepository(controller)
+	chatUsecase := usecase.NewChatUsecaseImpl(mockChatRepository)
+
+	adThread := &models.AdThread{
+		Id:             2,
+		AdId:           1,
+		UserAuthorId:   101,
+		UserExecutorId: 102,
+	}
+	adMessage := &models.AdMessage{
+		UserAuthorId: adThread.UserExecutorId,
+		Text:         "Буду у общежития через 10 минут",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	expectedAdMessage := &models.AdMessage{
+		Id:           1,
+		AdThreadId:   adThread.Id,
+		UserAuthorId: adMessage.UserAuthorId,
+		Text:         adMessage.Text,
+		DateTime:     *dateTime,
+	}
+
+	callSelectAdThreadByAdId := mockChatRepository.
+		EXPECT().
+		SelectAdThreadByAdId(gomock.Eq(adThread.AdId)).
+		Return(adThread, nil)
+	mockChatRepository.
+		EXPECT().
+		InsertAdMessage(gomock.Eq(&models.AdMessage{
+			AdThreadId:   adThread.Id,
+			UserAuthorId: adMessage.UserAuthorId,
+			Text:         adMessage.Text,
+		})).
+		Return(expectedAdMessage, nil).
+		After(callSelectAdThreadByAdId)
+
+	response_ := chatUsecase.CreateAdMessage(adThread.AdId, adMessage)
+	assert.Equal(t, response.NewResponse(consts.Created, expectedAdMessage), response_)
+}
+
+func TestChatUsecase_CreateAdMessage_notParticipant(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockChatRepository := mock_chat.NewMockRepository(controller)
+	chatUsecase := usecase.NewChatUsecaseImpl(mockChatRepository)
+
+	adThread := &models.AdThread{
+		Id:             2,
+		AdId:           1,
+		UserAuthorId:   101,
+		UserExecutorId: 102,
+	}
+	adMessage := &models.AdMessage{
+		UserAuthorId: 103,
+		Text:         "Могу доставить дешевле",
+	}
+
+	mockChatRepository.
+		EXPECT().
+		SelectAdThreadByAdId(gomock.Eq(adThread.AdId)).
+		Return(adThread, nil)
+
+	response_ := chatUsecase.CreateAdMessage(adThread.AdId, adMessage)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestChatUsecase_CreateAdMessage_archived(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockChatRepository := mock_chat.NewMockRepository(controller)
+	chatUsecase := usecase.NewChatUsecaseImpl(mockChatRepository)
+
+	adThread := &models.AdThread{
+		Id:             2,
+		AdId:           1,
+		UserAuthorId:   101,
+		UserExecutorId: 102,
+		Archived:       true,
+	}
+	adMessage := &models.AdMessage{
+		UserAuthorId: adThread.UserAuthorId,
+		Text:         "Спасибо!",
+	}
+
+	mockChatRepository.
+		EXPECT().
+		SelectAdThreadByAdId(gomock.Eq(adThread.AdId)).
+		Return(adThread, nil)
+
+	response_ := chatUsecase.CreateAdMessage(adThread.AdId, adMessage)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestChatUsecase_ListAdMessages(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockChatRepository := mock_chat.NewMockRepository(controller)
+	chatUsecase := usecase.NewChatUsecaseImpl(mockChatRepository)
+
+	adThread := &models.AdThread{
+		Id:             2,
+		AdId:           1,
+		UserAuthorId:   101,
+		UserExecutorId: 102,
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	adMessages := &models.AdMessages{
+		&models.AdMessage{
+			Id:           3,
+			AdThreadId:   adThread.Id,
+			UserAuthorId: adThread.UserExecutorId,
+			Text:         "Подъезжаю",
+			DateTime:     *dateTime,
+		},
+		&models.AdMessage{
+			Id:           2,
+			AdThreadId:   adThread.Id,
+			UserAuthorId: adThread.UserAuthorId,
+			Text:         "Жду у входа",
+			DateTime:     *dateTime,
+		},
+	}
+	expectedAdMessages := &models.AdMessages{
+		(*adMessages)[0],
+	}
+	expectedNextCursor := models.NewAdMessagesCursor((*adMessages)[0]).String()
+
+	callSelectAdThreadByAdId := mockChatRepository.
+		EXPECT().
+		SelectAdThreadByAdId(gomock.Eq(adThread.AdId)).
+		Return(adThread, nil)
+	mockChatRepository.
+		EXPECT().
+		SelectAdMessageArray(gomock.Eq(adThread.Id), gomock.Nil(), gomock.Eq(uint32(2))).
+		Return(adMessages, nil).
+		After(callSelectAdThreadByAdId)
+
+	response_ := chatUsecase.ListAdMessages(adThread.UserAuthorId, adThread.AdId, nil, pointy.Uint32(1))
+	assert.Equal(t, response.NewPageResponse(consts.OK, expectedAdMessages, expectedNextCursor), response_)
+}
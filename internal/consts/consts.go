@@ -10,5 +10,8 @@ const (
 type RepositoryError error
 
 var (
-	RepErrNotFound RepositoryError = errors.New("Not found\n")
+	RepErrNotFound           RepositoryError = errors.New("Not found\n")
+	RepErrConflict           RepositoryError = errors.New("Conflict\n")
+	RepErrPreconditionFailed RepositoryError = errors.New("Precondition failed\n")
+	RepErrForbidden          RepositoryError = errors.New("Forbidden\n")
 )
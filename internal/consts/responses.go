@@ -13,15 +13,25 @@ const (
 	NotFound
 	Conflict
 	InternalError
+	PayloadTooLarge
+	UnsupportedMediaType
+	PreconditionFailed
+	PreconditionRequired
+	TooManyRequests
 )
 
 var StatusCodes = map[Code]int{
-	OK:            http.StatusOK,
-	Created:       http.StatusCreated,
-	BadRequest:    http.StatusBadRequest,
-	Unauthorized:  http.StatusUnauthorized,
-	Forbidden:     http.StatusForbidden,
-	NotFound:      http.StatusNotFound,
-	Conflict:      http.StatusConflict,
-	InternalError: http.StatusInternalServerError,
+	OK:                   http.StatusOK,
+	Created:              http.StatusCreated,
+	BadRequest:           http.StatusBadRequest,
+	Unauthorized:         http.StatusUnauthorized,
+	Forbidden:            http.StatusForbidden,
+	NotFound:             http.StatusNotFound,
+	Conflict:             http.StatusConflict,
+	InternalError:        http.StatusInternalServerError,
+	PayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	UnsupportedMediaType: http.StatusUnsupportedMediaType,
+	PreconditionFailed:   http.StatusPreconditionFailed,
+	PreconditionRequired: http.StatusPreconditionRequired,
+	TooManyRequests:      http.StatusTooManyRequests,
 }
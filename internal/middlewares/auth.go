@@ -13,12 +13,19 @@ import (
 type AuthMiddleware struct {
 	sessionUsecase session.Usecase
 	userUsecase    user.Usecase
+	adminVkIds     map[uint32]bool
 }
 
-func NewAuthMiddleware(sessionUsecase session.Usecase, userUsecase user.Usecase) *AuthMiddleware {
+func NewAuthMiddleware(sessionUsecase session.Usecase, userUsecase user.Usecase, adminVkIds []uint32) *AuthMiddleware {
+	adminVkIds_ := make(map[uint32]bool, len(adminVkIds))
+	for _, adminVkId := range adminVkIds {
+		adminVkIds_[adminVkId] = true
+	}
+
 	return &AuthMiddleware{
 		sessionUsecase: sessionUsecase,
 		userUsecase:    userUsecase,
+		adminVkIds:     adminVkIds_,
 	}
 }
 
@@ -43,12 +50,36 @@ func (authMiddleware *AuthMiddleware) checkAuth(next echo.HandlerFunc) echo.Hand
 		}
 
 		session_ := response_.Data.(*models.Session)
-		if response_ := authMiddleware.userUsecase.Get(session_.UserId); response_.Code != consts.OK {
+		response_ = authMiddleware.userUsecase.Get(session_.UserId)
+		if response_.Code != consts.OK {
 			return responser.Respond(context, response_)
 		}
 
+		if response_.Data.(*models.User).Suspended {
+			return responser.Respond(context, response.NewEmptyResponse(consts.Forbidden))
+		}
+
 		context.Set(consts.EchoContextKeyUserId, session_.UserId)
 
 		return next(context)
 	}
 }
+
+func (authMiddleware *AuthMiddleware) CheckAdmin() echo.MiddlewareFunc {
+	return authMiddleware.checkAdmin
+}
+
+func (authMiddleware *AuthMiddleware) checkAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(context echo.Context) error {
+		response_ := authMiddleware.userUsecase.Get(context.Get(consts.EchoContextKeyUserId).(uint32))
+		if response_.Code != consts.OK {
+			return responser.Respond(context, response_)
+		}
+
+		if !authMiddleware.adminVkIds[response_.Data.(*models.User).VkId] {
+			return responser.Respond(context, response.NewEmptyResponse(consts.Forbidden))
+		}
+
+		return next(context)
+	}
+}
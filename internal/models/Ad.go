@@ -1,20 +1,35 @@
 package models
 
-import . "github.com/TechnoHandOver/backend/internal/models/timestamps"
+import (
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"time"
+)
 
 type Ad struct {
-	Id               uint32   `json:"id"`
-	UserAuthorId     uint32   `json:"-"`
-	UserAuthorVkId   uint32   `json:"userAuthorVkId"`
-	UserAuthorName   string   `json:"userAuthorName"`
-	UserAuthorAvatar string   `json:"userAuthorAvatar"`
-	UserExecutorVkId *uint32  `json:"userExecutorVkId,omitempty"`
-	LocDep           string   `json:"locDep"`
-	LocArr           string   `json:"locArr"`
-	DateTimeArr      DateTime `json:"dateTimeArr"`
-	Item             string   `json:"item"`
-	MinPrice         uint32   `json:"minPrice"`
-	Comment          string   `json:"comment"`
+	Id               uint32    `json:"id"`
+	UserAuthorId     uint32    `json:"-"`
+	UserAuthorVkId   uint32    `json:"userAuthorVkId"`
+	UserAuthorName   string    `json:"userAuthorName"`
+	UserAuthorAvatar string    `json:"userAuthorAvatar"`
+	UserExecutorVkId *uint32   `json:"userExecutorVkId,omitempty"`
+	LocDep           string    `json:"locDep"`
+	LocArr           string    `json:"locArr"`
+	DateTimeDep      *DateTime `json:"dateTimeDep,omitempty"`
+	DateTimeArr      DateTime  `json:"dateTimeArr"`
+	Item             string    `json:"item"`
+	MinPrice         uint32    `json:"minPrice"`
+	Comment          string    `json:"comment"`
+	Status           AdStatus  `json:"status"`
+	LocDepPoint      *GeoPoint `json:"locDepPoint,omitempty"`
+	LocArrPoint      *GeoPoint `json:"locArrPoint,omitempty"`
+	LocDepPlaceId    *uint32   `json:"locDepPlaceId,omitempty"`
+	LocArrPlaceId    *uint32   `json:"locArrPlaceId,omitempty"`
+	Photos           AdPhotos  `json:"photos,omitempty"`
+	Version          uint32    `json:"-"`
 }
 
 type Ads []*Ad
+
+func (ad *Ad) HasValidTimeWindow() bool {
+	return ad.DateTimeDep == nil || !time.Time(*ad.DateTimeDep).After(time.Time(ad.DateTimeArr))
+}
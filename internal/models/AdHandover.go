@@ -0,0 +1,28 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	AdHandoverMaxFailedAttempts = 5
+	AdHandoverLockout           = 15 * time.Minute
+)
+
+type AdHandover struct {
+	AdId           uint32     `json:"adId"`
+	Code           string     `json:"code"`
+	FailedAttempts uint32     `json:"-"`
+	LockedUntil    *time.Time `json:"-"`
+}
+
+type AdHandoverQr struct {
+	Payload string `json:"payload"`
+}
+
+func (adHandover *AdHandover) Qr() *AdHandoverQr {
+	return &AdHandoverQr{
+		Payload: fmt.Sprintf("handover:%d:%s", adHandover.AdId, adHandover.Code),
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+type AdMatch struct {
+	*Ad
+	Fit float64 `json:"fit"`
+}
+
+type AdMatches []*AdMatch
+
+type AdMatchesCursor struct {
+	Fit float64 `json:"f"`
+	Id  uint32  `json:"i"`
+}
+
+func NewAdMatchesCursor(adMatch *AdMatch) *AdMatchesCursor {
+	return &AdMatchesCursor{
+		Fit: adMatch.Fit,
+		Id:  adMatch.Id,
+	}
+}
+
+func (adMatchesCursor *AdMatchesCursor) String() string {
+	bytes, _ := json.Marshal(adMatchesCursor)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func (adMatchesCursor *AdMatchesCursor) UnmarshalParam(src string) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, adMatchesCursor)
+}
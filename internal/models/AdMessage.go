@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+)
+
+type AdMessage struct {
+	Id           uint32    `json:"id"`
+	AdThreadId   uint32    `json:"adThreadId"`
+	UserAuthorId uint32    `json:"userAuthorId"`
+	Text         string    `json:"text"`
+	DateTime     DateTime  `json:"dateTime"`
+	DateTimeRead *DateTime `json:"dateTimeRead"`
+}
+
+type AdMessages []*AdMessage
+
+type AdMessagesUnread struct {
+	Count uint32 `json:"count"`
+}
+
+type AdMessagesCursor struct {
+	Id uint32 `json:"i"`
+}
+
+func NewAdMessagesCursor(adMessage *AdMessage) *AdMessagesCursor {
+	return &AdMessagesCursor{
+		Id: adMessage.Id,
+	}
+}
+
+func (adMessagesCursor *AdMessagesCursor) String() string {
+	bytes, _ := json.Marshal(adMessagesCursor)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func (adMessagesCursor *AdMessagesCursor) UnmarshalParam(src string) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, adMessagesCursor)
+}
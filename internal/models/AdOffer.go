@@ -0,0 +1,50 @@
+package models
+
+import "reflect"
+
+type AdOffer struct {
+	Id                 uint32        `json:"id"`
+	AdId               uint32        `json:"adId"`
+	UserExecutorId     uint32        `json:"-"`
+	UserExecutorVkId   uint32        `json:"userExecutorVkId"`
+	UserExecutorName   string        `json:"userExecutorName"`
+	UserExecutorAvatar string        `json:"userExecutorAvatar"`
+	UserExecutorRating float32       `json:"userExecutorRating"`
+	Price              uint32        `json:"price"`
+	Comment            string        `json:"comment"`
+	Status             AdOfferStatus `json:"status"`
+}
+
+type AdOffers []*AdOffer
+
+type AdOffersSearch struct {
+	AdId                  uint32
+	MinUserExecutorRating *float32
+	Order                 *AdOffersSearchOrder
+}
+
+type AdOffersSearchOrder int
+
+const (
+	AdOffersSearchOrderPriceAsc AdOffersSearchOrder = iota
+	AdOffersSearchOrderRatingDesc
+)
+
+func ValidateAdOffersSearchOrder(field reflect.Value) interface{} {
+	if order, ok := field.Interface().(AdOffersSearchOrder); ok {
+		switch order {
+		case AdOffersSearchOrderPriceAsc, AdOffersSearchOrderRatingDesc:
+			return true
+		}
+	}
+	return nil
+}
+
+type AdOfferStatus string
+
+const (
+	AdOfferStatusPending   AdOfferStatus = "pending"
+	AdOfferStatusAccepted  AdOfferStatus = "accepted"
+	AdOfferStatusRejected  AdOfferStatus = "rejected"
+	AdOfferStatusWithdrawn AdOfferStatus = "withdrawn"
+)
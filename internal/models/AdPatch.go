@@ -0,0 +1,74 @@
+package models
+
+import . "github.com/TechnoHandOver/backend/internal/models/timestamps"
+
+type AdPatch struct {
+	Id                uint32
+	UserAuthorId      uint32
+	Version           uint32
+	LocDep            *string
+	LocArr            *string
+	DateTimeDep       *DateTime
+	DateTimeDepNull   bool
+	DateTimeArr       *DateTime
+	Item              *string
+	MinPrice          *uint32
+	Comment           *string
+	LocDepPoint       *GeoPoint
+	LocDepPointNull   bool
+	LocArrPoint       *GeoPoint
+	LocArrPointNull   bool
+	LocDepPlaceId     *uint32
+	LocDepPlaceIdNull bool
+	LocArrPlaceId     *uint32
+	LocArrPlaceIdNull bool
+}
+
+func (adPatch *AdPatch) Apply(ad *Ad) *Ad {
+	patchedAd := *ad
+	if adPatch.LocDep != nil {
+		patchedAd.LocDep = *adPatch.LocDep
+	}
+	if adPatch.LocArr != nil {
+		patchedAd.LocArr = *adPatch.LocArr
+	}
+	if adPatch.DateTimeDepNull {
+		patchedAd.DateTimeDep = nil
+	} else if adPatch.DateTimeDep != nil {
+		patchedAd.DateTimeDep = adPatch.DateTimeDep
+	}
+	if adPatch.DateTimeArr != nil {
+		patchedAd.DateTimeArr = *adPatch.DateTimeArr
+	}
+	if adPatch.Item != nil {
+		patchedAd.Item = *adPatch.Item
+	}
+	if adPatch.MinPrice != nil {
+		patchedAd.MinPrice = *adPatch.MinPrice
+	}
+	if adPatch.Comment != nil {
+		patchedAd.Comment = *adPatch.Comment
+	}
+	if adPatch.LocDepPointNull {
+		patchedAd.LocDepPoint = nil
+	} else if adPatch.LocDepPoint != nil {
+		patchedAd.LocDepPoint = adPatch.LocDepPoint
+	}
+	if adPatch.LocArrPointNull {
+		patchedAd.LocArrPoint = nil
+	} else if adPatch.LocArrPoint != nil {
+		patchedAd.LocArrPoint = adPatch.LocArrPoint
+	}
+	if adPatch.LocDepPlaceIdNull {
+		patchedAd.LocDepPlaceId = nil
+	} else if adPatch.LocDepPlaceId != nil {
+		patchedAd.LocDepPlaceId = adPatch.LocDepPlaceId
+	}
+	if adPatch.LocArrPlaceIdNull {
+		patchedAd.LocArrPlaceId = nil
+	} else if adPatch.LocArrPlaceId != nil {
+		patchedAd.LocArrPlaceId = adPatch.LocArrPlaceId
+	}
+
+	return &patchedAd
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	AdPhotosMaxCount = 10
+	AdPhotoMaxSize   = 5 << 20
+	AdPhotoMaxPixels = 40 * 1000 * 1000
+)
+
+type AdPhoto struct {
+	Id               uint32 `json:"id"`
+	AdId             uint32 `json:"adId"`
+	ContentType      string `json:"contentType"`
+	Size             uint32 `json:"size"`
+	BlobKey          string `json:"-"`
+	ThumbnailBlobKey string `json:"-"`
+}
+
+type AdPhotos []*AdPhoto
+
+type AdPhotoContent struct {
+	ContentType string
+	Data        []byte
+}
+
+func (adPhoto *AdPhoto) MarshalJSON() ([]byte, error) {
+	type adPhotoJson AdPhoto
+	return json.Marshal(struct {
+		*adPhotoJson
+		Url          string `json:"url"`
+		ThumbnailUrl string `json:"thumbnailUrl"`
+	}{
+		adPhotoJson:  (*adPhotoJson)(adPhoto),
+		Url:          fmt.Sprintf("/api/ads/%d/photos/%d", adPhoto.AdId, adPhoto.Id),
+		ThumbnailUrl: fmt.Sprintf("/api/ads/%d/photos/%d/thumbnail", adPhoto.AdId, adPhoto.Id),
+	})
+}
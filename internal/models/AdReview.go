@@ -0,0 +1,17 @@
+package models
+
+import (
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+)
+
+type AdReview struct {
+	Id           uint32   `json:"id"`
+	AdId         uint32   `json:"adId"`
+	UserAuthorId uint32   `json:"-"`
+	UserTargetId uint32   `json:"-"`
+	Rating       uint32   `json:"rating"`
+	Comment      string   `json:"comment"`
+	DateTime     DateTime `json:"dateTime"`
+}
+
+type AdReviews []*AdReview
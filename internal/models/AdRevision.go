@@ -0,0 +1,130 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+)
+
+type AdRevision struct {
+	Id       uint32           `json:"id"`
+	AdId     uint32           `json:"adId"`
+	UserId   *uint32          `json:"-"`
+	UserVkId *uint32          `json:"userVkId,omitempty"`
+	Action   AdRevisionAction `json:"action"`
+	DateTime DateTime         `json:"dateTime"`
+	Diff     AdRevisionDiff   `json:"diff"`
+}
+
+type AdRevisions []*AdRevision
+
+type AdRevisionAction string
+
+const (
+	AdRevisionActionInsert    AdRevisionAction = "insert"
+	AdRevisionActionUpdate    AdRevisionAction = "update"
+	AdRevisionActionDelete    AdRevisionAction = "delete"
+	AdRevisionActionExecution AdRevisionAction = "execution"
+)
+
+type AdRevisionChange struct {
+	Old json.RawMessage `json:"old"`
+	New json.RawMessage `json:"new"`
+}
+
+type AdRevisionDiff map[string]*AdRevisionChange
+
+func NewAdRevisionDiff(ad *Ad, newAd *Ad) (AdRevisionDiff, error) {
+	fields, err := adRevisionFields(ad)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := adRevisionFields(newAd)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(AdRevisionDiff)
+	for name, value := range fields {
+		if newValue := newFields[name]; !bytes.Equal(value, newValue) {
+			diff[name] = &AdRevisionChange{
+				Old: value,
+				New: newValue,
+			}
+		}
+	}
+	for name, newValue := range newFields {
+		if _, ok := fields[name]; !ok {
+			diff[name] = &AdRevisionChange{
+				New: newValue,
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// UserAuthorId returns the actor of the insert or the delete revision, both are recorded on behalf of the author
+func (adRevisions AdRevisions) UserAuthorId() *uint32 {
+	for _, adRevision := range adRevisions {
+		if adRevision.Action == AdRevisionActionInsert || adRevision.Action == AdRevisionActionDelete {
+			return adRevision.UserId
+		}
+	}
+
+	return nil
+}
+
+func (diff AdRevisionDiff) Value() (driver.Value, error) {
+	bytes_, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(bytes_), nil //pq would send []byte as bytea
+}
+
+func (diff *AdRevisionDiff) Scan(src interface{}) error {
+	switch src_ := src.(type) {
+	case []byte:
+		return json.Unmarshal(src_, diff)
+	case string:
+		return json.Unmarshal([]byte(src_), diff)
+	default:
+		return errors.New("AdRevisionDiff: unsupported source type")
+	}
+}
+
+func adRevisionFields(ad *Ad) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if ad == nil {
+		return fields, nil
+	}
+
+	values := map[string]interface{}{
+		"userExecutorVkId": ad.UserExecutorVkId,
+		"locDep":           ad.LocDep,
+		"locArr":           ad.LocArr,
+		"locDepPoint":      ad.LocDepPoint,
+		"locArrPoint":      ad.LocArrPoint,
+		"locDepPlaceId":    ad.LocDepPlaceId,
+		"locArrPlaceId":    ad.LocArrPlaceId,
+		"dateTimeDep":      ad.DateTimeDep,
+		"dateTimeArr":      &ad.DateTimeArr,
+		"item":             ad.Item,
+		"minPrice":         ad.MinPrice,
+		"comment":          ad.Comment,
+		"status":           ad.Status,
+	}
+	for name, value := range values {
+		value_, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = value_
+	}
+
+	return fields, nil
+}
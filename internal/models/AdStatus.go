@@ -0,0 +1,37 @@
+package models
+
+type AdStatus string
+
+const (
+	AdStatusOpen      AdStatus = "open"
+	AdStatusAssigned  AdStatus = "assigned"
+	AdStatusPickedUp  AdStatus = "picked_up"
+	AdStatusDelivered AdStatus = "delivered"
+	AdStatusConfirmed AdStatus = "confirmed"
+	AdStatusCancelled AdStatus = "cancelled"
+	AdStatusExpired   AdStatus = "expired"
+)
+
+var adStatusTransitions = map[AdStatus][]AdStatus{
+	AdStatusOpen:      {AdStatusAssigned, AdStatusCancelled, AdStatusExpired},
+	AdStatusAssigned:  {AdStatusOpen, AdStatusPickedUp, AdStatusCancelled},
+	AdStatusPickedUp:  {AdStatusDelivered},
+	AdStatusDelivered: {AdStatusConfirmed},
+}
+
+func (adStatus AdStatus) IsExecuting() bool {
+	switch adStatus {
+	case AdStatusAssigned, AdStatusPickedUp, AdStatusDelivered:
+		return true
+	}
+	return false
+}
+
+func (adStatus AdStatus) CanTransitTo(nextAdStatus AdStatus) bool {
+	for _, adStatus_ := range adStatusTransitions[adStatus] {
+		if adStatus_ == nextAdStatus {
+			return true
+		}
+	}
+	return false
+}
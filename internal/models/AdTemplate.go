@@ -0,0 +1,88 @@
+package models
+
+import (
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"time"
+)
+
+type AdTemplate struct {
+	Id            uint32    `json:"id"`
+	UserAuthorId  uint32    `json:"-"`
+	LocDep        string    `json:"locDep"`
+	LocArr        string    `json:"locArr"`
+	Item          string    `json:"item"`
+	MinPrice      uint32    `json:"minPrice"`
+	Comment       string    `json:"comment"`
+	EvenWeek      bool      `json:"evenWeek"`
+	OddWeek       bool      `json:"oddWeek"`
+	DaysOfWeek    []uint32  `json:"daysOfWeek"`
+	TimeDep       *Time     `json:"timeDep,omitempty"`
+	TimeArr       Time      `json:"timeArr"`
+	LocDepPoint   *GeoPoint `json:"locDepPoint,omitempty"`
+	LocArrPoint   *GeoPoint `json:"locArrPoint,omitempty"`
+	LocDepPlaceId *uint32   `json:"locDepPlaceId,omitempty"`
+	LocArrPlaceId *uint32   `json:"locArrPlaceId,omitempty"`
+	Paused        bool      `json:"paused"`
+}
+
+type AdTemplates []*AdTemplate
+
+type AdTemplateOccurrence struct {
+	AdTemplate *AdTemplate
+	Date       Date
+}
+
+type AdTemplateOccurrences []*AdTemplateOccurrence
+
+func (adTemplate *AdTemplate) HasValidTimeWindow() bool {
+	return adTemplate.TimeDep == nil || !time.Time(*adTemplate.TimeDep).After(time.Time(adTemplate.TimeArr))
+}
+
+// HasOccurrence tells even and odd weeks apart by the ISO week number, the same way the scheduler does
+func (adTemplate *AdTemplate) HasOccurrence(date time.Time) bool {
+	if _, week := date.ISOWeek(); week%2 == 0 && !adTemplate.EvenWeek || week%2 == 1 && !adTemplate.OddWeek {
+		return false
+	}
+
+	dayOfWeek := uint32(date.Weekday())
+	if dayOfWeek == 0 { //ISO week starts on Monday, so Sunday is the 7th day
+		dayOfWeek = 7
+	}
+	for _, dayOfWeek_ := range adTemplate.DaysOfWeek {
+		if dayOfWeek_ == dayOfWeek {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (adTemplateOccurrence *AdTemplateOccurrence) NewAd() *Ad {
+	adTemplate := adTemplateOccurrence.AdTemplate
+	date := time.Time(adTemplateOccurrence.Date)
+	atDate := func(time_ Time) DateTime {
+		clock := time.Time(time_)
+		return DateTime(time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0,
+			date.Location()))
+	}
+
+	ad := &Ad{
+		UserAuthorId:  adTemplate.UserAuthorId,
+		LocDep:        adTemplate.LocDep,
+		LocArr:        adTemplate.LocArr,
+		DateTimeArr:   atDate(adTemplate.TimeArr),
+		Item:          adTemplate.Item,
+		MinPrice:      adTemplate.MinPrice,
+		Comment:       adTemplate.Comment,
+		LocDepPoint:   adTemplate.LocDepPoint,
+		LocArrPoint:   adTemplate.LocArrPoint,
+		LocDepPlaceId: adTemplate.LocDepPlaceId,
+		LocArrPlaceId: adTemplate.LocArrPlaceId,
+	}
+	if adTemplate.TimeDep != nil {
+		dateTimeDep := atDate(*adTemplate.TimeDep)
+		ad.DateTimeDep = &dateTimeDep
+	}
+
+	return ad
+}
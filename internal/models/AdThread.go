@@ -0,0 +1,13 @@
+package models
+
+type AdThread struct {
+	Id             uint32 `json:"id"`
+	AdId           uint32 `json:"adId"`
+	UserAuthorId   uint32 `json:"userAuthorId"`
+	UserExecutorId uint32 `json:"userExecutorId"`
+	Archived       bool   `json:"archived"`
+}
+
+func (adThread *AdThread) HasParticipant(userId uint32) bool {
+	return adThread.UserAuthorId == userId || adThread.UserExecutorId == userId
+}
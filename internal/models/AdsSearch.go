@@ -1,19 +1,33 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
 	"reflect"
+	"time"
 )
 
 type AdsSearch struct {
-	UserAuthorId         *uint32
-	NotUserAuthorId      *uint32
-	NullUserExecutorVkId *bool
-	LocDep               *string
-	LocArr               *string
-	MinDateTimeArr       *DateTime
-	MaxPrice             *uint32
-	Order                *AdsSearchOrder
+	UserAuthorId        *uint32
+	NotUserAuthorId     *uint32
+	NotBlockedUserId    *uint32
+	UserExecutorId      *uint32
+	Status              *AdStatus
+	Active              *bool
+	LocDep              *string
+	LocArr              *string
+	LocDepPlaceId       *uint32
+	LocArrPlaceId       *uint32
+	MinDateTimeArr      *DateTime
+	MaxPrice            *uint32
+	MinUserAuthorRating *float32
+	NearDep             *GeoPoint
+	NearArr             *GeoPoint
+	RadiusM             *uint32
+	Order               *AdsSearchOrder
+	Cursor              *AdsSearchCursor
+	Limit               *uint32
 }
 
 type AdsSearchOrder int
@@ -35,3 +49,33 @@ func ValidateAdsSearchOrder(field reflect.Value) interface{} {
 	}
 	return nil
 }
+
+type AdsSearchCursor struct {
+	Order       AdsSearchOrder `json:"o"`
+	DateTimeArr time.Time      `json:"d"`
+	MinPrice    uint32         `json:"p"`
+	Id          uint32         `json:"i"`
+}
+
+func NewAdsSearchCursor(order AdsSearchOrder, ad *Ad) *AdsSearchCursor {
+	return &AdsSearchCursor{
+		Order:       order,
+		DateTimeArr: time.Time(ad.DateTimeArr),
+		MinPrice:    ad.MinPrice,
+		Id:          ad.Id,
+	}
+}
+
+func (adsSearchCursor *AdsSearchCursor) String() string {
+	bytes, _ := json.Marshal(adsSearchCursor)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func (adsSearchCursor *AdsSearchCursor) UnmarshalParam(src string) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, adsSearchCursor)
+}
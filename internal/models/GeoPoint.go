@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var errGeoPointFormat = errors.New("GeoPoint: invalid format")
+
+type GeoPoint struct {
+	Lat float64 `json:"lat" validate:"gte=-90,lte=90"`
+	Lon float64 `json:"lon" validate:"gte=-180,lte=180"`
+}
+
+func (geoPoint *GeoPoint) UnmarshalParam(src string) error {
+	lat, lon, err := parseGeoPointCoordinates(src)
+	if err != nil {
+		return err
+	}
+
+	geoPoint.Lat, geoPoint.Lon = lat, lon
+	return nil
+}
+
+func (geoPoint GeoPoint) Value() (driver.Value, error) {
+	//PostgreSQL point is "(x,y)", that is "(lon,lat)"
+	return "(" + strconv.FormatFloat(geoPoint.Lon, 'f', -1, 64) + "," +
+		strconv.FormatFloat(geoPoint.Lat, 'f', -1, 64) + ")", nil
+}
+
+func (geoPoint *GeoPoint) Scan(src interface{}) error {
+	var string_ string
+	switch src_ := src.(type) {
+	case []byte:
+		string_ = string(src_)
+	case string:
+		string_ = src_
+	default:
+		return fmt.Errorf("GeoPoint: unsupported source type %T", src)
+	}
+
+	lon, lat, err := parseGeoPointCoordinates(strings.Trim(string_, "()"))
+	if err != nil {
+		return err
+	}
+
+	geoPoint.Lat, geoPoint.Lon = lat, lon
+	return nil
+}
+
+func parseGeoPointCoordinates(string_ string) (float64, float64, error) {
+	coordinates := strings.Split(string_, ",")
+	if len(coordinates) != 2 {
+		return 0, 0, errGeoPointFormat
+	}
+
+	first, err := strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	second, err := strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return first, second, nil
+}
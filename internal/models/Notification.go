@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+)
+
+type Notification struct {
+	Id       uint32              `json:"id"`
+	UserId   uint32              `json:"-"`
+	Type     NotificationType    `json:"type"`
+	Payload  NotificationPayload `json:"payload"`
+	Read     bool                `json:"read"`
+	DateTime DateTime            `json:"dateTime"`
+}
+
+type Notifications []*Notification
+
+type NotificationType string
+
+const (
+	NotificationTypeRouteMatch       NotificationType = "route_match"
+	NotificationTypeSavedSearchMatch NotificationType = "saved_search_match"
+	NotificationTypeAdExpired        NotificationType = "ad_expired"
+	NotificationTypeDigest           NotificationType = "digest"
+)
+
+type NotificationPayload struct {
+	AdId          uint32   `json:"adId,omitempty"`
+	AdIds         []uint32 `json:"adIds,omitempty"`
+	SavedSearchId *uint32  `json:"savedSearchId,omitempty"`
+}
+
+func (payload NotificationPayload) Value() (driver.Value, error) {
+	bytes_, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(bytes_), nil //pq would send []byte as bytea
+}
+
+func (payload *NotificationPayload) Scan(src interface{}) error {
+	switch src_ := src.(type) {
+	case []byte:
+		return json.Unmarshal(src_, payload)
+	case string:
+		return json.Unmarshal([]byte(src_), payload)
+	default:
+		return errors.New("NotificationPayload: unsupported source type")
+	}
+}
+
+type NotificationOutboxItem struct {
+	Id                   uint32                   `json:"id"`
+	Notification         Notification             `json:"notification"`
+	NotificationChannel  NotificationChannel      `json:"notificationChannel"`
+	NotificationSettings NotificationSettings     `json:"notificationSettings"`
+	Status               NotificationOutboxStatus `json:"status"`
+	Attempts             uint32                   `json:"attempts"`
+	LastError            *string                  `json:"lastError,omitempty"`
+	DateTimeNextAttempt  DateTime                 `json:"dateTimeNextAttempt"`
+	DateTimeSent         *DateTime                `json:"dateTimeSent,omitempty"`
+}
+
+type NotificationOutboxItems []*NotificationOutboxItem
+
+type NotificationOutboxStatus string
+
+const (
+	NotificationOutboxStatusPending NotificationOutboxStatus = "pending"
+	NotificationOutboxStatusSent    NotificationOutboxStatus = "sent"
+	NotificationOutboxStatusDead    NotificationOutboxStatus = "dead"
+)
+
+type NotificationsUnread struct {
+	Count uint32 `json:"count"`
+}
+
+type NotificationsCursor struct {
+	Id uint32 `json:"i"`
+}
+
+func NewNotificationsCursor(notification *Notification) *NotificationsCursor {
+	return &NotificationsCursor{
+		Id: notification.Id,
+	}
+}
+
+func (notificationsCursor *NotificationsCursor) String() string {
+	bytes, _ := json.Marshal(notificationsCursor)
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func (notificationsCursor *NotificationsCursor) UnmarshalParam(src string) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, notificationsCursor)
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"net/mail"
+	"net/url"
+)
+
+type NotificationChannel struct {
+	UserId  uint32                  `json:"-"`
+	Channel NotificationChannelKind `json:"channel"`
+	Address *string                 `json:"address,omitempty"`
+}
+
+type NotificationChannelKind string
+
+const (
+	NotificationChannelKindVkBot   NotificationChannelKind = "vk_bot"
+	NotificationChannelKindWebhook NotificationChannelKind = "webhook"
+	NotificationChannelKindEmail   NotificationChannelKind = "email"
+	NotificationChannelKindLog     NotificationChannelKind = "log"
+)
+
+func NewDefaultNotificationChannel(userId uint32) *NotificationChannel {
+	return &NotificationChannel{
+		UserId:  userId,
+		Channel: NotificationChannelKindVkBot,
+	}
+}
+
+// HasValidAddress also rejects the channels users cannot choose, that is the log one
+func (notificationChannel *NotificationChannel) HasValidAddress() bool {
+	switch notificationChannel.Channel {
+	case NotificationChannelKindVkBot:
+		return notificationChannel.Address == nil
+	case NotificationChannelKindEmail:
+		if notificationChannel.Address == nil {
+			return false
+		}
+		address, err := mail.ParseAddress(*notificationChannel.Address)
+		return err == nil && address.Address == *notificationChannel.Address
+	case NotificationChannelKindWebhook:
+		if notificationChannel.Address == nil {
+			return false
+		}
+		url_, err := url.ParseRequestURI(*notificationChannel.Address)
+		//the body is signed, not encrypted, and the host is checked once more when dialing
+		return err == nil && url_.Scheme == "https" && url_.Host != ""
+	default:
+		return false
+	}
+}
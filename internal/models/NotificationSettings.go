@@ -0,0 +1,134 @@
+package models
+
+import (
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"time"
+)
+
+const DefaultNotificationSettingsTimezone = "Europe/Moscow"
+
+type NotificationSettings struct {
+	UserId             uint32     `json:"-"`
+	Timezone           string     `json:"timezone"`
+	QuietHoursFrom     *Time      `json:"quietHoursFrom,omitempty"`
+	QuietHoursTo       *Time      `json:"quietHoursTo,omitempty"`
+	MaxPerHour         *uint32    `json:"maxPerHour,omitempty"`
+	MinPrice           *uint32    `json:"minPrice,omitempty"`
+	MutedRouteIds      []uint32   `json:"mutedRouteIds"`
+	DigestInterval     *uint32    `json:"digestInterval,omitempty"`
+	DigestTimes        []Time     `json:"digestTimes"`
+	DateTimeDigestNext *time.Time `json:"-"`
+}
+
+type NotificationSettingsArray []*NotificationSettings
+
+type NotificationsSent struct {
+	Count         uint32     `json:"count"`
+	DateTimeFirst *time.Time `json:"dateTimeFirst,omitempty"`
+}
+
+func NewDefaultNotificationSettings(userId uint32) *NotificationSettings {
+	return &NotificationSettings{
+		UserId:        userId,
+		Timezone:      DefaultNotificationSettingsTimezone,
+		MutedRouteIds: make([]uint32, 0),
+		DigestTimes:   make([]Time, 0),
+	}
+}
+
+func (notificationSettings *NotificationSettings) HasValidQuietHours() bool {
+	return (notificationSettings.QuietHoursFrom == nil) == (notificationSettings.QuietHoursTo == nil)
+}
+
+// HasValidDigest allows either the interval or the fixed times of the digest, but not both
+func (notificationSettings *NotificationSettings) HasValidDigest() bool {
+	if notificationSettings.DigestInterval != nil && len(notificationSettings.DigestTimes) != 0 {
+		return false
+	}
+
+	digestTimes := make(map[int]bool, len(notificationSettings.DigestTimes))
+	for _, digestTime := range notificationSettings.DigestTimes {
+		digestTime_ := minutesOfDay(time.Time(digestTime))
+		if digestTimes[digestTime_] {
+			return false
+		}
+		digestTimes[digestTime_] = true
+	}
+
+	return true
+}
+
+func (notificationSettings *NotificationSettings) HasValidTimezone() bool {
+	_, err := time.LoadLocation(notificationSettings.Timezone)
+	return err == nil
+}
+
+// QuietHoursEnd reports whether dateTime falls into the quiet hours and when they end; the quiet hours may span
+// midnight, for example from 23:00 till 07:00
+func (notificationSettings *NotificationSettings) QuietHoursEnd(dateTime time.Time) (time.Time, bool) {
+	if notificationSettings.QuietHoursFrom == nil || notificationSettings.QuietHoursTo == nil {
+		return time.Time{}, false
+	}
+
+	location, err := time.LoadLocation(notificationSettings.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	dateTime = dateTime.In(location)
+	from := minutesOfDay(time.Time(*notificationSettings.QuietHoursFrom))
+	to := minutesOfDay(time.Time(*notificationSettings.QuietHoursTo))
+	now := minutesOfDay(dateTime)
+
+	var days int
+	switch {
+	case from < to && from <= now && now < to:
+		days = 0
+	case from > to && now >= from:
+		days = 1
+	case from > to && now < to:
+		days = 0
+	default:
+		return time.Time{}, false
+	}
+
+	return time.Date(dateTime.Year(), dateTime.Month(), dateTime.Day()+days, to/60, to%60, 0, 0, location), true
+}
+
+// NextDigest reports whether the ad notifications are collected into the digests and when the digest after dateTime
+// is due
+func (notificationSettings *NotificationSettings) NextDigest(dateTime time.Time) (time.Time, bool) {
+	if notificationSettings.DigestInterval != nil {
+		return dateTime.Add(time.Duration(*notificationSettings.DigestInterval) * time.Minute), true
+	}
+
+	if len(notificationSettings.DigestTimes) == 0 {
+		return time.Time{}, false
+	}
+
+	location, err := time.LoadLocation(notificationSettings.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	dateTime = dateTime.In(location)
+	var dateTimeNext time.Time
+	for _, digestTime := range notificationSettings.DigestTimes {
+		digestTime_ := minutesOfDay(time.Time(digestTime))
+		dateTimeDigest := time.Date(dateTime.Year(), dateTime.Month(), dateTime.Day(), digestTime_/60,
+			digestTime_%60, 0, 0, location)
+		if !dateTimeDigest.After(dateTime) {
+			dateTimeDigest = dateTimeDigest.AddDate(0, 0, 1)
+		}
+
+		if dateTimeNext.IsZero() || dateTimeDigest.Before(dateTimeNext) {
+			dateTimeNext = dateTimeDigest
+		}
+	}
+
+	return dateTimeNext, true
+}
+
+func minutesOfDay(time_ time.Time) int {
+	return time_.Hour()*60 + time_.Minute()
+}
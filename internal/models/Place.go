@@ -0,0 +1,19 @@
+package models
+
+type PlaceKind string
+
+const (
+	PlaceKindBuilding PlaceKind = "building"
+	PlaceKindDorm     PlaceKind = "dorm"
+	PlaceKindMetro    PlaceKind = "metro"
+)
+
+type Place struct {
+	Id      uint32    `json:"id"`
+	Name    string    `json:"name"`
+	Aliases []string  `json:"aliases"`
+	Kind    PlaceKind `json:"kind"`
+	Point   GeoPoint  `json:"point"`
+}
+
+type Places []*Place
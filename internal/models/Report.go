@@ -0,0 +1,45 @@
+package models
+
+import (
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+)
+
+type Report struct {
+	Id             uint32        `json:"id"`
+	UserAuthorId   uint32        `json:"userAuthorId"`
+	AdId           *uint32       `json:"adId,omitempty"`
+	AdUserAuthorId *uint32       `json:"adUserAuthorId,omitempty"`
+	UserTargetId   *uint32       `json:"userTargetId,omitempty"`
+	Reason         ReportReason  `json:"reason"`
+	Comment        string        `json:"comment"`
+	Status         ReportStatus  `json:"status"`
+	Action         *ReportAction `json:"action,omitempty"`
+	DateTime       DateTime      `json:"dateTime"`
+}
+
+type Reports []*Report
+
+type ReportReason string
+
+const (
+	ReportReasonScam       ReportReason = "scam"
+	ReportReasonOffensive  ReportReason = "offensive"
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonProhibited ReportReason = "prohibited"
+	ReportReasonOther      ReportReason = "other"
+)
+
+type ReportStatus string
+
+const (
+	ReportStatusPending   ReportStatus = "pending"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+type ReportAction string
+
+const (
+	ReportActionHideAd      ReportAction = "hide_ad"
+	ReportActionSuspendUser ReportAction = "suspend_user"
+)
@@ -3,16 +3,21 @@ package models
 import . "github.com/TechnoHandOver/backend/internal/models/timestamps"
 
 type RoutePerm struct {
-	Id           uint32 `json:"id"`
-	UserAuthorId uint32 `json:"-"`
-	LocDep       string `json:"locDep"`
-	LocArr       string `json:"locArr"`
-	MinPrice     uint32 `json:"minPrice"`
-	EvenWeek     bool   `json:"evenWeek"`
-	OddWeek      bool   `json:"oddWeek"`
-	DayOfWeek    uint32 `json:"dayOfWeek"`
-	TimeDep      Time   `json:"timeDep"`
-	TimeArr      Time   `json:"timeArr"`
+	Id            uint32    `json:"id"`
+	UserAuthorId  uint32    `json:"-"`
+	LocDep        string    `json:"locDep"`
+	LocArr        string    `json:"locArr"`
+	LocDepPoint   *GeoPoint `json:"locDepPoint,omitempty"`
+	LocArrPoint   *GeoPoint `json:"locArrPoint,omitempty"`
+	LocDepPlaceId *uint32   `json:"locDepPlaceId,omitempty"`
+	LocArrPlaceId *uint32   `json:"locArrPlaceId,omitempty"`
+	MinPrice      uint32    `json:"minPrice"`
+	EvenWeek      bool      `json:"evenWeek"`
+	OddWeek       bool      `json:"oddWeek"`
+	DayOfWeek     uint32    `json:"dayOfWeek"`
+	TimeDep       Time      `json:"timeDep"`
+	TimeArr       Time      `json:"timeArr"`
+	Version       uint32    `json:"-"`
 }
 
 type RoutesPerm []*RoutePerm
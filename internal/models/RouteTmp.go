@@ -3,13 +3,18 @@ package models
 import . "github.com/TechnoHandOver/backend/internal/models/timestamps"
 
 type RouteTmp struct {
-	Id           uint32   `json:"id"`
-	UserAuthorId uint32   `json:"-"`
-	LocDep       string   `json:"locDep"`
-	LocArr       string   `json:"locArr"`
-	MinPrice     uint32   `json:"minPrice"`
-	DateTimeDep  DateTime `json:"dateTimeDep"`
-	DateTimeArr  DateTime `json:"dateTimeArr"`
+	Id            uint32    `json:"id"`
+	UserAuthorId  uint32    `json:"-"`
+	LocDep        string    `json:"locDep"`
+	LocArr        string    `json:"locArr"`
+	LocDepPoint   *GeoPoint `json:"locDepPoint,omitempty"`
+	LocArrPoint   *GeoPoint `json:"locArrPoint,omitempty"`
+	LocDepPlaceId *uint32   `json:"locDepPlaceId,omitempty"`
+	LocArrPlaceId *uint32   `json:"locArrPlaceId,omitempty"`
+	MinPrice      uint32    `json:"minPrice"`
+	DateTimeDep   DateTime  `json:"dateTimeDep"`
+	DateTimeArr   DateTime  `json:"dateTimeArr"`
+	Version       uint32    `json:"-"`
 }
 
 type RoutesTmp []*RouteTmp
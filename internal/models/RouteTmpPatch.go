@@ -0,0 +1,22 @@
+package models
+
+import . "github.com/TechnoHandOver/backend/internal/models/timestamps"
+
+type RouteTmpPatch struct {
+	Id                uint32
+	UserAuthorId      uint32
+	Version           uint32
+	LocDep            *string
+	LocArr            *string
+	LocDepPoint       *GeoPoint
+	LocDepPointNull   bool
+	LocArrPoint       *GeoPoint
+	LocArrPointNull   bool
+	LocDepPlaceId     *uint32
+	LocDepPlaceIdNull bool
+	LocArrPlaceId     *uint32
+	LocArrPlaceIdNull bool
+	MinPrice          *uint32
+	DateTimeDep       *DateTime
+	DateTimeArr       *DateTime
+}
@@ -0,0 +1,13 @@
+package models
+
+type SavedSearch struct {
+	Id           uint32          `json:"id"`
+	UserAuthorId uint32          `json:"-"`
+	Name         string          `json:"name"`
+	LocDep       *string         `json:"locDep,omitempty"`
+	LocArr       *string         `json:"locArr,omitempty"`
+	MaxPrice     *uint32         `json:"maxPrice,omitempty"`
+	Order        *AdsSearchOrder `json:"order,omitempty"`
+}
+
+type SavedSearches []*SavedSearch
@@ -1,10 +1,13 @@
 package models
 
 type User struct {
-	Id     uint32 `json:"id"`
-	VkId   uint32 `json:"vkId"`
-	Name   string `json:"name"`
-	Avatar string `json:"avatar"`
+	Id          uint32  `json:"id"`
+	VkId        uint32  `json:"vkId"`
+	Name        string  `json:"name"`
+	Avatar      string  `json:"avatar"`
+	Rating      float32 `json:"rating"`
+	RatingCount uint32  `json:"ratingCount"`
+	Suspended   bool    `json:"-"`
 }
 
 type Users []*User
@@ -0,0 +1,15 @@
+package models
+
+import (
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+)
+
+type UserBlock struct {
+	UserId            uint32   `json:"-"`
+	UserBlockedId     uint32   `json:"userBlockedId"`
+	UserBlockedName   string   `json:"userBlockedName"`
+	UserBlockedAvatar string   `json:"userBlockedAvatar"`
+	DateTime          DateTime `json:"dateTime"`
+}
+
+type UserBlocks []*UserBlock
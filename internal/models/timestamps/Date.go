@@ -0,0 +1,41 @@
+package timestamps
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Date time.Time
+
+const dateLayout = "02.01.2006"
+
+func NewDate(string_ string) (*Date, error) {
+	time_, err := time.Parse(dateLayout, string_)
+	if err != nil {
+		return nil, err
+	}
+
+	date := Date(time_)
+	return &date, nil
+}
+
+func (date *Date) String() string {
+	time_ := time.Time(*date)
+	return fmt.Sprintf("%q", time_.Format(dateLayout))
+}
+
+func (date *Date) UnmarshalParam(src string) (err error) {
+	var string_ = strings.Trim(src, `"`)
+	time_, err := time.Parse(dateLayout, string_)
+	*date = Date(time_)
+	return
+}
+
+func (date *Date) UnmarshalJSON(b []byte) (err error) {
+	return date.UnmarshalParam(string(b))
+}
+
+func (date *Date) MarshalJSON() ([]byte, error) {
+	return []byte(date.String()), nil
+}
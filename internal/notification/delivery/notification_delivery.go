@@ -0,0 +1,164 @@
+package delivery
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	. "github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/notification"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationDelivery struct {
+	notificationUsecase notification.Usecase
+}
+
+func NewNotificationDelivery(notificationUsecase notification.Usecase) *NotificationDelivery {
+	return &NotificationDelivery{
+		notificationUsecase: notificationUsecase,
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) Configure(echo_ *echo.Echo, middlewaresManager *middlewares.Manager) {
+	echo_.GET("/api/notifications", notificationDelivery.HandlerNotificationsList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/notifications/:id/read", notificationDelivery.HandlerNotificationRead(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/notifications/read", notificationDelivery.HandlerNotificationsRead(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/notifications/unread", notificationDelivery.HandlerNotificationsUnread(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/notifications/channel", notificationDelivery.HandlerNotificationChannelGet(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.PUT("/api/notifications/channel", notificationDelivery.HandlerNotificationChannelUpdate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/users/me/notification-settings", notificationDelivery.HandlerNotificationSettingsGet(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.PUT("/api/users/me/notification-settings", notificationDelivery.HandlerNotificationSettingsUpdate(), middlewaresManager.AuthMiddleware.CheckAuth())
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationsList() echo.HandlerFunc {
+	type NotificationsListRequest struct {
+		Cursor *models.NotificationsCursor `query:"cursor" validate:"omitempty"`
+		Limit  *uint32                     `query:"limit" validate:"omitempty,min=1,max=100"`
+	}
+
+	return func(context echo.Context) error {
+		notificationsListRequest := new(NotificationsListRequest)
+		if err := parser.ParseRequest(context, notificationsListRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.ListNotifications(userId,
+			notificationsListRequest.Cursor, notificationsListRequest.Limit))
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationRead() echo.HandlerFunc {
+	type NotificationReadRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		notificationReadRequest := new(NotificationReadRequest)
+		if err := parser.ParseRequest(context, notificationReadRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.ReadNotification(userId,
+			*notificationReadRequest.Id))
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationsRead() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.ReadAllNotifications(userId))
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationsUnread() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.GetNotificationsUnread(userId))
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationChannelGet() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.GetNotificationChannel(userId))
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationChannelUpdate() echo.HandlerFunc {
+	type NotificationChannelUpdateRequest struct {
+		Channel *models.NotificationChannelKind `json:"channel" validate:"required,eq=vk_bot|eq=webhook|eq=email"`
+		Address *string                         `json:"address" validate:"omitempty,lte=500"`
+	}
+
+	return func(context echo.Context) error {
+		notificationChannelUpdateRequest := new(NotificationChannelUpdateRequest)
+		if err := parser.ParseRequest(context, notificationChannelUpdateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		notificationChannel := &models.NotificationChannel{
+			UserId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			Channel: *notificationChannelUpdateRequest.Channel,
+			Address: notificationChannelUpdateRequest.Address,
+		}
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.UpdateNotificationChannel(
+			notificationChannel))
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationSettingsGet() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.GetNotificationSettings(userId))
+	}
+}
+
+func (notificationDelivery *NotificationDelivery) HandlerNotificationSettingsUpdate() echo.HandlerFunc {
+	type NotificationSettingsUpdateRequest struct {
+		Timezone       *string   `json:"timezone" validate:"required,lte=50"`
+		QuietHoursFrom *Time     `json:"quietHoursFrom" validate:"omitempty"`
+		QuietHoursTo   *Time     `json:"quietHoursTo" validate:"omitempty"`
+		MaxPerHour     *uint32   `json:"maxPerHour" validate:"omitempty,min=1"`
+		MinPrice       *uint32   `json:"minPrice" validate:"omitempty"`
+		MutedRouteIds  *[]uint32 `json:"mutedRouteIds" validate:"required,max=100,unique"`
+		DigestInterval *uint32   `json:"digestInterval" validate:"omitempty,min=5,max=1440"`
+		DigestTimes    *[]Time   `json:"digestTimes" validate:"omitempty,max=24"`
+	}
+
+	return func(context echo.Context) error {
+		notificationSettingsUpdateRequest := new(NotificationSettingsUpdateRequest)
+		if err := parser.ParseRequest(context, notificationSettingsUpdateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		notificationSettings := &models.NotificationSettings{
+			UserId:         context.Get(consts.EchoContextKeyUserId).(uint32),
+			Timezone:       *notificationSettingsUpdateRequest.Timezone,
+			QuietHoursFrom: notificationSettingsUpdateRequest.QuietHoursFrom,
+			QuietHoursTo:   notificationSettingsUpdateRequest.QuietHoursTo,
+			MaxPerHour:     notificationSettingsUpdateRequest.MaxPerHour,
+			MinPrice:       notificationSettingsUpdateRequest.MinPrice,
+			MutedRouteIds:  *notificationSettingsUpdateRequest.MutedRouteIds,
+			DigestInterval: notificationSettingsUpdateRequest.DigestInterval,
+			DigestTimes:    make([]Time, 0),
+		}
+		if notificationSettingsUpdateRequest.DigestTimes != nil {
+			notificationSettings.DigestTimes = *notificationSettingsUpdateRequest.DigestTimes
+		}
+
+		return responser.Respond(context, notificationDelivery.notificationUsecase.UpdateNotificationSettings(
+			notificationSettings))
+	}
+}
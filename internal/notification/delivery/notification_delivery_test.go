@@ -0,0 +1,206 @@
+package delivery_test
+
+import (
+	"encoding/json"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/notification/delivery"
+	"github.com/TechnoHandOver/backend/internal/notification/mock_notification"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	HandoverValidator "github.com/TechnoHandOver/backend/internal/tools/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNotificationDelivery_HandlerNotificationRead(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	notificationDelivery := delivery.NewNotificationDelivery(mockNotificationUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	notificationDelivery.Configure(echo_, &middlewares.Manager{})
+
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	expectedNotification := &models.Notification{
+		Id:       1,
+		UserId:   101,
+		Type:     models.NotificationTypeRouteMatch,
+		Payload:  models.NotificationPayload{AdId: 1},
+		Read:     true,
+		DateTime: *dateTime,
+	}
+
+	mockNotificationUsecase.
+		EXPECT().
+		ReadNotification(gomock.Eq(expectedNotification.UserId), gomock.Eq(expectedNotification.Id)).
+		Return(response.NewResponse(consts.OK, expectedNotification))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedNotification,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/notifications/:id/read")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedNotification.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, expectedNotification.UserId)
+
+	handler := notificationDelivery.HandlerNotificationRead()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestNotificationDelivery_HandlerNotificationsUnread(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	notificationDelivery := delivery.NewNotificationDelivery(mockNotificationUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	notificationDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	expectedNotificationsUnread := &models.NotificationsUnread{
+		Count: 3,
+	}
+
+	mockNotificationUsecase.
+		EXPECT().
+		GetNotificationsUnread(gomock.Eq(userId)).
+		Return(response.NewResponse(consts.OK, expectedNotificationsUnread))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedNotificationsUnread,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/notifications/unread")
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := notificationDelivery.HandlerNotificationsUnread()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestNotificationDelivery_HandlerNotificationSettingsUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	notificationDelivery := delivery.NewNotificationDelivery(mockNotificationUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	notificationDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId uint32 = 101
+	quietHoursFrom, err := timestamps.NewTime("23:00")
+	assert.Nil(t, err)
+	quietHoursTo, err := timestamps.NewTime("07:00")
+	assert.Nil(t, err)
+	digestTime, err := timestamps.NewTime("09:00")
+	assert.Nil(t, err)
+	notificationSettings := &models.NotificationSettings{
+		UserId:         userId,
+		Timezone:       "Europe/Moscow",
+		QuietHoursFrom: quietHoursFrom,
+		QuietHoursTo:   quietHoursTo,
+		MaxPerHour:     pointy.Uint32(5),
+		MutedRouteIds:  []uint32{3},
+		DigestTimes:    []timestamps.Time{*digestTime},
+	}
+
+	mockNotificationUsecase.
+		EXPECT().
+		UpdateNotificationSettings(gomock.Eq(notificationSettings)).
+		Return(response.NewResponse(consts.OK, notificationSettings))
+
+	jsonRequest, err := json.Marshal(notificationSettings)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: notificationSettings,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/me/notification-settings")
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := notificationDelivery.HandlerNotificationSettingsUpdate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestNotificationDelivery_HandlerNotificationSettingsUpdate_duplicateMutedRouteIds(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	notificationDelivery := delivery.NewNotificationDelivery(mockNotificationUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	notificationDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPut, "/",
+		strings.NewReader(`{"timezone":"Europe/Moscow","mutedRouteIds":[3,3]}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/me/notification-settings")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := notificationDelivery.HandlerNotificationSettingsUpdate()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
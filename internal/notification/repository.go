@@ -1,7 +1,26 @@
 package notification
 
-import "github.com/TechnoHandOver/backend/internal/models"
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"time"
+)
 
 type Repository interface {
 	SelectUsersByRoutesWithSuitableTimeInterval(ad *models.Ad) (*models.Users, error)
+	SelectSavedSearchArrayBySuitableAd(ad *models.Ad) (*models.SavedSearches, error)
+	InsertNotificationArray(notifications *models.Notifications) (*models.Notifications, error)
+	SelectNotification(id uint32) (*models.Notification, error)
+	SelectNotificationArrayByUserId(userId uint32, cursor *models.NotificationsCursor, limit uint32) (*models.Notifications, error)
+	UpdateNotificationRead(id uint32) (*models.Notification, error)
+	UpdateNotificationsReadByUserId(userId uint32) error
+	SelectNotificationsUnreadByUserId(userId uint32) (*models.NotificationsUnread, error)
+	UpdateNotificationOutboxItemArrayLease(limit uint32, dateTime time.Time, dateTimeLeaseExpiry time.Time) (*models.NotificationOutboxItems, error)
+	UpdateNotificationOutboxItem(notificationOutboxItem *models.NotificationOutboxItem) error
+	SelectNotificationChannelByUserId(userId uint32) (*models.NotificationChannel, error)
+	InsertOrUpdateNotificationChannel(notificationChannel *models.NotificationChannel) (*models.NotificationChannel, error)
+	UpdateNotificationOutboxItemSentReserve(notificationOutboxItem *models.NotificationOutboxItem, maxPerHour uint32, dateTimeFrom time.Time, dateTime time.Time) (*models.NotificationsSent, error)
+	SelectNotificationSettingsByUserId(userId uint32) (*models.NotificationSettings, error)
+	UpdateNotificationSettings(notificationSettings *models.NotificationSettings) (*models.NotificationSettings, error)
+	SelectNotificationSettingsArrayByDigestDue(dateTime time.Time) (*models.NotificationSettingsArray, error)
+	InsertNotificationDigest(userId uint32, dateTime time.Time, dateTimeDigestNext time.Time) (*models.Notification, error)
 }
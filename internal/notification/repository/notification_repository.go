@@ -2,11 +2,17 @@ package repository
 
 import (
 	"database/sql"
+	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
 	"github.com/TechnoHandOver/backend/internal/notification"
+	"github.com/TechnoHandOver/backend/internal/tools/geo"
+	"github.com/lib/pq"
 	"time"
 )
 
+const routeSuitableRadiusM = 1000
+
 type NotificationRepository struct {
 	db *sql.DB
 }
@@ -18,17 +24,26 @@ func NewNotificationRepositoryImpl(db *sql.DB) notification.Repository {
 }
 
 func (notificationRepository *NotificationRepository) SelectUsersByRoutesWithSuitableTimeInterval(ad *models.Ad) (*models.Users, error) {
-	const query = `
+	var query = `
 (SELECT user_.id, user_.vk_id, user_.name, user_.avatar
 FROM user_
 JOIN (SELECT route.user_author_id FROM route_tmp
     JOIN route ON route_tmp.id = route.id
 WHERE route.user_author_id != $1 AND
-      to_tsvector('russian', route.loc_dep) @@ plainto_tsquery('russian', $2) AND
-      to_tsvector('russian', route.loc_arr) @@ plainto_tsquery('russian', $3) AND
+      NOT EXISTS (SELECT FROM ad WHERE ad.id = $12 AND ad.hidden) AND
+      NOT user_blocked(route.user_author_id, $1) AND
+      NOT EXISTS (SELECT FROM route_mute WHERE route_mute.route_id = route.id) AND
+      NOT EXISTS (SELECT FROM notification_settings
+                  WHERE notification_settings.user_id = route.user_author_id AND notification_settings.min_price > $4) AND
+      coalesce(route.loc_dep_place_id = $9,
+               to_tsvector('russian', route.loc_dep) @@ plainto_tsquery('russian', $2) OR
+               ` + geo.DistanceExpression("route.loc_dep_point", "$6::point") + ` <= $8) AND
+      coalesce(route.loc_arr_place_id = $10,
+               to_tsvector('russian', route.loc_arr) @@ plainto_tsquery('russian', $3) OR
+               ` + geo.DistanceExpression("route.loc_arr_point", "$7::point") + ` <= $8) AND
       route.min_price <= $4 AND
       route_tmp.date_time_dep <= $5 AND
-      route_tmp.date_time_arr >= $5) AS "route_tmp_"
+      route_tmp.date_time_arr >= coalesce($11, $5)) AS "route_tmp_"
     ON route_tmp_.user_author_id = user_.id)
 UNION
 (SELECT user_.id, user_.vk_id, user_.name, user_.avatar
@@ -36,16 +51,26 @@ FROM user_
 JOIN (SELECT route.user_author_id FROM route_perm
     JOIN route ON route_perm.id = route.id
 WHERE route.user_author_id != $1 AND
-      to_tsvector('russian', route.loc_dep) @@ plainto_tsquery('russian', $2) AND
-      to_tsvector('russian', route.loc_arr) @@ plainto_tsquery('russian', $3) AND
+      NOT EXISTS (SELECT FROM ad WHERE ad.id = $12 AND ad.hidden) AND
+      NOT user_blocked(route.user_author_id, $1) AND
+      NOT EXISTS (SELECT FROM route_mute WHERE route_mute.route_id = route.id) AND
+      NOT EXISTS (SELECT FROM notification_settings
+                  WHERE notification_settings.user_id = route.user_author_id AND notification_settings.min_price > $4) AND
+      coalesce(route.loc_dep_place_id = $9,
+               to_tsvector('russian', route.loc_dep) @@ plainto_tsquery('russian', $2) OR
+               ` + geo.DistanceExpression("route.loc_dep_point", "$6::point") + ` <= $8) AND
+      coalesce(route.loc_arr_place_id = $10,
+               to_tsvector('russian', route.loc_arr) @@ plainto_tsquery('russian', $3) OR
+               ` + geo.DistanceExpression("route.loc_arr_point", "$7::point") + ` <= $8) AND
       route.min_price <= $4 AND
-      route_perm.day_of_week = extract(ISODOW FROM $2) AND
-      to_timestamp(to_char(route_perm.time_dep, 'HH24:mi'), 'HH24:mi') >= $5 AND
-      to_timestamp(to_char(route_perm.time_arr, 'HH24:mi'), 'HH24:mi') <= $5) AS "route_perm_"
+      route_perm.day_of_week = extract(ISODOW FROM $5) AND
+      route_perm.time_dep::time <= $5::time AND
+      route_perm.time_arr::time >= greatest(coalesce($11, $5), date_trunc('day', $5))::time) AS "route_perm_"
     ON route_perm_.user_author_id = user_.id)`
 
 	rows, err := notificationRepository.db.Query(query, ad.UserAuthorId, ad.LocDep, ad.LocArr, ad.MinPrice,
-		time.Time(ad.DateTimeArr))
+		time.Time(ad.DateTimeArr), ad.LocDepPoint, ad.LocArrPoint, routeSuitableRadiusM, ad.LocDepPlaceId,
+		ad.LocArrPlaceId, (*time.Time)(ad.DateTimeDep), ad.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -65,3 +90,618 @@ WHERE route.user_author_id != $1 AND
 
 	return &users, nil
 }
+
+func (notificationRepository *NotificationRepository) SelectSavedSearchArrayBySuitableAd(ad *models.Ad) (*models.SavedSearches, error) {
+	//one saved search per owner is enough to notify them once about the ad
+	const query = `
+SELECT DISTINCT ON (user_author_id) id, user_author_id, name, loc_dep, loc_arr, max_price, order_
+FROM saved_search
+WHERE user_author_id != $1 AND
+      NOT EXISTS (SELECT FROM ad WHERE ad.id = $5 AND ad.hidden) AND
+      NOT user_blocked(user_author_id, $1) AND
+      NOT EXISTS (SELECT FROM notification_settings
+                  WHERE notification_settings.user_id = saved_search.user_author_id AND
+                        notification_settings.min_price > $4) AND
+      (loc_dep IS NULL OR to_tsvector('russian', $2) @@ plainto_tsquery('russian', loc_dep)) AND
+      (loc_arr IS NULL OR to_tsvector('russian', $3) @@ plainto_tsquery('russian', loc_arr)) AND
+      (max_price IS NULL OR $4 <= max_price)
+ORDER BY user_author_id, id`
+
+	rows, err := notificationRepository.db.Query(query, ad.UserAuthorId, ad.LocDep, ad.LocArr, ad.MinPrice, ad.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	savedSearches := make(models.SavedSearches, 0)
+	for rows.Next() {
+		savedSearch := new(models.SavedSearch)
+		if err := rows.Scan(&savedSearch.Id, &savedSearch.UserAuthorId, &savedSearch.Name, &savedSearch.LocDep,
+			&savedSearch.LocArr, &savedSearch.MaxPrice, &savedSearch.Order); err != nil {
+			return nil, err
+		}
+
+		savedSearches = append(savedSearches, savedSearch)
+	}
+
+	return &savedSearches, nil
+}
+
+func (notificationRepository *NotificationRepository) InsertNotificationArray(notifications *models.Notifications) (*models.Notifications, error) {
+	//the ad notifications of the users, who chose the digests, are buffered instead of being sent one by one; the
+	//settings row is shared locked, so the digests cannot be turned off, and the buffer released, before the commit.
+	//An ad match notification, which is enqueued already, is skipped, so the retried ad does not notify twice
+	const query = `
+WITH notification_ AS (
+    INSERT INTO notification (user_id, type, payload)
+    VALUES ($1, $2, $3)
+    ON CONFLICT (user_id, ((payload->>'adId')::int)) WHERE type IN ('route_match', 'saved_search_match') DO NOTHING
+    RETURNING id, user_id, type, payload, read, date_time
+), notification_digest_ AS (
+    SELECT notification_.id, notification_.user_id, notification_.payload,
+           notification_.type IN ('route_match', 'saved_search_match') AND EXISTS (
+               SELECT FROM notification_settings
+               WHERE user_id = notification_.user_id AND date_time_digest_next IS NOT NULL
+               FOR SHARE) AS digest
+    FROM notification_
+), notification_outbox_ AS (
+    INSERT INTO notification_outbox (notification_id)
+    SELECT id FROM notification_digest_ WHERE NOT digest
+), notification_digest_item_ AS (
+    INSERT INTO notification_digest_item (notification_id, user_id, ad_id)
+    SELECT id, user_id, (payload->>'adId')::int FROM notification_digest_ WHERE digest
+)
+SELECT id, user_id, type, payload, read, date_time FROM notification_`
+
+	tx, err := notificationRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	insertedNotifications := make(models.Notifications, 0, len(*notifications))
+	for _, notification_ := range *notifications {
+		if err := tx.QueryRow(query, notification_.UserId, notification_.Type, notification_.Payload).Scan(
+			&notification_.Id, &notification_.UserId, &notification_.Type, &notification_.Payload,
+			&notification_.Read, &notification_.DateTime); err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+
+			return nil, err
+		}
+
+		insertedNotifications = append(insertedNotifications, notification_)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &insertedNotifications, nil
+}
+
+func (notificationRepository *NotificationRepository) SelectNotification(id uint32) (*models.Notification, error) {
+	const query = "SELECT id, user_id, type, payload, read, date_time FROM notification WHERE id = $1"
+
+	notification_ := new(models.Notification)
+	if err := notificationRepository.db.QueryRow(query, id).Scan(&notification_.Id, &notification_.UserId,
+		&notification_.Type, &notification_.Payload, &notification_.Read, &notification_.DateTime); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return notification_, nil
+}
+
+func (notificationRepository *NotificationRepository) SelectNotificationArrayByUserId(userId uint32, cursor *models.NotificationsCursor, limit uint32) (*models.Notifications, error) {
+	const query = `
+SELECT id, user_id, type, payload, read, date_time FROM notification
+WHERE user_id = $1 AND ($2::int IS NULL OR id < $2)
+ORDER BY id DESC
+LIMIT $3`
+
+	var cursorId interface{}
+	if cursor != nil {
+		cursorId = cursor.Id
+	}
+
+	rows, err := notificationRepository.db.Query(query, userId, cursorId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notifications := make(models.Notifications, 0)
+	for rows.Next() {
+		notification_ := new(models.Notification)
+		if err := rows.Scan(&notification_.Id, &notification_.UserId, &notification_.Type, &notification_.Payload,
+			&notification_.Read, &notification_.DateTime); err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification_)
+	}
+
+	return &notifications, nil
+}
+
+func (notificationRepository *NotificationRepository) UpdateNotificationRead(id uint32) (*models.Notification, error) {
+	const query = `
+UPDATE notification SET read = TRUE
+WHERE id = $1
+RETURNING id, user_id, type, payload, read, date_time`
+
+	notification_ := new(models.Notification)
+	if err := notificationRepository.db.QueryRow(query, id).Scan(&notification_.Id, &notification_.UserId,
+		&notification_.Type, &notification_.Payload, &notification_.Read, &notification_.DateTime); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return notification_, nil
+}
+
+func (notificationRepository *NotificationRepository) UpdateNotificationsReadByUserId(userId uint32) error {
+	const query = "UPDATE notification SET read = TRUE WHERE user_id = $1 AND NOT read"
+
+	_, err := notificationRepository.db.Exec(query, userId)
+	return err
+}
+
+func (notificationRepository *NotificationRepository) SelectNotificationsUnreadByUserId(userId uint32) (*models.NotificationsUnread, error) {
+	const query = "SELECT count(*) FROM notification WHERE user_id = $1 AND NOT read"
+
+	notificationsUnread := new(models.NotificationsUnread)
+	if err := notificationRepository.db.QueryRow(query, userId).Scan(&notificationsUnread.Count); err != nil {
+		return nil, err
+	}
+
+	return notificationsUnread, nil
+}
+
+func (notificationRepository *NotificationRepository) UpdateNotificationOutboxItemArrayLease(limit uint32, dateTime time.Time, dateTimeLeaseExpiry time.Time) (*models.NotificationOutboxItems, error) {
+	//the lease keeps other workers away; if the worker dies, the item is picked up again once the lease expires
+	const query = `
+WITH notification_outbox_ AS (
+    UPDATE notification_outbox SET attempts = attempts + 1, date_time_next_attempt = $3
+    WHERE id IN (SELECT id FROM notification_outbox
+                 WHERE status = 'pending' AND date_time_next_attempt <= $2
+                 ORDER BY date_time_next_attempt
+                 LIMIT $1
+                 FOR UPDATE SKIP LOCKED)
+    RETURNING id, notification_id, status, attempts, last_error, date_time_next_attempt
+)
+SELECT notification_outbox_.id, notification_outbox_.status, notification_outbox_.attempts,
+       notification_outbox_.last_error, notification_outbox_.date_time_next_attempt, notification.id,
+       notification.user_id, notification.type, notification.payload, notification.read, notification.date_time,
+       coalesce(notification_channel.channel, 'vk_bot'), notification_channel.address,
+       coalesce(notification_settings.timezone, 'Europe/Moscow'), notification_settings.quiet_hours_from,
+       notification_settings.quiet_hours_to, notification_settings.max_per_hour
+FROM notification_outbox_
+JOIN notification ON notification_outbox_.notification_id = notification.id
+LEFT JOIN notification_channel ON notification.user_id = notification_channel.user_id
+LEFT JOIN notification_settings ON notification.user_id = notification_settings.user_id
+ORDER BY notification_outbox_.id`
+
+	rows, err := notificationRepository.db.Query(query, limit, dateTime, dateTimeLeaseExpiry)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notificationOutboxItems := make(models.NotificationOutboxItems, 0)
+	for rows.Next() {
+		notificationOutboxItem := new(models.NotificationOutboxItem)
+		if err := rows.Scan(&notificationOutboxItem.Id, &notificationOutboxItem.Status,
+			&notificationOutboxItem.Attempts, &notificationOutboxItem.LastError,
+			&notificationOutboxItem.DateTimeNextAttempt, &notificationOutboxItem.Notification.Id,
+			&notificationOutboxItem.Notification.UserId, &notificationOutboxItem.Notification.Type,
+			&notificationOutboxItem.Notification.Payload, &notificationOutboxItem.Notification.Read,
+			&notificationOutboxItem.Notification.DateTime, &notificationOutboxItem.NotificationChannel.Channel,
+			&notificationOutboxItem.NotificationChannel.Address,
+			&notificationOutboxItem.NotificationSettings.Timezone,
+			&notificationOutboxItem.NotificationSettings.QuietHoursFrom,
+			&notificationOutboxItem.NotificationSettings.QuietHoursTo,
+			&notificationOutboxItem.NotificationSettings.MaxPerHour); err != nil {
+			return nil, err
+		}
+		notificationOutboxItem.NotificationChannel.UserId = notificationOutboxItem.Notification.UserId
+		notificationOutboxItem.NotificationSettings.UserId = notificationOutboxItem.Notification.UserId
+
+		notificationOutboxItems = append(notificationOutboxItems, notificationOutboxItem)
+	}
+
+	return &notificationOutboxItems, nil
+}
+
+func (notificationRepository *NotificationRepository) UpdateNotificationOutboxItem(notificationOutboxItem *models.NotificationOutboxItem) error {
+	const query = `
+UPDATE notification_outbox SET status = $2, attempts = $3, last_error = $4, date_time_next_attempt = $5,
+                               date_time_sent = $6
+WHERE id = $1`
+
+	_, err := notificationRepository.db.Exec(query, notificationOutboxItem.Id, notificationOutboxItem.Status,
+		notificationOutboxItem.Attempts, notificationOutboxItem.LastError,
+		time.Time(notificationOutboxItem.DateTimeNextAttempt), (*time.Time)(notificationOutboxItem.DateTimeSent))
+	return err
+}
+
+func (notificationRepository *NotificationRepository) SelectNotificationSettingsArrayByDigestDue(dateTime time.Time) (*models.NotificationSettingsArray, error) {
+	const query = `
+SELECT user_id, timezone, digest_interval, digest_times, date_time_digest_next
+FROM notification_settings
+WHERE date_time_digest_next <= $1
+ORDER BY date_time_digest_next`
+
+	rows, err := notificationRepository.db.Query(query, dateTime)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notificationSettingsArray := make(models.NotificationSettingsArray, 0)
+	for rows.Next() {
+		var digestTimes pq.Int64Array
+		notificationSettings := new(models.NotificationSettings)
+		if err := rows.Scan(&notificationSettings.UserId, &notificationSettings.Timezone,
+			&notificationSettings.DigestInterval, &digestTimes, &notificationSettings.DateTimeDigestNext); err != nil {
+			return nil, err
+		}
+		notificationSettings.DigestTimes = newDigestTimesFromArray(digestTimes)
+
+		notificationSettingsArray = append(notificationSettingsArray, notificationSettings)
+	}
+
+	return &notificationSettingsArray, nil
+}
+
+func (notificationRepository *NotificationRepository) InsertNotificationDigest(userId uint32, dateTime time.Time, dateTimeDigestNext time.Time) (*models.Notification, error) {
+	//the update claims the due digest, so it is sent once, even if several schedulers run
+	const queryNotificationSettingsUpdate = `
+UPDATE notification_settings SET date_time_digest_next = $3
+WHERE user_id = $1 AND date_time_digest_next <= $2
+RETURNING user_id`
+	//the ads, which were taken or hidden meanwhile, are dropped; the deleted ones are gone with the cascade
+	const queryNotificationDigestItemDelete = `
+WITH notification_digest_item_ AS (
+    DELETE FROM notification_digest_item WHERE user_id = $1
+    RETURNING ad_id
+)
+SELECT array_agg(DISTINCT ad.id)
+FROM notification_digest_item_
+JOIN ad ON notification_digest_item_.ad_id = ad.id
+WHERE ad.status = 'open' AND NOT ad.hidden`
+	const queryNotificationInsert = `
+WITH notification_ AS (
+    INSERT INTO notification (user_id, type, payload)
+    VALUES ($1, $2, $3)
+    RETURNING id, user_id, type, payload, read, date_time
+), notification_outbox_ AS (
+    INSERT INTO notification_outbox (notification_id)
+    SELECT id FROM notification_
+)
+SELECT id, user_id, type, payload, read, date_time FROM notification_`
+
+	tx, err := notificationRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := tx.QueryRow(queryNotificationSettingsUpdate, userId, dateTime, dateTimeDigestNext).Scan(
+		&userId); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	var adIds pq.Int64Array
+	if err := tx.QueryRow(queryNotificationDigestItemDelete, userId).Scan(&adIds); err != nil {
+		return nil, err
+	}
+
+	if len(adIds) == 0 {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+
+		return nil, consts.RepErrNotFound
+	}
+
+	notification_ := &models.Notification{
+		UserId: userId,
+		Type:   models.NotificationTypeDigest,
+		Payload: models.NotificationPayload{
+			AdIds: make([]uint32, len(adIds)),
+		},
+	}
+	for i, adId := range adIds {
+		notification_.Payload.AdIds[i] = uint32(adId)
+	}
+
+	if err := tx.QueryRow(queryNotificationInsert, notification_.UserId, notification_.Type,
+		notification_.Payload).Scan(&notification_.Id, &notification_.UserId, &notification_.Type,
+		&notification_.Payload, &notification_.Read, &notification_.DateTime); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return notification_, nil
+}
+
+func (notificationRepository *NotificationRepository) SelectNotificationChannelByUserId(userId uint32) (*models.NotificationChannel, error) {
+	const query = "SELECT user_id, channel, address FROM notification_channel WHERE user_id = $1"
+
+	notificationChannel := new(models.NotificationChannel)
+	if err := notificationRepository.db.QueryRow(query, userId).Scan(&notificationChannel.UserId,
+		&notificationChannel.Channel, &notificationChannel.Address); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (notificationRepository *NotificationRepository) InsertOrUpdateNotificationChannel(notificationChannel *models.NotificationChannel) (*models.NotificationChannel, error) {
+	const query = `
+INSERT INTO notification_channel (user_id, channel, address)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET channel = excluded.channel, address = excluded.address
+RETURNING user_id, channel, address`
+
+	if err := notificationRepository.db.QueryRow(query, notificationChannel.UserId, notificationChannel.Channel,
+		notificationChannel.Address).Scan(&notificationChannel.UserId, &notificationChannel.Channel,
+		&notificationChannel.Address); err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (notificationRepository *NotificationRepository) UpdateNotificationOutboxItemSentReserve(notificationOutboxItem *models.NotificationOutboxItem, maxPerHour uint32, dateTimeFrom time.Time, dateTime time.Time) (*models.NotificationsSent, error) {
+	//the lock serializes the deliveries of the user, so the concurrent workers do not exceed the cap together
+	const queryNotificationSettings = `
+SELECT user_id
+FROM notification_settings
+WHERE user_id = $1
+FOR UPDATE`
+	//the items being delivered hold date_time_sent while pending, so they count as sent; the item itself may hold it,
+	//if the worker stopped during the delivery
+	const queryCount = `
+SELECT count(*), min(notification_outbox.date_time_sent)
+FROM notification_outbox
+JOIN notification ON notification_outbox.notification_id = notification.id
+WHERE notification.user_id = $1 AND notification_outbox.status IN ('pending', 'sent') AND
+      notification_outbox.date_time_sent > $2 AND notification_outbox.id <> $3`
+	const queryUpdate = `
+UPDATE notification_outbox SET date_time_sent = $2
+WHERE id = $1`
+
+	tx, err := notificationRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var userId uint32
+	if err := tx.QueryRow(queryNotificationSettings, notificationOutboxItem.Notification.UserId).Scan(
+		&userId); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	notificationsSent := new(models.NotificationsSent)
+	if err := tx.QueryRow(queryCount, notificationOutboxItem.Notification.UserId, dateTimeFrom,
+		notificationOutboxItem.Id).Scan(&notificationsSent.Count, &notificationsSent.DateTimeFirst); err != nil {
+		return nil, err
+	}
+
+	if notificationsSent.Count >= maxPerHour {
+		return notificationsSent, nil
+	}
+
+	if _, err := tx.Exec(queryUpdate, notificationOutboxItem.Id, dateTime); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return notificationsSent, nil
+}
+
+func (notificationRepository *NotificationRepository) SelectNotificationSettingsByUserId(userId uint32) (*models.NotificationSettings, error) {
+	return selectNotificationSettingsByUserId(notificationRepository.db.QueryRow, userId)
+}
+
+func (notificationRepository *NotificationRepository) UpdateNotificationSettings(notificationSettings *models.NotificationSettings) (*models.NotificationSettings, error) {
+	//the due digest is kept, unless the digest schedule changes
+	const query = `
+INSERT INTO notification_settings (user_id, timezone, quiet_hours_from, quiet_hours_to, max_per_hour, min_price,
+                                   digest_interval, digest_times, date_time_digest_next)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (user_id) DO UPDATE SET timezone = excluded.timezone, quiet_hours_from = excluded.quiet_hours_from,
+                                    quiet_hours_to = excluded.quiet_hours_to, max_per_hour = excluded.max_per_hour,
+                                    min_price = excluded.min_price, digest_interval = excluded.digest_interval,
+                                    digest_times = excluded.digest_times,
+                                    date_time_digest_next = CASE
+                                        WHEN notification_settings.date_time_digest_next IS NOT NULL AND
+                                             excluded.date_time_digest_next IS NOT NULL AND
+                                             notification_settings.timezone = excluded.timezone AND
+                                             notification_settings.digest_interval IS NOT DISTINCT FROM
+                                             excluded.digest_interval AND
+                                             notification_settings.digest_times = excluded.digest_times
+                                            THEN notification_settings.date_time_digest_next
+                                        ELSE excluded.date_time_digest_next END`
+	const queryNotificationDigestItemRelease = `
+WITH notification_digest_item_ AS (
+    DELETE FROM notification_digest_item WHERE user_id = $1
+    RETURNING notification_id, ad_id
+)
+INSERT INTO notification_outbox (notification_id)
+SELECT notification_digest_item_.notification_id
+FROM notification_digest_item_
+JOIN ad ON notification_digest_item_.ad_id = ad.id
+WHERE ad.status = 'open' AND NOT ad.hidden`
+	const queryRouteMuteCount = "SELECT count(*) FROM route WHERE user_author_id = $1 AND id = ANY($2)"
+	const queryRouteMuteDelete = `
+DELETE FROM route_mute
+USING route
+WHERE route_mute.route_id = route.id AND route.user_author_id = $1 AND route.id != ALL($2)`
+	const queryRouteMuteInsert = `
+INSERT INTO route_mute (route_id)
+SELECT unnest($1::int[])
+ON CONFLICT DO NOTHING`
+
+	mutedRouteIds := make(pq.Int64Array, len(notificationSettings.MutedRouteIds))
+	for i, mutedRouteId := range notificationSettings.MutedRouteIds {
+		mutedRouteIds[i] = int64(mutedRouteId)
+	}
+
+	tx, err := notificationRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var routeMuteCount int
+	if err := tx.QueryRow(queryRouteMuteCount, notificationSettings.UserId, mutedRouteIds).Scan(
+		&routeMuteCount); err != nil {
+		return nil, err
+	}
+	if routeMuteCount != len(notificationSettings.MutedRouteIds) {
+		return nil, consts.RepErrNotFound
+	}
+
+	var quietHoursFrom, quietHoursTo *time.Time
+	if notificationSettings.QuietHoursFrom != nil && notificationSettings.QuietHoursTo != nil {
+		quietHoursFrom = (*time.Time)(notificationSettings.QuietHoursFrom)
+		quietHoursTo = (*time.Time)(notificationSettings.QuietHoursTo)
+	}
+
+	if _, err := tx.Exec(query, notificationSettings.UserId, notificationSettings.Timezone, quietHoursFrom,
+		quietHoursTo, notificationSettings.MaxPerHour, notificationSettings.MinPrice,
+		notificationSettings.DigestInterval, newDigestTimes(notificationSettings.DigestTimes),
+		notificationSettings.DateTimeDigestNext); err != nil {
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	//once the digests are off, the buffered notifications are sent one by one
+	if notificationSettings.DateTimeDigestNext == nil {
+		if _, err := tx.Exec(queryNotificationDigestItemRelease, notificationSettings.UserId); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(queryRouteMuteDelete, notificationSettings.UserId, mutedRouteIds); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(queryRouteMuteInsert, mutedRouteIds); err != nil {
+		return nil, err
+	}
+
+	notificationSettings, err = selectNotificationSettingsByUserId(tx.QueryRow, notificationSettings.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return notificationSettings, nil
+}
+
+func selectNotificationSettingsByUserId(queryRow func(query string, args ...interface{}) *sql.Row,
+	userId uint32) (*models.NotificationSettings, error) {
+	const query = `
+SELECT user_.id, coalesce(notification_settings.timezone, 'Europe/Moscow'), notification_settings.quiet_hours_from,
+       notification_settings.quiet_hours_to, notification_settings.max_per_hour, notification_settings.min_price,
+       array(SELECT route_mute.route_id FROM route_mute
+             JOIN route ON route_mute.route_id = route.id
+             WHERE route.user_author_id = user_.id
+             ORDER BY route_mute.route_id),
+       notification_settings.digest_interval, coalesce(notification_settings.digest_times, '{}'),
+       notification_settings.date_time_digest_next
+FROM user_
+LEFT JOIN notification_settings ON user_.id = notification_settings.user_id
+WHERE user_.id = $1`
+
+	var mutedRouteIds, digestTimes pq.Int64Array
+	notificationSettings := new(models.NotificationSettings)
+	if err := queryRow(query, userId).Scan(&notificationSettings.UserId, &notificationSettings.Timezone,
+		&notificationSettings.QuietHoursFrom, &notificationSettings.QuietHoursTo, &notificationSettings.MaxPerHour,
+		&notificationSettings.MinPrice, &mutedRouteIds, &notificationSettings.DigestInterval, &digestTimes,
+		&notificationSettings.DateTimeDigestNext); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	notificationSettings.MutedRouteIds = make([]uint32, len(mutedRouteIds))
+	for i, mutedRouteId := range mutedRouteIds {
+		notificationSettings.MutedRouteIds[i] = uint32(mutedRouteId)
+	}
+	notificationSettings.DigestTimes = newDigestTimesFromArray(digestTimes)
+
+	return notificationSettings, nil
+}
+
+// newDigestTimes stores the digest times as the minutes since midnight, since pq does not scan the timestamp arrays
+func newDigestTimes(digestTimes []timestamps.Time) pq.Int64Array {
+	digestTimes_ := make(pq.Int64Array, len(digestTimes))
+	for i, digestTime := range digestTimes {
+		digestTime_ := time.Time(digestTime)
+		digestTimes_[i] = int64(digestTime_.Hour()*60 + digestTime_.Minute())
+	}
+
+	return digestTimes_
+}
+
+func newDigestTimesFromArray(digestTimes pq.Int64Array) []timestamps.Time {
+	digestTimes_ := make([]timestamps.Time, len(digestTimes))
+	for i, digestTime := range digestTimes {
+		digestTimes_[i] = timestamps.Time(time.Date(0, time.January, 1, int(digestTime/60), int(digestTime%60), 0, 0,
+			time.UTC))
+	}
+
+	return digestTimes_
+}
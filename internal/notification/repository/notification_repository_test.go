@@ -1 +1,608 @@
 package repository_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/notification/repository"
+	"github.com/lib/pq"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNotificationRepository_InsertNotificationArray(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	notifications := &models.Notifications{
+		&models.Notification{
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		&models.Notification{
+			UserId: 102,
+			Type:   models.NotificationTypeSavedSearchMatch,
+			Payload: models.NotificationPayload{
+				AdId:          1,
+				SavedSearchId: pointy.Uint32(3),
+			},
+		},
+	}
+	expectedNotifications := &models.Notifications{
+		&models.Notification{
+			Id:       1,
+			UserId:   (*notifications)[0].UserId,
+			Type:     (*notifications)[0].Type,
+			Payload:  (*notifications)[0].Payload,
+			DateTime: *dateTime,
+		},
+		&models.Notification{
+			Id:       2,
+			UserId:   (*notifications)[1].UserId,
+			Type:     (*notifications)[1].Type,
+			Payload:  (*notifications)[1].Payload,
+			DateTime: *dateTime,
+		},
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("INSERT INTO notification (.+) FOR SHARE(.+) INSERT INTO notification_outbox (.+) "+
+			"SELECT (.+) FROM notification_").
+		WithArgs((*notifications)[0].UserId, string((*notifications)[0].Type), `{"adId":1}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "payload", "read", "date_time"}).
+			AddRow(1, 101, "route_match", []byte(`{"adId":1}`), false, time.Time(*dateTime)))
+	sqlmock_.
+		ExpectQuery("INSERT INTO notification (.+) FOR SHARE(.+) INSERT INTO notification_outbox (.+) "+
+			"SELECT (.+) FROM notification_").
+		WithArgs((*notifications)[1].UserId, string((*notifications)[1].Type), `{"adId":1,"savedSearchId":3}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "payload", "read", "date_time"}).
+			AddRow(2, 102, "saved_search_match", []byte(`{"adId":1,"savedSearchId":3}`), false,
+				time.Time(*dateTime)))
+	sqlmock_.ExpectCommit()
+
+	resultNotifications, resultErr := notificationRepository.InsertNotificationArray(notifications)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotifications, resultNotifications)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_InsertNotificationArray_enqueued(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	notifications := &models.Notifications{
+		&models.Notification{
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("INSERT INTO notification (.+) ON CONFLICT (.+) DO NOTHING (.+) FOR SHARE").
+		WithArgs((*notifications)[0].UserId, string((*notifications)[0].Type), `{"adId":1}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "payload", "read", "date_time"}))
+	sqlmock_.ExpectCommit()
+
+	resultNotifications, resultErr := notificationRepository.InsertNotificationArray(notifications)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, &models.Notifications{}, resultNotifications)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_SelectNotificationArrayByUserId(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	const userId uint32 = 101
+	cursor := &models.NotificationsCursor{Id: 5}
+	const limit uint32 = 2
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	expectedNotifications := &models.Notifications{
+		&models.Notification{
+			Id:       4,
+			UserId:   userId,
+			Type:     models.NotificationTypeAdExpired,
+			Payload:  models.NotificationPayload{AdId: 2},
+			DateTime: *dateTime,
+		},
+		&models.Notification{
+			Id:       3,
+			UserId:   userId,
+			Type:     models.NotificationTypeRouteMatch,
+			Payload:  models.NotificationPayload{AdId: 1},
+			Read:     true,
+			DateTime: *dateTime,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "type", "payload", "read", "date_time"})
+	for _, notification_ := range *expectedNotifications {
+		payload, err := notification_.Payload.Value()
+		assert.Nil(t, err)
+		rows.AddRow(notification_.Id, notification_.UserId, string(notification_.Type), []byte(payload.(string)),
+			notification_.Read, time.Time(notification_.DateTime))
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM notification WHERE user_id = \\$1 AND (.+) ORDER BY id DESC LIMIT \\$3").
+		WithArgs(userId, cursor.Id, limit).
+		WillReturnRows(rows)
+
+	resultNotifications, resultErr := notificationRepository.SelectNotificationArrayByUserId(userId, cursor, limit)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotifications, resultNotifications)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_UpdateNotificationRead_notFound(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	const id uint32 = 1
+
+	sqlmock_.
+		ExpectQuery("UPDATE notification SET read = TRUE WHERE id = \\$1 RETURNING (.+)").
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	resultNotification, resultErr := notificationRepository.UpdateNotificationRead(id)
+	assert.Nil(t, resultNotification)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_UpdateNotificationOutboxItemArrayLease(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	const limit uint32 = 4
+	dateTime := time.Date(2021, 12, 5, 19, 50, 0, 0, time.UTC)
+	dateTimeLeaseExpiry := dateTime.Add(time.Minute)
+	expectedNotificationOutboxItems := &models.NotificationOutboxItems{
+		&models.NotificationOutboxItem{
+			Id: 2,
+			Notification: models.Notification{
+				Id:       3,
+				UserId:   101,
+				Type:     models.NotificationTypeRouteMatch,
+				Payload:  models.NotificationPayload{AdId: 1},
+				DateTime: timestamps.DateTime(dateTime),
+			},
+			NotificationChannel: models.NotificationChannel{
+				UserId:  101,
+				Channel: models.NotificationChannelKindEmail,
+				Address: pointy.String("courier@example.com"),
+			},
+			NotificationSettings: models.NotificationSettings{
+				UserId:     101,
+				Timezone:   "Europe/Moscow",
+				MaxPerHour: pointy.Uint32(5),
+			},
+			Status:              models.NotificationOutboxStatusPending,
+			Attempts:            2,
+			LastError:           pointy.String("cannot access vk bot: response code = 502"),
+			DateTimeNextAttempt: timestamps.DateTime(dateTimeLeaseExpiry),
+		},
+	}
+
+	sqlmock_.
+		ExpectQuery("UPDATE notification_outbox SET attempts = attempts \\+ 1, date_time_next_attempt = \\$3 "+
+			"WHERE id IN \\(SELECT id FROM notification_outbox WHERE status = 'pending' AND "+
+			"date_time_next_attempt <= \\$2 (.+) LIMIT \\$1 FOR UPDATE SKIP LOCKED\\) (.+) "+
+			"LEFT JOIN notification_channel ON notification.user_id = notification_channel.user_id "+
+			"LEFT JOIN notification_settings ON notification.user_id = notification_settings.user_id").
+		WithArgs(limit, dateTime, dateTimeLeaseExpiry).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "attempts", "last_error", "date_time_next_attempt",
+			"id", "user_id", "type", "payload", "read", "date_time", "channel", "address", "timezone", "quiet_hours_from", "quiet_hours_to", "max_per_hour"}).
+			AddRow(2, "pending", 2, "cannot access vk bot: response code = 502", dateTimeLeaseExpiry, 3, 101,
+				"route_match", []byte(`{"adId":1}`), false, dateTime, "email", "courier@example.com", "Europe/Moscow", nil, nil, 5))
+
+	resultNotificationOutboxItems, resultErr := notificationRepository.UpdateNotificationOutboxItemArrayLease(limit,
+		dateTime, dateTimeLeaseExpiry)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotificationOutboxItems, resultNotificationOutboxItems)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_UpdateNotificationOutboxItem(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	dateTimeNextAttempt := time.Date(2021, 12, 5, 19, 51, 0, 0, time.UTC)
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id:                  2,
+		Status:              models.NotificationOutboxStatusDead,
+		Attempts:            8,
+		LastError:           pointy.String("cannot access vk bot: response code = 502"),
+		DateTimeNextAttempt: timestamps.DateTime(dateTimeNextAttempt),
+	}
+
+	sqlmock_.
+		ExpectExec("UPDATE notification_outbox SET status = \\$2, attempts = \\$3, last_error = \\$4, "+
+			"date_time_next_attempt = \\$5, date_time_sent = \\$6 WHERE id = \\$1").
+		WithArgs(notificationOutboxItem.Id, string(notificationOutboxItem.Status), notificationOutboxItem.Attempts,
+			*notificationOutboxItem.LastError, dateTimeNextAttempt, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	resultErr := notificationRepository.UpdateNotificationOutboxItem(notificationOutboxItem)
+	assert.Nil(t, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_UpdateNotificationOutboxItemSentReserve(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	dateTime := time.Date(2021, 12, 5, 19, 51, 0, 0, time.UTC)
+	dateTimeFrom := dateTime.Add(-time.Hour)
+	dateTimeFirst := dateTime.Add(-20 * time.Minute)
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id:           2,
+		Notification: models.Notification{Id: 3, UserId: 101},
+		Status:       models.NotificationOutboxStatusPending,
+		Attempts:     1,
+	}
+	expectedNotificationsSent := &models.NotificationsSent{
+		Count:         1,
+		DateTimeFirst: &dateTimeFirst,
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT user_id FROM notification_settings WHERE user_id = \\$1 FOR UPDATE").
+		WithArgs(notificationOutboxItem.Notification.UserId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(notificationOutboxItem.Notification.UserId))
+	sqlmock_.
+		ExpectQuery("SELECT count\\(\\*\\), min\\(notification_outbox.date_time_sent\\) FROM notification_outbox").
+		WithArgs(notificationOutboxItem.Notification.UserId, dateTimeFrom, notificationOutboxItem.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "min"}).AddRow(expectedNotificationsSent.Count,
+			dateTimeFirst))
+	sqlmock_.
+		ExpectExec("UPDATE notification_outbox SET date_time_sent = \\$2 WHERE id = \\$1").
+		WithArgs(notificationOutboxItem.Id, dateTime).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock_.ExpectCommit()
+
+	resultNotificationsSent, resultErr := notificationRepository.UpdateNotificationOutboxItemSentReserve(
+		notificationOutboxItem, 2, dateTimeFrom, dateTime)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotificationsSent, resultNotificationsSent)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_UpdateNotificationOutboxItemSentReserve_full(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	dateTime := time.Date(2021, 12, 5, 19, 51, 0, 0, time.UTC)
+	dateTimeFrom := dateTime.Add(-time.Hour)
+	dateTimeFirst := dateTime.Add(-20 * time.Minute)
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id:           2,
+		Notification: models.Notification{Id: 3, UserId: 101},
+		Status:       models.NotificationOutboxStatusPending,
+		Attempts:     1,
+	}
+	expectedNotificationsSent := &models.NotificationsSent{
+		Count:         2,
+		DateTimeFirst: &dateTimeFirst,
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT user_id FROM notification_settings WHERE user_id = \\$1 FOR UPDATE").
+		WithArgs(notificationOutboxItem.Notification.UserId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(notificationOutboxItem.Notification.UserId))
+	sqlmock_.
+		ExpectQuery("SELECT count\\(\\*\\), min\\(notification_outbox.date_time_sent\\) FROM notification_outbox").
+		WithArgs(notificationOutboxItem.Notification.UserId, dateTimeFrom, notificationOutboxItem.Id).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "min"}).AddRow(expectedNotificationsSent.Count,
+			dateTimeFirst))
+	sqlmock_.ExpectRollback()
+
+	resultNotificationsSent, resultErr := notificationRepository.UpdateNotificationOutboxItemSentReserve(
+		notificationOutboxItem, 2, dateTimeFrom, dateTime)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotificationsSent, resultNotificationsSent)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_InsertOrUpdateNotificationChannel(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	notificationChannel := &models.NotificationChannel{
+		UserId:  101,
+		Channel: models.NotificationChannelKindWebhook,
+		Address: pointy.String("https://example.com/handover"),
+	}
+	expectedNotificationChannel := &models.NotificationChannel{
+		UserId:  notificationChannel.UserId,
+		Channel: notificationChannel.Channel,
+		Address: notificationChannel.Address,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO notification_channel (.+) VALUES (.+) ON CONFLICT \\(user_id\\) DO UPDATE SET (.+) "+
+			"RETURNING user_id, channel, address").
+		WithArgs(notificationChannel.UserId, string(notificationChannel.Channel), *notificationChannel.Address).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "channel", "address"}).
+			AddRow(expectedNotificationChannel.UserId, string(expectedNotificationChannel.Channel),
+				*expectedNotificationChannel.Address))
+
+	resultNotificationChannel, resultErr := notificationRepository.InsertOrUpdateNotificationChannel(
+		notificationChannel)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotificationChannel, resultNotificationChannel)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_UpdateNotificationSettings(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	quietHoursFrom, err := timestamps.NewTime("23:00")
+	assert.Nil(t, err)
+	quietHoursTo, err := timestamps.NewTime("07:00")
+	assert.Nil(t, err)
+	notificationSettings := &models.NotificationSettings{
+		UserId:         101,
+		Timezone:       "Europe/Moscow",
+		QuietHoursFrom: quietHoursFrom,
+		QuietHoursTo:   quietHoursTo,
+		MaxPerHour:     pointy.Uint32(5),
+		MutedRouteIds:  []uint32{3, 4},
+	}
+	expectedNotificationSettings := &models.NotificationSettings{
+		UserId:         notificationSettings.UserId,
+		Timezone:       notificationSettings.Timezone,
+		QuietHoursFrom: notificationSettings.QuietHoursFrom,
+		QuietHoursTo:   notificationSettings.QuietHoursTo,
+		MaxPerHour:     notificationSettings.MaxPerHour,
+		MutedRouteIds:  notificationSettings.MutedRouteIds,
+		DigestTimes:    []timestamps.Time{},
+	}
+	mutedRouteIds := pq.Int64Array{3, 4}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT count\\(\\*\\) FROM route WHERE user_author_id = \\$1 AND id = ANY\\(\\$2\\)").
+		WithArgs(notificationSettings.UserId, mutedRouteIds).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	sqlmock_.
+		ExpectExec("INSERT INTO notification_settings (.+) VALUES (.+) ON CONFLICT \\(user_id\\) DO UPDATE SET (.+)").
+		WithArgs(notificationSettings.UserId, notificationSettings.Timezone, time.Time(*quietHoursFrom),
+			time.Time(*quietHoursTo), *notificationSettings.MaxPerHour, nil, nil, pq.Int64Array{}, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock_.
+		ExpectExec("DELETE FROM notification_digest_item WHERE user_id = \\$1 (.+) INSERT INTO notification_outbox (.+)").
+		WithArgs(notificationSettings.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlmock_.
+		ExpectExec("DELETE FROM route_mute USING route WHERE (.+) route.id != ALL\\(\\$2\\)").
+		WithArgs(notificationSettings.UserId, mutedRouteIds).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock_.
+		ExpectExec("INSERT INTO route_mute \\(route_id\\) SELECT unnest\\(\\$1::int\\[\\]\\) ON CONFLICT DO NOTHING").
+		WithArgs(mutedRouteIds).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlmock_.
+		ExpectQuery("SELECT (.+) FROM user_ LEFT JOIN notification_settings (.+) WHERE user_.id = \\$1").
+		WithArgs(notificationSettings.UserId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "timezone", "quiet_hours_from", "quiet_hours_to",
+			"max_per_hour", "min_price", "array", "digest_interval", "digest_times", "date_time_digest_next"}).
+			AddRow(101, "Europe/Moscow", time.Time(*quietHoursFrom), time.Time(*quietHoursTo), 5, nil, "{3,4}", nil,
+				"{}", nil))
+	sqlmock_.ExpectCommit()
+
+	resultNotificationSettings, resultErr := notificationRepository.UpdateNotificationSettings(notificationSettings)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotificationSettings, resultNotificationSettings)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_UpdateNotificationSettings_routeNotFound(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	notificationSettings := &models.NotificationSettings{
+		UserId:        101,
+		Timezone:      "Europe/Moscow",
+		MutedRouteIds: []uint32{3, 4},
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("SELECT count\\(\\*\\) FROM route WHERE user_author_id = \\$1 AND id = ANY\\(\\$2\\)").
+		WithArgs(notificationSettings.UserId, pq.Int64Array{3, 4}).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	sqlmock_.ExpectRollback()
+
+	resultNotificationSettings, resultErr := notificationRepository.UpdateNotificationSettings(notificationSettings)
+	assert.Nil(t, resultNotificationSettings)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_InsertNotificationDigest(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	dateTime := time.Date(2021, 12, 5, 19, 50, 0, 0, time.UTC)
+	dateTimeDigestNext := dateTime.Add(time.Hour)
+	const userId uint32 = 101
+	expectedNotification := &models.Notification{
+		Id:       5,
+		UserId:   userId,
+		Type:     models.NotificationTypeDigest,
+		Payload:  models.NotificationPayload{AdIds: []uint32{1, 3}},
+		DateTime: timestamps.DateTime(dateTime),
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("UPDATE notification_settings SET date_time_digest_next = \\$3 WHERE user_id = \\$1 AND "+
+			"date_time_digest_next <= \\$2 RETURNING user_id").
+		WithArgs(userId, dateTime, dateTimeDigestNext).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userId))
+	sqlmock_.
+		ExpectQuery("DELETE FROM notification_digest_item WHERE user_id = \\$1 (.+) SELECT array_agg\\(DISTINCT ad.id\\) " +
+			"(.+) WHERE ad.status = 'open' AND NOT ad.hidden").
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"array_agg"}).AddRow("{1,3}"))
+	sqlmock_.
+		ExpectQuery("INSERT INTO notification (.+) INSERT INTO notification_outbox (.+) SELECT (.+) FROM notification_").
+		WithArgs(userId, string(models.NotificationTypeDigest), `{"adIds":[1,3]}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "payload", "read", "date_time"}).
+			AddRow(5, userId, "digest", []byte(`{"adIds":[1,3]}`), false, dateTime))
+	sqlmock_.ExpectCommit()
+
+	resultNotification, resultErr := notificationRepository.InsertNotificationDigest(userId, dateTime,
+		dateTimeDigestNext)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedNotification, resultNotification)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_InsertNotificationDigest_empty(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	dateTime := time.Date(2021, 12, 5, 19, 50, 0, 0, time.UTC)
+	dateTimeDigestNext := dateTime.Add(time.Hour)
+	const userId uint32 = 101
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("UPDATE notification_settings SET date_time_digest_next = \\$3 (.+) RETURNING user_id").
+		WithArgs(userId, dateTime, dateTimeDigestNext).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userId))
+	sqlmock_.
+		ExpectQuery("DELETE FROM notification_digest_item WHERE user_id = \\$1 (.+) SELECT array_agg\\(DISTINCT ad.id\\)").
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"array_agg"}).AddRow(nil))
+	sqlmock_.ExpectCommit()
+
+	resultNotification, resultErr := notificationRepository.InsertNotificationDigest(userId, dateTime,
+		dateTimeDigestNext)
+	assert.Nil(t, resultNotification)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_InsertNotificationDigest_notDue(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	notificationRepository := repository.NewNotificationRepositoryImpl(db)
+
+	dateTime := time.Date(2021, 12, 5, 19, 50, 0, 0, time.UTC)
+	dateTimeDigestNext := dateTime.Add(time.Hour)
+	const userId uint32 = 101
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("UPDATE notification_settings SET date_time_digest_next = \\$3 (.+) RETURNING user_id").
+		WithArgs(userId, dateTime, dateTimeDigestNext).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	sqlmock_.ExpectRollback()
+
+	resultNotification, resultErr := notificationRepository.InsertNotificationDigest(userId, dateTime,
+		dateTimeDigestNext)
+	assert.Nil(t, resultNotification)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
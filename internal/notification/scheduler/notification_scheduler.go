@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"github.com/TechnoHandOver/backend/internal/notification"
+	"log"
+	"time"
+)
+
+type NotificationScheduler struct {
+	notificationUsecase notification.Usecase
+	digestSweepInterval time.Duration
+	stop                chan struct{}
+}
+
+func NewNotificationScheduler(notificationUsecase notification.Usecase,
+	digestSweepInterval time.Duration) *NotificationScheduler {
+	return &NotificationScheduler{
+		notificationUsecase: notificationUsecase,
+		digestSweepInterval: digestSweepInterval,
+		stop:                make(chan struct{}),
+	}
+}
+
+func (notificationScheduler *NotificationScheduler) Start() {
+	go notificationScheduler.run()
+}
+
+func (notificationScheduler *NotificationScheduler) Stop() {
+	close(notificationScheduler.stop)
+}
+
+func (notificationScheduler *NotificationScheduler) run() {
+	ticker := time.NewTicker(notificationScheduler.digestSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		notificationScheduler.sendDigests()
+
+		select {
+		case <-ticker.C:
+		case <-notificationScheduler.stop:
+			return
+		}
+	}
+}
+
+func (notificationScheduler *NotificationScheduler) sendDigests() {
+	if response_ := notificationScheduler.notificationUsecase.SendNotificationDigests(); response_.Error != nil {
+		log.Println(response_.Error)
+	}
+}
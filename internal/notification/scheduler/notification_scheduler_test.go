@@ -0,0 +1,38 @@
+package scheduler_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notification/mock_notification"
+	"github.com/TechnoHandOver/backend/internal/notification/scheduler"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/golang/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestNotificationScheduler_Start(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	notificationScheduler := scheduler.NewNotificationScheduler(mockNotificationUsecase, time.Millisecond)
+
+	sent := make(chan struct{}, 2)
+	mockNotificationUsecase.
+		EXPECT().
+		SendNotificationDigests().
+		DoAndReturn(func() *response.Response {
+			select {
+			case sent <- struct{}{}:
+			default:
+			}
+			return response.NewResponse(consts.OK, &models.Notifications{})
+		}).
+		MinTimes(2)
+
+	notificationScheduler.Start()
+	<-sent
+	<-sent
+	notificationScheduler.Stop()
+}
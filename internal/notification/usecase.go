@@ -6,5 +6,17 @@ import (
 )
 
 type Usecase interface {
-	NotifySuitableUsers(ad *models.Ad) *response.Response
+	NotifyAdMatches(ad *models.Ad) *response.Response
+	NotifyAdExpired(ad *models.Ad) *response.Response
+	ListNotifications(userId uint32, cursor *models.NotificationsCursor, limit *uint32) *response.Response
+	ReadNotification(userId uint32, id uint32) *response.Response
+	ReadAllNotifications(userId uint32) *response.Response
+	GetNotificationsUnread(userId uint32) *response.Response
+	LeaseNotificationOutbox(limit uint32) *response.Response
+	DeliverNotificationOutboxItem(notificationOutboxItem *models.NotificationOutboxItem) *response.Response
+	GetNotificationChannel(userId uint32) *response.Response
+	UpdateNotificationChannel(notificationChannel *models.NotificationChannel) *response.Response
+	GetNotificationSettings(userId uint32) *response.Response
+	UpdateNotificationSettings(notificationSettings *models.NotificationSettings) *response.Response
+	SendNotificationDigests() *response.Response
 }
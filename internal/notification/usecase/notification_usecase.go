@@ -4,47 +4,368 @@ import (
 	"fmt"
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
 	"github.com/TechnoHandOver/backend/internal/notification"
+	"github.com/TechnoHandOver/backend/internal/notifier"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
 	"log"
-	"net/http"
 	"time"
 )
 
+const (
+	notificationsDefaultLimit          uint32 = 20
+	notificationOutboxLeaseDuration           = time.Minute
+	notificationOutboxMaxAttempts      uint32 = 8
+	notificationOutboxBackoffBase             = 30 * time.Second
+	notificationOutboxBackoffMax              = 6 * time.Hour
+	notificationOutboxLastErrorMaxSize        = 500
+)
+
 type NotificationUsecase struct {
 	notificationRepository notification.Repository
+	notifiers              map[models.NotificationChannelKind]notifier.Notifier
+	fallbackNotifier       notifier.Notifier
 }
 
-func NewNotificationUsecaseImpl(notificationRepository notification.Repository) notification.Usecase {
+func NewNotificationUsecaseImpl(notificationRepository notification.Repository,
+	notifiers map[models.NotificationChannelKind]notifier.Notifier,
+	fallbackNotifier notifier.Notifier) notification.Usecase {
 	return &NotificationUsecase{
 		notificationRepository: notificationRepository,
+		notifiers:              notifiers,
+		fallbackNotifier:       fallbackNotifier,
 	}
 }
 
-func (notificationUsecase *NotificationUsecase) NotifySuitableUsers(ad *models.Ad) *response.Response {
+// NotifyAdMatches notifies every user, whose route or saved search suits the ad, only once; the route match wins
+func (notificationUsecase *NotificationUsecase) NotifyAdMatches(ad *models.Ad) *response.Response {
 	users, err := notificationUsecase.notificationRepository.SelectUsersByRoutesWithSuitableTimeInterval(ad)
 	if err != nil {
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
+	savedSearches, err := notificationUsecase.notificationRepository.SelectSavedSearchArrayBySuitableAd(ad)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
 
-	client := &http.Client{
-		Transport: &http.Transport{ //TODO: настроить
-			MaxIdleConns:       10,
-			IdleConnTimeout:    30 * time.Second,
-			DisableCompression: true,
+	notifications := make(models.Notifications, 0, len(*users)+len(*savedSearches))
+	userIds := make(map[uint32]bool)
+	for _, user := range *users {
+		if userIds[user.Id] {
+			continue
+		}
+		userIds[user.Id] = true
+
+		notifications = append(notifications, &models.Notification{
+			UserId:  user.Id,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: ad.Id},
+		})
+	}
+	for _, savedSearch := range *savedSearches {
+		if userIds[savedSearch.UserAuthorId] {
+			continue
+		}
+		userIds[savedSearch.UserAuthorId] = true
+
+		notifications = append(notifications, &models.Notification{
+			UserId: savedSearch.UserAuthorId,
+			Type:   models.NotificationTypeSavedSearchMatch,
+			Payload: models.NotificationPayload{
+				AdId:          ad.Id,
+				SavedSearchId: &savedSearch.Id,
+			},
+		})
+	}
+
+	//every user is notified on their own, so the failure for one of them does not cost the rest their notifications
+	var failedCount int
+	var lastErr error
+	for _, notification_ := range notifications {
+		if _, err := notificationUsecase.notificationRepository.InsertNotificationArray(
+			&models.Notifications{notification_}); err != nil {
+			log.Println(err)
+			failedCount++
+			lastErr = err
+		}
+	}
+
+	if failedCount != 0 {
+		return response.NewErrorResponse(consts.InternalError,
+			fmt.Errorf("NotifyAdMatches: %d of %d users are not notified: %w", failedCount, len(notifications), lastErr))
+	}
+
+	return response.NewEmptyResponse(consts.OK)
+}
+
+func (notificationUsecase *NotificationUsecase) NotifyAdExpired(ad *models.Ad) *response.Response {
+	notifications := models.Notifications{
+		&models.Notification{
+			UserId:  ad.UserAuthorId,
+			Type:    models.NotificationTypeAdExpired,
+			Payload: models.NotificationPayload{AdId: ad.Id},
 		},
 	}
-	var anyErrorLogged = false
-	for _, user := range *users {
-		response_, err := client.Get(fmt.Sprintf("https://handover.space/bot/schedule?user_id=%d", user.Id))
+	if _, err := notificationUsecase.notificationRepository.InsertNotificationArray(&notifications); err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewEmptyResponse(consts.OK)
+}
+
+func (notificationUsecase *NotificationUsecase) ListNotifications(userId uint32, cursor *models.NotificationsCursor,
+	limit *uint32) *response.Response {
+	limit_ := parser.GetOrDefault(limit, notificationsDefaultLimit).(uint32)
+	notifications, err := notificationUsecase.notificationRepository.SelectNotificationArrayByUserId(userId, cursor,
+		limit_+1)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	var nextCursor string
+	if uint32(len(*notifications)) > limit_ {
+		*notifications = (*notifications)[:limit_]
+		nextCursor = models.NewNotificationsCursor((*notifications)[limit_-1]).String()
+	}
+
+	return response.NewPageResponse(consts.OK, notifications, nextCursor)
+}
+
+func (notificationUsecase *NotificationUsecase) ReadNotification(userId uint32, id uint32) *response.Response {
+	existingNotification, err := notificationUsecase.notificationRepository.SelectNotification(id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if userId != existingNotification.UserId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	notification_, err := notificationUsecase.notificationRepository.UpdateNotificationRead(id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notification_)
+}
+
+func (notificationUsecase *NotificationUsecase) ReadAllNotifications(userId uint32) *response.Response {
+	if err := notificationUsecase.notificationRepository.UpdateNotificationsReadByUserId(userId); err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewEmptyResponse(consts.OK)
+}
+
+func (notificationUsecase *NotificationUsecase) GetNotificationsUnread(userId uint32) *response.Response {
+	notificationsUnread, err := notificationUsecase.notificationRepository.SelectNotificationsUnreadByUserId(userId)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationsUnread)
+}
+
+func (notificationUsecase *NotificationUsecase) LeaseNotificationOutbox(limit uint32) *response.Response {
+	dateTime := time.Now()
+	notificationOutboxItems, err := notificationUsecase.notificationRepository.UpdateNotificationOutboxItemArrayLease(
+		limit, dateTime, dateTime.Add(notificationOutboxLeaseDuration))
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationOutboxItems)
+}
+
+func (notificationUsecase *NotificationUsecase) DeliverNotificationOutboxItem(
+	notificationOutboxItem *models.NotificationOutboxItem) *response.Response {
+	dateTime := time.Now()
+	if dateTimeQuietHoursEnd, ok := notificationOutboxItem.NotificationSettings.QuietHoursEnd(dateTime); ok {
+		return notificationUsecase.postponeNotificationOutboxItem(notificationOutboxItem, dateTimeQuietHoursEnd)
+	}
+
+	//the slot is reserved before the delivery, and released by the update below, unless it is sent
+	if maxPerHour := notificationOutboxItem.NotificationSettings.MaxPerHour; maxPerHour != nil {
+		notificationsSent, err := notificationUsecase.notificationRepository.UpdateNotificationOutboxItemSentReserve(
+			notificationOutboxItem, *maxPerHour, dateTime.Add(-time.Hour), dateTime)
 		if err != nil {
 			return response.NewErrorResponse(consts.InternalError, err)
 		}
-		if response_.StatusCode != http.StatusOK && !anyErrorLogged {
-			log.Println("lobaevni: ", "cannot access vk bot: response code = ", response_.StatusCode)
-			anyErrorLogged = true
+
+		if notificationsSent.Count >= *maxPerHour && notificationsSent.DateTimeFirst != nil {
+			return notificationUsecase.postponeNotificationOutboxItem(notificationOutboxItem,
+				notificationsSent.DateTimeFirst.Add(time.Hour))
 		}
 	}
 
-	return response.NewEmptyResponse(consts.OK)
+	notifier_, ok := notificationUsecase.notifiers[notificationOutboxItem.NotificationChannel.Channel]
+	if !ok {
+		notifier_ = notificationUsecase.fallbackNotifier
+	}
+
+	if err := notifier_.Notify(&notificationOutboxItem.NotificationChannel,
+		&notificationOutboxItem.Notification); err != nil {
+		lastError := err.Error()
+		if len(lastError) > notificationOutboxLastErrorMaxSize {
+			lastError = lastError[:notificationOutboxLastErrorMaxSize]
+		}
+		notificationOutboxItem.LastError = &lastError
+
+		if notificationOutboxItem.Attempts >= notificationOutboxMaxAttempts {
+			notificationOutboxItem.Status = models.NotificationOutboxStatusDead
+		} else {
+			notificationOutboxItem.DateTimeNextAttempt = timestamps.DateTime(time.Now().Add(
+				notificationOutboxBackoff(notificationOutboxItem.Attempts)))
+		}
+	} else {
+		notificationOutboxItem.Status = models.NotificationOutboxStatusSent
+		dateTimeSent := timestamps.DateTime(time.Now())
+		notificationOutboxItem.DateTimeSent = &dateTimeSent
+	}
+
+	if err := notificationUsecase.notificationRepository.UpdateNotificationOutboxItem(
+		notificationOutboxItem); err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationOutboxItem)
+}
+
+func (notificationUsecase *NotificationUsecase) GetNotificationChannel(userId uint32) *response.Response {
+	notificationChannel, err := notificationUsecase.notificationRepository.SelectNotificationChannelByUserId(userId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewResponse(consts.OK, models.NewDefaultNotificationChannel(userId))
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationChannel)
+}
+
+func (notificationUsecase *NotificationUsecase) UpdateNotificationChannel(
+	notificationChannel *models.NotificationChannel) *response.Response {
+	if !notificationChannel.HasValidAddress() {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	notificationChannel, err := notificationUsecase.notificationRepository.InsertOrUpdateNotificationChannel(
+		notificationChannel)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationChannel)
+}
+
+func (notificationUsecase *NotificationUsecase) GetNotificationSettings(userId uint32) *response.Response {
+	notificationSettings, err := notificationUsecase.notificationRepository.SelectNotificationSettingsByUserId(userId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationSettings)
+}
+
+func (notificationUsecase *NotificationUsecase) UpdateNotificationSettings(
+	notificationSettings *models.NotificationSettings) *response.Response {
+	if !notificationSettings.HasValidTimezone() || !notificationSettings.HasValidQuietHours() ||
+		!notificationSettings.HasValidDigest() {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	notificationSettings.DateTimeDigestNext = nil
+	if dateTimeDigestNext, ok := notificationSettings.NextDigest(time.Now()); ok {
+		notificationSettings.DateTimeDigestNext = &dateTimeDigestNext
+	}
+
+	notificationSettings, err := notificationUsecase.notificationRepository.UpdateNotificationSettings(
+		notificationSettings)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationSettings)
+}
+
+// SendNotificationDigests collects the buffered ad notifications of every user, whose digest is due, into a single
+// notification
+func (notificationUsecase *NotificationUsecase) SendNotificationDigests() *response.Response {
+	dateTime := time.Now()
+	notificationSettingsArray, err := notificationUsecase.notificationRepository.
+		SelectNotificationSettingsArrayByDigestDue(dateTime)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	notifications := make(models.Notifications, 0)
+	for _, notificationSettings := range *notificationSettingsArray {
+		dateTimeDigestNext, ok := notificationSettings.NextDigest(dateTime)
+		if !ok {
+			//the timezone was valid when saved, but is gone from the tz database since, so the user has to pick another
+			log.Printf("cannot schedule the digest of user %d: unknown timezone %s", notificationSettings.UserId,
+				notificationSettings.Timezone)
+			continue
+		}
+
+		notification_, err := notificationUsecase.notificationRepository.InsertNotificationDigest(
+			notificationSettings.UserId, dateTime, dateTimeDigestNext)
+		if err != nil {
+			if err != consts.RepErrNotFound {
+				log.Println(err)
+			}
+
+			continue
+		}
+
+		notifications = append(notifications, notification_)
+	}
+
+	return response.NewResponse(consts.OK, &notifications)
+}
+
+// postponeNotificationOutboxItem does not count the attempt, since nothing was sent
+func (notificationUsecase *NotificationUsecase) postponeNotificationOutboxItem(
+	notificationOutboxItem *models.NotificationOutboxItem, dateTimeNextAttempt time.Time) *response.Response {
+	notificationOutboxItem.Attempts--
+	notificationOutboxItem.DateTimeNextAttempt = timestamps.DateTime(dateTimeNextAttempt)
+
+	if err := notificationUsecase.notificationRepository.UpdateNotificationOutboxItem(
+		notificationOutboxItem); err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, notificationOutboxItem)
+}
+
+// notificationOutboxBackoff waits notificationOutboxBackoffBase before the first retry and twice as long before
+// every next one
+func notificationOutboxBackoff(attempts uint32) time.Duration {
+	backoff := notificationOutboxBackoffBase
+	for i := uint32(1); i < attempts && backoff < notificationOutboxBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > notificationOutboxBackoffMax {
+		return notificationOutboxBackoffMax
+	}
+
+	return backoff
 }
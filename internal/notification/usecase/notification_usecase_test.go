@@ -1 +1,859 @@
-package usecase
+package usecase_test
+
+import (
+	"errors"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/notification/mock_notification"
+	"github.com/TechnoHandOver/backend/internal/notification/usecase"
+	"github.com/TechnoHandOver/backend/internal/notifier"
+	"github.com/TechnoHandOver/backend/internal/notifier/mock_notifier"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/golang/mock/gomock"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNotificationUsecase_NotifyAdMatches(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	ad := &models.Ad{
+		Id:           1,
+		UserAuthorId: 101,
+	}
+	users := &models.Users{
+		&models.User{Id: 102},
+		&models.User{Id: 103},
+	}
+	savedSearches := &models.SavedSearches{
+		&models.SavedSearch{Id: 11, UserAuthorId: 103},
+		&models.SavedSearch{Id: 12, UserAuthorId: 104},
+		&models.SavedSearch{Id: 13, UserAuthorId: 104},
+	}
+
+	insertErr := errors.New("insert failed")
+
+	call := mockNotificationRepository.
+		EXPECT().
+		SelectUsersByRoutesWithSuitableTimeInterval(gomock.Eq(ad)).
+		Return(users, nil)
+	call = mockNotificationRepository.
+		EXPECT().
+		SelectSavedSearchArrayBySuitableAd(gomock.Eq(ad)).
+		Return(savedSearches, nil).
+		After(call)
+	call = mockNotificationRepository.
+		EXPECT().
+		InsertNotificationArray(gomock.Eq(&models.Notifications{
+			&models.Notification{
+				UserId:  102,
+				Type:    models.NotificationTypeRouteMatch,
+				Payload: models.NotificationPayload{AdId: ad.Id},
+			},
+		})).
+		Return(nil, insertErr).
+		After(call)
+	call = mockNotificationRepository.
+		EXPECT().
+		InsertNotificationArray(gomock.Eq(&models.Notifications{
+			&models.Notification{
+				UserId:  103,
+				Type:    models.NotificationTypeRouteMatch,
+				Payload: models.NotificationPayload{AdId: ad.Id},
+			},
+		})).
+		DoAndReturn(func(notifications *models.Notifications) (*models.Notifications, error) {
+			return notifications, nil
+		}).
+		After(call)
+	mockNotificationRepository.
+		EXPECT().
+		InsertNotificationArray(gomock.Eq(&models.Notifications{
+			&models.Notification{
+				UserId: 104,
+				Type:   models.NotificationTypeSavedSearchMatch,
+				Payload: models.NotificationPayload{
+					AdId:          ad.Id,
+					SavedSearchId: pointy.Uint32(12),
+				},
+			},
+		})).
+		DoAndReturn(func(notifications *models.Notifications) (*models.Notifications, error) {
+			return notifications, nil
+		}).
+		After(call)
+
+	response_ := notificationUsecase.NotifyAdMatches(ad)
+	assert.Equal(t, consts.InternalError, response_.Code)
+	assert.True(t, errors.Is(response_.Error, insertErr))
+}
+
+func TestNotificationUsecase_NotifyAdExpired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	ad := &models.Ad{
+		Id:           1,
+		UserAuthorId: 101,
+	}
+
+	mockNotificationRepository.
+		EXPECT().
+		InsertNotificationArray(gomock.Eq(&models.Notifications{
+			&models.Notification{
+				UserId:  ad.UserAuthorId,
+				Type:    models.NotificationTypeAdExpired,
+				Payload: models.NotificationPayload{AdId: ad.Id},
+			},
+		})).
+		DoAndReturn(func(notifications *models.Notifications) (*models.Notifications, error) {
+			return notifications, nil
+		})
+
+	response_ := notificationUsecase.NotifyAdExpired(ad)
+	assert.Equal(t, response.NewEmptyResponse(consts.OK), response_)
+}
+
+func TestNotificationUsecase_ListNotifications(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	const userId uint32 = 101
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	notifications := &models.Notifications{
+		&models.Notification{
+			Id:       4,
+			UserId:   userId,
+			Type:     models.NotificationTypeAdExpired,
+			Payload:  models.NotificationPayload{AdId: 2},
+			DateTime: *dateTime,
+		},
+		&models.Notification{
+			Id:       3,
+			UserId:   userId,
+			Type:     models.NotificationTypeRouteMatch,
+			Payload:  models.NotificationPayload{AdId: 1},
+			DateTime: *dateTime,
+		},
+	}
+	expectedNotifications := &models.Notifications{
+		(*notifications)[0],
+	}
+	expectedNextCursor := models.NewNotificationsCursor((*notifications)[0]).String()
+
+	mockNotificationRepository.
+		EXPECT().
+		SelectNotificationArrayByUserId(gomock.Eq(userId), gomock.Nil(), gomock.Eq(uint32(2))).
+		Return(notifications, nil)
+
+	response_ := notificationUsecase.ListNotifications(userId, nil, pointy.Uint32(1))
+	assert.Equal(t, response.NewPageResponse(consts.OK, expectedNotifications, expectedNextCursor), response_)
+}
+
+func TestNotificationUsecase_ReadNotification(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	existingNotification := &models.Notification{
+		Id:       1,
+		UserId:   101,
+		Type:     models.NotificationTypeRouteMatch,
+		Payload:  models.NotificationPayload{AdId: 1},
+		DateTime: *dateTime,
+	}
+	expectedNotification := &models.Notification{
+		Id:       existingNotification.Id,
+		UserId:   existingNotification.UserId,
+		Type:     existingNotification.Type,
+		Payload:  existingNotification.Payload,
+		Read:     true,
+		DateTime: existingNotification.DateTime,
+	}
+
+	callSelectNotification := mockNotificationRepository.
+		EXPECT().
+		SelectNotification(gomock.Eq(existingNotification.Id)).
+		Return(existingNotification, nil)
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationRead(gomock.Eq(existingNotification.Id)).
+		Return(expectedNotification, nil).
+		After(callSelectNotification)
+
+	response_ := notificationUsecase.ReadNotification(existingNotification.UserId, existingNotification.Id)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedNotification), response_)
+}
+
+func TestNotificationUsecase_ReadNotification_forbidden(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	dateTime, err := timestamps.NewDateTime("05.12.2021 19:50")
+	assert.Nil(t, err)
+	existingNotification := &models.Notification{
+		Id:       1,
+		UserId:   101,
+		Type:     models.NotificationTypeRouteMatch,
+		Payload:  models.NotificationPayload{AdId: 1},
+		DateTime: *dateTime,
+	}
+
+	mockNotificationRepository.
+		EXPECT().
+		SelectNotification(gomock.Eq(existingNotification.Id)).
+		Return(existingNotification, nil)
+
+	response_ := notificationUsecase.ReadNotification(102, existingNotification.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestNotificationUsecase_ReadNotification_notFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	const id uint32 = 1
+
+	mockNotificationRepository.
+		EXPECT().
+		SelectNotification(gomock.Eq(id)).
+		Return(nil, consts.RepErrNotFound)
+
+	response_ := notificationUsecase.ReadNotification(101, id)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}
+
+func TestNotificationUsecase_GetNotificationsUnread(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	const userId uint32 = 101
+	expectedNotificationsUnread := &models.NotificationsUnread{
+		Count: 3,
+	}
+
+	mockNotificationRepository.
+		EXPECT().
+		SelectNotificationsUnreadByUserId(gomock.Eq(userId)).
+		Return(expectedNotificationsUnread, nil)
+
+	response_ := notificationUsecase.GetNotificationsUnread(userId)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedNotificationsUnread), response_)
+}
+
+func TestNotificationUsecase_LeaseNotificationOutbox(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	const limit uint32 = 4
+	expectedNotificationOutboxItems := &models.NotificationOutboxItems{
+		&models.NotificationOutboxItem{
+			Id: 2,
+			Notification: models.Notification{
+				Id:      3,
+				UserId:  101,
+				Type:    models.NotificationTypeRouteMatch,
+				Payload: models.NotificationPayload{AdId: 1},
+			},
+			Status:   models.NotificationOutboxStatusPending,
+			Attempts: 1,
+		},
+	}
+
+	before := time.Now()
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItemArrayLease(gomock.Eq(limit), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(limit uint32, dateTime time.Time,
+			dateTimeLeaseExpiry time.Time) (*models.NotificationOutboxItems, error) {
+			assert.False(t, dateTime.Before(before))
+			assert.False(t, dateTime.After(time.Now()))
+			assert.True(t, dateTimeLeaseExpiry.After(dateTime))
+			return expectedNotificationOutboxItems, nil
+		})
+
+	response_ := notificationUsecase.LeaseNotificationOutbox(limit)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedNotificationOutboxItems), response_)
+}
+
+func TestNotificationUsecase_DeliverNotificationOutboxItem(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id: 2,
+		Notification: models.Notification{
+			Id:      3,
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		NotificationChannel: *models.NewDefaultNotificationChannel(101),
+		Status:              models.NotificationOutboxStatusPending,
+		Attempts:            1,
+	}
+
+	call := mockNotifier.
+		EXPECT().
+		Notify(gomock.Eq(&notificationOutboxItem.NotificationChannel), gomock.Eq(&notificationOutboxItem.Notification)).
+		Return(nil)
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+		Return(nil).
+		After(call)
+
+	response_ := notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationOutboxItem), response_)
+	assert.Equal(t, models.NotificationOutboxStatusSent, notificationOutboxItem.Status)
+}
+
+func TestNotificationUsecase_DeliverNotificationOutboxItem_retry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id: 2,
+		Notification: models.Notification{
+			Id:      3,
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		NotificationChannel: *models.NewDefaultNotificationChannel(101),
+		Status:              models.NotificationOutboxStatusPending,
+		Attempts:            3,
+	}
+
+	before := time.Now()
+	call := mockNotifier.
+		EXPECT().
+		Notify(gomock.Any(), gomock.Any()).
+		Return(errors.New("cannot access vk bot: response code = 502"))
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+		Return(nil).
+		After(call)
+
+	response_ := notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationOutboxItem), response_)
+	assert.Equal(t, models.NotificationOutboxStatusPending, notificationOutboxItem.Status)
+	assert.Equal(t, pointy.String("cannot access vk bot: response code = 502"), notificationOutboxItem.LastError)
+	//the third attempt has failed, so the next one waits four times the base backoff
+	assert.False(t, time.Time(notificationOutboxItem.DateTimeNextAttempt).Before(before.Add(2*time.Minute)))
+	assert.False(t, time.Time(notificationOutboxItem.DateTimeNextAttempt).After(time.Now().Add(2*time.Minute)))
+}
+
+func TestNotificationUsecase_DeliverNotificationOutboxItem_dead(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id: 2,
+		Notification: models.Notification{
+			Id:      3,
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		NotificationChannel: *models.NewDefaultNotificationChannel(101),
+		Status:              models.NotificationOutboxStatusPending,
+		Attempts:            8,
+	}
+
+	call := mockNotifier.
+		EXPECT().
+		Notify(gomock.Any(), gomock.Any()).
+		Return(errors.New("cannot access vk bot: response code = 502"))
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+		Return(nil).
+		After(call)
+
+	response_ := notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationOutboxItem), response_)
+	assert.Equal(t, models.NotificationOutboxStatusDead, notificationOutboxItem.Status)
+}
+
+func TestNotificationUsecase_DeliverNotificationOutboxItem_fallback(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	mockFallbackNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockFallbackNotifier)
+
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id: 2,
+		Notification: models.Notification{
+			Id:      3,
+			UserId:  101,
+			Type:    models.NotificationTypeAdExpired,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		NotificationChannel: models.NotificationChannel{
+			UserId:  101,
+			Channel: models.NotificationChannelKindEmail,
+			Address: pointy.String("courier@example.com"),
+		},
+		Status:   models.NotificationOutboxStatusPending,
+		Attempts: 1,
+	}
+
+	call := mockFallbackNotifier.
+		EXPECT().
+		Notify(gomock.Eq(&notificationOutboxItem.NotificationChannel), gomock.Eq(&notificationOutboxItem.Notification)).
+		Return(nil)
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+		Return(nil).
+		After(call)
+
+	response_ := notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationOutboxItem), response_)
+}
+
+func TestNotificationUsecase_GetNotificationChannel_default(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	const userId uint32 = 101
+
+	mockNotificationRepository.
+		EXPECT().
+		SelectNotificationChannelByUserId(gomock.Eq(userId)).
+		Return(nil, consts.RepErrNotFound)
+
+	response_ := notificationUsecase.GetNotificationChannel(userId)
+	assert.Equal(t, response.NewResponse(consts.OK, &models.NotificationChannel{
+		UserId:  userId,
+		Channel: models.NotificationChannelKindVkBot,
+	}), response_)
+}
+
+func TestNotificationUsecase_UpdateNotificationChannel_invalidAddress(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationChannel := &models.NotificationChannel{
+		UserId:  101,
+		Channel: models.NotificationChannelKindEmail,
+		Address: pointy.String("courier"),
+	}
+
+	response_ := notificationUsecase.UpdateNotificationChannel(notificationChannel)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestNotificationUsecase_DeliverNotificationOutboxItem_quietHours(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	before := time.Now().Truncate(time.Minute)
+	quietHoursFrom := timestamps.Time(before.UTC().Add(-time.Hour))
+	quietHoursTo := timestamps.Time(before.UTC().Add(time.Hour))
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id: 2,
+		Notification: models.Notification{
+			Id:      3,
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		NotificationChannel: *models.NewDefaultNotificationChannel(101),
+		NotificationSettings: models.NotificationSettings{
+			UserId:         101,
+			Timezone:       "UTC",
+			QuietHoursFrom: &quietHoursFrom,
+			QuietHoursTo:   &quietHoursTo,
+		},
+		Status:   models.NotificationOutboxStatusPending,
+		Attempts: 1,
+	}
+
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+		Return(nil)
+
+	response_ := notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationOutboxItem), response_)
+	assert.Equal(t, models.NotificationOutboxStatusPending, notificationOutboxItem.Status)
+	assert.Equal(t, uint32(0), notificationOutboxItem.Attempts)
+	assert.True(t, time.Time(notificationOutboxItem.DateTimeNextAttempt).Equal(before.Add(time.Hour)))
+}
+
+func TestNotificationUsecase_DeliverNotificationOutboxItem_maxPerHour(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id: 2,
+		Notification: models.Notification{
+			Id:      3,
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		NotificationChannel: *models.NewDefaultNotificationChannel(101),
+		NotificationSettings: models.NotificationSettings{
+			UserId:     101,
+			Timezone:   "Europe/Moscow",
+			MaxPerHour: pointy.Uint32(2),
+		},
+		Status:   models.NotificationOutboxStatusPending,
+		Attempts: 1,
+	}
+	dateTimeFirst := time.Now().Add(-20 * time.Minute)
+
+	call := mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItemSentReserve(gomock.Eq(notificationOutboxItem), gomock.Eq(uint32(2)), gomock.Any(),
+			gomock.Any()).
+		Return(&models.NotificationsSent{
+			Count:         2,
+			DateTimeFirst: &dateTimeFirst,
+		}, nil)
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+		Return(nil).
+		After(call)
+
+	response_ := notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationOutboxItem), response_)
+	assert.Equal(t, uint32(0), notificationOutboxItem.Attempts)
+	assert.True(t, time.Time(notificationOutboxItem.DateTimeNextAttempt).Equal(dateTimeFirst.Add(time.Hour)))
+}
+
+func TestNotificationUsecase_DeliverNotificationOutboxItem_maxPerHourReserved(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationOutboxItem := &models.NotificationOutboxItem{
+		Id: 2,
+		Notification: models.Notification{
+			Id:      3,
+			UserId:  101,
+			Type:    models.NotificationTypeRouteMatch,
+			Payload: models.NotificationPayload{AdId: 1},
+		},
+		NotificationChannel: *models.NewDefaultNotificationChannel(101),
+		NotificationSettings: models.NotificationSettings{
+			UserId:     101,
+			Timezone:   "Europe/Moscow",
+			MaxPerHour: pointy.Uint32(2),
+		},
+		Status:   models.NotificationOutboxStatusPending,
+		Attempts: 1,
+	}
+	dateTimeFirst := time.Now().Add(-20 * time.Minute)
+
+	before := time.Now()
+	call := mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItemSentReserve(gomock.Eq(notificationOutboxItem), gomock.Eq(uint32(2)), gomock.Any(),
+			gomock.Any()).
+		DoAndReturn(func(notificationOutboxItem *models.NotificationOutboxItem, maxPerHour uint32,
+			dateTimeFrom time.Time, dateTime time.Time) (*models.NotificationsSent, error) {
+			assert.False(t, dateTime.Before(before))
+			assert.Equal(t, dateTime.Add(-time.Hour), dateTimeFrom)
+			return &models.NotificationsSent{
+				Count:         1,
+				DateTimeFirst: &dateTimeFirst,
+			}, nil
+		})
+	call = mockNotifier.
+		EXPECT().
+		Notify(gomock.Eq(&notificationOutboxItem.NotificationChannel), gomock.Eq(&notificationOutboxItem.Notification)).
+		Return(nil).
+		After(call)
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+		Return(nil).
+		After(call)
+
+	response_ := notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationOutboxItem), response_)
+	assert.Equal(t, models.NotificationOutboxStatusSent, notificationOutboxItem.Status)
+}
+
+func TestNotificationUsecase_UpdateNotificationSettings_invalidTimezone(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationSettings := &models.NotificationSettings{
+		UserId:        101,
+		Timezone:      "Europe/Baumanka",
+		MutedRouteIds: []uint32{},
+	}
+
+	response_ := notificationUsecase.UpdateNotificationSettings(notificationSettings)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestNotificationUsecase_UpdateNotificationSettings_digest(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	notificationSettings := &models.NotificationSettings{
+		UserId:         101,
+		Timezone:       "Europe/Moscow",
+		MutedRouteIds:  []uint32{},
+		DigestInterval: pointy.Uint32(30),
+		DigestTimes:    []timestamps.Time{},
+	}
+
+	dateTime := time.Now()
+	mockNotificationRepository.
+		EXPECT().
+		UpdateNotificationSettings(gomock.Eq(notificationSettings)).
+		DoAndReturn(func(notificationSettings *models.NotificationSettings) (*models.NotificationSettings, error) {
+			assert.NotNil(t, notificationSettings.DateTimeDigestNext)
+			assert.WithinDuration(t, dateTime.Add(30*time.Minute), *notificationSettings.DateTimeDigestNext,
+				time.Minute)
+			return notificationSettings, nil
+		})
+
+	response_ := notificationUsecase.UpdateNotificationSettings(notificationSettings)
+	assert.Equal(t, response.NewResponse(consts.OK, notificationSettings), response_)
+}
+
+func TestNotificationUsecase_UpdateNotificationSettings_invalidDigest(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	digestTime, err := timestamps.NewTime("09:00")
+	assert.Nil(t, err)
+	notificationSettings := &models.NotificationSettings{
+		UserId:         101,
+		Timezone:       "Europe/Moscow",
+		MutedRouteIds:  []uint32{},
+		DigestInterval: pointy.Uint32(30),
+		DigestTimes:    []timestamps.Time{*digestTime},
+	}
+
+	response_ := notificationUsecase.UpdateNotificationSettings(notificationSettings)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestNotificationUsecase_SendNotificationDigests(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	digestTime, err := timestamps.NewTime("09:00")
+	assert.Nil(t, err)
+	notificationSettingsArray := &models.NotificationSettingsArray{
+		&models.NotificationSettings{
+			UserId:         101,
+			Timezone:       "Europe/Moscow",
+			DigestInterval: pointy.Uint32(30),
+		},
+		&models.NotificationSettings{
+			UserId:      102,
+			Timezone:    "Europe/Moscow",
+			DigestTimes: []timestamps.Time{*digestTime},
+		},
+	}
+	notification_ := &models.Notification{
+		Id:      5,
+		UserId:  101,
+		Type:    models.NotificationTypeDigest,
+		Payload: models.NotificationPayload{AdIds: []uint32{1, 3}},
+	}
+	expectedNotifications := &models.Notifications{notification_}
+
+	call := mockNotificationRepository.
+		EXPECT().
+		SelectNotificationSettingsArrayByDigestDue(gomock.Any()).
+		Return(notificationSettingsArray, nil)
+	call = mockNotificationRepository.
+		EXPECT().
+		InsertNotificationDigest(gomock.Eq(uint32(101)), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(userId uint32, dateTime time.Time, dateTimeDigestNext time.Time) (*models.Notification,
+			error) {
+			assert.Equal(t, 30*time.Minute, dateTimeDigestNext.Sub(dateTime))
+			return notification_, nil
+		}).
+		After(call)
+	mockNotificationRepository.
+		EXPECT().
+		InsertNotificationDigest(gomock.Eq(uint32(102)), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(userId uint32, dateTime time.Time, dateTimeDigestNext time.Time) (*models.Notification,
+			error) {
+			location, err := time.LoadLocation("Europe/Moscow")
+			assert.Nil(t, err)
+			dateTimeDigestNext = dateTimeDigestNext.In(location)
+			assert.Equal(t, 9, dateTimeDigestNext.Hour())
+			assert.Equal(t, 0, dateTimeDigestNext.Minute())
+			assert.True(t, dateTimeDigestNext.After(dateTime))
+			assert.True(t, dateTimeDigestNext.Sub(dateTime) <= 24*time.Hour)
+			return nil, consts.RepErrNotFound
+		}).
+		After(call)
+
+	response_ := notificationUsecase.SendNotificationDigests()
+	assert.Equal(t, response.NewResponse(consts.OK, expectedNotifications), response_)
+}
+
+func TestNotificationUsecase_SendNotificationDigests_unknownTimezone(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationRepository := mock_notification.NewMockRepository(controller)
+	mockNotifier := mock_notifier.NewMockNotifier(controller)
+	notificationUsecase := usecase.NewNotificationUsecaseImpl(mockNotificationRepository,
+		map[models.NotificationChannelKind]notifier.Notifier{models.NotificationChannelKindVkBot: mockNotifier},
+		mockNotifier)
+
+	digestTime, err := timestamps.NewTime("09:00")
+	assert.Nil(t, err)
+	notificationSettingsArray := &models.NotificationSettingsArray{
+		&models.NotificationSettings{
+			UserId:      102,
+			Timezone:    "Europe/Baumanka",
+			DigestTimes: []timestamps.Time{*digestTime},
+		},
+	}
+
+	mockNotificationRepository.
+		EXPECT().
+		SelectNotificationSettingsArrayByDigestDue(gomock.Any()).
+		Return(notificationSettingsArray, nil)
+
+	response_ := notificationUsecase.SendNotificationDigests()
+	assert.Equal(t, response.NewResponse(consts.OK, &models.Notifications{}), response_)
+}
@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notification"
+	"log"
+	"sync"
+	"time"
+)
+
+type NotificationWorker struct {
+	notificationUsecase notification.Usecase
+	pollInterval        time.Duration
+	poolSize            uint32
+	notificationOutbox  chan *models.NotificationOutboxItem
+	stop                chan struct{}
+	waitGroup           sync.WaitGroup
+}
+
+func NewNotificationWorker(notificationUsecase notification.Usecase, pollInterval time.Duration,
+	poolSize uint32) *NotificationWorker {
+	return &NotificationWorker{
+		notificationUsecase: notificationUsecase,
+		pollInterval:        pollInterval,
+		poolSize:            poolSize,
+		notificationOutbox:  make(chan *models.NotificationOutboxItem),
+		stop:                make(chan struct{}),
+	}
+}
+
+func (notificationWorker *NotificationWorker) Start() {
+	notificationWorker.waitGroup.Add(int(notificationWorker.poolSize))
+	for i := uint32(0); i < notificationWorker.poolSize; i++ {
+		go notificationWorker.deliver()
+	}
+
+	go notificationWorker.run()
+}
+
+// Stop waits for the items being delivered; the leased but not yet delivered ones are resumed after the lease expires
+func (notificationWorker *NotificationWorker) Stop() {
+	close(notificationWorker.stop)
+	notificationWorker.waitGroup.Wait()
+}
+
+func (notificationWorker *NotificationWorker) run() {
+	defer close(notificationWorker.notificationOutbox)
+
+	ticker := time.NewTicker(notificationWorker.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		//a full batch means there may be more pending items, so there is no reason to wait for the ticker
+		if notificationWorker.lease() == notificationWorker.poolSize {
+			select {
+			case <-notificationWorker.stop:
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-notificationWorker.stop:
+			return
+		}
+	}
+}
+
+func (notificationWorker *NotificationWorker) lease() uint32 {
+	response_ := notificationWorker.notificationUsecase.LeaseNotificationOutbox(notificationWorker.poolSize)
+	if response_.Error != nil {
+		log.Println(response_.Error)
+		return 0
+	}
+
+	notificationOutboxItems := response_.Data.(*models.NotificationOutboxItems)
+	for _, notificationOutboxItem := range *notificationOutboxItems {
+		select {
+		case notificationWorker.notificationOutbox <- notificationOutboxItem:
+		case <-notificationWorker.stop:
+			return 0
+		}
+	}
+
+	return uint32(len(*notificationOutboxItems))
+}
+
+func (notificationWorker *NotificationWorker) deliver() {
+	defer notificationWorker.waitGroup.Done()
+
+	for notificationOutboxItem := range notificationWorker.notificationOutbox {
+		response_ := notificationWorker.notificationUsecase.DeliverNotificationOutboxItem(notificationOutboxItem)
+		if response_.Error != nil {
+			log.Println(response_.Error)
+		}
+	}
+}
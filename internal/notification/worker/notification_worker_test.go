@@ -0,0 +1,61 @@
+package worker_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notification/mock_notification"
+	"github.com/TechnoHandOver/backend/internal/notification/worker"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/golang/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestNotificationWorker_Start(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockNotificationUsecase := mock_notification.NewMockUsecase(controller)
+	const poolSize uint32 = 2
+	notificationWorker := worker.NewNotificationWorker(mockNotificationUsecase, time.Millisecond, poolSize)
+
+	notificationOutboxItems := &models.NotificationOutboxItems{
+		&models.NotificationOutboxItem{
+			Id:       1,
+			Status:   models.NotificationOutboxStatusPending,
+			Attempts: 1,
+		},
+		&models.NotificationOutboxItem{
+			Id:       2,
+			Status:   models.NotificationOutboxStatusPending,
+			Attempts: 1,
+		},
+	}
+
+	delivered := make(chan struct{}, len(*notificationOutboxItems))
+	call := mockNotificationUsecase.
+		EXPECT().
+		LeaseNotificationOutbox(gomock.Eq(poolSize)).
+		Return(response.NewResponse(consts.OK, notificationOutboxItems))
+	mockNotificationUsecase.
+		EXPECT().
+		LeaseNotificationOutbox(gomock.Eq(poolSize)).
+		Return(response.NewResponse(consts.OK, &models.NotificationOutboxItems{})).
+		After(call).
+		AnyTimes()
+	for _, notificationOutboxItem := range *notificationOutboxItems {
+		mockNotificationUsecase.
+			EXPECT().
+			DeliverNotificationOutboxItem(gomock.Eq(notificationOutboxItem)).
+			DoAndReturn(func(notificationOutboxItem *models.NotificationOutboxItem) *response.Response {
+				delivered <- struct{}{}
+				return response.NewResponse(consts.OK, notificationOutboxItem)
+			})
+	}
+
+	notificationWorker.Start()
+	for range *notificationOutboxItems {
+		<-delivered
+	}
+	notificationWorker.Stop()
+}
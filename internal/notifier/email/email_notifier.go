@@ -0,0 +1,151 @@
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notifier"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// sendTimeout bounds the whole session, since smtp.SendMail has none, and a stalled server would hold the worker
+const sendTimeout = 30 * time.Second
+
+var (
+	errNoAddress = errors.New("email address is not set")
+	errNoAuth    = errors.New("smtp server does not support AUTH")
+)
+
+type EmailNotifier struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewEmailNotifier(host string, port uint16, username string, password string, from string) notifier.Notifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &EmailNotifier{
+		host: host,
+		addr: fmt.Sprintf("%s:%d", host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (emailNotifier *EmailNotifier) Notify(notificationChannel *models.NotificationChannel,
+	notification *models.Notification) error {
+	if notificationChannel.Address == nil {
+		return errNoAddress
+	}
+
+	subject, text, err := newMessage(notification)
+	if err != nil {
+		return err
+	}
+
+	message := strings.Join([]string{
+		"From: " + emailNotifier.from,
+		"To: " + *notificationChannel.Address,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		text,
+	}, "\r\n")
+
+	return emailNotifier.send(*notificationChannel.Address, []byte(message))
+}
+
+// send does what smtp.SendMail does, with the whole session bounded by sendTimeout
+func (emailNotifier *EmailNotifier) send(to string, message []byte) error {
+	conn, err := net.DialTimeout("tcp", emailNotifier.addr, sendTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if err := conn.SetDeadline(time.Now().Add(sendTimeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, emailNotifier.host)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: emailNotifier.host}); err != nil {
+			return err
+		}
+	}
+
+	if emailNotifier.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errNoAuth
+		}
+		if err := client.Auth(emailNotifier.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(emailNotifier.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func newMessage(notification *models.Notification) (string, string, error) {
+	switch notification.Type {
+	case models.NotificationTypeRouteMatch:
+		return "Новое объявление на вашем маршруте",
+			fmt.Sprintf("Появилось объявление №%d, подходящее под ваш маршрут.", notification.Payload.AdId), nil
+	case models.NotificationTypeSavedSearchMatch:
+		return "Новое объявление по сохранённому поиску",
+			fmt.Sprintf("Появилось объявление №%d, подходящее под ваш сохранённый поиск.",
+				notification.Payload.AdId), nil
+	case models.NotificationTypeAdExpired:
+		return "Объявление истекло",
+			fmt.Sprintf("Срок вашего объявления №%d истёк, и оно больше не показывается в поиске.",
+				notification.Payload.AdId), nil
+	case models.NotificationTypeDigest:
+		adIds := make([]string, len(notification.Payload.AdIds))
+		for i, adId := range notification.Payload.AdIds {
+			adIds[i] = fmt.Sprintf("№%d", adId)
+		}
+		return "Подборка новых объявлений",
+			fmt.Sprintf("Появились объявления, подходящие под ваши маршруты и сохранённые поиски: %s.",
+				strings.Join(adIds, ", ")), nil
+	default:
+		return "", "", fmt.Errorf("unknown notification type: %s", notification.Type)
+	}
+}
@@ -0,0 +1,30 @@
+package logsink
+
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notifier"
+	"log"
+)
+
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+func NewLogNotifier(logger *log.Logger) notifier.Notifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+func (logNotifier *LogNotifier) Notify(notificationChannel *models.NotificationChannel,
+	notification *models.Notification) error {
+	if notification.Type == models.NotificationTypeDigest {
+		logNotifier.logger.Printf("notification %d for user %d via %s: %s, ads %v", notification.Id,
+			notification.UserId, notificationChannel.Channel, notification.Type, notification.Payload.AdIds)
+		return nil
+	}
+
+	logNotifier.logger.Printf("notification %d for user %d via %s: %s, ad %d", notification.Id,
+		notification.UserId, notificationChannel.Channel, notification.Type, notification.Payload.AdId)
+	return nil
+}
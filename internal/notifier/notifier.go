@@ -0,0 +1,7 @@
+package notifier
+
+import "github.com/TechnoHandOver/backend/internal/models"
+
+type Notifier interface {
+	Notify(notificationChannel *models.NotificationChannel, notification *models.Notification) error
+}
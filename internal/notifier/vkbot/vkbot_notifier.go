@@ -0,0 +1,66 @@
+package vkbot
+
+import (
+	"fmt"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notifier"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+type VkBotNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewVkBotNotifier(url string) notifier.Notifier {
+	return &VkBotNotifier{
+		url: url,
+		client: &http.Client{
+			Timeout: requestTimeout,
+			Transport: &http.Transport{ //TODO: настроить
+				MaxIdleConns:       10,
+				IdleConnTimeout:    30 * time.Second,
+				DisableCompression: true,
+			},
+		},
+	}
+}
+
+func (vkBotNotifier *VkBotNotifier) Notify(_ *models.NotificationChannel, notification *models.Notification) error {
+	var url string
+	switch notification.Type {
+	case models.NotificationTypeRouteMatch:
+		url = fmt.Sprintf("%s/schedule?user_id=%d", vkBotNotifier.url, notification.UserId)
+	case models.NotificationTypeSavedSearchMatch:
+		url = fmt.Sprintf("%s/saved-search?user_id=%d&ad_id=%d&saved_search_id=%d", vkBotNotifier.url,
+			notification.UserId, notification.Payload.AdId, *notification.Payload.SavedSearchId)
+	case models.NotificationTypeAdExpired:
+		url = fmt.Sprintf("%s/expired?user_id=%d&ad_id=%d", vkBotNotifier.url, notification.UserId,
+			notification.Payload.AdId)
+	case models.NotificationTypeDigest:
+		adIds := make([]string, len(notification.Payload.AdIds))
+		for i, adId := range notification.Payload.AdIds {
+			adIds[i] = strconv.FormatUint(uint64(adId), 10)
+		}
+		url = fmt.Sprintf("%s/digest?user_id=%d&ad_ids=%s", vkBotNotifier.url, notification.UserId,
+			strings.Join(adIds, ","))
+	default:
+		return fmt.Errorf("unknown notification type: %s", notification.Type)
+	}
+
+	response_, err := vkBotNotifier.client.Get(url)
+	if err != nil {
+		return err
+	}
+	_ = response_.Body.Close()
+	if response_.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot access vk bot: response code = %d", response_.StatusCode)
+	}
+
+	return nil
+}
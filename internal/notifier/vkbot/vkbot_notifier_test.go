@@ -0,0 +1,75 @@
+package vkbot_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notifier/vkbot"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVkBotNotifier_Notify(t *testing.T) {
+	var requestUri string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requestUri = request.URL.RequestURI()
+	}))
+	defer server.Close()
+
+	vkBotNotifier := vkbot.NewVkBotNotifier(server.URL + "/bot")
+
+	notification := &models.Notification{
+		Id:     3,
+		UserId: 101,
+		Type:   models.NotificationTypeSavedSearchMatch,
+		Payload: models.NotificationPayload{
+			AdId:          1,
+			SavedSearchId: pointy.Uint32(2),
+		},
+	}
+
+	err := vkBotNotifier.Notify(models.NewDefaultNotificationChannel(notification.UserId), notification)
+	assert.Nil(t, err)
+	assert.Equal(t, "/bot/saved-search?user_id=101&ad_id=1&saved_search_id=2", requestUri)
+}
+
+func TestVkBotNotifier_Notify_responseCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	vkBotNotifier := vkbot.NewVkBotNotifier(server.URL + "/bot")
+
+	notification := &models.Notification{
+		Id:      3,
+		UserId:  101,
+		Type:    models.NotificationTypeAdExpired,
+		Payload: models.NotificationPayload{AdId: 1},
+	}
+
+	err := vkBotNotifier.Notify(models.NewDefaultNotificationChannel(notification.UserId), notification)
+	assert.EqualError(t, err, "cannot access vk bot: response code = 502")
+}
+
+func TestVkBotNotifier_Notify_digest(t *testing.T) {
+	var requestUri string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requestUri = request.URL.RequestURI()
+	}))
+	defer server.Close()
+
+	vkBotNotifier := vkbot.NewVkBotNotifier(server.URL + "/bot")
+
+	notification := &models.Notification{
+		Id:      5,
+		UserId:  101,
+		Type:    models.NotificationTypeDigest,
+		Payload: models.NotificationPayload{AdIds: []uint32{1, 3}},
+	}
+
+	err := vkBotNotifier.Notify(models.NewDefaultNotificationChannel(notification.UserId), notification)
+	assert.Nil(t, err)
+	assert.Equal(t, "/bot/digest?user_id=101&ad_ids=1,3", requestUri)
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notifier"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderSignature = "X-Handover-Signature"
+	requestTimeout  = 10 * time.Second
+	dialTimeout     = 5 * time.Second
+)
+
+var (
+	errNoAddress       = errors.New("webhook address is not set")
+	errInsecureAddress = errors.New("webhook address is not https")
+	errForbiddenHost   = errors.New("webhook host is not public")
+)
+
+type WebhookNotifier struct {
+	secret []byte
+	client *http.Client
+}
+
+type webhookNotification struct {
+	UserId uint32 `json:"userId"`
+	*models.Notification
+}
+
+func NewWebhookNotifier(secret string, client *http.Client) notifier.Notifier {
+	return &WebhookNotifier{
+		secret: []byte(secret),
+		client: client,
+	}
+}
+
+// NewClient makes the client, which dials public hosts only, so that users cannot reach the internal network through
+// their webhooks, whatever the address resolves to at the moment
+func NewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: control,
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:               nil, //the proxy would dial the host instead
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: dialTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     30 * time.Second,
+		},
+		//a redirect is a response of its own, so it fails the delivery
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: requestTimeout,
+	}
+}
+
+// Sign makes the value of HeaderSignature, so that the receiver can check the body with the shared secret
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (webhookNotifier *WebhookNotifier) Notify(notificationChannel *models.NotificationChannel,
+	notification *models.Notification) error {
+	if notificationChannel.Address == nil {
+		return errNoAddress
+	}
+	//the addresses saved before https was required are still in the database
+	if url_, err := url.Parse(*notificationChannel.Address); err != nil || url_.Scheme != "https" {
+		return errInsecureAddress
+	}
+
+	body, err := json.Marshal(&webhookNotification{
+		UserId:       notification.UserId,
+		Notification: notification,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, *notificationChannel.Address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderSignature, Sign(webhookNotifier.secret, body))
+
+	response_, err := webhookNotifier.client.Do(request)
+	if err != nil {
+		return err
+	}
+	_ = response_.Body.Close()
+	if response_.StatusCode < 200 || response_.StatusCode >= 300 {
+		return fmt.Errorf("cannot access webhook: response code = %d", response_.StatusCode)
+	}
+
+	return nil
+}
+
+func control(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return errForbiddenHost
+	}
+
+	return nil
+}
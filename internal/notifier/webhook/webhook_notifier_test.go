@@ -0,0 +1,100 @@
+package webhook_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/notifier/webhook"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	const secret = "secret"
+
+	var body []byte
+	var signature string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ = ioutil.ReadAll(request.Body)
+		signature = request.Header.Get(webhook.HeaderSignature)
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhookNotifier := webhook.NewWebhookNotifier(secret, server.Client())
+
+	address := server.URL + "/handover"
+	notificationChannel := &models.NotificationChannel{
+		UserId:  101,
+		Channel: models.NotificationChannelKindWebhook,
+		Address: &address,
+	}
+	notification := &models.Notification{
+		Id:      3,
+		UserId:  notificationChannel.UserId,
+		Type:    models.NotificationTypeRouteMatch,
+		Payload: models.NotificationPayload{AdId: 1},
+	}
+
+	err := webhookNotifier.Notify(notificationChannel, notification)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"userId":101,"id":3,"type":"route_match","payload":{"adId":1},"read":false,`+
+		`"dateTime":"01.01.0001 00:00"}`, string(body))
+	assert.Equal(t, webhook.Sign([]byte(secret), body), signature)
+}
+
+func TestWebhookNotifier_Notify_insecureAddress(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	webhookNotifier := webhook.NewWebhookNotifier("secret", server.Client())
+
+	address := server.URL + "/handover"
+	notificationChannel := &models.NotificationChannel{
+		UserId:  101,
+		Channel: models.NotificationChannelKindWebhook,
+		Address: &address,
+	}
+	notification := &models.Notification{
+		Id:      3,
+		UserId:  notificationChannel.UserId,
+		Type:    models.NotificationTypeRouteMatch,
+		Payload: models.NotificationPayload{AdId: 1},
+	}
+
+	err := webhookNotifier.Notify(notificationChannel, notification)
+	assert.NotNil(t, err)
+	assert.False(t, requested)
+}
+
+func TestWebhookNotifier_Notify_loopbackHost(t *testing.T) {
+	requested := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	webhookNotifier := webhook.NewWebhookNotifier("secret", webhook.NewClient())
+
+	address := server.URL + "/handover"
+	notificationChannel := &models.NotificationChannel{
+		UserId:  101,
+		Channel: models.NotificationChannelKindWebhook,
+		Address: &address,
+	}
+	notification := &models.Notification{
+		Id:      3,
+		UserId:  notificationChannel.UserId,
+		Type:    models.NotificationTypeRouteMatch,
+		Payload: models.NotificationPayload{AdId: 1},
+	}
+
+	err := webhookNotifier.Notify(notificationChannel, notification)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "webhook host is not public")
+	assert.False(t, requested)
+}
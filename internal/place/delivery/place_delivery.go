@@ -0,0 +1,89 @@
+package delivery
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/place"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	"github.com/labstack/echo/v4"
+	"strings"
+)
+
+type PlaceDelivery struct {
+	placeUsecase place.Usecase
+}
+
+func NewPlaceDelivery(placeUsecase place.Usecase) *PlaceDelivery {
+	return &PlaceDelivery{
+		placeUsecase: placeUsecase,
+	}
+}
+
+func (placeDelivery *PlaceDelivery) Configure(echo_ *echo.Echo, middlewaresManager *middlewares.Manager) {
+	echo_.GET("/api/places/autocomplete", placeDelivery.HandlerPlacesAutocomplete(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/places/import", placeDelivery.HandlerPlacesImport(), middlewaresManager.AuthMiddleware.CheckAuth(),
+		middlewaresManager.AuthMiddleware.CheckAdmin())
+}
+
+func (placeDelivery *PlaceDelivery) HandlerPlacesImport() echo.HandlerFunc {
+	type PlaceImportRequest struct {
+		Name    *string           `json:"name" validate:"required,gte=2,lte=100"`
+		Aliases []string          `json:"aliases" validate:"omitempty,max=20,dive,gte=2,lte=100"`
+		Kind    *models.PlaceKind `json:"kind" validate:"required,eq=building|eq=dorm|eq=metro"`
+		Point   *models.GeoPoint  `json:"point" validate:"required"`
+	}
+
+	type PlacesImportRequest struct {
+		Places []*PlaceImportRequest `json:"places" validate:"required,min=1,max=1000,dive,required"`
+	}
+
+	return func(context echo.Context) error {
+		placesImportRequest := new(PlacesImportRequest)
+		if err := parser.ParseRequest(context, placesImportRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		places := make(models.Places, len(placesImportRequest.Places))
+		for i, placeImportRequest := range placesImportRequest.Places {
+			places[i] = &models.Place{
+				Name:    *placeImportRequest.Name,
+				Aliases: placeImportRequest.Aliases,
+				Kind:    *placeImportRequest.Kind,
+				Point:   *placeImportRequest.Point,
+			}
+			if places[i].Aliases == nil {
+				places[i].Aliases = make([]string, 0)
+			}
+		}
+
+		return responser.Respond(context, placeDelivery.placeUsecase.Import(&places))
+	}
+}
+
+func (placeDelivery *PlaceDelivery) HandlerPlacesAutocomplete() echo.HandlerFunc {
+	type PlacesAutocompleteRequest struct {
+		Query *string `query:"q" validate:"required,min=1,lte=100"`
+		Limit *uint32 `query:"limit" validate:"omitempty,min=1,max=50"`
+	}
+
+	return func(context echo.Context) error {
+		placesAutocompleteRequest := new(PlacesAutocompleteRequest)
+		if err := context.Bind(placesAutocompleteRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		//the query is trimmed before the validation, so a blank one is rejected instead of matching every place
+		if placesAutocompleteRequest.Query != nil {
+			*placesAutocompleteRequest.Query = strings.TrimSpace(*placesAutocompleteRequest.Query)
+		}
+		if err := context.Validate(placesAutocompleteRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		return responser.Respond(context, placeDelivery.placeUsecase.Autocomplete(*placesAutocompleteRequest.Query,
+			placesAutocompleteRequest.Limit))
+	}
+}
@@ -0,0 +1,192 @@
+package delivery_test
+
+import (
+	"encoding/json"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/place/delivery"
+	"github.com/TechnoHandOver/backend/internal/place/mock_place"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	HandoverValidator "github.com/TechnoHandOver/backend/internal/tools/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPlaceDelivery_HandlerPlacesImport(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPlaceUsecase := mock_place.NewMockUsecase(controller)
+	placeDelivery := delivery.NewPlaceDelivery(mockPlaceUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	placeDelivery.Configure(echo_, &middlewares.Manager{})
+
+	places := &models.Places{
+		&models.Place{
+			Name:    "Общежитие №10",
+			Aliases: []string{"Десятка"},
+			Kind:    models.PlaceKindDorm,
+			Point:   models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		},
+		&models.Place{
+			Name:    "Бауманская",
+			Aliases: []string{},
+			Kind:    models.PlaceKindMetro,
+			Point:   models.GeoPoint{Lat: 55.772, Lon: 37.679},
+		},
+	}
+	expectedPlaces := &models.Places{
+		&models.Place{
+			Id:      1,
+			Name:    (*places)[0].Name,
+			Aliases: (*places)[0].Aliases,
+			Kind:    (*places)[0].Kind,
+			Point:   (*places)[0].Point,
+		},
+		&models.Place{
+			Id:      2,
+			Name:    (*places)[1].Name,
+			Aliases: (*places)[1].Aliases,
+			Kind:    (*places)[1].Kind,
+			Point:   (*places)[1].Point,
+		},
+	}
+
+	mockPlaceUsecase.
+		EXPECT().
+		Import(gomock.Eq(places)).
+		Return(response.NewResponse(consts.OK, expectedPlaces))
+
+	jsonRequest, err := json.Marshal(struct {
+		Places *models.Places `json:"places"`
+	}{places})
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedPlaces,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/api/places/import", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := placeDelivery.HandlerPlacesImport()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestPlaceDelivery_HandlerPlacesImport_invalidKind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPlaceUsecase := mock_place.NewMockUsecase(controller)
+	placeDelivery := delivery.NewPlaceDelivery(mockPlaceUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	placeDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPost, "/api/places/import", strings.NewReader(
+		`{"places":[{"name":"Бауманская","kind":"station","point":{"lat":55.772,"lon":37.679}}]}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := placeDelivery.HandlerPlacesImport()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestPlaceDelivery_HandlerPlacesAutocomplete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPlaceUsecase := mock_place.NewMockUsecase(controller)
+	placeDelivery := delivery.NewPlaceDelivery(mockPlaceUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	placeDelivery.Configure(echo_, &middlewares.Manager{})
+
+	expectedPlaces := &models.Places{
+		&models.Place{
+			Id:      1,
+			Name:    "Бауманская",
+			Aliases: []string{},
+			Kind:    models.PlaceKindMetro,
+			Point:   models.GeoPoint{Lat: 55.772, Lon: 37.679},
+		},
+	}
+
+	mockPlaceUsecase.
+		EXPECT().
+		Autocomplete(gomock.Eq("бау"), gomock.Nil()).
+		Return(response.NewResponse(consts.OK, expectedPlaces))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedPlaces,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/api/places/autocomplete?q=%20%D0%B1%D0%B0%D1%83%20", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := placeDelivery.HandlerPlacesAutocomplete()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestPlaceDelivery_HandlerPlacesAutocomplete_blankQuery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPlaceUsecase := mock_place.NewMockUsecase(controller)
+	placeDelivery := delivery.NewPlaceDelivery(mockPlaceUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	placeDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodGet, "/api/places/autocomplete?q=%20%20", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := placeDelivery.HandlerPlacesAutocomplete()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,8 @@
+package place
+
+import "github.com/TechnoHandOver/backend/internal/models"
+
+type Repository interface {
+	InsertOrUpdateArray(places *models.Places) (*models.Places, error)
+	SelectArrayByQuery(query string, limit uint32) (*models.Places, error)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/place"
+	"github.com/lib/pq"
+	"strings"
+)
+
+type PlaceRepository struct {
+	db *sql.DB
+}
+
+func NewPlaceRepositoryImpl(db *sql.DB) place.Repository {
+	return &PlaceRepository{
+		db: db,
+	}
+}
+
+func (placeRepository *PlaceRepository) InsertOrUpdateArray(places *models.Places) (*models.Places, error) {
+	const query = `
+INSERT INTO place (name, aliases, kind, point)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (name) DO UPDATE SET aliases = excluded.aliases, kind = excluded.kind, point = excluded.point
+RETURNING id, name, aliases, kind, point`
+
+	tx, err := placeRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, place_ := range *places {
+		if err := tx.QueryRow(query, place_.Name, pq.Array(place_.Aliases), place_.Kind, place_.Point).Scan(&place_.Id,
+			&place_.Name, pq.Array(&place_.Aliases), &place_.Kind, &place_.Point); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return places, nil
+}
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (placeRepository *PlaceRepository) SelectArrayByQuery(query string, limit uint32) (*models.Places, error) {
+	//prefix matches on the name or any alias go first, then the closest fuzzy (trigram) matches
+	const query_ = `
+SELECT id, name, aliases, kind, point
+FROM place, LATERAL (SELECT bool_or(lower(name_) LIKE $1 || '%') AS prefix,
+                            max(word_similarity($2, lower(name_))) AS similarity
+                     FROM unnest(array_prepend(name, aliases)) AS name_) AS match_
+WHERE match_.prefix OR match_.similarity >= 0.4
+ORDER BY match_.prefix DESC, match_.similarity DESC, name
+LIMIT $3`
+
+	rows, err := placeRepository.db.Query(query_, likePatternEscaper.Replace(query), query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	places := make(models.Places, 0)
+	for rows.Next() {
+		place_ := new(models.Place)
+		if err := rows.Scan(&place_.Id, &place_.Name, pq.Array(&place_.Aliases), &place_.Kind,
+			&place_.Point); err != nil {
+			return nil, err
+		}
+
+		places = append(places, place_)
+	}
+
+	return &places, nil
+}
@@ -0,0 +1,108 @@
+package repository_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/place/repository"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestPlaceRepository_InsertOrUpdateArray(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	placeRepository := repository.NewPlaceRepositoryImpl(db)
+
+	places := &models.Places{
+		&models.Place{
+			Name:    "Общежитие №10",
+			Aliases: []string{"Десятка", "Общага 10"},
+			Kind:    models.PlaceKindDorm,
+			Point:   models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		},
+		&models.Place{
+			Name:    "Бауманская",
+			Aliases: []string{},
+			Kind:    models.PlaceKindMetro,
+			Point:   models.GeoPoint{Lat: 55.772, Lon: 37.679},
+		},
+	}
+	expectedPlaces := &models.Places{
+		&models.Place{
+			Id:      1,
+			Name:    (*places)[0].Name,
+			Aliases: (*places)[0].Aliases,
+			Kind:    (*places)[0].Kind,
+			Point:   (*places)[0].Point,
+		},
+		&models.Place{
+			Id:      2,
+			Name:    (*places)[1].Name,
+			Aliases: (*places)[1].Aliases,
+			Kind:    (*places)[1].Kind,
+			Point:   (*places)[1].Point,
+		},
+	}
+
+	sqlmock_.ExpectBegin()
+	for _, expectedPlace := range *expectedPlaces {
+		sqlmock_.
+			ExpectQuery("INSERT INTO place").
+			WithArgs(expectedPlace.Name, pq.Array(expectedPlace.Aliases), expectedPlace.Kind, expectedPlace.Point).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "aliases", "kind", "point"}).
+				AddRow(expectedPlace.Id, expectedPlace.Name, pq.Array(expectedPlace.Aliases), expectedPlace.Kind,
+					expectedPlace.Point))
+	}
+	sqlmock_.ExpectCommit()
+
+	resultPlaces, resultErr := placeRepository.InsertOrUpdateArray(places)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedPlaces, resultPlaces)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestPlaceRepository_SelectArrayByQuery(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	placeRepository := repository.NewPlaceRepositoryImpl(db)
+
+	query := "общ_10%"
+	var limit uint32 = 10
+	expectedPlaces := &models.Places{
+		&models.Place{
+			Id:      1,
+			Name:    "Общежитие №10",
+			Aliases: []string{"Десятка", "Общага 10"},
+			Kind:    models.PlaceKindDorm,
+			Point:   models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "name", "aliases", "kind", "point"})
+	for _, expectedPlace := range *expectedPlaces {
+		rows.AddRow(expectedPlace.Id, expectedPlace.Name, pq.Array(expectedPlace.Aliases), expectedPlace.Kind,
+			expectedPlace.Point)
+	}
+	sqlmock_.
+		ExpectQuery(regexp.QuoteMeta("FROM place")).
+		WithArgs(`общ\_10\%`, query, limit).
+		WillReturnRows(rows)
+
+	resultPlaces, resultErr := placeRepository.SelectArrayByQuery(query, limit)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedPlaces, resultPlaces)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
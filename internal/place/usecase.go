@@ -0,0 +1,11 @@
+package place
+
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+)
+
+type Usecase interface {
+	Import(places *models.Places) *response.Response
+	Autocomplete(query string, limit *uint32) *response.Response
+}
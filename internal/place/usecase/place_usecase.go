@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/place"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"strings"
+)
+
+const placesAutocompleteDefaultLimit uint32 = 10
+
+type PlaceUsecase struct {
+	placeRepository place.Repository
+}
+
+func NewPlaceUsecaseImpl(placeRepository place.Repository) place.Usecase {
+	return &PlaceUsecase{
+		placeRepository: placeRepository,
+	}
+}
+
+func (placeUsecase *PlaceUsecase) Import(places *models.Places) *response.Response {
+	places, err := placeUsecase.placeRepository.InsertOrUpdateArray(places)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, places)
+}
+
+func (placeUsecase *PlaceUsecase) Autocomplete(query string, limit *uint32) *response.Response {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	limit_ := placesAutocompleteDefaultLimit
+	if limit != nil {
+		limit_ = *limit
+	}
+
+	places, err := placeUsecase.placeRepository.SelectArrayByQuery(query, limit_)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, places)
+}
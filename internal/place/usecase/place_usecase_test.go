@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/place/mock_place"
+	"github.com/TechnoHandOver/backend/internal/place/usecase"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/golang/mock/gomock"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPlaceUsecase_Import(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPlaceRepository := mock_place.NewMockRepository(controller)
+	placeUsecase := usecase.NewPlaceUsecaseImpl(mockPlaceRepository)
+
+	places := &models.Places{
+		&models.Place{
+			Name:    "Общежитие №10",
+			Aliases: []string{"Десятка"},
+			Kind:    models.PlaceKindDorm,
+			Point:   models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		},
+	}
+	expectedPlaces := &models.Places{
+		&models.Place{
+			Id:      1,
+			Name:    (*places)[0].Name,
+			Aliases: (*places)[0].Aliases,
+			Kind:    (*places)[0].Kind,
+			Point:   (*places)[0].Point,
+		},
+	}
+
+	mockPlaceRepository.
+		EXPECT().
+		InsertOrUpdateArray(gomock.Eq(places)).
+		Return(expectedPlaces, nil)
+
+	response_ := placeUsecase.Import(places)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedPlaces), response_)
+}
+
+func TestPlaceUsecase_Autocomplete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPlaceRepository := mock_place.NewMockRepository(controller)
+	placeUsecase := usecase.NewPlaceUsecaseImpl(mockPlaceRepository)
+
+	expectedPlaces := &models.Places{
+		&models.Place{
+			Id:      1,
+			Name:    "Общежитие №10",
+			Aliases: []string{"Десятка"},
+			Kind:    models.PlaceKindDorm,
+			Point:   models.GeoPoint{Lat: 55.752, Lon: 37.681},
+		},
+	}
+
+	mockPlaceRepository.
+		EXPECT().
+		SelectArrayByQuery(gomock.Eq("общ"), gomock.Eq(uint32(5))).
+		Return(expectedPlaces, nil)
+
+	response_ := placeUsecase.Autocomplete("  Общ ", pointy.Uint32(5))
+	assert.Equal(t, response.NewResponse(consts.OK, expectedPlaces), response_)
+}
+
+func TestPlaceUsecase_Autocomplete_defaultLimit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPlaceRepository := mock_place.NewMockRepository(controller)
+	placeUsecase := usecase.NewPlaceUsecaseImpl(mockPlaceRepository)
+
+	expectedPlaces := &models.Places{}
+
+	mockPlaceRepository.
+		EXPECT().
+		SelectArrayByQuery(gomock.Eq("бауманская"), gomock.Eq(uint32(10))).
+		Return(expectedPlaces, nil)
+
+	response_ := placeUsecase.Autocomplete("Бауманская", nil)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedPlaces), response_)
+}
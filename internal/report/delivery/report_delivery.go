@@ -0,0 +1,113 @@
+package delivery
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/report"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	"github.com/labstack/echo/v4"
+)
+
+type ReportDelivery struct {
+	reportUsecase report.Usecase
+}
+
+func NewReportDelivery(reportUsecase report.Usecase) *ReportDelivery {
+	return &ReportDelivery{
+		reportUsecase: reportUsecase,
+	}
+}
+
+func (reportDelivery *ReportDelivery) Configure(echo_ *echo.Echo, middlewaresManager *middlewares.Manager) {
+	echo_.POST("/api/ads/:id/report", reportDelivery.HandlerReportCreate(true), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/users/:id/report", reportDelivery.HandlerReportCreate(false), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/reports/list", reportDelivery.HandlerReportsList(), middlewaresManager.AuthMiddleware.CheckAuth(),
+		middlewaresManager.AuthMiddleware.CheckAdmin())
+	echo_.POST("/api/reports/:id/resolution", reportDelivery.HandlerReportResolve(), middlewaresManager.AuthMiddleware.CheckAuth(),
+		middlewaresManager.AuthMiddleware.CheckAdmin())
+	echo_.POST("/api/reports/:id/dismissal", reportDelivery.HandlerReportDismiss(), middlewaresManager.AuthMiddleware.CheckAuth(),
+		middlewaresManager.AuthMiddleware.CheckAdmin())
+}
+
+func (reportDelivery *ReportDelivery) HandlerReportCreate(targetAd bool) echo.HandlerFunc {
+	type ReportCreateRequest struct {
+		TargetId *uint32              `param:"id" validate:"required"`
+		Reason   *models.ReportReason `json:"reason" validate:"required,eq=scam|eq=offensive|eq=spam|eq=prohibited|eq=other"`
+		Comment  *string              `json:"comment" validate:"omitempty,lte=300"`
+	}
+
+	return func(context echo.Context) error {
+		reportCreateRequest := new(ReportCreateRequest)
+		if err := parser.ParseRequest(context, reportCreateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		report_ := &models.Report{
+			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
+			Reason:       *reportCreateRequest.Reason,
+			Comment:      parser.GetOrDefault(reportCreateRequest.Comment, "").(string),
+		}
+		if targetAd {
+			report_.AdId = reportCreateRequest.TargetId
+		} else {
+			report_.UserTargetId = reportCreateRequest.TargetId
+		}
+
+		return responser.Respond(context, reportDelivery.reportUsecase.Create(report_))
+	}
+}
+
+func (reportDelivery *ReportDelivery) HandlerReportsList() echo.HandlerFunc {
+	type ReportsListRequest struct {
+		Status *models.ReportStatus `query:"status" validate:"omitempty,eq=pending|eq=resolved|eq=dismissed"`
+	}
+
+	return func(context echo.Context) error {
+		reportsListRequest := new(ReportsListRequest)
+		if err := parser.ParseRequest(context, reportsListRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		status := models.ReportStatusPending
+		if reportsListRequest.Status != nil {
+			status = *reportsListRequest.Status
+		}
+
+		return responser.Respond(context, reportDelivery.reportUsecase.List(status))
+	}
+}
+
+func (reportDelivery *ReportDelivery) HandlerReportResolve() echo.HandlerFunc {
+	type ReportResolveRequest struct {
+		Id     *uint32              `param:"id" validate:"required"`
+		Action *models.ReportAction `json:"action" validate:"omitempty,eq=hide_ad|eq=suspend_user"`
+	}
+
+	return func(context echo.Context) error {
+		reportResolveRequest := new(ReportResolveRequest)
+		if err := parser.ParseRequest(context, reportResolveRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		return responser.Respond(context, reportDelivery.reportUsecase.Resolve(*reportResolveRequest.Id,
+			reportResolveRequest.Action))
+	}
+}
+
+func (reportDelivery *ReportDelivery) HandlerReportDismiss() echo.HandlerFunc {
+	type ReportDismissRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		reportDismissRequest := new(ReportDismissRequest)
+		if err := parser.ParseRequest(context, reportDismissRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		return responser.Respond(context, reportDelivery.reportUsecase.Dismiss(*reportDismissRequest.Id))
+	}
+}
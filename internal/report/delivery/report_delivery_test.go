@@ -0,0 +1,168 @@
+package delivery_test
+
+import (
+	"encoding/json"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/middlewares"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/report/delivery"
+	"github.com/TechnoHandOver/backend/internal/report/mock_report"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/TechnoHandOver/backend/internal/tools/responser"
+	HandoverValidator "github.com/TechnoHandOver/backend/internal/tools/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReportDelivery_HandlerReportCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportUsecase := mock_report.NewMockUsecase(controller)
+	reportDelivery := delivery.NewReportDelivery(mockReportUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	reportDelivery.Configure(echo_, &middlewares.Manager{})
+
+	report := &models.Report{
+		UserAuthorId: 101,
+		AdId:         pointy.Uint32(1),
+		Reason:       models.ReportReasonScam,
+		Comment:      "Просит предоплату",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedReport := &models.Report{
+		Id:           1,
+		UserAuthorId: report.UserAuthorId,
+		AdId:         report.AdId,
+		Reason:       report.Reason,
+		Comment:      report.Comment,
+		Status:       models.ReportStatusPending,
+		DateTime:     *dateTime,
+	}
+
+	mockReportUsecase.
+		EXPECT().
+		Create(gomock.Eq(report)).
+		Return(response.NewResponse(consts.Created, expectedReport))
+
+	jsonRequest, err := json.Marshal(report)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedReport,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/ads/:id/report")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(*report.AdId), 10))
+	context.Set(consts.EchoContextKeyUserId, report.UserAuthorId)
+
+	handler := reportDelivery.HandlerReportCreate(true)
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestReportDelivery_HandlerReportCreate_badReason(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportUsecase := mock_report.NewMockUsecase(controller)
+	reportDelivery := delivery.NewReportDelivery(mockReportUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	reportDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reason":"boring"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/:id/report")
+	context.SetParamNames("id")
+	context.SetParamValues("102")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := reportDelivery.HandlerReportCreate(false)
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestReportDelivery_HandlerReportResolve(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportUsecase := mock_report.NewMockUsecase(controller)
+	reportDelivery := delivery.NewReportDelivery(mockReportUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	reportDelivery.Configure(echo_, &middlewares.Manager{})
+
+	action := models.ReportActionSuspendUser
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedReport := &models.Report{
+		Id:           1,
+		UserAuthorId: 101,
+		UserTargetId: pointy.Uint32(102),
+		Reason:       models.ReportReasonOffensive,
+		Status:       models.ReportStatusResolved,
+		Action:       &action,
+		DateTime:     *dateTime,
+	}
+
+	mockReportUsecase.
+		EXPECT().
+		Resolve(gomock.Eq(expectedReport.Id), gomock.Eq(&action)).
+		Return(response.NewResponse(consts.OK, expectedReport))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedReport,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"action":"suspend_user"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/reports/:id/resolution")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedReport.Id), 10))
+
+	handler := reportDelivery.HandlerReportResolve()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
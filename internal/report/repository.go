@@ -0,0 +1,10 @@
+package report
+
+import "github.com/TechnoHandOver/backend/internal/models"
+
+type Repository interface {
+	Insert(report *models.Report) (*models.Report, error)
+	Select(id uint32) (*models.Report, error)
+	SelectArrayByStatus(status models.ReportStatus) (*models.Reports, error)
+	UpdateStatus(id uint32, status models.ReportStatus, newStatus models.ReportStatus, action *models.ReportAction) (*models.Report, error)
+}
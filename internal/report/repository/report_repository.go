@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/report"
+	"github.com/lib/pq"
+)
+
+type ReportRepository struct {
+	db *sql.DB
+}
+
+func NewReportRepositoryImpl(db *sql.DB) report.Repository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+func (reportRepository *ReportRepository) Insert(report_ *models.Report) (*models.Report, error) {
+	const query = `
+INSERT INTO report (user_author_id, ad_id, user_target_id, reason, comment)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_author_id, ad_id, user_target_id, reason, comment, status, action, date_time`
+
+	if err := reportRepository.db.QueryRow(query, report_.UserAuthorId, report_.AdId, report_.UserTargetId,
+		report_.Reason, report_.Comment).Scan(&report_.Id, &report_.UserAuthorId, &report_.AdId,
+		&report_.UserTargetId, &report_.Reason, &report_.Comment, &report_.Status, &report_.Action,
+		&report_.DateTime); err != nil {
+		if err_, ok := err.(*pq.Error); ok {
+			switch err_.Code {
+			case "23503":
+				return nil, consts.RepErrNotFound
+			case "23505":
+				return nil, consts.RepErrConflict
+			}
+		}
+
+		return nil, err
+	}
+
+	return report_, nil
+}
+
+func (reportRepository *ReportRepository) Select(id uint32) (*models.Report, error) {
+	const query = `
+SELECT report.id, report.user_author_id, report.ad_id, ad.user_author_id, report.user_target_id, report.reason, report.comment, report.status, report.action, report.date_time
+FROM report
+    LEFT JOIN ad ON ad.id = report.ad_id
+WHERE report.id = $1`
+
+	report_ := new(models.Report)
+	if err := reportRepository.db.QueryRow(query, id).Scan(&report_.Id, &report_.UserAuthorId, &report_.AdId,
+		&report_.AdUserAuthorId, &report_.UserTargetId, &report_.Reason, &report_.Comment, &report_.Status,
+		&report_.Action, &report_.DateTime); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return report_, nil
+}
+
+func (reportRepository *ReportRepository) SelectArrayByStatus(status models.ReportStatus) (*models.Reports, error) {
+	const query = `
+SELECT report.id, report.user_author_id, report.ad_id, ad.user_author_id, report.user_target_id, report.reason, report.comment, report.status, report.action, report.date_time
+FROM report
+    LEFT JOIN ad ON ad.id = report.ad_id
+WHERE report.status = $1
+ORDER BY report.date_time, report.id`
+
+	rows, err := reportRepository.db.Query(query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	reports := make(models.Reports, 0)
+	for rows.Next() {
+		report_ := new(models.Report)
+		if err := rows.Scan(&report_.Id, &report_.UserAuthorId, &report_.AdId, &report_.AdUserAuthorId,
+			&report_.UserTargetId, &report_.Reason, &report_.Comment, &report_.Status, &report_.Action,
+			&report_.DateTime); err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report_)
+	}
+
+	return &reports, nil
+}
+
+func (reportRepository *ReportRepository) UpdateStatus(id uint32, status models.ReportStatus, newStatus models.ReportStatus, action *models.ReportAction) (*models.Report, error) {
+	const query = `
+UPDATE report SET status = $3, action = $4
+WHERE id = $1 AND status = $2
+RETURNING id, user_author_id, ad_id, user_target_id, reason, comment, status, action, date_time`
+	const queryHideAd = "UPDATE ad SET hidden = TRUE, hidden_by_suspension = FALSE WHERE id = $1"
+	const querySuspendUser = `
+UPDATE user_ SET suspended = TRUE
+WHERE id = coalesce($1, (SELECT ad.user_author_id FROM ad WHERE ad.id = $2))`
+
+	tx, err := reportRepository.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	report_ := new(models.Report)
+	if err := tx.QueryRow(query, id, status, newStatus, action).Scan(&report_.Id, &report_.UserAuthorId,
+		&report_.AdId, &report_.UserTargetId, &report_.Reason, &report_.Comment, &report_.Status, &report_.Action,
+		&report_.DateTime); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	if action != nil {
+		//the action is applied in the same transaction, so a report is never resolved without its effect
+		switch *action {
+		case models.ReportActionHideAd:
+			_, err = tx.Exec(queryHideAd, report_.AdId)
+		case models.ReportActionSuspendUser:
+			_, err = tx.Exec(querySuspendUser, report_.UserTargetId, report_.AdId)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return report_, nil
+}
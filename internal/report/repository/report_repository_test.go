@@ -0,0 +1,212 @@
+package repository_test
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/report/repository"
+	"github.com/lib/pq"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReportRepository_Insert(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	reportRepository := repository.NewReportRepositoryImpl(db)
+
+	report := &models.Report{
+		UserAuthorId: 101,
+		AdId:         pointy.Uint32(1),
+		Reason:       models.ReportReasonScam,
+		Comment:      "Просит предоплату",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedReport := &models.Report{
+		Id:           1,
+		UserAuthorId: report.UserAuthorId,
+		AdId:         report.AdId,
+		Reason:       report.Reason,
+		Comment:      report.Comment,
+		Status:       models.ReportStatusPending,
+		DateTime:     *dateTime,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO report").
+		WithArgs(report.UserAuthorId, report.AdId, report.UserTargetId, report.Reason, report.Comment).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_author_id", "ad_id", "user_target_id", "reason", "comment", "status",
+				"action", "date_time"}).
+				AddRow(expectedReport.Id, expectedReport.UserAuthorId, *expectedReport.AdId, nil, expectedReport.Reason,
+					expectedReport.Comment, expectedReport.Status, nil, time.Time(expectedReport.DateTime)))
+
+	resultReport, resultErr := reportRepository.Insert(report)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedReport, resultReport)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestReportRepository_Insert_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	reportRepository := repository.NewReportRepositoryImpl(db)
+
+	report := &models.Report{
+		UserAuthorId: 101,
+		UserTargetId: pointy.Uint32(102),
+		Reason:       models.ReportReasonSpam,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO report").
+		WithArgs(report.UserAuthorId, report.AdId, report.UserTargetId, report.Reason, report.Comment).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	resultReport, resultErr := reportRepository.Insert(report)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultReport)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestReportRepository_SelectArrayByStatus(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	reportRepository := repository.NewReportRepositoryImpl(db)
+
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedReports := &models.Reports{
+		&models.Report{
+			Id:             1,
+			UserAuthorId:   101,
+			AdId:           pointy.Uint32(1),
+			AdUserAuthorId: pointy.Uint32(103),
+			Reason:         models.ReportReasonScam,
+			Comment:        "Просит предоплату",
+			Status:         models.ReportStatusPending,
+			DateTime:       *dateTime,
+		},
+		&models.Report{
+			Id:           2,
+			UserAuthorId: 101,
+			UserTargetId: pointy.Uint32(102),
+			Reason:       models.ReportReasonOffensive,
+			Comment:      "",
+			Status:       models.ReportStatusPending,
+			DateTime:     *dateTime,
+		},
+	}
+
+	sqlmock_.
+		ExpectQuery("SELECT .+ FROM report LEFT JOIN ad ON ad.id = report.ad_id WHERE report.status = \\$1").
+		WithArgs(models.ReportStatusPending).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_author_id", "ad_id", "user_author_id", "user_target_id", "reason",
+				"comment", "status", "action", "date_time"}).
+				AddRow((*expectedReports)[0].Id, (*expectedReports)[0].UserAuthorId, *(*expectedReports)[0].AdId,
+					*(*expectedReports)[0].AdUserAuthorId, nil, (*expectedReports)[0].Reason,
+					(*expectedReports)[0].Comment, (*expectedReports)[0].Status, nil,
+					time.Time((*expectedReports)[0].DateTime)).
+				AddRow((*expectedReports)[1].Id, (*expectedReports)[1].UserAuthorId, nil, nil,
+					*(*expectedReports)[1].UserTargetId, (*expectedReports)[1].Reason, (*expectedReports)[1].Comment,
+					(*expectedReports)[1].Status, nil, time.Time((*expectedReports)[1].DateTime)))
+
+	resultReports, resultErr := reportRepository.SelectArrayByStatus(models.ReportStatusPending)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedReports, resultReports)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestReportRepository_UpdateStatus(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	reportRepository := repository.NewReportRepositoryImpl(db)
+
+	action := models.ReportActionHideAd
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedReport := &models.Report{
+		Id:           1,
+		UserAuthorId: 101,
+		AdId:         pointy.Uint32(1),
+		Reason:       models.ReportReasonScam,
+		Comment:      "Просит предоплату",
+		Status:       models.ReportStatusResolved,
+		Action:       &action,
+		DateTime:     *dateTime,
+	}
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("UPDATE report SET status").
+		WithArgs(expectedReport.Id, models.ReportStatusPending, expectedReport.Status, expectedReport.Action).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_author_id", "ad_id", "user_target_id", "reason", "comment", "status",
+				"action", "date_time"}).
+				AddRow(expectedReport.Id, expectedReport.UserAuthorId, *expectedReport.AdId, nil, expectedReport.Reason,
+					expectedReport.Comment, expectedReport.Status, *expectedReport.Action,
+					time.Time(expectedReport.DateTime)))
+	sqlmock_.
+		ExpectExec("UPDATE ad SET hidden = TRUE, hidden_by_suspension = FALSE WHERE id = \\$1").
+		WithArgs(*expectedReport.AdId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlmock_.ExpectCommit()
+
+	resultReport, resultErr := reportRepository.UpdateStatus(expectedReport.Id, models.ReportStatusPending,
+		expectedReport.Status, expectedReport.Action)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedReport, resultReport)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestReportRepository_UpdateStatus_notFound(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	reportRepository := repository.NewReportRepositoryImpl(db)
+
+	const id uint32 = 1
+
+	sqlmock_.ExpectBegin()
+	sqlmock_.
+		ExpectQuery("UPDATE report SET status").
+		WithArgs(id, models.ReportStatusPending, models.ReportStatusDismissed, nil).
+		WillReturnError(sql.ErrNoRows)
+	sqlmock_.ExpectRollback()
+
+	resultReport, resultErr := reportRepository.UpdateStatus(id, models.ReportStatusPending,
+		models.ReportStatusDismissed, nil)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+	assert.Nil(t, resultReport)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
@@ -0,0 +1,13 @@
+package report
+
+import (
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+)
+
+type Usecase interface {
+	Create(report *models.Report) *response.Response
+	List(status models.ReportStatus) *response.Response
+	Resolve(id uint32, action *models.ReportAction) *response.Response
+	Dismiss(id uint32) *response.Response
+}
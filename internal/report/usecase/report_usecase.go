@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/report"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+)
+
+type ReportUsecase struct {
+	reportRepository report.Repository
+}
+
+func NewReportUsecaseImpl(reportRepository report.Repository) report.Usecase {
+	return &ReportUsecase{
+		reportRepository: reportRepository,
+	}
+}
+
+func (reportUsecase *ReportUsecase) Create(report_ *models.Report) *response.Response {
+	if report_.UserTargetId != nil && *report_.UserTargetId == report_.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	report_, err := reportUsecase.reportRepository.Insert(report_)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, report_)
+}
+
+func (reportUsecase *ReportUsecase) List(status models.ReportStatus) *response.Response {
+	reports, err := reportUsecase.reportRepository.SelectArrayByStatus(status)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, reports)
+}
+
+func (reportUsecase *ReportUsecase) Resolve(id uint32, action *models.ReportAction) *response.Response {
+	return reportUsecase.updateStatus(id, models.ReportStatusResolved, action)
+}
+
+func (reportUsecase *ReportUsecase) Dismiss(id uint32) *response.Response {
+	return reportUsecase.updateStatus(id, models.ReportStatusDismissed, nil)
+}
+
+func (reportUsecase *ReportUsecase) updateStatus(id uint32, newStatus models.ReportStatus, action *models.ReportAction) *response.Response {
+	report_, err := reportUsecase.reportRepository.Select(id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if report_.Status != models.ReportStatusPending {
+		return response.NewEmptyResponse(consts.Conflict)
+	}
+	if action != nil && *action == models.ReportActionHideAd && report_.AdId == nil {
+		return response.NewEmptyResponse(consts.BadRequest)
+	}
+
+	updatedReport, err := reportUsecase.reportRepository.UpdateStatus(id, report_.Status, newStatus, action)
+	if err != nil {
+		if err == consts.RepErrNotFound { //status has been changed concurrently
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	updatedReport.AdUserAuthorId = report_.AdUserAuthorId
+	return response.NewResponse(consts.OK, updatedReport)
+}
@@ -0,0 +1,170 @@
+package usecase_test
+
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/report/mock_report"
+	"github.com/TechnoHandOver/backend/internal/report/usecase"
+	"github.com/TechnoHandOver/backend/internal/tools/response"
+	"github.com/golang/mock/gomock"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReportUsecase_Create(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportRepository := mock_report.NewMockRepository(controller)
+	reportUsecase := usecase.NewReportUsecaseImpl(mockReportRepository)
+
+	report := &models.Report{
+		UserAuthorId: 101,
+		AdId:         pointy.Uint32(1),
+		Reason:       models.ReportReasonScam,
+		Comment:      "Просит предоплату",
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedReport := &models.Report{
+		Id:           1,
+		UserAuthorId: report.UserAuthorId,
+		AdId:         report.AdId,
+		Reason:       report.Reason,
+		Comment:      report.Comment,
+		Status:       models.ReportStatusPending,
+		DateTime:     *dateTime,
+	}
+
+	mockReportRepository.
+		EXPECT().
+		Insert(gomock.Eq(report)).
+		Return(expectedReport, nil)
+
+	response_ := reportUsecase.Create(report)
+	assert.Equal(t, response.NewResponse(consts.Created, expectedReport), response_)
+}
+
+func TestReportUsecase_Create_self(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportRepository := mock_report.NewMockRepository(controller)
+	reportUsecase := usecase.NewReportUsecaseImpl(mockReportRepository)
+
+	report := &models.Report{
+		UserAuthorId: 101,
+		UserTargetId: pointy.Uint32(101),
+		Reason:       models.ReportReasonSpam,
+	}
+
+	response_ := reportUsecase.Create(report)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestReportUsecase_Resolve(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportRepository := mock_report.NewMockRepository(controller)
+	reportUsecase := usecase.NewReportUsecaseImpl(mockReportRepository)
+
+	action := models.ReportActionHideAd
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	report := &models.Report{
+		Id:             1,
+		UserAuthorId:   101,
+		AdId:           pointy.Uint32(1),
+		AdUserAuthorId: pointy.Uint32(103),
+		Reason:         models.ReportReasonScam,
+		Comment:        "Просит предоплату",
+		Status:         models.ReportStatusPending,
+		DateTime:       *dateTime,
+	}
+	expectedReport := &models.Report{
+		Id:             report.Id,
+		UserAuthorId:   report.UserAuthorId,
+		AdId:           report.AdId,
+		AdUserAuthorId: report.AdUserAuthorId,
+		Reason:         report.Reason,
+		Comment:        report.Comment,
+		Status:         models.ReportStatusResolved,
+		Action:         &action,
+		DateTime:       report.DateTime,
+	}
+
+	callSelect := mockReportRepository.
+		EXPECT().
+		Select(gomock.Eq(report.Id)).
+		Return(report, nil)
+	mockReportRepository.
+		EXPECT().
+		UpdateStatus(gomock.Eq(report.Id), gomock.Eq(models.ReportStatusPending),
+			gomock.Eq(models.ReportStatusResolved), gomock.Eq(&action)).
+		Return(&models.Report{
+			Id:           expectedReport.Id,
+			UserAuthorId: expectedReport.UserAuthorId,
+			AdId:         expectedReport.AdId,
+			Reason:       expectedReport.Reason,
+			Comment:      expectedReport.Comment,
+			Status:       expectedReport.Status,
+			Action:       expectedReport.Action,
+			DateTime:     expectedReport.DateTime,
+		}, nil).
+		After(callSelect)
+
+	response_ := reportUsecase.Resolve(report.Id, &action)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedReport), response_)
+}
+
+func TestReportUsecase_Resolve_hideUser(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportRepository := mock_report.NewMockRepository(controller)
+	reportUsecase := usecase.NewReportUsecaseImpl(mockReportRepository)
+
+	action := models.ReportActionHideAd
+	report := &models.Report{
+		Id:           1,
+		UserAuthorId: 101,
+		UserTargetId: pointy.Uint32(102),
+		Reason:       models.ReportReasonOffensive,
+		Status:       models.ReportStatusPending,
+	}
+
+	mockReportRepository.
+		EXPECT().
+		Select(gomock.Eq(report.Id)).
+		Return(report, nil)
+
+	response_ := reportUsecase.Resolve(report.Id, &action)
+	assert.Equal(t, response.NewEmptyResponse(consts.BadRequest), response_)
+}
+
+func TestReportUsecase_Dismiss_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockReportRepository := mock_report.NewMockRepository(controller)
+	reportUsecase := usecase.NewReportUsecaseImpl(mockReportRepository)
+
+	report := &models.Report{
+		Id:           1,
+		UserAuthorId: 101,
+		UserTargetId: pointy.Uint32(102),
+		Reason:       models.ReportReasonOffensive,
+		Status:       models.ReportStatusResolved,
+	}
+
+	mockReportRepository.
+		EXPECT().
+		Select(gomock.Eq(report.Id)).
+		Return(report, nil)
+
+	response_ := reportUsecase.Dismiss(report.Id)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
@@ -0,0 +1,75 @@
+package etag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// AnyVersion is what If-Match: * stands for, versions start at 1, so it never names a real one
+const AnyVersion uint32 = 0
+
+var (
+	errETagFormat  = errors.New("ETag: invalid format")
+	errETagNoMatch = errors.New("ETag: no strong entity tag to match")
+)
+
+// Format makes a strong validator of entity version, that is "42"
+func Format(version uint32) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+// Parse reads the If-Match list; weak entity tags are skipped, since If-Match uses the strong comparison, so they
+// never match
+func Parse(ifMatch string) (uint32, error) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "*" {
+		return AnyVersion, nil
+	}
+
+	versions := make([]uint32, 0, 1)
+	for _, eTag := range strings.Split(ifMatch, ",") {
+		eTag = strings.TrimSpace(eTag)
+		if strings.HasPrefix(eTag, "W/") {
+			if !isQuoted(eTag[2:]) {
+				return 0, errETagFormat
+			}
+
+			continue
+		}
+
+		if !isQuoted(eTag) {
+			return 0, errETagFormat
+		}
+
+		version, err := strconv.ParseUint(eTag[1:len(eTag)-1], 10, 32)
+		if err != nil || version == uint64(AnyVersion) {
+			return 0, errETagFormat
+		}
+
+		if len(versions) == 0 || versions[0] != uint32(version) {
+			versions = append(versions, uint32(version))
+		}
+	}
+
+	//the entity has one current version only, so several different ones can't be checked with a single comparison
+	if len(versions) != 1 {
+		return 0, errETagNoMatch
+	}
+
+	return versions[0], nil
+}
+
+// Matches compares the version from If-Match with the current one
+func Matches(version uint32, currentVersion uint32) bool {
+	return version == AnyVersion || version == currentVersion
+}
+
+func isQuoted(eTag string) bool {
+	return len(eTag) >= 2 && eTag[0] == '"' && eTag[len(eTag)-1] == '"'
+}
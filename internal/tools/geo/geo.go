@@ -0,0 +1,11 @@
+package geo
+
+const earthRadiusM = "6371000"
+
+func DistanceExpression(point1 string, point2 string) string {
+	//haversine formula over PostgreSQL points of (lon,lat), in meters
+	return "(2 * " + earthRadiusM + " * asin(sqrt(" +
+		"power(sin(radians((" + point2 + ")[1] - (" + point1 + ")[1]) / 2), 2) + " +
+		"cos(radians((" + point1 + ")[1])) * cos(radians((" + point2 + ")[1])) * " +
+		"power(sin(radians((" + point2 + ")[0] - (" + point1 + ")[0]) / 2), 2))))"
+}
@@ -0,0 +1,49 @@
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
+var null = []byte("null")
+
+// Document is a JSON Merge Patch (RFC 7396): absent members stay unchanged, null members are removed
+type Document map[string]json.RawMessage
+
+func (document Document) Has(key string) bool {
+	_, ok := document[key]
+	return ok
+}
+
+func (document Document) IsNull(key string) bool {
+	value, ok := document[key]
+	return ok && bytes.Equal(bytes.TrimSpace(value), null)
+}
+
+func (document Document) IsAnyNull(keys ...string) bool {
+	for _, key := range keys {
+		if document.IsNull(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNullOrSuperseded reports whether key is removed explicitly or implicitly, that is when it is absent, but any of
+// supersedingKeys is present
+func (document Document) IsNullOrSuperseded(key string, supersedingKeys ...string) bool {
+	if document.Has(key) {
+		return document.IsNull(key)
+	}
+
+	for _, supersedingKey := range supersedingKeys {
+		if document.Has(supersedingKey) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/TechnoHandOver/backend/internal/tools/mergepatch"
+	"github.com/labstack/echo/v4"
+	"io/ioutil"
+	"strings"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("Unsupported media type\n")
+	errMergePatchNotObject  = errors.New("Merge patch document must be an object\n")
+)
+
+func ParseMergePatchRequest(context echo.Context, object interface{}) (mergepatch.Document, error) {
+	if err := (&echo.DefaultBinder{}).BindPathParams(context, object); err != nil {
+		return nil, err
+	}
+
+	contentType := context.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, mergepatch.MIMEApplicationMergePatchJSON) &&
+		!strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	body, err := ioutil.ReadAll(context.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var document mergepatch.Document
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, errMergePatchNotObject
+	}
+	if err := json.Unmarshal(body, object); err != nil {
+		return nil, err
+	}
+
+	return document, context.Validate(object)
+}
@@ -1,6 +1,12 @@
 package parser
 
-import "github.com/labstack/echo/v4"
+import (
+	"errors"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+	"github.com/labstack/echo/v4"
+)
+
+var ErrPreconditionRequired = errors.New("Precondition required\n")
 
 func ParseRequest(context echo.Context, object interface{}) error {
 	if err := context.Bind(object); err != nil {
@@ -9,3 +15,12 @@ func ParseRequest(context echo.Context, object interface{}) error {
 
 	return context.Validate(object)
 }
+
+func ParseIfMatch(context echo.Context) (uint32, error) {
+	ifMatch := context.Request().Header.Get(etag.HeaderIfMatch)
+	if ifMatch == "" {
+		return 0, ErrPreconditionRequired
+	}
+
+	return etag.Parse(ifMatch)
+}
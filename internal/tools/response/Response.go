@@ -1,11 +1,16 @@
 package response
 
-import "github.com/TechnoHandOver/backend/internal/consts"
+import (
+	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+)
 
 type Response struct {
-	Code  consts.Code
-	Data  interface{}
-	Error error
+	Code       consts.Code
+	Data       interface{}
+	NextCursor string
+	ETag       string
+	Error      error
 }
 
 func NewResponse(code consts.Code, data interface{}) *Response {
@@ -15,6 +20,22 @@ func NewResponse(code consts.Code, data interface{}) *Response {
 	}
 }
 
+func NewPageResponse(code consts.Code, data interface{}, nextCursor string) *Response {
+	return &Response{
+		Code:       code,
+		Data:       data,
+		NextCursor: nextCursor,
+	}
+}
+
+func NewVersionedResponse(code consts.Code, data interface{}, version uint32) *Response {
+	return &Response{
+		Code: code,
+		Data: data,
+		ETag: etag.Format(version),
+	}
+}
+
 func NewErrorResponse(code consts.Code, error_ error) *Response {
 	return &Response{
 		Code:  code,
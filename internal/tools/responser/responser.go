@@ -2,13 +2,15 @@ package responser
 
 import (
 	"github.com/TechnoHandOver/backend/internal/consts"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
 	"github.com/labstack/echo/v4"
 	"log"
 )
 
 type DataResponse struct {
-	Data interface{} `json:"data"`
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"nextCursor,omitempty"`
 }
 
 func Respond(context echo.Context, response_ *response.Response) error {
@@ -16,10 +18,15 @@ func Respond(context echo.Context, response_ *response.Response) error {
 		log.Println(response_.Error)
 	}
 
+	if response_.ETag != "" {
+		context.Response().Header().Set(etag.HeaderETag, response_.ETag)
+	}
+
 	if response_.Data == nil {
 		return context.NoContent(consts.StatusCodes[response_.Code])
 	}
 	return context.JSON(consts.StatusCodes[response_.Code], DataResponse{
-		Data: response_.Data,
+		Data:       response_.Data,
+		NextCursor: response_.NextCursor,
 	}) //TODO: возможно, нужно кастомно отдавать ввиду UTF-8...
 }
@@ -0,0 +1,66 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+const jpegQuality = 80
+
+func Make(image_ image.Image, maxSide int) ([]byte, error) {
+	bounds := image_.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSide || height > maxSide {
+		if width > height {
+			width, height = maxSide, maxInt(1, height*maxSide/width)
+		} else {
+			width, height = maxInt(1, width*maxSide/height), maxSide
+		}
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := jpeg.Encode(buffer, scale(image_, width, height), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func scale(image_ image.Image, width int, height int) image.Image {
+	bounds := image_.Bounds()
+	result := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := maxInt(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := maxInt(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, count uint64
+			for sourceY := y0; sourceY < y1; sourceY++ {
+				for sourceX := x0; sourceX < x1; sourceX++ {
+					r_, g_, b_, a_ := image_.At(sourceX, sourceY).RGBA()
+					r, g, b, a = r+uint64(r_), g+uint64(g_), b+uint64(b_), a+uint64(a_)
+					count++
+				}
+			}
+			result.Set(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+
+	return result
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -18,6 +18,9 @@ func NewRequestValidator() *RequestValidator {
 	var adsSearchOrder models.AdsSearchOrder
 	validator_.RegisterCustomTypeFunc(models.ValidateAdsSearchOrder, adsSearchOrder)
 
+	var adOffersSearchOrder models.AdOffersSearchOrder
+	validator_.RegisterCustomTypeFunc(models.ValidateAdOffersSearchOrder, adOffersSearchOrder)
+
 	return &RequestValidator{
 		validator: validator_,
 	}
@@ -23,25 +23,72 @@ func NewUserDelivery(userUsecase user.Usecase) *UserDelivery {
 }
 
 func (userDelivery *UserDelivery) Configure(echo_ *echo.Echo, middlewaresManager *middlewares.Manager) {
+	echo_.GET("/api/users/:id", userDelivery.HandlerUserGet(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/users/:id/reviews", userDelivery.HandlerUserReviewsList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/users/:id/block", userDelivery.HandlerUserBlock(true), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.DELETE("/api/users/:id/block", userDelivery.HandlerUserBlock(false), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/users/blocks/list", userDelivery.HandlerUserBlocksList(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.POST("/api/users/routes-tmp", userDelivery.HandlerRouteTmpCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.GET("/api/users/routes-tmp/:id", userDelivery.HandlerRouteTmpGet(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.PUT("/api/users/routes-tmp/:id", userDelivery.HandlerRouteTmpUpdate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.PATCH("/api/users/routes-tmp/:id", userDelivery.HandlerRouteTmpPatch(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.DELETE("/api/users/routes-tmp/:id", userDelivery.HandlerRouteTmpDelete(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.GET("/api/users/routes-tmp/list", userDelivery.HandlerRouteTmpList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/users/routes-tmp/:id/ads", userDelivery.HandlerRouteTmpAds(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.POST("/api/users/routes-perm", userDelivery.HandlerRoutePermCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.GET("/api/users/routes-perm/:id", userDelivery.HandlerRoutePermGet(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.PUT("/api/users/routes-perm/:id", userDelivery.HandlerRoutePermUpdate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.PATCH("/api/users/routes-perm/:id", userDelivery.HandlerRoutePermPatch(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.DELETE("/api/users/routes-perm/:id", userDelivery.HandlerRoutePermDelete(), middlewaresManager.AuthMiddleware.CheckAuth())
 	echo_.GET("/api/users/routes-perm/list", userDelivery.HandlerRoutePermList(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/users/routes-perm/:id/ads", userDelivery.HandlerRoutePermAds(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.POST("/api/users/saved-searches", userDelivery.HandlerSavedSearchCreate(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.PUT("/api/users/saved-searches/:id", userDelivery.HandlerSavedSearchRename(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.DELETE("/api/users/saved-searches/:id", userDelivery.HandlerSavedSearchDelete(), middlewaresManager.AuthMiddleware.CheckAuth())
+	echo_.GET("/api/users/saved-searches/list", userDelivery.HandlerSavedSearchList(), middlewaresManager.AuthMiddleware.CheckAuth())
+}
+
+func (userDelivery *UserDelivery) HandlerUserGet() echo.HandlerFunc {
+	type UserGetRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		userGetRequest := new(UserGetRequest)
+		if err := parser.ParseRequest(context, userGetRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		return responser.Respond(context, userDelivery.userUsecase.Get(*userGetRequest.Id))
+	}
+}
+
+func (userDelivery *UserDelivery) HandlerUserReviewsList() echo.HandlerFunc {
+	type UserReviewsListRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		userReviewsListRequest := new(UserReviewsListRequest)
+		if err := parser.ParseRequest(context, userReviewsListRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		return responser.Respond(context, userDelivery.userUsecase.ListUserReviews(*userReviewsListRequest.Id))
+	}
 }
 
 func (userDelivery *UserDelivery) HandlerRouteTmpCreate() echo.HandlerFunc {
 	type RouteTmpCreateRequest struct {
-		LocDep      *string   `json:"locDep" validate:"required,gte=2,lte=100"`
-		LocArr      *string   `json:"locArr" validate:"required,gte=2,lte=100"`
-		MinPrice    *uint32   `json:"minPrice" validate:"required"`
-		DateTimeDep *DateTime `json:"dateTimeDep" validate:"required"`
-		DateTimeArr *DateTime `json:"dateTimeArr" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"required_without=LocDepPlaceId,omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"required_without=LocArrPlaceId,omitempty,gte=2,lte=100"`
+		MinPrice      *uint32          `json:"minPrice" validate:"required"`
+		DateTimeDep   *DateTime        `json:"dateTimeDep" validate:"required"`
+		DateTimeArr   *DateTime        `json:"dateTimeArr" validate:"required"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
 	}
 
 	return func(context echo.Context) error {
@@ -51,12 +98,16 @@ func (userDelivery *UserDelivery) HandlerRouteTmpCreate() echo.HandlerFunc {
 		}
 
 		routeTmp := &models.RouteTmp{
-			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
-			LocDep:       *routeTmpCreateRequest.LocDep,
-			LocArr:       *routeTmpCreateRequest.LocArr,
-			MinPrice:     *routeTmpCreateRequest.MinPrice,
-			DateTimeDep:  *routeTmpCreateRequest.DateTimeDep,
-			DateTimeArr:  *routeTmpCreateRequest.DateTimeArr,
+			UserAuthorId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			LocDep:        parser.GetOrDefault(routeTmpCreateRequest.LocDep, "").(string),
+			LocArr:        parser.GetOrDefault(routeTmpCreateRequest.LocArr, "").(string),
+			MinPrice:      *routeTmpCreateRequest.MinPrice,
+			DateTimeDep:   *routeTmpCreateRequest.DateTimeDep,
+			DateTimeArr:   *routeTmpCreateRequest.DateTimeArr,
+			LocDepPoint:   routeTmpCreateRequest.LocDepPoint,
+			LocArrPoint:   routeTmpCreateRequest.LocArrPoint,
+			LocDepPlaceId: routeTmpCreateRequest.LocDepPlaceId,
+			LocArrPlaceId: routeTmpCreateRequest.LocArrPlaceId,
 		}
 
 		return responser.Respond(context, userDelivery.userUsecase.CreateRouteTmp(routeTmp))
@@ -83,12 +134,16 @@ func (userDelivery *UserDelivery) HandlerRouteTmpGet() echo.HandlerFunc {
 
 func (userDelivery *UserDelivery) HandlerRouteTmpUpdate() echo.HandlerFunc {
 	type RouteTmpUpdateRequest struct {
-		Id          *uint32   `param:"id" validate:"required"`
-		LocDep      *string   `json:"locDep" validate:"required,gte=2,lte=100"`
-		LocArr      *string   `json:"locArr" validate:"required,gte=2,lte=100"`
-		MinPrice    *uint32   `json:"minPrice" validate:"required"`
-		DateTimeDep *DateTime `json:"dateTimeDep" validate:"required"`
-		DateTimeArr *DateTime `json:"dateTimeArr" validate:"required"`
+		Id            *uint32          `param:"id" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"required_without=LocDepPlaceId,omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"required_without=LocArrPlaceId,omitempty,gte=2,lte=100"`
+		MinPrice      *uint32          `json:"minPrice" validate:"required"`
+		DateTimeDep   *DateTime        `json:"dateTimeDep" validate:"required"`
+		DateTimeArr   *DateTime        `json:"dateTimeArr" validate:"required"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
 	}
 
 	return func(context echo.Context) error {
@@ -97,20 +152,94 @@ func (userDelivery *UserDelivery) HandlerRouteTmpUpdate() echo.HandlerFunc {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
 		routeTmp := &models.RouteTmp{
-			Id:           *routeTmpUpdateRequest.Id,
-			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
-			LocDep:       *routeTmpUpdateRequest.LocDep,
-			LocArr:       *routeTmpUpdateRequest.LocArr,
-			MinPrice:     *routeTmpUpdateRequest.MinPrice,
-			DateTimeDep:  *routeTmpUpdateRequest.DateTimeDep,
-			DateTimeArr:  *routeTmpUpdateRequest.DateTimeArr,
+			Id:            *routeTmpUpdateRequest.Id,
+			UserAuthorId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			LocDep:        parser.GetOrDefault(routeTmpUpdateRequest.LocDep, "").(string),
+			LocArr:        parser.GetOrDefault(routeTmpUpdateRequest.LocArr, "").(string),
+			MinPrice:      *routeTmpUpdateRequest.MinPrice,
+			DateTimeDep:   *routeTmpUpdateRequest.DateTimeDep,
+			DateTimeArr:   *routeTmpUpdateRequest.DateTimeArr,
+			LocDepPoint:   routeTmpUpdateRequest.LocDepPoint,
+			LocArrPoint:   routeTmpUpdateRequest.LocArrPoint,
+			LocDepPlaceId: routeTmpUpdateRequest.LocDepPlaceId,
+			LocArrPlaceId: routeTmpUpdateRequest.LocArrPlaceId,
+			Version:       version,
 		}
 
 		return responser.Respond(context, userDelivery.userUsecase.UpdateRouteTmp(routeTmp))
 	}
 }
 
+func (userDelivery *UserDelivery) HandlerRouteTmpPatch() echo.HandlerFunc {
+	type RouteTmpPatchRequest struct {
+		Id            *uint32          `param:"id" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"omitempty,gte=2,lte=100"`
+		MinPrice      *uint32          `json:"minPrice" validate:"omitempty"`
+		DateTimeDep   *DateTime        `json:"dateTimeDep" validate:"omitempty"`
+		DateTimeArr   *DateTime        `json:"dateTimeArr" validate:"omitempty"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
+	}
+
+	return func(context echo.Context) error {
+		routeTmpPatchRequest := new(RouteTmpPatchRequest)
+		document, err := parser.ParseMergePatchRequest(context, routeTmpPatchRequest)
+		if err != nil {
+			if err == parser.ErrUnsupportedMediaType {
+				return responser.Respond(context, response.NewEmptyResponse(consts.UnsupportedMediaType))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+		if document.IsAnyNull("locDep", "locArr", "minPrice", "dateTimeDep", "dateTimeArr") {
+			return responser.Respond(context, response.NewEmptyResponse(consts.BadRequest))
+		}
+
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
+		routeTmpPatch := &models.RouteTmpPatch{
+			Id:                *routeTmpPatchRequest.Id,
+			UserAuthorId:      context.Get(consts.EchoContextKeyUserId).(uint32),
+			Version:           version,
+			LocDep:            routeTmpPatchRequest.LocDep,
+			LocArr:            routeTmpPatchRequest.LocArr,
+			LocDepPoint:       routeTmpPatchRequest.LocDepPoint,
+			LocDepPointNull:   document.IsNull("locDepPoint"),
+			LocArrPoint:       routeTmpPatchRequest.LocArrPoint,
+			LocArrPointNull:   document.IsNull("locArrPoint"),
+			LocDepPlaceId:     routeTmpPatchRequest.LocDepPlaceId,
+			LocDepPlaceIdNull: document.IsNullOrSuperseded("locDepPlaceId", "locDep", "locDepPoint"),
+			LocArrPlaceId:     routeTmpPatchRequest.LocArrPlaceId,
+			LocArrPlaceIdNull: document.IsNullOrSuperseded("locArrPlaceId", "locArr", "locArrPoint"),
+			MinPrice:          routeTmpPatchRequest.MinPrice,
+			DateTimeDep:       routeTmpPatchRequest.DateTimeDep,
+			DateTimeArr:       routeTmpPatchRequest.DateTimeArr,
+		}
+
+		return responser.Respond(context, userDelivery.userUsecase.PatchRouteTmp(routeTmpPatch))
+	}
+}
+
 func (userDelivery *UserDelivery) HandlerRouteTmpDelete() echo.HandlerFunc {
 	type RouteTmpDeleteRequest struct {
 		Id *uint32 `param:"id" validate:"required"`
@@ -122,10 +251,19 @@ func (userDelivery *UserDelivery) HandlerRouteTmpDelete() echo.HandlerFunc {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
 		id := *routeTmpDeleteRequest.Id
 		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
 
-		return responser.Respond(context, userDelivery.userUsecase.DeleteRouteTmp(userId, id))
+		return responser.Respond(context, userDelivery.userUsecase.DeleteRouteTmp(userId, id, version))
 	}
 }
 
@@ -137,16 +275,41 @@ func (userDelivery *UserDelivery) HandlerRouteTmpList() echo.HandlerFunc {
 	}
 }
 
+func (userDelivery *UserDelivery) HandlerRouteTmpAds() echo.HandlerFunc {
+	type RouteTmpAdsRequest struct {
+		Id     *uint32                 `param:"id" validate:"required"`
+		Cursor *models.AdMatchesCursor `query:"cursor" validate:"omitempty"`
+		Limit  *uint32                 `query:"limit" validate:"omitempty,min=1,max=100"`
+	}
+
+	return func(context echo.Context) error {
+		routeTmpAdsRequest := new(RouteTmpAdsRequest)
+		if err := parser.ParseRequest(context, routeTmpAdsRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		id := *routeTmpAdsRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, userDelivery.userUsecase.ListRouteTmpAds(userId, id,
+			routeTmpAdsRequest.Cursor, routeTmpAdsRequest.Limit))
+	}
+}
+
 func (userDelivery *UserDelivery) HandlerRoutePermCreate() echo.HandlerFunc {
 	type RoutePermCreateRequest struct {
-		LocDep    *string    `json:"locDep" validate:"required,gte=2,lte=100"`
-		LocArr    *string    `json:"locArr" validate:"required,gte=2,lte=100"`
-		MinPrice  *uint32    `json:"minPrice" validate:"required"`
-		EvenWeek  *bool      `json:"evenWeek" validate:"required"`
-		OddWeek   *bool      `json:"oddWeek" validate:"required"`
-		DayOfWeek *DayOfWeek `json:"dayOfWeek" validate:"required,eq=Mon|eq=Tue|eq=Wed|eq=Thu|eq=Fri|eq=Sat|eq=Sun"`
-		TimeDep   *Time      `json:"timeDep" validate:"required"`
-		TimeArr   *Time      `json:"timeArr" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"required_without=LocDepPlaceId,omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"required_without=LocArrPlaceId,omitempty,gte=2,lte=100"`
+		MinPrice      *uint32          `json:"minPrice" validate:"required"`
+		EvenWeek      *bool            `json:"evenWeek" validate:"required"`
+		OddWeek       *bool            `json:"oddWeek" validate:"required"`
+		DayOfWeek     *DayOfWeek       `json:"dayOfWeek" validate:"required,eq=Mon|eq=Tue|eq=Wed|eq=Thu|eq=Fri|eq=Sat|eq=Sun"`
+		TimeDep       *Time            `json:"timeDep" validate:"required"`
+		TimeArr       *Time            `json:"timeArr" validate:"required"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
 	}
 
 	return func(context echo.Context) error {
@@ -160,15 +323,19 @@ func (userDelivery *UserDelivery) HandlerRoutePermCreate() echo.HandlerFunc {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 		routePerm := &models.RoutePerm{
-			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
-			LocDep:       *routePermCreateRequest.LocDep,
-			LocArr:       *routePermCreateRequest.LocArr,
-			MinPrice:     parser.GetOrDefault(routePermCreateRequest.MinPrice, 0).(uint32),
-			EvenWeek:     parser.GetOrDefault(routePermCreateRequest.EvenWeek, true).(bool),
-			OddWeek:      parser.GetOrDefault(routePermCreateRequest.OddWeek, true).(bool),
-			DayOfWeek:    dayOfWeek,
-			TimeDep:      *routePermCreateRequest.TimeDep,
-			TimeArr:      *routePermCreateRequest.TimeArr,
+			UserAuthorId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			LocDep:        parser.GetOrDefault(routePermCreateRequest.LocDep, "").(string),
+			LocArr:        parser.GetOrDefault(routePermCreateRequest.LocArr, "").(string),
+			MinPrice:      parser.GetOrDefault(routePermCreateRequest.MinPrice, 0).(uint32),
+			EvenWeek:      parser.GetOrDefault(routePermCreateRequest.EvenWeek, true).(bool),
+			OddWeek:       parser.GetOrDefault(routePermCreateRequest.OddWeek, true).(bool),
+			DayOfWeek:     dayOfWeek,
+			TimeDep:       *routePermCreateRequest.TimeDep,
+			TimeArr:       *routePermCreateRequest.TimeArr,
+			LocDepPoint:   routePermCreateRequest.LocDepPoint,
+			LocArrPoint:   routePermCreateRequest.LocArrPoint,
+			LocDepPlaceId: routePermCreateRequest.LocDepPlaceId,
+			LocArrPlaceId: routePermCreateRequest.LocArrPlaceId,
 		}
 
 		return responser.Respond(context, userDelivery.userUsecase.CreateRoutePerm(routePerm))
@@ -195,15 +362,19 @@ func (userDelivery *UserDelivery) HandlerRoutePermGet() echo.HandlerFunc {
 
 func (userDelivery *UserDelivery) HandlerRoutePermUpdate() echo.HandlerFunc {
 	type RoutePermUpdateRequest struct {
-		Id        *uint32    `param:"id" validate:"required"`
-		LocDep    *string    `json:"locDep" validate:"required,gte=2,lte=100"`
-		LocArr    *string    `json:"locArr" validate:"required,gte=2,lte=100"`
-		MinPrice  *uint32    `json:"minPrice" validate:"required"`
-		EvenWeek  *bool      `json:"evenWeek" validate:"required"`
-		OddWeek   *bool      `json:"oddWeek" validate:"required"`
-		DayOfWeek *DayOfWeek `json:"dayOfWeek" validate:"required,eq=Mon|eq=Tue|eq=Wed|eq=Thu|eq=Fri|eq=Sat|eq=Sun"`
-		TimeDep   *Time      `json:"timeDep" validate:"required"`
-		TimeArr   *Time      `json:"timeArr" validate:"required"`
+		Id            *uint32          `param:"id" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"required_without=LocDepPlaceId,omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"required_without=LocArrPlaceId,omitempty,gte=2,lte=100"`
+		MinPrice      *uint32          `json:"minPrice" validate:"required"`
+		EvenWeek      *bool            `json:"evenWeek" validate:"required"`
+		OddWeek       *bool            `json:"oddWeek" validate:"required"`
+		DayOfWeek     *DayOfWeek       `json:"dayOfWeek" validate:"required,eq=Mon|eq=Tue|eq=Wed|eq=Thu|eq=Fri|eq=Sat|eq=Sun"`
+		TimeDep       *Time            `json:"timeDep" validate:"required"`
+		TimeArr       *Time            `json:"timeArr" validate:"required"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
 	}
 
 	return func(context echo.Context) error {
@@ -212,27 +383,115 @@ func (userDelivery *UserDelivery) HandlerRoutePermUpdate() echo.HandlerFunc {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
 		dayOfWeek, err := routePermUpdateRequest.DayOfWeek.ToUint32()
 		if err != nil {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 		routePerm := &models.RoutePerm{
-			Id:           *routePermUpdateRequest.Id,
-			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
-			LocDep:       *routePermUpdateRequest.LocDep,
-			LocArr:       *routePermUpdateRequest.LocArr,
-			MinPrice:     *routePermUpdateRequest.MinPrice,
-			EvenWeek:     *routePermUpdateRequest.EvenWeek,
-			OddWeek:      *routePermUpdateRequest.OddWeek,
-			DayOfWeek:    dayOfWeek,
-			TimeDep:      *routePermUpdateRequest.TimeDep,
-			TimeArr:      *routePermUpdateRequest.TimeArr,
+			Id:            *routePermUpdateRequest.Id,
+			UserAuthorId:  context.Get(consts.EchoContextKeyUserId).(uint32),
+			LocDep:        parser.GetOrDefault(routePermUpdateRequest.LocDep, "").(string),
+			LocArr:        parser.GetOrDefault(routePermUpdateRequest.LocArr, "").(string),
+			MinPrice:      *routePermUpdateRequest.MinPrice,
+			EvenWeek:      *routePermUpdateRequest.EvenWeek,
+			OddWeek:       *routePermUpdateRequest.OddWeek,
+			DayOfWeek:     dayOfWeek,
+			TimeDep:       *routePermUpdateRequest.TimeDep,
+			TimeArr:       *routePermUpdateRequest.TimeArr,
+			LocDepPoint:   routePermUpdateRequest.LocDepPoint,
+			LocArrPoint:   routePermUpdateRequest.LocArrPoint,
+			LocDepPlaceId: routePermUpdateRequest.LocDepPlaceId,
+			LocArrPlaceId: routePermUpdateRequest.LocArrPlaceId,
+			Version:       version,
 		}
 
 		return responser.Respond(context, userDelivery.userUsecase.UpdateRoutePerm(routePerm))
 	}
 }
 
+func (userDelivery *UserDelivery) HandlerRoutePermPatch() echo.HandlerFunc {
+	type RoutePermPatchRequest struct {
+		Id            *uint32          `param:"id" validate:"required"`
+		LocDep        *string          `json:"locDep" validate:"omitempty,gte=2,lte=100"`
+		LocArr        *string          `json:"locArr" validate:"omitempty,gte=2,lte=100"`
+		MinPrice      *uint32          `json:"minPrice" validate:"omitempty"`
+		EvenWeek      *bool            `json:"evenWeek" validate:"omitempty"`
+		OddWeek       *bool            `json:"oddWeek" validate:"omitempty"`
+		DayOfWeek     *DayOfWeek       `json:"dayOfWeek" validate:"omitempty,eq=Mon|eq=Tue|eq=Wed|eq=Thu|eq=Fri|eq=Sat|eq=Sun"`
+		TimeDep       *Time            `json:"timeDep" validate:"omitempty"`
+		TimeArr       *Time            `json:"timeArr" validate:"omitempty"`
+		LocDepPoint   *models.GeoPoint `json:"locDepPoint" validate:"omitempty"`
+		LocArrPoint   *models.GeoPoint `json:"locArrPoint" validate:"omitempty"`
+		LocDepPlaceId *uint32          `json:"locDepPlaceId" validate:"omitempty"`
+		LocArrPlaceId *uint32          `json:"locArrPlaceId" validate:"omitempty"`
+	}
+
+	return func(context echo.Context) error {
+		routePermPatchRequest := new(RoutePermPatchRequest)
+		document, err := parser.ParseMergePatchRequest(context, routePermPatchRequest)
+		if err != nil {
+			if err == parser.ErrUnsupportedMediaType {
+				return responser.Respond(context, response.NewEmptyResponse(consts.UnsupportedMediaType))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+		if document.IsAnyNull("locDep", "locArr", "minPrice", "evenWeek", "oddWeek", "dayOfWeek", "timeDep",
+			"timeArr") {
+			return responser.Respond(context, response.NewEmptyResponse(consts.BadRequest))
+		}
+
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
+		var dayOfWeek *uint32
+		if routePermPatchRequest.DayOfWeek != nil {
+			dayOfWeek = new(uint32)
+			if *dayOfWeek, err = routePermPatchRequest.DayOfWeek.ToUint32(); err != nil {
+				return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+			}
+		}
+		routePermPatch := &models.RoutePermPatch{
+			Id:                *routePermPatchRequest.Id,
+			UserAuthorId:      context.Get(consts.EchoContextKeyUserId).(uint32),
+			Version:           version,
+			LocDep:            routePermPatchRequest.LocDep,
+			LocArr:            routePermPatchRequest.LocArr,
+			LocDepPoint:       routePermPatchRequest.LocDepPoint,
+			LocDepPointNull:   document.IsNull("locDepPoint"),
+			LocArrPoint:       routePermPatchRequest.LocArrPoint,
+			LocArrPointNull:   document.IsNull("locArrPoint"),
+			LocDepPlaceId:     routePermPatchRequest.LocDepPlaceId,
+			LocDepPlaceIdNull: document.IsNullOrSuperseded("locDepPlaceId", "locDep", "locDepPoint"),
+			LocArrPlaceId:     routePermPatchRequest.LocArrPlaceId,
+			LocArrPlaceIdNull: document.IsNullOrSuperseded("locArrPlaceId", "locArr", "locArrPoint"),
+			MinPrice:          routePermPatchRequest.MinPrice,
+			EvenWeek:          routePermPatchRequest.EvenWeek,
+			OddWeek:           routePermPatchRequest.OddWeek,
+			DayOfWeek:         dayOfWeek,
+			TimeDep:           routePermPatchRequest.TimeDep,
+			TimeArr:           routePermPatchRequest.TimeArr,
+		}
+
+		return responser.Respond(context, userDelivery.userUsecase.PatchRoutePerm(routePermPatch))
+	}
+}
+
 func (userDelivery *UserDelivery) HandlerRoutePermDelete() echo.HandlerFunc {
 	type RoutePermDeleteRequest struct {
 		Id *uint32 `param:"id" validate:"required"`
@@ -244,10 +503,19 @@ func (userDelivery *UserDelivery) HandlerRoutePermDelete() echo.HandlerFunc {
 			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
 		}
 
+		version, err := parser.ParseIfMatch(context)
+		if err != nil {
+			if err == parser.ErrPreconditionRequired {
+				return responser.Respond(context, response.NewEmptyResponse(consts.PreconditionRequired))
+			}
+
+			return responser.Respond(context, response.NewErrorResponse(consts.PreconditionFailed, err))
+		}
+
 		id := *routePermDeleteRequest.Id
 		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
 
-		return responser.Respond(context, userDelivery.userUsecase.DeleteRoutePerm(userId, id))
+		return responser.Respond(context, userDelivery.userUsecase.DeleteRoutePerm(userId, id, version))
 	}
 }
 
@@ -258,3 +526,134 @@ func (userDelivery *UserDelivery) HandlerRoutePermList() echo.HandlerFunc {
 		return responser.Respond(context, userDelivery.userUsecase.ListRoutePerm(userId))
 	}
 }
+
+func (userDelivery *UserDelivery) HandlerRoutePermAds() echo.HandlerFunc {
+	type RoutePermAdsRequest struct {
+		Id     *uint32                 `param:"id" validate:"required"`
+		Cursor *models.AdMatchesCursor `query:"cursor" validate:"omitempty"`
+		Limit  *uint32                 `query:"limit" validate:"omitempty,min=1,max=100"`
+	}
+
+	return func(context echo.Context) error {
+		routePermAdsRequest := new(RoutePermAdsRequest)
+		if err := parser.ParseRequest(context, routePermAdsRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		id := *routePermAdsRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, userDelivery.userUsecase.ListRoutePermAds(userId, id,
+			routePermAdsRequest.Cursor, routePermAdsRequest.Limit))
+	}
+}
+
+func (userDelivery *UserDelivery) HandlerSavedSearchCreate() echo.HandlerFunc {
+	type SavedSearchCreateRequest struct {
+		Name     *string                `json:"name" validate:"required,gte=1,lte=50"`
+		LocDep   *string                `json:"locDep" validate:"omitempty,lte=100"`
+		LocArr   *string                `json:"locArr" validate:"omitempty,lte=100"`
+		MaxPrice *uint32                `json:"maxPrice" validate:"omitempty"`
+		Order    *models.AdsSearchOrder `json:"order" validate:"omitempty"`
+	}
+
+	return func(context echo.Context) error {
+		savedSearchCreateRequest := new(SavedSearchCreateRequest)
+		if err := parser.ParseRequest(context, savedSearchCreateRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		savedSearch := &models.SavedSearch{
+			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
+			Name:         *savedSearchCreateRequest.Name,
+			LocDep:       savedSearchCreateRequest.LocDep,
+			LocArr:       savedSearchCreateRequest.LocArr,
+			MaxPrice:     savedSearchCreateRequest.MaxPrice,
+			Order:        savedSearchCreateRequest.Order,
+		}
+
+		return responser.Respond(context, userDelivery.userUsecase.CreateSavedSearch(savedSearch))
+	}
+}
+
+func (userDelivery *UserDelivery) HandlerSavedSearchRename() echo.HandlerFunc {
+	type SavedSearchRenameRequest struct {
+		Id   *uint32 `param:"id" validate:"required"`
+		Name *string `json:"name" validate:"required,gte=1,lte=50"`
+	}
+
+	return func(context echo.Context) error {
+		savedSearchRenameRequest := new(SavedSearchRenameRequest)
+		if err := parser.ParseRequest(context, savedSearchRenameRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		savedSearch := &models.SavedSearch{
+			Id:           *savedSearchRenameRequest.Id,
+			UserAuthorId: context.Get(consts.EchoContextKeyUserId).(uint32),
+			Name:         *savedSearchRenameRequest.Name,
+		}
+
+		return responser.Respond(context, userDelivery.userUsecase.RenameSavedSearch(savedSearch))
+	}
+}
+
+func (userDelivery *UserDelivery) HandlerSavedSearchDelete() echo.HandlerFunc {
+	type SavedSearchDeleteRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		savedSearchDeleteRequest := new(SavedSearchDeleteRequest)
+		if err := parser.ParseRequest(context, savedSearchDeleteRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		id := *savedSearchDeleteRequest.Id
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, userDelivery.userUsecase.DeleteSavedSearch(userId, id))
+	}
+}
+
+func (userDelivery *UserDelivery) HandlerSavedSearchList() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, userDelivery.userUsecase.ListSavedSearch(userId))
+	}
+}
+
+func (userDelivery *UserDelivery) HandlerUserBlock(block bool) echo.HandlerFunc {
+	type UserBlockRequest struct {
+		Id *uint32 `param:"id" validate:"required"`
+	}
+
+	return func(context echo.Context) error {
+		userBlockRequest := new(UserBlockRequest)
+		if err := parser.ParseRequest(context, userBlockRequest); err != nil {
+			return responser.Respond(context, response.NewErrorResponse(consts.BadRequest, err))
+		}
+
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		if !block {
+			return responser.Respond(context, userDelivery.userUsecase.UnblockUser(userId, *userBlockRequest.Id))
+		}
+
+		userBlock := &models.UserBlock{
+			UserId:        userId,
+			UserBlockedId: *userBlockRequest.Id,
+		}
+
+		return responser.Respond(context, userDelivery.userUsecase.BlockUser(userBlock))
+	}
+}
+
+func (userDelivery *UserDelivery) HandlerUserBlocksList() echo.HandlerFunc {
+	return func(context echo.Context) error {
+		userId := context.Get(consts.EchoContextKeyUserId).(uint32)
+
+		return responser.Respond(context, userDelivery.userUsecase.ListUserBlocks(userId))
+	}
+}
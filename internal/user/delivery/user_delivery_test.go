@@ -6,6 +6,8 @@ import (
 	"github.com/TechnoHandOver/backend/internal/middlewares"
 	"github.com/TechnoHandOver/backend/internal/models"
 	"github.com/TechnoHandOver/backend/internal/models/timestamps"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+	"github.com/TechnoHandOver/backend/internal/tools/mergepatch"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
 	"github.com/TechnoHandOver/backend/internal/tools/responser"
 	HandoverValidator "github.com/TechnoHandOver/backend/internal/tools/validator"
@@ -40,6 +42,8 @@ func TestUserDelivery_HandlerRouteTmpCreate(t *testing.T) {
 		UserAuthorId: 101,
 		LocDep:       "Корпус Энерго",
 		LocArr:       "Корпус УЛК",
+		LocDepPoint:  &models.GeoPoint{Lat: 55.765, Lon: 37.685},
+		LocArrPoint:  &models.GeoPoint{Lat: 55.771, Lon: 37.691},
 		MinPrice:     500,
 		DateTimeDep:  *dateTimeDep,
 		DateTimeArr:  *dateTimeArr,
@@ -49,6 +53,8 @@ func TestUserDelivery_HandlerRouteTmpCreate(t *testing.T) {
 		UserAuthorId: routeTmp.UserAuthorId,
 		LocDep:       routeTmp.LocDep,
 		LocArr:       routeTmp.LocArr,
+		LocDepPoint:  routeTmp.LocDepPoint,
+		LocArrPoint:  routeTmp.LocArrPoint,
 		MinPrice:     routeTmp.MinPrice,
 		DateTimeDep:  routeTmp.DateTimeDep,
 		DateTimeArr:  routeTmp.DateTimeArr,
@@ -89,6 +95,56 @@ func TestUserDelivery_HandlerRouteTmpCreate(t *testing.T) {
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
 
+func TestUserDelivery_HandlerUserGet(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	expectedUser := &models.User{
+		Id:          102,
+		VkId:        202,
+		Name:        "Pupok Vasiliev",
+		Avatar:      "https://yandex.ru/logo2.png",
+		Rating:      4.5,
+		RatingCount: 2,
+	}
+
+	mockUserUsecase.
+		EXPECT().
+		Get(gomock.Eq(expectedUser.Id)).
+		Return(response.NewResponse(consts.OK, expectedUser))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedUser,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/:id")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(expectedUser.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := userDelivery.HandlerUserGet()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
 func TestUserDelivery_HandlerRouteTmpGet(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -166,6 +222,7 @@ func TestUserDelivery_HandlerRouteTmpUpdate(t *testing.T) {
 		MinPrice:     500,
 		DateTimeDep:  *dateTimeDep,
 		DateTimeArr:  *dateTimeArr,
+		Version:      2,
 	}
 
 	mockUserUsecase.
@@ -184,6 +241,7 @@ func TestUserDelivery_HandlerRouteTmpUpdate(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(jsonRequest)))
 	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(expectedRouteTmp.Version))
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
@@ -225,11 +283,13 @@ func TestUserDelivery_HandlerRouteTmpDelete(t *testing.T) {
 		MinPrice:     500,
 		DateTimeDep:  *dateTimeDep,
 		DateTimeArr:  *dateTimeArr,
+		Version:      2,
 	}
 
 	mockUserUsecase.
 		EXPECT().
-		DeleteRouteTmp(gomock.Eq(expectedRouteTmp.UserAuthorId), gomock.Eq(expectedRouteTmp.Id)).
+		DeleteRouteTmp(gomock.Eq(expectedRouteTmp.UserAuthorId), gomock.Eq(expectedRouteTmp.Id),
+			gomock.Eq(expectedRouteTmp.Version)).
 		Return(response.NewResponse(consts.OK, expectedRouteTmp))
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
@@ -239,6 +299,7 @@ func TestUserDelivery_HandlerRouteTmpDelete(t *testing.T) {
 	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
 
 	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(expectedRouteTmp.Version))
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
@@ -258,6 +319,32 @@ func TestUserDelivery_HandlerRouteTmpDelete(t *testing.T) {
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
 
+func TestUserDelivery_HandlerRouteTmpDelete_preconditionRequired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/routes-tmp/:id")
+	context.SetParamNames("id")
+	context.SetParamValues("1")
+	context.Set(consts.EchoContextKeyUserId, uint32(101))
+
+	handler := userDelivery.HandlerRouteTmpDelete()
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+}
+
 func TestUserDelivery_HandlerRouteTmpList(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -348,7 +435,7 @@ func TestUserDelivery_HandlerRoutePermCreate(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -373,7 +460,13 @@ func TestUserDelivery_HandlerRoutePermCreate(t *testing.T) {
 			return response.NewResponse(consts.Created, routePerm)
 		})
 
-	jsonRequest, err := json.Marshal(routePerm)
+	jsonRequest, err := json.Marshal(struct {
+		*models.RoutePerm
+		DayOfWeek timestamps.DayOfWeek `json:"dayOfWeek"`
+	}{
+		RoutePerm: routePerm,
+		DayOfWeek: timestamps.DayOfWeekWednesday,
+	})
 	assert.Nil(t, err)
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
@@ -422,7 +515,7 @@ func TestUserDelivery_HandlerRoutePermGet(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -480,9 +573,10 @@ func TestUserDelivery_HandlerRoutePermUpdate(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
+		Version:      2,
 	}
 
 	mockUserUsecase.
@@ -490,7 +584,13 @@ func TestUserDelivery_HandlerRoutePermUpdate(t *testing.T) {
 		UpdateRoutePerm(gomock.Eq(expectedRoutePerm)).
 		Return(response.NewResponse(consts.OK, expectedRoutePerm))
 
-	jsonRequest, err := json.Marshal(expectedRoutePerm)
+	jsonRequest, err := json.Marshal(struct {
+		*models.RoutePerm
+		DayOfWeek timestamps.DayOfWeek `json:"dayOfWeek"`
+	}{
+		RoutePerm: expectedRoutePerm,
+		DayOfWeek: timestamps.DayOfWeekWednesday,
+	})
 	assert.Nil(t, err)
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
@@ -501,6 +601,7 @@ func TestUserDelivery_HandlerRoutePermUpdate(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(jsonRequest)))
 	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(expectedRoutePerm.Version))
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
@@ -520,6 +621,78 @@ func TestUserDelivery_HandlerRoutePermUpdate(t *testing.T) {
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
 
+func TestUserDelivery_HandlerRoutePermPatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	timeDep, err := timestamps.NewTime("16:15")
+	assert.Nil(t, err)
+	timeArr, err := timestamps.NewTime("16:20")
+	assert.Nil(t, err)
+	oddWeek := false
+	dayOfWeek := uint32(3)
+	routePermPatch := &models.RoutePermPatch{
+		Id:                1,
+		UserAuthorId:      101,
+		Version:           2,
+		LocDepPointNull:   true,
+		LocDepPlaceIdNull: true,
+		OddWeek:           &oddWeek,
+		DayOfWeek:         &dayOfWeek,
+	}
+	expectedRoutePerm := &models.RoutePerm{
+		Id:           routePermPatch.Id,
+		UserAuthorId: routePermPatch.UserAuthorId,
+		LocDep:       "Корпус Энерго",
+		LocArr:       "Корпус УЛК",
+		MinPrice:     500,
+		EvenWeek:     true,
+		OddWeek:      oddWeek,
+		DayOfWeek:    dayOfWeek,
+		TimeDep:      *timeDep,
+		TimeArr:      *timeArr,
+	}
+
+	mockUserUsecase.
+		EXPECT().
+		PatchRoutePerm(gomock.Eq(routePermPatch)).
+		Return(response.NewResponse(consts.OK, expectedRoutePerm))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedRoutePerm,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPatch, "/",
+		strings.NewReader(`{"oddWeek": false, "dayOfWeek": "Wed", "locDepPoint": null}`))
+	request.Header.Set(echo.HeaderContentType, mergepatch.MIMEApplicationMergePatchJSON)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(routePermPatch.Version))
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/routes-perm/:id")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(routePermPatch.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, routePermPatch.UserAuthorId)
+
+	handler := userDelivery.HandlerRoutePermPatch()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
 func TestUserDelivery_HandlerRoutePermDelete(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -542,14 +715,16 @@ func TestUserDelivery_HandlerRoutePermDelete(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
+		Version:      2,
 	}
 
 	mockUserUsecase.
 		EXPECT().
-		DeleteRoutePerm(gomock.Eq(expectedRoutePerm.UserAuthorId), gomock.Eq(expectedRoutePerm.Id)).
+		DeleteRoutePerm(gomock.Eq(expectedRoutePerm.UserAuthorId), gomock.Eq(expectedRoutePerm.Id),
+			gomock.Eq(expectedRoutePerm.Version)).
 		Return(response.NewResponse(consts.OK, expectedRoutePerm))
 
 	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
@@ -559,6 +734,7 @@ func TestUserDelivery_HandlerRoutePermDelete(t *testing.T) {
 	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
 
 	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+	request.Header.Set(etag.HeaderIfMatch, etag.Format(expectedRoutePerm.Version))
 
 	recorder := httptest.NewRecorder()
 	context := echo_.NewContext(request, recorder)
@@ -606,7 +782,7 @@ func TestUserDelivery_HandlerRoutePermList(t *testing.T) {
 			MinPrice:     500,
 			EvenWeek:     true,
 			OddWeek:      false,
-			DayOfWeek:    timestamps.DayOfWeekWednesday,
+			DayOfWeek:    3,
 			TimeDep:      *timeDep1,
 			TimeArr:      *timeArr1,
 		},
@@ -618,7 +794,7 @@ func TestUserDelivery_HandlerRoutePermList(t *testing.T) {
 			MinPrice:     600,
 			EvenWeek:     false,
 			OddWeek:      true,
-			DayOfWeek:    timestamps.DayOfWeekSaturday,
+			DayOfWeek:    6,
 			TimeDep:      *timeDep2,
 			TimeArr:      *timeArr2,
 		},
@@ -652,3 +828,264 @@ func TestUserDelivery_HandlerRoutePermList(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, jsonExpectedResponse, responseBody)
 }
+
+func TestUserDelivery_HandlerSavedSearchCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	locDep := "Общежитие №10"
+	var maxPrice uint32 = 700
+	order := models.AdsSearchOrderMinPriceAsc
+	savedSearch := &models.SavedSearch{
+		UserAuthorId: 101,
+		Name:         "Из общежития",
+		LocDep:       &locDep,
+		MaxPrice:     &maxPrice,
+		Order:        &order,
+	}
+	expectedSavedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: savedSearch.UserAuthorId,
+		Name:         savedSearch.Name,
+		LocDep:       savedSearch.LocDep,
+		MaxPrice:     savedSearch.MaxPrice,
+		Order:        savedSearch.Order,
+	}
+
+	mockUserUsecase.
+		EXPECT().
+		CreateSavedSearch(gomock.Eq(savedSearch)).
+		DoAndReturn(func(savedSearch *models.SavedSearch) *response.Response {
+			savedSearch.Id = expectedSavedSearch.Id
+			return response.NewResponse(consts.Created, savedSearch)
+		})
+
+	jsonRequest, err := json.Marshal(savedSearch)
+	assert.Nil(t, err)
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedSavedSearch,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/saved-searches",
+		strings.NewReader(string(jsonRequest)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.Set(consts.EchoContextKeyUserId, savedSearch.UserAuthorId)
+
+	handler := userDelivery.HandlerSavedSearchCreate()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestUserDelivery_HandlerSavedSearchRename(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	savedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: 101,
+		Name:         "Из десятки",
+	}
+	locDep := "Общежитие №10"
+	expectedSavedSearch := &models.SavedSearch{
+		Id:           savedSearch.Id,
+		UserAuthorId: savedSearch.UserAuthorId,
+		Name:         savedSearch.Name,
+		LocDep:       &locDep,
+	}
+
+	mockUserUsecase.
+		EXPECT().
+		RenameSavedSearch(gomock.Eq(savedSearch)).
+		Return(response.NewResponse(consts.OK, expectedSavedSearch))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedSavedSearch,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"name":"Из десятки"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/saved-searches/:id")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(savedSearch.Id), 10))
+	context.Set(consts.EchoContextKeyUserId, savedSearch.UserAuthorId)
+
+	handler := userDelivery.HandlerSavedSearchRename()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestUserDelivery_HandlerRouteTmpAds(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	var userId, routeTmpId, limit uint32 = 101, 1, 10
+	cursor := &models.AdMatchesCursor{
+		Fit: 0.75,
+		Id:  5,
+	}
+	expectedAdMatches := &models.AdMatches{
+		&models.AdMatch{
+			Ad:  &models.Ad{Id: 3, LocDep: "Корпус Энерго", LocArr: "Корпус УЛК", MinPrice: 600},
+			Fit: 0.5,
+		},
+	}
+
+	mockUserUsecase.
+		EXPECT().
+		ListRouteTmpAds(gomock.Eq(userId), gomock.Eq(routeTmpId), gomock.Eq(cursor), gomock.Eq(&limit)).
+		Return(response.NewPageResponse(consts.OK, expectedAdMatches, ""))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedAdMatches,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodGet, "/?cursor="+cursor.String()+"&limit=10", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/routes-tmp/:id/ads")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(routeTmpId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := userDelivery.HandlerRouteTmpAds()
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestUserDelivery_HandlerUserBlock(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	userBlock := &models.UserBlock{
+		UserId:        101,
+		UserBlockedId: 102,
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedUserBlock := &models.UserBlock{
+		UserId:            userBlock.UserId,
+		UserBlockedId:     userBlock.UserBlockedId,
+		UserBlockedName:   "Петр Васильев",
+		UserBlockedAvatar: "https://mail.ru/petr_vasiliev_avatar.jpg",
+		DateTime:          *dateTime,
+	}
+
+	mockUserUsecase.
+		EXPECT().
+		BlockUser(gomock.Eq(userBlock)).
+		Return(response.NewResponse(consts.Created, expectedUserBlock))
+
+	jsonExpectedResponse, err := json.Marshal(responser.DataResponse{
+		Data: expectedUserBlock,
+	})
+	assert.Nil(t, err)
+	jsonExpectedResponse = append(jsonExpectedResponse, '\n')
+
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/:id/block")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(userBlock.UserBlockedId), 10))
+	context.Set(consts.EchoContextKeyUserId, userBlock.UserId)
+
+	handler := userDelivery.HandlerUserBlock(true)
+
+	err = handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	responseBody, err := ioutil.ReadAll(recorder.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonExpectedResponse, responseBody)
+}
+
+func TestUserDelivery_HandlerUserUnblock(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserUsecase := mock_user.NewMockUsecase(controller)
+	userDelivery := delivery.NewUserDelivery(mockUserUsecase)
+	echo_ := echo.New()
+	echo_.Validator = HandoverValidator.NewRequestValidator()
+	userDelivery.Configure(echo_, &middlewares.Manager{})
+
+	const userId, userBlockedId uint32 = 101, 102
+
+	mockUserUsecase.
+		EXPECT().
+		UnblockUser(gomock.Eq(userId), gomock.Eq(userBlockedId)).
+		Return(response.NewEmptyResponse(consts.NotFound))
+
+	request := httptest.NewRequest(http.MethodDelete, "/", nil)
+
+	recorder := httptest.NewRecorder()
+	context := echo_.NewContext(request, recorder)
+	context.SetPath("/api/users/:id/block")
+	context.SetParamNames("id")
+	context.SetParamValues(strconv.FormatUint(uint64(userBlockedId), 10))
+	context.Set(consts.EchoContextKeyUserId, userId)
+
+	handler := userDelivery.HandlerUserBlock(false)
+
+	err := handler(context)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
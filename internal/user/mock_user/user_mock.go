@@ -35,6 +35,20 @@ func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
 	return m.recorder
 }
 
+// BlockUser mocks base method.
+func (m *MockUsecase) BlockUser(arg0 *models.UserBlock) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockUsecaseMockRecorder) BlockUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockUsecase)(nil).BlockUser), arg0)
+}
+
 // CreateRoutePerm mocks base method.
 func (m *MockUsecase) CreateRoutePerm(arg0 *models.RoutePerm) *response.Response {
 	m.ctrl.T.Helper()
@@ -63,32 +77,60 @@ func (mr *MockUsecaseMockRecorder) CreateRouteTmp(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRouteTmp", reflect.TypeOf((*MockUsecase)(nil).CreateRouteTmp), arg0)
 }
 
+// CreateSavedSearch mocks base method.
+func (m *MockUsecase) CreateSavedSearch(arg0 *models.SavedSearch) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSavedSearch", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// CreateSavedSearch indicates an expected call of CreateSavedSearch.
+func (mr *MockUsecaseMockRecorder) CreateSavedSearch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSavedSearch", reflect.TypeOf((*MockUsecase)(nil).CreateSavedSearch), arg0)
+}
+
 // DeleteRoutePerm mocks base method.
-func (m *MockUsecase) DeleteRoutePerm(arg0, arg1 uint32) *response.Response {
+func (m *MockUsecase) DeleteRoutePerm(arg0, arg1, arg2 uint32) *response.Response {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRoutePerm", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteRoutePerm", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*response.Response)
 	return ret0
 }
 
 // DeleteRoutePerm indicates an expected call of DeleteRoutePerm.
-func (mr *MockUsecaseMockRecorder) DeleteRoutePerm(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockUsecaseMockRecorder) DeleteRoutePerm(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoutePerm", reflect.TypeOf((*MockUsecase)(nil).DeleteRoutePerm), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoutePerm", reflect.TypeOf((*MockUsecase)(nil).DeleteRoutePerm), arg0, arg1, arg2)
 }
 
 // DeleteRouteTmp mocks base method.
-func (m *MockUsecase) DeleteRouteTmp(arg0, arg1 uint32) *response.Response {
+func (m *MockUsecase) DeleteRouteTmp(arg0, arg1, arg2 uint32) *response.Response {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRouteTmp", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteRouteTmp", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*response.Response)
 	return ret0
 }
 
 // DeleteRouteTmp indicates an expected call of DeleteRouteTmp.
-func (mr *MockUsecaseMockRecorder) DeleteRouteTmp(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockUsecaseMockRecorder) DeleteRouteTmp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouteTmp", reflect.TypeOf((*MockUsecase)(nil).DeleteRouteTmp), arg0, arg1, arg2)
+}
+
+// DeleteSavedSearch mocks base method.
+func (m *MockUsecase) DeleteSavedSearch(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedSearch", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// DeleteSavedSearch indicates an expected call of DeleteSavedSearch.
+func (mr *MockUsecaseMockRecorder) DeleteSavedSearch(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouteTmp", reflect.TypeOf((*MockUsecase)(nil).DeleteRouteTmp), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedSearch", reflect.TypeOf((*MockUsecase)(nil).DeleteSavedSearch), arg0, arg1)
 }
 
 // Get mocks base method.
@@ -147,6 +189,20 @@ func (mr *MockUsecaseMockRecorder) ListRoutePerm(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoutePerm", reflect.TypeOf((*MockUsecase)(nil).ListRoutePerm), arg0)
 }
 
+// ListRoutePermAds mocks base method.
+func (m *MockUsecase) ListRoutePermAds(arg0, arg1 uint32, arg2 *models.AdMatchesCursor, arg3 *uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoutePermAds", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListRoutePermAds indicates an expected call of ListRoutePermAds.
+func (mr *MockUsecaseMockRecorder) ListRoutePermAds(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoutePermAds", reflect.TypeOf((*MockUsecase)(nil).ListRoutePermAds), arg0, arg1, arg2, arg3)
+}
+
 // ListRouteTmp mocks base method.
 func (m *MockUsecase) ListRouteTmp(arg0 uint32) *response.Response {
 	m.ctrl.T.Helper()
@@ -161,6 +217,62 @@ func (mr *MockUsecaseMockRecorder) ListRouteTmp(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRouteTmp", reflect.TypeOf((*MockUsecase)(nil).ListRouteTmp), arg0)
 }
 
+// ListRouteTmpAds mocks base method.
+func (m *MockUsecase) ListRouteTmpAds(arg0, arg1 uint32, arg2 *models.AdMatchesCursor, arg3 *uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRouteTmpAds", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListRouteTmpAds indicates an expected call of ListRouteTmpAds.
+func (mr *MockUsecaseMockRecorder) ListRouteTmpAds(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRouteTmpAds", reflect.TypeOf((*MockUsecase)(nil).ListRouteTmpAds), arg0, arg1, arg2, arg3)
+}
+
+// ListSavedSearch mocks base method.
+func (m *MockUsecase) ListSavedSearch(arg0 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSavedSearch", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListSavedSearch indicates an expected call of ListSavedSearch.
+func (mr *MockUsecaseMockRecorder) ListSavedSearch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSavedSearch", reflect.TypeOf((*MockUsecase)(nil).ListSavedSearch), arg0)
+}
+
+// ListUserBlocks mocks base method.
+func (m *MockUsecase) ListUserBlocks(arg0 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserBlocks", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListUserBlocks indicates an expected call of ListUserBlocks.
+func (mr *MockUsecaseMockRecorder) ListUserBlocks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserBlocks", reflect.TypeOf((*MockUsecase)(nil).ListUserBlocks), arg0)
+}
+
+// ListUserReviews mocks base method.
+func (m *MockUsecase) ListUserReviews(arg0 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserReviews", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// ListUserReviews indicates an expected call of ListUserReviews.
+func (mr *MockUsecaseMockRecorder) ListUserReviews(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserReviews", reflect.TypeOf((*MockUsecase)(nil).ListUserReviews), arg0)
+}
+
 // Login mocks base method.
 func (m *MockUsecase) Login(arg0 *models.User) *response.Response {
 	m.ctrl.T.Helper()
@@ -175,6 +287,62 @@ func (mr *MockUsecaseMockRecorder) Login(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUsecase)(nil).Login), arg0)
 }
 
+// PatchRoutePerm mocks base method.
+func (m *MockUsecase) PatchRoutePerm(arg0 *models.RoutePermPatch) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchRoutePerm", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// PatchRoutePerm indicates an expected call of PatchRoutePerm.
+func (mr *MockUsecaseMockRecorder) PatchRoutePerm(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchRoutePerm", reflect.TypeOf((*MockUsecase)(nil).PatchRoutePerm), arg0)
+}
+
+// PatchRouteTmp mocks base method.
+func (m *MockUsecase) PatchRouteTmp(arg0 *models.RouteTmpPatch) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchRouteTmp", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// PatchRouteTmp indicates an expected call of PatchRouteTmp.
+func (mr *MockUsecaseMockRecorder) PatchRouteTmp(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchRouteTmp", reflect.TypeOf((*MockUsecase)(nil).PatchRouteTmp), arg0)
+}
+
+// RenameSavedSearch mocks base method.
+func (m *MockUsecase) RenameSavedSearch(arg0 *models.SavedSearch) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameSavedSearch", arg0)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// RenameSavedSearch indicates an expected call of RenameSavedSearch.
+func (mr *MockUsecaseMockRecorder) RenameSavedSearch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSavedSearch", reflect.TypeOf((*MockUsecase)(nil).RenameSavedSearch), arg0)
+}
+
+// UnblockUser mocks base method.
+func (m *MockUsecase) UnblockUser(arg0, arg1 uint32) *response.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", arg0, arg1)
+	ret0, _ := ret[0].(*response.Response)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockUsecaseMockRecorder) UnblockUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockUsecase)(nil).UnblockUser), arg0, arg1)
+}
+
 // UpdateRoutePerm mocks base method.
 func (m *MockUsecase) UpdateRoutePerm(arg0 *models.RoutePerm) *response.Response {
 	m.ctrl.T.Helper()
@@ -227,33 +395,63 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // DeleteRoutePerm mocks base method.
-func (m *MockRepository) DeleteRoutePerm(arg0 uint32) (*models.RoutePerm, error) {
+func (m *MockRepository) DeleteRoutePerm(arg0, arg1 uint32) (*models.RoutePerm, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRoutePerm", arg0)
+	ret := m.ctrl.Call(m, "DeleteRoutePerm", arg0, arg1)
 	ret0, _ := ret[0].(*models.RoutePerm)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteRoutePerm indicates an expected call of DeleteRoutePerm.
-func (mr *MockRepositoryMockRecorder) DeleteRoutePerm(arg0 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteRoutePerm(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoutePerm", reflect.TypeOf((*MockRepository)(nil).DeleteRoutePerm), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoutePerm", reflect.TypeOf((*MockRepository)(nil).DeleteRoutePerm), arg0, arg1)
 }
 
 // DeleteRouteTmp mocks base method.
-func (m *MockRepository) DeleteRouteTmp(arg0 uint32) (*models.RouteTmp, error) {
+func (m *MockRepository) DeleteRouteTmp(arg0, arg1 uint32) (*models.RouteTmp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRouteTmp", arg0)
+	ret := m.ctrl.Call(m, "DeleteRouteTmp", arg0, arg1)
 	ret0, _ := ret[0].(*models.RouteTmp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteRouteTmp indicates an expected call of DeleteRouteTmp.
-func (mr *MockRepositoryMockRecorder) DeleteRouteTmp(arg0 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteRouteTmp(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouteTmp", reflect.TypeOf((*MockRepository)(nil).DeleteRouteTmp), arg0, arg1)
+}
+
+// DeleteSavedSearch mocks base method.
+func (m *MockRepository) DeleteSavedSearch(arg0 uint32) (*models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedSearch", arg0)
+	ret0, _ := ret[0].(*models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSavedSearch indicates an expected call of DeleteSavedSearch.
+func (mr *MockRepositoryMockRecorder) DeleteSavedSearch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedSearch", reflect.TypeOf((*MockRepository)(nil).DeleteSavedSearch), arg0)
+}
+
+// DeleteUserBlock mocks base method.
+func (m *MockRepository) DeleteUserBlock(arg0, arg1 uint32) (*models.UserBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserBlock", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserBlock indicates an expected call of DeleteUserBlock.
+func (mr *MockRepositoryMockRecorder) DeleteUserBlock(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRouteTmp", reflect.TypeOf((*MockRepository)(nil).DeleteRouteTmp), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserBlock", reflect.TypeOf((*MockRepository)(nil).DeleteUserBlock), arg0, arg1)
 }
 
 // Insert mocks base method.
@@ -301,6 +499,66 @@ func (mr *MockRepositoryMockRecorder) InsertRouteTmp(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRouteTmp", reflect.TypeOf((*MockRepository)(nil).InsertRouteTmp), arg0)
 }
 
+// InsertSavedSearch mocks base method.
+func (m *MockRepository) InsertSavedSearch(arg0 *models.SavedSearch) (*models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSavedSearch", arg0)
+	ret0, _ := ret[0].(*models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSavedSearch indicates an expected call of InsertSavedSearch.
+func (mr *MockRepositoryMockRecorder) InsertSavedSearch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSavedSearch", reflect.TypeOf((*MockRepository)(nil).InsertSavedSearch), arg0)
+}
+
+// InsertUserBlock mocks base method.
+func (m *MockRepository) InsertUserBlock(arg0 *models.UserBlock) (*models.UserBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserBlock", arg0)
+	ret0, _ := ret[0].(*models.UserBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertUserBlock indicates an expected call of InsertUserBlock.
+func (mr *MockRepositoryMockRecorder) InsertUserBlock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserBlock", reflect.TypeOf((*MockRepository)(nil).InsertUserBlock), arg0)
+}
+
+// PatchRoutePerm mocks base method.
+func (m *MockRepository) PatchRoutePerm(arg0 *models.RoutePermPatch) (*models.RoutePerm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchRoutePerm", arg0)
+	ret0, _ := ret[0].(*models.RoutePerm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchRoutePerm indicates an expected call of PatchRoutePerm.
+func (mr *MockRepositoryMockRecorder) PatchRoutePerm(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchRoutePerm", reflect.TypeOf((*MockRepository)(nil).PatchRoutePerm), arg0)
+}
+
+// PatchRouteTmp mocks base method.
+func (m *MockRepository) PatchRouteTmp(arg0 *models.RouteTmpPatch) (*models.RouteTmp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchRouteTmp", arg0)
+	ret0, _ := ret[0].(*models.RouteTmp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchRouteTmp indicates an expected call of PatchRouteTmp.
+func (mr *MockRepositoryMockRecorder) PatchRouteTmp(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchRouteTmp", reflect.TypeOf((*MockRepository)(nil).PatchRouteTmp), arg0)
+}
+
 // Select mocks base method.
 func (m *MockRepository) Select(arg0 uint32) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -316,6 +574,51 @@ func (mr *MockRepositoryMockRecorder) Select(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Select", reflect.TypeOf((*MockRepository)(nil).Select), arg0)
 }
 
+// SelectAdMatchArrayByRoutePermId mocks base method.
+func (m *MockRepository) SelectAdMatchArrayByRoutePermId(arg0 uint32, arg1 *models.AdMatchesCursor, arg2 uint32) (*models.AdMatches, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdMatchArrayByRoutePermId", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.AdMatches)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdMatchArrayByRoutePermId indicates an expected call of SelectAdMatchArrayByRoutePermId.
+func (mr *MockRepositoryMockRecorder) SelectAdMatchArrayByRoutePermId(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdMatchArrayByRoutePermId", reflect.TypeOf((*MockRepository)(nil).SelectAdMatchArrayByRoutePermId), arg0, arg1, arg2)
+}
+
+// SelectAdMatchArrayByRouteTmpId mocks base method.
+func (m *MockRepository) SelectAdMatchArrayByRouteTmpId(arg0 uint32, arg1 *models.AdMatchesCursor, arg2 uint32) (*models.AdMatches, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdMatchArrayByRouteTmpId", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.AdMatches)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdMatchArrayByRouteTmpId indicates an expected call of SelectAdMatchArrayByRouteTmpId.
+func (mr *MockRepositoryMockRecorder) SelectAdMatchArrayByRouteTmpId(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdMatchArrayByRouteTmpId", reflect.TypeOf((*MockRepository)(nil).SelectAdMatchArrayByRouteTmpId), arg0, arg1, arg2)
+}
+
+// SelectAdReviewArrayByUserTargetId mocks base method.
+func (m *MockRepository) SelectAdReviewArrayByUserTargetId(arg0 uint32) (*models.AdReviews, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAdReviewArrayByUserTargetId", arg0)
+	ret0, _ := ret[0].(*models.AdReviews)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAdReviewArrayByUserTargetId indicates an expected call of SelectAdReviewArrayByUserTargetId.
+func (mr *MockRepositoryMockRecorder) SelectAdReviewArrayByUserTargetId(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAdReviewArrayByUserTargetId", reflect.TypeOf((*MockRepository)(nil).SelectAdReviewArrayByUserTargetId), arg0)
+}
+
 // SelectByVkId mocks base method.
 func (m *MockRepository) SelectByVkId(arg0 uint32) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -391,6 +694,51 @@ func (mr *MockRepositoryMockRecorder) SelectRouteTmpArrayByUserAuthorId(arg0 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectRouteTmpArrayByUserAuthorId", reflect.TypeOf((*MockRepository)(nil).SelectRouteTmpArrayByUserAuthorId), arg0)
 }
 
+// SelectSavedSearch mocks base method.
+func (m *MockRepository) SelectSavedSearch(arg0 uint32) (*models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectSavedSearch", arg0)
+	ret0, _ := ret[0].(*models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectSavedSearch indicates an expected call of SelectSavedSearch.
+func (mr *MockRepositoryMockRecorder) SelectSavedSearch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectSavedSearch", reflect.TypeOf((*MockRepository)(nil).SelectSavedSearch), arg0)
+}
+
+// SelectSavedSearchArrayByUserAuthorId mocks base method.
+func (m *MockRepository) SelectSavedSearchArrayByUserAuthorId(arg0 uint32) (*models.SavedSearches, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectSavedSearchArrayByUserAuthorId", arg0)
+	ret0, _ := ret[0].(*models.SavedSearches)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectSavedSearchArrayByUserAuthorId indicates an expected call of SelectSavedSearchArrayByUserAuthorId.
+func (mr *MockRepositoryMockRecorder) SelectSavedSearchArrayByUserAuthorId(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectSavedSearchArrayByUserAuthorId", reflect.TypeOf((*MockRepository)(nil).SelectSavedSearchArrayByUserAuthorId), arg0)
+}
+
+// SelectUserBlockArrayByUserId mocks base method.
+func (m *MockRepository) SelectUserBlockArrayByUserId(arg0 uint32) (*models.UserBlocks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserBlockArrayByUserId", arg0)
+	ret0, _ := ret[0].(*models.UserBlocks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectUserBlockArrayByUserId indicates an expected call of SelectUserBlockArrayByUserId.
+func (mr *MockRepositoryMockRecorder) SelectUserBlockArrayByUserId(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserBlockArrayByUserId", reflect.TypeOf((*MockRepository)(nil).SelectUserBlockArrayByUserId), arg0)
+}
+
 // Update mocks base method.
 func (m *MockRepository) Update(arg0 *models.User) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -435,3 +783,18 @@ func (mr *MockRepositoryMockRecorder) UpdateRouteTmp(arg0 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRouteTmp", reflect.TypeOf((*MockRepository)(nil).UpdateRouteTmp), arg0)
 }
+
+// UpdateSavedSearchName mocks base method.
+func (m *MockRepository) UpdateSavedSearchName(arg0 uint32, arg1 string) (*models.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSavedSearchName", arg0, arg1)
+	ret0, _ := ret[0].(*models.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSavedSearchName indicates an expected call of UpdateSavedSearchName.
+func (mr *MockRepositoryMockRecorder) UpdateSavedSearchName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSavedSearchName", reflect.TypeOf((*MockRepository)(nil).UpdateSavedSearchName), arg0, arg1)
+}
@@ -11,10 +11,23 @@ type Repository interface {
 	SelectRouteTmp(routeTmpId uint32) (*models.RouteTmp, error)
 	SelectRouteTmpArrayByUserAuthorId(userAuthorId uint32) (*models.RoutesTmp, error)
 	UpdateRouteTmp(routeTmp *models.RouteTmp) (*models.RouteTmp, error)
-	DeleteRouteTmp(routeTmpId uint32) (*models.RouteTmp, error)
+	PatchRouteTmp(routeTmpPatch *models.RouteTmpPatch) (*models.RouteTmp, error)
+	DeleteRouteTmp(routeTmpId uint32, version uint32) (*models.RouteTmp, error)
 	InsertRoutePerm(routePerm *models.RoutePerm) (*models.RoutePerm, error)
 	SelectRoutePerm(routePermId uint32) (*models.RoutePerm, error)
 	UpdateRoutePerm(routePerm *models.RoutePerm) (*models.RoutePerm, error)
-	DeleteRoutePerm(routePermId uint32) (*models.RoutePerm, error)
+	PatchRoutePerm(routePermPatch *models.RoutePermPatch) (*models.RoutePerm, error)
+	DeleteRoutePerm(routePermId uint32, version uint32) (*models.RoutePerm, error)
 	SelectRoutePermArrayByUserAuthorId(userAuthorId uint32) (*models.RoutesPerm, error)
+	SelectAdMatchArrayByRouteTmpId(routeTmpId uint32, cursor *models.AdMatchesCursor, limit uint32) (*models.AdMatches, error)
+	SelectAdMatchArrayByRoutePermId(routePermId uint32, cursor *models.AdMatchesCursor, limit uint32) (*models.AdMatches, error)
+	InsertSavedSearch(savedSearch *models.SavedSearch) (*models.SavedSearch, error)
+	SelectSavedSearch(savedSearchId uint32) (*models.SavedSearch, error)
+	SelectSavedSearchArrayByUserAuthorId(userAuthorId uint32) (*models.SavedSearches, error)
+	UpdateSavedSearchName(savedSearchId uint32, name string) (*models.SavedSearch, error)
+	DeleteSavedSearch(savedSearchId uint32) (*models.SavedSearch, error)
+	SelectAdReviewArrayByUserTargetId(userTargetId uint32) (*models.AdReviews, error)
+	InsertUserBlock(userBlock *models.UserBlock) (*models.UserBlock, error)
+	DeleteUserBlock(userId uint32, userBlockedId uint32) (*models.UserBlock, error)
+	SelectUserBlockArrayByUserId(userId uint32) (*models.UserBlocks, error)
 }
@@ -4,11 +4,16 @@ import (
 	"database/sql"
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+	"github.com/TechnoHandOver/backend/internal/tools/geo"
 	"github.com/TechnoHandOver/backend/internal/user"
+	"github.com/lib/pq"
 	"strconv"
 	"time"
 )
 
+const routeSuitableRadiusM = 1000
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -20,10 +25,10 @@ func NewUserRepositoryImpl(db *sql.DB) user.Repository {
 }
 
 func (userRepository *UserRepository) Insert(user_ *models.User) (*models.User, error) {
-	const query = "INSERT INTO user_ (vk_id, name, avatar) VALUES ($1, $2, $3) RETURNING id, vk_id, name, avatar"
+	const query = "INSERT INTO user_ (vk_id, name, avatar) VALUES ($1, $2, $3) RETURNING id, vk_id, name, avatar, rating, rating_count, suspended"
 
 	if err := userRepository.db.QueryRow(query, user_.VkId, user_.Name, user_.Avatar).Scan(&user_.Id, &user_.VkId,
-		&user_.Name, &user_.Avatar); err != nil {
+		&user_.Name, &user_.Avatar, &user_.Rating, &user_.RatingCount, &user_.Suspended); err != nil {
 		return nil, err
 	}
 
@@ -31,11 +36,11 @@ func (userRepository *UserRepository) Insert(user_ *models.User) (*models.User,
 }
 
 func (userRepository *UserRepository) Select(id uint32) (*models.User, error) {
-	const query = "SELECT id, vk_id, name, avatar FROM user_ WHERE id = $1"
+	const query = "SELECT id, vk_id, name, avatar, rating, rating_count, suspended FROM user_ WHERE id = $1"
 
 	user_ := new(models.User)
-	if err := userRepository.db.QueryRow(query, id).Scan(&user_.Id, &user_.VkId, &user_.Name,
-		&user_.Avatar); err != nil {
+	if err := userRepository.db.QueryRow(query, id).Scan(&user_.Id, &user_.VkId, &user_.Name, &user_.Avatar,
+		&user_.Rating, &user_.RatingCount, &user_.Suspended); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
@@ -47,11 +52,12 @@ func (userRepository *UserRepository) Select(id uint32) (*models.User, error) {
 }
 
 func (userRepository *UserRepository) SelectByVkId(vkId uint32) (*models.User, error) {
-	const query = "SELECT id, vk_id, name, avatar FROM user_ WHERE vk_id = $1"
+	const query = "SELECT id, vk_id, name, avatar, rating, rating_count, suspended FROM user_ WHERE vk_id = $1"
 
 	user_ := new(models.User)
 	var avatar sql.NullString
-	if err := userRepository.db.QueryRow(query, vkId).Scan(&user_.Id, &user_.VkId, &user_.Name, &avatar); err != nil {
+	if err := userRepository.db.QueryRow(query, vkId).Scan(&user_.Id, &user_.VkId, &user_.Name, &avatar,
+		&user_.Rating, &user_.RatingCount, &user_.Suspended); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
@@ -71,7 +77,7 @@ func (userRepository *UserRepository) Update(user_ *models.User) (*models.User,
 	const queryAvatar = "avatar"
 	const queryEquals = " = $"
 	const queryComma = ", "
-	const queryEnd = "WHERE id = $1 RETURNING id, vk_id, name, avatar"
+	const queryEnd = "WHERE id = $1 RETURNING id, vk_id, name, avatar, rating, rating_count, suspended"
 
 	query := queryStart
 	queryArgs := make([]interface{}, 0)
@@ -95,7 +101,8 @@ func (userRepository *UserRepository) Update(user_ *models.User) (*models.User,
 
 	updatedUser := new(models.User)
 	if err := userRepository.db.QueryRow(query, queryArgs...).Scan(&updatedUser.Id, &updatedUser.VkId,
-		&updatedUser.Name, &updatedUser.Avatar); err != nil {
+		&updatedUser.Name, &updatedUser.Avatar, &updatedUser.Rating, &updatedUser.RatingCount,
+		&updatedUser.Suspended); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
@@ -108,14 +115,21 @@ func (userRepository *UserRepository) Update(user_ *models.User) (*models.User,
 
 func (userRepository *UserRepository) InsertRouteTmp(routeTmp *models.RouteTmp) (*models.RouteTmp, error) {
 	const query = `
-INSERT INTO view_route_tmp (user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr`
+INSERT INTO view_route_tmp (user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point,
+                            loc_dep_place_id, loc_arr_place_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
 
 	if err := userRepository.db.QueryRow(query, routeTmp.UserAuthorId, routeTmp.LocDep, routeTmp.LocArr,
-		routeTmp.MinPrice, time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr)).Scan(&routeTmp.Id,
-		&routeTmp.UserAuthorId, &routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep,
-		&routeTmp.DateTimeArr); err != nil {
+		routeTmp.MinPrice, time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr), routeTmp.LocDepPoint,
+		routeTmp.LocArrPoint, routeTmp.LocDepPlaceId, routeTmp.LocArrPlaceId).Scan(&routeTmp.Id, &routeTmp.UserAuthorId,
+		&routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep, &routeTmp.DateTimeArr,
+		&routeTmp.LocDepPoint, &routeTmp.LocArrPoint, &routeTmp.LocDepPlaceId, &routeTmp.LocArrPlaceId,
+		&routeTmp.Version); err != nil {
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return nil, consts.RepErrNotFound
+		}
+
 		return nil, err
 	}
 
@@ -124,13 +138,13 @@ RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_t
 
 func (userRepository *UserRepository) SelectRouteTmp(routeTmpId uint32) (*models.RouteTmp, error) {
 	const query = `
-SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr FROM view_route_tmp
+SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_tmp
 WHERE id = $1`
 
 	routeTmp := new(models.RouteTmp)
-	if err := userRepository.db.QueryRow(query, routeTmpId).Scan(&routeTmp.Id, &routeTmp.UserAuthorId,
-		&routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep,
-		&routeTmp.DateTimeArr); err != nil {
+	if err := userRepository.db.QueryRow(query, routeTmpId).Scan(&routeTmp.Id, &routeTmp.UserAuthorId, &routeTmp.LocDep,
+		&routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep, &routeTmp.DateTimeArr, &routeTmp.LocDepPoint,
+		&routeTmp.LocArrPoint, &routeTmp.LocDepPlaceId, &routeTmp.LocArrPlaceId, &routeTmp.Version); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
@@ -143,7 +157,7 @@ WHERE id = $1`
 
 func (userRepository *UserRepository) SelectRouteTmpArrayByUserAuthorId(userAuthorId uint32) (*models.RoutesTmp, error) {
 	const query = `
-SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr FROM view_route_tmp
+SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_tmp
 WHERE user_author_id = $1
 ORDER BY date_time_dep, date_time_arr, min_price DESC, id`
 
@@ -159,7 +173,8 @@ ORDER BY date_time_dep, date_time_arr, min_price DESC, id`
 	for rows.Next() {
 		routeTmp := new(models.RouteTmp)
 		if err := rows.Scan(&routeTmp.Id, &routeTmp.UserAuthorId, &routeTmp.LocDep, &routeTmp.LocArr,
-			&routeTmp.MinPrice, &routeTmp.DateTimeDep, &routeTmp.DateTimeArr); err != nil {
+			&routeTmp.MinPrice, &routeTmp.DateTimeDep, &routeTmp.DateTimeArr, &routeTmp.LocDepPoint,
+			&routeTmp.LocArrPoint, &routeTmp.LocDepPlaceId, &routeTmp.LocArrPlaceId, &routeTmp.Version); err != nil {
 			return nil, err
 		}
 
@@ -171,15 +186,21 @@ ORDER BY date_time_dep, date_time_arr, min_price DESC, id`
 
 func (userRepository *UserRepository) UpdateRouteTmp(routeTmp *models.RouteTmp) (*models.RouteTmp, error) {
 	const query = `
-UPDATE view_route_tmp SET loc_dep = $2, loc_arr = $3, min_price = $4, date_time_dep = $5, date_time_arr = $6
-WHERE id = $1
-RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr`
+UPDATE view_route_tmp SET loc_dep = $2, loc_arr = $3, min_price = $4, date_time_dep = $5, date_time_arr = $6, loc_dep_point = $7, loc_arr_point = $8,
+                          loc_dep_place_id = $9, loc_arr_place_id = $10
+WHERE id = $1 AND (version = $11 OR $11 = 0)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
 
 	if err := userRepository.db.QueryRow(query, routeTmp.Id, routeTmp.LocDep, routeTmp.LocArr, routeTmp.MinPrice,
-		time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr)).Scan(&routeTmp.Id, &routeTmp.UserAuthorId,
-		&routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep,
-		&routeTmp.DateTimeArr); err != nil {
+		time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr), routeTmp.LocDepPoint, routeTmp.LocArrPoint,
+		routeTmp.LocDepPlaceId, routeTmp.LocArrPlaceId, routeTmp.Version).Scan(&routeTmp.Id, &routeTmp.UserAuthorId,
+		&routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep, &routeTmp.DateTimeArr,
+		&routeTmp.LocDepPoint, &routeTmp.LocArrPoint, &routeTmp.LocDepPlaceId, &routeTmp.LocArrPlaceId,
+		&routeTmp.Version); err != nil {
 		if err == sql.ErrNoRows {
+			return nil, consts.RepErrPreconditionFailed
+		}
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
 			return nil, consts.RepErrNotFound
 		}
 
@@ -189,18 +210,114 @@ RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_t
 	return routeTmp, nil
 }
 
-func (userRepository *UserRepository) DeleteRouteTmp(routeTmpId uint32) (*models.RouteTmp, error) {
+func (userRepository *UserRepository) PatchRouteTmp(routeTmpPatch *models.RouteTmpPatch) (*models.RouteTmp, error) {
+	const queryStart = "UPDATE view_route_tmp SET "
+	const queryLocDep = "loc_dep"
+	const queryLocArr = "loc_arr"
+	const queryLocDepPoint = "loc_dep_point"
+	const queryLocArrPoint = "loc_arr_point"
+	const queryLocDepPlaceId = "loc_dep_place_id"
+	const queryLocArrPlaceId = "loc_arr_place_id"
+	const queryMinPrice = "min_price"
+	const queryDateTimeDep = "date_time_dep"
+	const queryDateTimeArr = "date_time_arr"
+	const queryEquals = " = $"
+	const queryComma = ", "
+	const queryEnd = `
+WHERE id = $1 AND (version = $2 OR $2 = 0)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
+
+	query := queryStart
+	queryArgs := make([]interface{}, 0)
+	queryArgs = append(queryArgs, routeTmpPatch.Id, routeTmpPatch.Version)
+	set := func(queryColumn string, queryArg interface{}) {
+		query += queryColumn + queryEquals + strconv.Itoa(len(queryArgs)+1) + queryComma
+		queryArgs = append(queryArgs, queryArg)
+	}
+
+	if routeTmpPatch.LocDep != nil {
+		set(queryLocDep, *routeTmpPatch.LocDep)
+	}
+	if routeTmpPatch.LocArr != nil {
+		set(queryLocArr, *routeTmpPatch.LocArr)
+	}
+	if routeTmpPatch.LocDepPointNull {
+		set(queryLocDepPoint, nil)
+	} else if routeTmpPatch.LocDepPoint != nil {
+		set(queryLocDepPoint, *routeTmpPatch.LocDepPoint)
+	}
+	if routeTmpPatch.LocArrPointNull {
+		set(queryLocArrPoint, nil)
+	} else if routeTmpPatch.LocArrPoint != nil {
+		set(queryLocArrPoint, *routeTmpPatch.LocArrPoint)
+	}
+	if routeTmpPatch.LocDepPlaceIdNull {
+		set(queryLocDepPlaceId, nil)
+	} else if routeTmpPatch.LocDepPlaceId != nil {
+		set(queryLocDepPlaceId, *routeTmpPatch.LocDepPlaceId)
+	}
+	if routeTmpPatch.LocArrPlaceIdNull {
+		set(queryLocArrPlaceId, nil)
+	} else if routeTmpPatch.LocArrPlaceId != nil {
+		set(queryLocArrPlaceId, *routeTmpPatch.LocArrPlaceId)
+	}
+	if routeTmpPatch.MinPrice != nil {
+		set(queryMinPrice, *routeTmpPatch.MinPrice)
+	}
+	if routeTmpPatch.DateTimeDep != nil {
+		set(queryDateTimeDep, time.Time(*routeTmpPatch.DateTimeDep))
+	}
+	if routeTmpPatch.DateTimeArr != nil {
+		set(queryDateTimeArr, time.Time(*routeTmpPatch.DateTimeArr))
+	}
+
+	//an empty merge patch changes nothing, but it is still conditional
+	if len(queryArgs) == 2 {
+		routeTmp, err := userRepository.SelectRouteTmp(routeTmpPatch.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !etag.Matches(routeTmpPatch.Version, routeTmp.Version) {
+			return nil, consts.RepErrPreconditionFailed
+		}
+
+		return routeTmp, nil
+	}
+
+	query = query[:len(query)-2] + queryEnd
+
+	routeTmp := new(models.RouteTmp)
+	if err := userRepository.db.QueryRow(query, queryArgs...).Scan(&routeTmp.Id, &routeTmp.UserAuthorId,
+		&routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep, &routeTmp.DateTimeArr,
+		&routeTmp.LocDepPoint, &routeTmp.LocArrPoint, &routeTmp.LocDepPlaceId, &routeTmp.LocArrPlaceId,
+		&routeTmp.Version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrPreconditionFailed
+		}
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return routeTmp, nil
+}
+
+func (userRepository *UserRepository) DeleteRouteTmp(routeTmpId uint32, version uint32) (*models.RouteTmp, error) {
 	const query = `
 DELETE FROM view_route_tmp
-WHERE id = $1
-RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr`
+WHERE id = $1 AND (version = $2 OR $2 = 0)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
 
 	routeTmp := new(models.RouteTmp)
-	if err := userRepository.db.QueryRow(query, routeTmpId).Scan(&routeTmp.Id, &routeTmp.UserAuthorId,
-		&routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep,
-		&routeTmp.DateTimeArr); err != nil {
+	if err := userRepository.db.QueryRow(query, routeTmpId, version).Scan(&routeTmp.Id, &routeTmp.UserAuthorId,
+		&routeTmp.LocDep, &routeTmp.LocArr, &routeTmp.MinPrice, &routeTmp.DateTimeDep, &routeTmp.DateTimeArr,
+		&routeTmp.LocDepPoint, &routeTmp.LocArrPoint, &routeTmp.LocDepPlaceId, &routeTmp.LocArrPlaceId,
+		&routeTmp.Version); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, consts.RepErrNotFound
+			return nil, consts.RepErrPreconditionFailed
 		}
 
 		return nil, err
@@ -211,15 +328,22 @@ RETURNING id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_t
 
 func (userRepository *UserRepository) InsertRoutePerm(routePerm *models.RoutePerm) (*models.RoutePerm, error) {
 	const query = `
-INSERT INTO view_route_perm (user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr`
+INSERT INTO view_route_perm (user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point,
+                             loc_dep_place_id, loc_arr_place_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
 
 	if err := userRepository.db.QueryRow(query, routePerm.UserAuthorId, routePerm.LocDep, routePerm.LocArr,
 		routePerm.MinPrice, routePerm.EvenWeek, routePerm.OddWeek, routePerm.DayOfWeek, time.Time(routePerm.TimeDep),
-		time.Time(routePerm.TimeArr)).Scan(&routePerm.Id, &routePerm.UserAuthorId, &routePerm.LocDep, &routePerm.LocArr,
+		time.Time(routePerm.TimeArr), routePerm.LocDepPoint, routePerm.LocArrPoint, routePerm.LocDepPlaceId,
+		routePerm.LocArrPlaceId).Scan(&routePerm.Id, &routePerm.UserAuthorId, &routePerm.LocDep, &routePerm.LocArr,
 		&routePerm.MinPrice, &routePerm.EvenWeek, &routePerm.OddWeek, &routePerm.DayOfWeek, &routePerm.TimeDep,
-		&routePerm.TimeArr); err != nil {
+		&routePerm.TimeArr, &routePerm.LocDepPoint, &routePerm.LocArrPoint, &routePerm.LocDepPlaceId,
+		&routePerm.LocArrPlaceId, &routePerm.Version); err != nil {
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return nil, consts.RepErrNotFound
+		}
+
 		return nil, err
 	}
 
@@ -228,13 +352,14 @@ RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week,
 
 func (userRepository *UserRepository) SelectRoutePerm(routePermId uint32) (*models.RoutePerm, error) {
 	const query = `
-SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr FROM view_route_perm
+SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_perm
 WHERE id = $1`
 
 	routePerm := new(models.RoutePerm)
 	if err := userRepository.db.QueryRow(query, routePermId).Scan(&routePerm.Id, &routePerm.UserAuthorId,
 		&routePerm.LocDep, &routePerm.LocArr, &routePerm.MinPrice, &routePerm.EvenWeek, &routePerm.OddWeek,
-		&routePerm.DayOfWeek, &routePerm.TimeDep, &routePerm.TimeArr); err != nil {
+		&routePerm.DayOfWeek, &routePerm.TimeDep, &routePerm.TimeArr, &routePerm.LocDepPoint, &routePerm.LocArrPoint,
+		&routePerm.LocDepPlaceId, &routePerm.LocArrPlaceId, &routePerm.Version); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, consts.RepErrNotFound
 		}
@@ -247,16 +372,22 @@ WHERE id = $1`
 
 func (userRepository *UserRepository) UpdateRoutePerm(routePerm *models.RoutePerm) (*models.RoutePerm, error) {
 	const query = `
-UPDATE view_route_perm SET loc_dep = $2, loc_arr = $3, min_price = $4, even_week = $5, odd_week = $6, day_of_week = $7, time_dep = $8, time_arr = $9
-WHERE id = $1
-RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr`
+UPDATE view_route_perm SET loc_dep = $2, loc_arr = $3, min_price = $4, even_week = $5, odd_week = $6, day_of_week = $7, time_dep = $8, time_arr = $9, loc_dep_point = $10, loc_arr_point = $11,
+                           loc_dep_place_id = $12, loc_arr_place_id = $13
+WHERE id = $1 AND (version = $14 OR $14 = 0)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
 
 	if err := userRepository.db.QueryRow(query, routePerm.Id, routePerm.LocDep, routePerm.LocArr, routePerm.MinPrice,
 		routePerm.EvenWeek, routePerm.OddWeek, routePerm.DayOfWeek, time.Time(routePerm.TimeDep),
-		time.Time(routePerm.TimeArr)).Scan(&routePerm.Id, &routePerm.UserAuthorId, &routePerm.LocDep, &routePerm.LocArr,
-		&routePerm.MinPrice, &routePerm.EvenWeek, &routePerm.OddWeek, &routePerm.DayOfWeek, &routePerm.TimeDep,
-		&routePerm.TimeArr); err != nil {
+		time.Time(routePerm.TimeArr), routePerm.LocDepPoint, routePerm.LocArrPoint, routePerm.LocDepPlaceId,
+		routePerm.LocArrPlaceId, routePerm.Version).Scan(&routePerm.Id, &routePerm.UserAuthorId, &routePerm.LocDep,
+		&routePerm.LocArr, &routePerm.MinPrice, &routePerm.EvenWeek, &routePerm.OddWeek, &routePerm.DayOfWeek,
+		&routePerm.TimeDep, &routePerm.TimeArr, &routePerm.LocDepPoint, &routePerm.LocArrPoint, &routePerm.LocDepPlaceId,
+		&routePerm.LocArrPlaceId, &routePerm.Version); err != nil {
 		if err == sql.ErrNoRows {
+			return nil, consts.RepErrPreconditionFailed
+		}
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
 			return nil, consts.RepErrNotFound
 		}
 
@@ -266,18 +397,126 @@ RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week,
 	return routePerm, nil
 }
 
-func (userRepository *UserRepository) DeleteRoutePerm(routePermId uint32) (*models.RoutePerm, error) {
+func (userRepository *UserRepository) PatchRoutePerm(routePermPatch *models.RoutePermPatch) (*models.RoutePerm, error) {
+	const queryStart = "UPDATE view_route_perm SET "
+	const queryLocDep = "loc_dep"
+	const queryLocArr = "loc_arr"
+	const queryLocDepPoint = "loc_dep_point"
+	const queryLocArrPoint = "loc_arr_point"
+	const queryLocDepPlaceId = "loc_dep_place_id"
+	const queryLocArrPlaceId = "loc_arr_place_id"
+	const queryMinPrice = "min_price"
+	const queryEvenWeek = "even_week"
+	const queryOddWeek = "odd_week"
+	const queryDayOfWeek = "day_of_week"
+	const queryTimeDep = "time_dep"
+	const queryTimeArr = "time_arr"
+	const queryEquals = " = $"
+	const queryComma = ", "
+	const queryEnd = `
+WHERE id = $1 AND (version = $2 OR $2 = 0)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
+
+	query := queryStart
+	queryArgs := make([]interface{}, 0)
+	queryArgs = append(queryArgs, routePermPatch.Id, routePermPatch.Version)
+	set := func(queryColumn string, queryArg interface{}) {
+		query += queryColumn + queryEquals + strconv.Itoa(len(queryArgs)+1) + queryComma
+		queryArgs = append(queryArgs, queryArg)
+	}
+
+	if routePermPatch.LocDep != nil {
+		set(queryLocDep, *routePermPatch.LocDep)
+	}
+	if routePermPatch.LocArr != nil {
+		set(queryLocArr, *routePermPatch.LocArr)
+	}
+	if routePermPatch.LocDepPointNull {
+		set(queryLocDepPoint, nil)
+	} else if routePermPatch.LocDepPoint != nil {
+		set(queryLocDepPoint, *routePermPatch.LocDepPoint)
+	}
+	if routePermPatch.LocArrPointNull {
+		set(queryLocArrPoint, nil)
+	} else if routePermPatch.LocArrPoint != nil {
+		set(queryLocArrPoint, *routePermPatch.LocArrPoint)
+	}
+	if routePermPatch.LocDepPlaceIdNull {
+		set(queryLocDepPlaceId, nil)
+	} else if routePermPatch.LocDepPlaceId != nil {
+		set(queryLocDepPlaceId, *routePermPatch.LocDepPlaceId)
+	}
+	if routePermPatch.LocArrPlaceIdNull {
+		set(queryLocArrPlaceId, nil)
+	} else if routePermPatch.LocArrPlaceId != nil {
+		set(queryLocArrPlaceId, *routePermPatch.LocArrPlaceId)
+	}
+	if routePermPatch.MinPrice != nil {
+		set(queryMinPrice, *routePermPatch.MinPrice)
+	}
+	if routePermPatch.EvenWeek != nil {
+		set(queryEvenWeek, *routePermPatch.EvenWeek)
+	}
+	if routePermPatch.OddWeek != nil {
+		set(queryOddWeek, *routePermPatch.OddWeek)
+	}
+	if routePermPatch.DayOfWeek != nil {
+		set(queryDayOfWeek, *routePermPatch.DayOfWeek)
+	}
+	if routePermPatch.TimeDep != nil {
+		set(queryTimeDep, time.Time(*routePermPatch.TimeDep))
+	}
+	if routePermPatch.TimeArr != nil {
+		set(queryTimeArr, time.Time(*routePermPatch.TimeArr))
+	}
+
+	//an empty merge patch changes nothing, but it is still conditional
+	if len(queryArgs) == 2 {
+		routePerm, err := userRepository.SelectRoutePerm(routePermPatch.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !etag.Matches(routePermPatch.Version, routePerm.Version) {
+			return nil, consts.RepErrPreconditionFailed
+		}
+
+		return routePerm, nil
+	}
+
+	query = query[:len(query)-2] + queryEnd
+
+	routePerm := new(models.RoutePerm)
+	if err := userRepository.db.QueryRow(query, queryArgs...).Scan(&routePerm.Id, &routePerm.UserAuthorId,
+		&routePerm.LocDep, &routePerm.LocArr, &routePerm.MinPrice, &routePerm.EvenWeek, &routePerm.OddWeek,
+		&routePerm.DayOfWeek, &routePerm.TimeDep, &routePerm.TimeArr, &routePerm.LocDepPoint, &routePerm.LocArrPoint,
+		&routePerm.LocDepPlaceId, &routePerm.LocArrPlaceId, &routePerm.Version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrPreconditionFailed
+		}
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23503" {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return routePerm, nil
+}
+
+func (userRepository *UserRepository) DeleteRoutePerm(routePermId uint32, version uint32) (*models.RoutePerm, error) {
 	const query = `
 DELETE FROM view_route_perm
-WHERE id = $1
-RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr`
+WHERE id = $1 AND (version = $2 OR $2 = 0)
+RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version`
 
 	routePerm := new(models.RoutePerm)
-	if err := userRepository.db.QueryRow(query, routePermId).Scan(&routePerm.Id, &routePerm.UserAuthorId,
+	if err := userRepository.db.QueryRow(query, routePermId, version).Scan(&routePerm.Id, &routePerm.UserAuthorId,
 		&routePerm.LocDep, &routePerm.LocArr, &routePerm.MinPrice, &routePerm.EvenWeek, &routePerm.OddWeek,
-		&routePerm.DayOfWeek, &routePerm.TimeDep, &routePerm.TimeArr); err != nil {
+		&routePerm.DayOfWeek, &routePerm.TimeDep, &routePerm.TimeArr, &routePerm.LocDepPoint, &routePerm.LocArrPoint,
+		&routePerm.LocDepPlaceId, &routePerm.LocArrPlaceId, &routePerm.Version); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, consts.RepErrNotFound
+			return nil, consts.RepErrPreconditionFailed
 		}
 
 		return nil, err
@@ -288,7 +527,7 @@ RETURNING id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week,
 
 func (userRepository *UserRepository) SelectRoutePermArrayByUserAuthorId(userAuthorId uint32) (*models.RoutesPerm, error) {
 	const query = `
-SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr FROM view_route_perm
+SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_perm
 WHERE user_author_id = $1
 ORDER BY day_of_week, time_dep, time_arr, even_week, odd_week, min_price DESC, id`
 
@@ -305,7 +544,8 @@ ORDER BY day_of_week, time_dep, time_arr, even_week, odd_week, min_price DESC, i
 		routePerm := new(models.RoutePerm)
 		if err := rows.Scan(&routePerm.Id, &routePerm.UserAuthorId, &routePerm.LocDep, &routePerm.LocArr,
 			&routePerm.MinPrice, &routePerm.EvenWeek, &routePerm.OddWeek, &routePerm.DayOfWeek, &routePerm.TimeDep,
-			&routePerm.TimeArr); err != nil {
+			&routePerm.TimeArr, &routePerm.LocDepPoint, &routePerm.LocArrPoint, &routePerm.LocDepPlaceId,
+			&routePerm.LocArrPlaceId, &routePerm.Version); err != nil {
 			return nil, err
 		}
 
@@ -314,3 +554,314 @@ ORDER BY day_of_week, time_dep, time_arr, even_week, odd_week, min_price DESC, i
 
 	return &routesPerm, nil
 }
+
+func (userRepository *UserRepository) SelectAdMatchArrayByRouteTmpId(routeTmpId uint32, cursor *models.AdMatchesCursor, limit uint32) (*models.AdMatches, error) {
+	const queryRouteView = "view_route_tmp"
+	const queryRouteCondition = `coalesce(ad.date_time_dep, ad.date_time_arr) <= route.date_time_arr AND
+            ad.date_time_arr >= route.date_time_dep`
+
+	return userRepository.selectAdMatchArray(queryRouteView, queryRouteCondition, routeTmpId, cursor, limit)
+}
+
+func (userRepository *UserRepository) SelectAdMatchArrayByRoutePermId(routePermId uint32, cursor *models.AdMatchesCursor, limit uint32) (*models.AdMatches, error) {
+	const queryRouteView = "view_route_perm"
+	const queryRouteCondition = `extract(ISODOW FROM ad.date_time_arr) = route.day_of_week AND
+            route.time_dep::time <= ad.date_time_arr::time AND
+            route.time_arr::time >= greatest(coalesce(ad.date_time_dep, ad.date_time_arr),
+                                             date_trunc('day', ad.date_time_arr))::time`
+
+	return userRepository.selectAdMatchArray(queryRouteView, queryRouteCondition, routePermId, cursor, limit)
+}
+
+func (userRepository *UserRepository) selectAdMatchArray(queryRouteView string, queryRouteCondition string,
+	routeId uint32, cursor *models.AdMatchesCursor, limit uint32) (*models.AdMatches, error) {
+	//the same location, price and time window matching as NotificationRepository, but from the route's side; each
+	//location adds up to 1/2 to the fit: exactly for the same place, by distance for points, and 1/4 for text matches
+	var query = `
+SELECT id, user_author_id, user_author_vk_id, user_author_name, user_author_avatar, user_executor_vk_id, loc_dep, loc_arr, date_time_arr, item, min_price, comment, status, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, date_time_dep, fit
+FROM (SELECT ad.id, ad.user_author_id, ad.user_author_vk_id, ad.user_author_name, ad.user_author_avatar,
+             ad.user_executor_vk_id, ad.loc_dep, ad.loc_arr, ad.date_time_arr, ad.item, ad.min_price, ad.comment,
+             ad.status, ad.loc_dep_point, ad.loc_arr_point, ad.loc_dep_place_id, ad.loc_arr_place_id,
+             ad.date_time_dep,
+             (CASE WHEN ad.loc_dep_place_id = route.loc_dep_place_id THEN 1
+                   WHEN ad.loc_dep_point IS NOT NULL AND route.loc_dep_point IS NOT NULL
+                       THEN greatest(0, 1 - ` + geo.DistanceExpression("ad.loc_dep_point", "route.loc_dep_point") + ` / $2)
+                   ELSE 0.5 END +
+              CASE WHEN ad.loc_arr_place_id = route.loc_arr_place_id THEN 1
+                   WHEN ad.loc_arr_point IS NOT NULL AND route.loc_arr_point IS NOT NULL
+                       THEN greatest(0, 1 - ` + geo.DistanceExpression("ad.loc_arr_point", "route.loc_arr_point") + ` / $2)
+                   ELSE 0.5 END) / 2 AS fit
+      FROM ad
+          JOIN ` + queryRouteView + ` AS route ON route.id = $1
+      WHERE ad.status = 'open' AND
+            NOT ad.hidden AND
+            ad.user_author_id != route.user_author_id AND
+            NOT user_blocked(ad.user_author_id, route.user_author_id) AND
+            coalesce(ad.loc_dep_place_id = route.loc_dep_place_id,
+                     to_tsvector('russian', route.loc_dep) @@ plainto_tsquery('russian', ad.loc_dep) OR
+                     ` + geo.DistanceExpression("ad.loc_dep_point", "route.loc_dep_point") + ` <= $2) AND
+            coalesce(ad.loc_arr_place_id = route.loc_arr_place_id,
+                     to_tsvector('russian', route.loc_arr) @@ plainto_tsquery('russian', ad.loc_arr) OR
+                     ` + geo.DistanceExpression("ad.loc_arr_point", "route.loc_arr_point") + ` <= $2) AND
+            ad.min_price >= route.min_price AND
+            ` + queryRouteCondition + `) AS ad_
+WHERE $3::float8 IS NULL OR fit < $3 OR fit = $3 AND id < $4
+ORDER BY fit DESC, id DESC
+LIMIT $5`
+
+	var cursorFit, cursorId interface{}
+	if cursor != nil {
+		cursorFit, cursorId = cursor.Fit, cursor.Id
+	}
+
+	rows, err := userRepository.db.Query(query, routeId, routeSuitableRadiusM, cursorFit, cursorId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adMatches := make(models.AdMatches, 0)
+	for rows.Next() {
+		adMatch := &models.AdMatch{Ad: new(models.Ad)}
+		var userExecutorVkId sql.NullInt32
+		if err := rows.Scan(&adMatch.Id, &adMatch.UserAuthorId, &adMatch.UserAuthorVkId, &adMatch.UserAuthorName,
+			&adMatch.UserAuthorAvatar, &userExecutorVkId, &adMatch.LocDep, &adMatch.LocArr, &adMatch.DateTimeArr,
+			&adMatch.Item, &adMatch.MinPrice, &adMatch.Comment, &adMatch.Status, &adMatch.LocDepPoint,
+			&adMatch.LocArrPoint, &adMatch.LocDepPlaceId, &adMatch.LocArrPlaceId, &adMatch.DateTimeDep,
+			&adMatch.Fit); err != nil {
+			return nil, err
+		}
+		if userExecutorVkId.Valid {
+			adMatch.UserExecutorVkId = new(uint32)
+			*adMatch.UserExecutorVkId = uint32(userExecutorVkId.Int32)
+		}
+
+		adMatches = append(adMatches, adMatch)
+	}
+
+	return &adMatches, nil
+}
+
+func (userRepository *UserRepository) InsertSavedSearch(savedSearch *models.SavedSearch) (*models.SavedSearch, error) {
+	const query = `
+INSERT INTO saved_search (user_author_id, name, loc_dep, loc_arr, max_price, order_)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_author_id, name, loc_dep, loc_arr, max_price, order_`
+
+	if err := userRepository.db.QueryRow(query, savedSearch.UserAuthorId, savedSearch.Name, savedSearch.LocDep,
+		savedSearch.LocArr, savedSearch.MaxPrice, savedSearch.Order).Scan(&savedSearch.Id, &savedSearch.UserAuthorId,
+		&savedSearch.Name, &savedSearch.LocDep, &savedSearch.LocArr, &savedSearch.MaxPrice,
+		&savedSearch.Order); err != nil {
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23505" {
+			return nil, consts.RepErrConflict
+		}
+
+		return nil, err
+	}
+
+	return savedSearch, nil
+}
+
+func (userRepository *UserRepository) SelectSavedSearch(savedSearchId uint32) (*models.SavedSearch, error) {
+	const query = `
+SELECT id, user_author_id, name, loc_dep, loc_arr, max_price, order_ FROM saved_search
+WHERE id = $1`
+
+	savedSearch := new(models.SavedSearch)
+	if err := userRepository.db.QueryRow(query, savedSearchId).Scan(&savedSearch.Id, &savedSearch.UserAuthorId,
+		&savedSearch.Name, &savedSearch.LocDep, &savedSearch.LocArr, &savedSearch.MaxPrice,
+		&savedSearch.Order); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return savedSearch, nil
+}
+
+func (userRepository *UserRepository) SelectSavedSearchArrayByUserAuthorId(userAuthorId uint32) (*models.SavedSearches, error) {
+	const query = `
+SELECT id, user_author_id, name, loc_dep, loc_arr, max_price, order_ FROM saved_search
+WHERE user_author_id = $1
+ORDER BY name, id`
+
+	rows, err := userRepository.db.Query(query, userAuthorId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	savedSearches := make(models.SavedSearches, 0)
+	for rows.Next() {
+		savedSearch := new(models.SavedSearch)
+		if err := rows.Scan(&savedSearch.Id, &savedSearch.UserAuthorId, &savedSearch.Name, &savedSearch.LocDep,
+			&savedSearch.LocArr, &savedSearch.MaxPrice, &savedSearch.Order); err != nil {
+			return nil, err
+		}
+
+		savedSearches = append(savedSearches, savedSearch)
+	}
+
+	return &savedSearches, nil
+}
+
+func (userRepository *UserRepository) UpdateSavedSearchName(savedSearchId uint32, name string) (*models.SavedSearch, error) {
+	const query = `
+UPDATE saved_search SET name = $2
+WHERE id = $1
+RETURNING id, user_author_id, name, loc_dep, loc_arr, max_price, order_`
+
+	savedSearch := new(models.SavedSearch)
+	if err := userRepository.db.QueryRow(query, savedSearchId, name).Scan(&savedSearch.Id, &savedSearch.UserAuthorId,
+		&savedSearch.Name, &savedSearch.LocDep, &savedSearch.LocArr, &savedSearch.MaxPrice,
+		&savedSearch.Order); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+		if err_, ok := err.(*pq.Error); ok && err_.Code == "23505" {
+			return nil, consts.RepErrConflict
+		}
+
+		return nil, err
+	}
+
+	return savedSearch, nil
+}
+
+func (userRepository *UserRepository) DeleteSavedSearch(savedSearchId uint32) (*models.SavedSearch, error) {
+	const query = `
+DELETE FROM saved_search
+WHERE id = $1
+RETURNING id, user_author_id, name, loc_dep, loc_arr, max_price, order_`
+
+	savedSearch := new(models.SavedSearch)
+	if err := userRepository.db.QueryRow(query, savedSearchId).Scan(&savedSearch.Id, &savedSearch.UserAuthorId,
+		&savedSearch.Name, &savedSearch.LocDep, &savedSearch.LocArr, &savedSearch.MaxPrice,
+		&savedSearch.Order); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return savedSearch, nil
+}
+
+func (userRepository *UserRepository) SelectAdReviewArrayByUserTargetId(userTargetId uint32) (*models.AdReviews, error) {
+	const query = `
+SELECT id, ad_id, user_author_id, user_target_id, rating, comment, date_time FROM ad_review
+WHERE user_target_id = $1
+ORDER BY date_time DESC, id DESC`
+
+	rows, err := userRepository.db.Query(query, userTargetId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	adReviews := make(models.AdReviews, 0)
+	for rows.Next() {
+		adReview := new(models.AdReview)
+		if err := rows.Scan(&adReview.Id, &adReview.AdId, &adReview.UserAuthorId, &adReview.UserTargetId,
+			&adReview.Rating, &adReview.Comment, &adReview.DateTime); err != nil {
+			return nil, err
+		}
+
+		adReviews = append(adReviews, adReview)
+	}
+
+	return &adReviews, nil
+}
+
+func (userRepository *UserRepository) InsertUserBlock(userBlock *models.UserBlock) (*models.UserBlock, error) {
+	const query = `
+WITH user_block_ AS (
+    INSERT INTO user_block (user_id, user_blocked_id)
+    VALUES ($1, $2)
+    RETURNING user_id, user_blocked_id, date_time
+)
+SELECT user_block_.user_id, user_block_.user_blocked_id, user_.name, user_.avatar, user_block_.date_time
+FROM user_block_
+    JOIN user_ ON user_.id = user_block_.user_blocked_id`
+
+	if err := userRepository.db.QueryRow(query, userBlock.UserId, userBlock.UserBlockedId).Scan(&userBlock.UserId,
+		&userBlock.UserBlockedId, &userBlock.UserBlockedName, &userBlock.UserBlockedAvatar,
+		&userBlock.DateTime); err != nil {
+		if err_, ok := err.(*pq.Error); ok {
+			switch err_.Code {
+			case "23503":
+				return nil, consts.RepErrNotFound
+			case "23505":
+				return nil, consts.RepErrConflict
+			}
+		}
+
+		return nil, err
+	}
+
+	return userBlock, nil
+}
+
+func (userRepository *UserRepository) DeleteUserBlock(userId uint32, userBlockedId uint32) (*models.UserBlock, error) {
+	const query = `
+WITH user_block_ AS (
+    DELETE FROM user_block
+    WHERE user_id = $1 AND user_blocked_id = $2
+    RETURNING user_id, user_blocked_id, date_time
+)
+SELECT user_block_.user_id, user_block_.user_blocked_id, user_.name, user_.avatar, user_block_.date_time
+FROM user_block_
+    JOIN user_ ON user_.id = user_block_.user_blocked_id`
+
+	userBlock := new(models.UserBlock)
+	if err := userRepository.db.QueryRow(query, userId, userBlockedId).Scan(&userBlock.UserId,
+		&userBlock.UserBlockedId, &userBlock.UserBlockedName, &userBlock.UserBlockedAvatar,
+		&userBlock.DateTime); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, consts.RepErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return userBlock, nil
+}
+
+func (userRepository *UserRepository) SelectUserBlockArrayByUserId(userId uint32) (*models.UserBlocks, error) {
+	const query = `
+SELECT user_block.user_id, user_block.user_blocked_id, user_.name, user_.avatar, user_block.date_time
+FROM user_block
+    JOIN user_ ON user_.id = user_block.user_blocked_id
+WHERE user_block.user_id = $1
+ORDER BY user_block.date_time DESC, user_block.user_blocked_id DESC`
+
+	rows, err := userRepository.db.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	userBlocks := make(models.UserBlocks, 0)
+	for rows.Next() {
+		userBlock := new(models.UserBlock)
+		if err := rows.Scan(&userBlock.UserId, &userBlock.UserBlockedId, &userBlock.UserBlockedName,
+			&userBlock.UserBlockedAvatar, &userBlock.DateTime); err != nil {
+			return nil, err
+		}
+
+		userBlocks = append(userBlocks, userBlock)
+	}
+
+	return &userBlocks, nil
+}
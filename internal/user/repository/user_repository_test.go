@@ -7,6 +7,7 @@ import (
 	"github.com/TechnoHandOver/backend/internal/models"
 	"github.com/TechnoHandOver/backend/internal/models/timestamps"
 	"github.com/TechnoHandOver/backend/internal/user/repository"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -37,8 +38,9 @@ func TestUserRepository_Insert(t *testing.T) {
 		ExpectQuery("INSERT INTO user_").
 		WithArgs(user.VkId, user.Name, user.Avatar).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar"}).
-				AddRow(expectedUser.Id, user.VkId, user.Name, user.Avatar))
+			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar", "rating", "rating_count", "suspended"}).
+				AddRow(expectedUser.Id, user.VkId, user.Name, user.Avatar, expectedUser.Rating,
+					expectedUser.RatingCount, expectedUser.Suspended))
 
 	resultUser, resultErr := userRepository.Insert(user)
 	assert.Nil(t, resultErr)
@@ -57,18 +59,21 @@ func TestUserRepository_Select(t *testing.T) {
 	userRepository := repository.NewUserRepositoryImpl(db)
 
 	expectedUser := &models.User{
-		Id:     1,
-		VkId:   2,
-		Name:   "Василий Петров",
-		Avatar: "https://mail.ru/vasiliy_petrov_avatar.jpg",
+		Id:          1,
+		VkId:        2,
+		Name:        "Василий Петров",
+		Avatar:      "https://mail.ru/vasiliy_petrov_avatar.jpg",
+		Rating:      4.5,
+		RatingCount: 2,
 	}
 
 	sqlmock_.
-		ExpectQuery("SELECT id, vk_id, name, avatar FROM user_").
+		ExpectQuery("SELECT id, vk_id, name, avatar, rating, rating_count, suspended FROM user_").
 		WithArgs(expectedUser.Id).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar"}).
-				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar))
+			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar", "rating", "rating_count", "suspended"}).
+				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar,
+					expectedUser.Rating, expectedUser.RatingCount, expectedUser.Suspended))
 
 	resultUser, resultErr := userRepository.Select(expectedUser.Id)
 	assert.Nil(t, resultErr)
@@ -89,7 +94,7 @@ func TestUserRepository_Select_notFound(t *testing.T) {
 	const id uint32 = 1
 
 	sqlmock_.
-		ExpectQuery("SELECT id, vk_id, name, avatar FROM user_").
+		ExpectQuery("SELECT id, vk_id, name, avatar, rating, rating_count, suspended FROM user_").
 		WithArgs(id).
 		WillReturnError(sql.ErrNoRows)
 
@@ -117,11 +122,12 @@ func TestUserRepository_SelectByVkId(t *testing.T) {
 	}
 
 	sqlmock_.
-		ExpectQuery("SELECT id, vk_id, name, avatar FROM user_").
+		ExpectQuery("SELECT id, vk_id, name, avatar, rating, rating_count, suspended FROM user_").
 		WithArgs(expectedUser.VkId).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar"}).
-				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar))
+			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar", "rating", "rating_count", "suspended"}).
+				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar,
+					expectedUser.Rating, expectedUser.RatingCount, expectedUser.Suspended))
 
 	resultUser, resultErr := userRepository.SelectByVkId(expectedUser.VkId)
 	assert.Nil(t, resultErr)
@@ -142,7 +148,7 @@ func TestUserRepository_SelectByVkId_notFound(t *testing.T) {
 	const vkId uint32 = 2
 
 	sqlmock_.
-		ExpectQuery("SELECT id, vk_id, name, avatar FROM user_").
+		ExpectQuery("SELECT id, vk_id, name, avatar, rating, rating_count, suspended FROM user_").
 		WithArgs(vkId).
 		WillReturnError(sql.ErrNoRows)
 
@@ -173,8 +179,9 @@ func TestUserRepository_Update(t *testing.T) {
 		ExpectQuery("UPDATE user_").
 		WithArgs(expectedUser.Id, expectedUser.Name, expectedUser.Avatar).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar"}).
-				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar))
+			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar", "rating", "rating_count", "suspended"}).
+				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar,
+					expectedUser.Rating, expectedUser.RatingCount, expectedUser.Suspended))
 
 	resultUser, resultErr := userRepository.Update(expectedUser)
 	assert.Nil(t, resultErr)
@@ -206,11 +213,12 @@ func TestUserRepository_Update_select(t *testing.T) { //TODO: бизнес-ло
 	}
 
 	sqlmock_.
-		ExpectQuery("SELECT id, vk_id, name, avatar FROM user_").
+		ExpectQuery("SELECT id, vk_id, name, avatar, rating, rating_count, suspended FROM user_").
 		WithArgs(user.Id).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar"}).
-				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar))
+			sqlmock.NewRows([]string{"id", "vk_id", "name", "avatar", "rating", "rating_count", "suspended"}).
+				AddRow(expectedUser.Id, expectedUser.VkId, expectedUser.Name, expectedUser.Avatar,
+					expectedUser.Rating, expectedUser.RatingCount, expectedUser.Suspended))
 
 	resultUser, resultErr := userRepository.Update(user)
 	assert.Nil(t, resultErr)
@@ -236,6 +244,8 @@ func TestUserRepository_InsertRouteTmp(t *testing.T) {
 		UserAuthorId: 101,
 		LocDep:       "Корпус Энерго",
 		LocArr:       "Корпус УЛК",
+		LocDepPoint:  &models.GeoPoint{Lat: 55.765, Lon: 37.685},
+		LocArrPoint:  &models.GeoPoint{Lat: 55.771, Lon: 37.691},
 		MinPrice:     500,
 		DateTimeDep:  *dateTimeDep,
 		DateTimeArr:  *dateTimeArr,
@@ -245,6 +255,8 @@ func TestUserRepository_InsertRouteTmp(t *testing.T) {
 		UserAuthorId: routeTmp.UserAuthorId,
 		LocDep:       routeTmp.LocDep,
 		LocArr:       routeTmp.LocArr,
+		LocDepPoint:  routeTmp.LocDepPoint,
+		LocArrPoint:  routeTmp.LocArrPoint,
 		MinPrice:     routeTmp.MinPrice,
 		DateTimeDep:  routeTmp.DateTimeDep,
 		DateTimeArr:  routeTmp.DateTimeArr,
@@ -253,12 +265,15 @@ func TestUserRepository_InsertRouteTmp(t *testing.T) {
 	sqlmock_.
 		ExpectQuery("INSERT INTO view_route_tmp").
 		WithArgs(routeTmp.UserAuthorId, routeTmp.LocDep, routeTmp.LocArr, routeTmp.MinPrice,
-			time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr)).
+			time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr), routeTmp.LocDepPoint,
+			routeTmp.LocArrPoint, routeTmp.LocDepPlaceId, routeTmp.LocArrPlaceId).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "date_time_dep",
-				"date_time_arr"}).
+				"date_time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "version"}).
 				AddRow(expectedRouteTmp.Id, routeTmp.UserAuthorId, routeTmp.LocDep, routeTmp.LocArr,
-					routeTmp.MinPrice, time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr)))
+					routeTmp.MinPrice, time.Time(routeTmp.DateTimeDep), time.Time(routeTmp.DateTimeArr),
+					routeTmp.LocDepPoint,
+					routeTmp.LocArrPoint, routeTmp.LocDepPlaceId, routeTmp.LocArrPlaceId, routeTmp.Version))
 
 	resultRouteTmp, resultErr := userRepository.InsertRouteTmp(routeTmp)
 	assert.Nil(t, resultErr)
@@ -291,14 +306,16 @@ func TestUserRepository_SelectRouteTmp(t *testing.T) {
 	}
 
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr FROM view_route_tmp").
+		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_tmp").
 		WithArgs(expectedRouteTmp.Id).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "date_time_dep",
-				"date_time_arr"}).
+				"date_time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "version"}).
 				AddRow(expectedRouteTmp.Id, expectedRouteTmp.UserAuthorId, expectedRouteTmp.LocDep,
 					expectedRouteTmp.LocArr, expectedRouteTmp.MinPrice, time.Time(expectedRouteTmp.DateTimeDep),
-					time.Time(expectedRouteTmp.DateTimeArr)))
+					time.Time(expectedRouteTmp.DateTimeArr), expectedRouteTmp.LocDepPoint,
+					expectedRouteTmp.LocArrPoint, expectedRouteTmp.LocDepPlaceId, expectedRouteTmp.LocArrPlaceId,
+					expectedRouteTmp.Version))
 
 	resultRouteTmp, resultErr := userRepository.SelectRouteTmp(expectedRouteTmp.Id)
 	assert.Nil(t, resultErr)
@@ -319,7 +336,7 @@ func TestUserRepository_SelectRouteTmp_notFound(t *testing.T) {
 	const routeTmpId uint32 = 1
 
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr FROM view_route_tmp").
+		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_tmp").
 		WithArgs(routeTmpId).
 		WillReturnError(sql.ErrNoRows)
 
@@ -356,13 +373,18 @@ func TestUserRepository_UpdateRouteTmp(t *testing.T) {
 	sqlmock_.
 		ExpectQuery("UPDATE view_route_tmp").
 		WithArgs(expectedRouteTmp.Id, expectedRouteTmp.LocDep, expectedRouteTmp.LocArr, expectedRouteTmp.MinPrice,
-			time.Time(expectedRouteTmp.DateTimeDep), time.Time(expectedRouteTmp.DateTimeArr)).
+			time.Time(expectedRouteTmp.DateTimeDep), time.Time(expectedRouteTmp.DateTimeArr),
+			expectedRouteTmp.LocDepPoint,
+			expectedRouteTmp.LocArrPoint, expectedRouteTmp.LocDepPlaceId, expectedRouteTmp.LocArrPlaceId,
+			expectedRouteTmp.Version).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "date_time_dep",
-				"date_time_arr"}).
+				"date_time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "version"}).
 				AddRow(expectedRouteTmp.Id, expectedRouteTmp.UserAuthorId, expectedRouteTmp.LocDep,
 					expectedRouteTmp.LocArr, expectedRouteTmp.MinPrice, time.Time(expectedRouteTmp.DateTimeDep),
-					time.Time(expectedRouteTmp.DateTimeArr)))
+					time.Time(expectedRouteTmp.DateTimeArr), expectedRouteTmp.LocDepPoint,
+					expectedRouteTmp.LocArrPoint, expectedRouteTmp.LocDepPlaceId, expectedRouteTmp.LocArrPlaceId,
+					expectedRouteTmp.Version))
 
 	resultRouteTmp, resultErr := userRepository.UpdateRouteTmp(expectedRouteTmp)
 	assert.Nil(t, resultErr)
@@ -371,7 +393,7 @@ func TestUserRepository_UpdateRouteTmp(t *testing.T) {
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestUserRepository_UpdateRouteTmp_notFound(t *testing.T) {
+func TestUserRepository_UpdateRouteTmp_preconditionFailed(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -397,16 +419,68 @@ func TestUserRepository_UpdateRouteTmp_notFound(t *testing.T) {
 	sqlmock_.
 		ExpectQuery("UPDATE view_route_tmp").
 		WithArgs(routeTmp.Id, routeTmp.LocDep, routeTmp.LocArr, routeTmp.MinPrice, time.Time(routeTmp.DateTimeDep),
-			time.Time(routeTmp.DateTimeArr)).
+			time.Time(routeTmp.DateTimeArr), routeTmp.LocDepPoint,
+			routeTmp.LocArrPoint, routeTmp.LocDepPlaceId, routeTmp.LocArrPlaceId, routeTmp.Version).
 		WillReturnError(sql.ErrNoRows)
 
 	resultRouteTmp, resultErr := userRepository.UpdateRouteTmp(routeTmp)
-	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	assert.Equal(t, resultErr, consts.RepErrPreconditionFailed)
 	assert.Nil(t, resultRouteTmp)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
+func TestUserRepository_PatchRouteTmp(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	dateTimeDep, err := timestamps.NewDateTime("13.11.2021 14:00")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("13.11.2021 14:05")
+	assert.Nil(t, err)
+	locDep := "Корпус Энерго"
+	routeTmpPatch := &models.RouteTmpPatch{
+		Id:                1,
+		UserAuthorId:      101,
+		Version:           2,
+		LocDep:            &locDep,
+		LocDepPlaceIdNull: true,
+		DateTimeArr:       dateTimeArr,
+	}
+	expectedRouteTmp := &models.RouteTmp{
+		Id:           routeTmpPatch.Id,
+		UserAuthorId: routeTmpPatch.UserAuthorId,
+		LocDep:       locDep,
+		LocArr:       "Корпус УЛК",
+		MinPrice:     500,
+		DateTimeDep:  *dateTimeDep,
+		DateTimeArr:  *dateTimeArr,
+	}
+
+	sqlmock_.
+		ExpectQuery("UPDATE view_route_tmp SET loc_dep = \\$3, loc_dep_place_id = \\$4, date_time_arr = \\$5 WHERE id = \\$1 AND \\(version = \\$2 OR \\$2 = 0\\)").
+		WithArgs(routeTmpPatch.Id, routeTmpPatch.Version, locDep, nil, time.Time(*dateTimeArr)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "date_time_dep",
+				"date_time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "version"}).
+				AddRow(expectedRouteTmp.Id, expectedRouteTmp.UserAuthorId, expectedRouteTmp.LocDep,
+					expectedRouteTmp.LocArr, expectedRouteTmp.MinPrice, time.Time(expectedRouteTmp.DateTimeDep),
+					time.Time(expectedRouteTmp.DateTimeArr), expectedRouteTmp.LocDepPoint,
+					expectedRouteTmp.LocArrPoint, expectedRouteTmp.LocDepPlaceId, expectedRouteTmp.LocArrPlaceId,
+					expectedRouteTmp.Version))
+
+	resultRouteTmp, resultErr := userRepository.PatchRouteTmp(routeTmpPatch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedRouteTmp, resultRouteTmp)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
 func TestUserRepository_DeleteRouteTmp(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
@@ -432,22 +506,24 @@ func TestUserRepository_DeleteRouteTmp(t *testing.T) {
 
 	sqlmock_.
 		ExpectQuery("DELETE FROM view_route_tmp").
-		WithArgs(expectedRouteTmp.Id).
+		WithArgs(expectedRouteTmp.Id, expectedRouteTmp.Version).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "date_time_dep",
-				"date_time_arr"}).
+				"date_time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "version"}).
 				AddRow(expectedRouteTmp.Id, expectedRouteTmp.UserAuthorId, expectedRouteTmp.LocDep,
 					expectedRouteTmp.LocArr, expectedRouteTmp.MinPrice, time.Time(expectedRouteTmp.DateTimeDep),
-					time.Time(expectedRouteTmp.DateTimeArr)))
+					time.Time(expectedRouteTmp.DateTimeArr), expectedRouteTmp.LocDepPoint,
+					expectedRouteTmp.LocArrPoint, expectedRouteTmp.LocDepPlaceId, expectedRouteTmp.LocArrPlaceId,
+					expectedRouteTmp.Version))
 
-	resultRouteTmp, resultErr := userRepository.DeleteRouteTmp(expectedRouteTmp.Id)
+	resultRouteTmp, resultErr := userRepository.DeleteRouteTmp(expectedRouteTmp.Id, expectedRouteTmp.Version)
 	assert.Nil(t, resultErr)
 	assert.Equal(t, expectedRouteTmp, resultRouteTmp)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestUserRepository_DeleteRouteTmp_notFound(t *testing.T) {
+func TestUserRepository_DeleteRouteTmp_preconditionFailed(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -457,14 +533,15 @@ func TestUserRepository_DeleteRouteTmp_notFound(t *testing.T) {
 	userRepository := repository.NewUserRepositoryImpl(db)
 
 	const routeTmpId uint32 = 1
+	const version uint32 = 2
 
 	sqlmock_.
 		ExpectQuery("DELETE FROM view_route_tmp").
-		WithArgs(routeTmpId).
+		WithArgs(routeTmpId, version).
 		WillReturnError(sql.ErrNoRows)
 
-	resultRouteTmp, resultErr := userRepository.DeleteRouteTmp(routeTmpId)
-	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	resultRouteTmp, resultErr := userRepository.DeleteRouteTmp(routeTmpId, version)
+	assert.Equal(t, resultErr, consts.RepErrPreconditionFailed)
 	assert.Nil(t, resultRouteTmp)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
@@ -510,14 +587,15 @@ func TestUserRepository_SelectRouteTmpArray(t *testing.T) {
 	}
 
 	rows := sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "date_time_dep",
-		"date_time_arr"})
+		"date_time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "version"})
 	for _, expectedRouteTmp := range *expectedRoutesTmp {
 		rows.AddRow(expectedRouteTmp.Id, expectedRouteTmp.UserAuthorId, expectedRouteTmp.LocDep,
 			expectedRouteTmp.LocArr, expectedRouteTmp.MinPrice, time.Time(expectedRouteTmp.DateTimeDep),
-			time.Time(expectedRouteTmp.DateTimeArr))
+			time.Time(expectedRouteTmp.DateTimeArr), expectedRouteTmp.LocDepPoint, expectedRouteTmp.LocArrPoint,
+			expectedRouteTmp.LocDepPlaceId, expectedRouteTmp.LocArrPlaceId, expectedRouteTmp.Version)
 	}
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr FROM view_route_tmp").
+		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, date_time_dep, date_time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_tmp").
 		WillReturnRows(rows)
 
 	resultRoutesTmp, resultErr := userRepository.SelectRouteTmpArrayByUserAuthorId(userId)
@@ -547,7 +625,7 @@ func TestUserRepository_InsertRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -567,13 +645,17 @@ func TestUserRepository_InsertRoutePerm(t *testing.T) {
 	sqlmock_.
 		ExpectQuery("INSERT INTO view_route_perm").
 		WithArgs(routePerm.UserAuthorId, routePerm.LocDep, routePerm.LocArr, routePerm.MinPrice, routePerm.EvenWeek,
-			routePerm.OddWeek, routePerm.DayOfWeek, time.Time(routePerm.TimeDep), time.Time(routePerm.TimeArr)).
+			routePerm.OddWeek, routePerm.DayOfWeek, time.Time(routePerm.TimeDep), time.Time(routePerm.TimeArr),
+			routePerm.LocDepPoint,
+			routePerm.LocArrPoint, routePerm.LocDepPlaceId, routePerm.LocArrPlaceId).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "even_week",
-				"odd_week", "day_of_week", "time_dep", "time_arr"}).
+				"odd_week", "day_of_week", "time_dep", "time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id",
+				"loc_arr_place_id", "version"}).
 				AddRow(expectedRoutePerm.Id, routePerm.UserAuthorId, routePerm.LocDep, routePerm.LocArr,
 					routePerm.MinPrice, routePerm.EvenWeek, routePerm.OddWeek, routePerm.DayOfWeek,
-					time.Time(routePerm.TimeDep), time.Time(routePerm.TimeArr)))
+					time.Time(routePerm.TimeDep), time.Time(routePerm.TimeArr), routePerm.LocDepPoint,
+					routePerm.LocArrPoint, routePerm.LocDepPlaceId, routePerm.LocArrPlaceId, routePerm.Version))
 
 	resultRoutePerm, resultErr := userRepository.InsertRoutePerm(routePerm)
 	assert.Nil(t, resultErr)
@@ -602,21 +684,24 @@ func TestUserRepository_SelectRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
 
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr FROM view_route_perm").
+		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_perm").
 		WithArgs(expectedRoutePerm.Id).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "even_week",
-				"odd_week", "day_of_week", "time_dep", "time_arr"}).
+				"odd_week", "day_of_week", "time_dep", "time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id",
+				"loc_arr_place_id", "version"}).
 				AddRow(expectedRoutePerm.Id, expectedRoutePerm.UserAuthorId, expectedRoutePerm.LocDep,
 					expectedRoutePerm.LocArr, expectedRoutePerm.MinPrice, expectedRoutePerm.EvenWeek,
 					expectedRoutePerm.OddWeek, expectedRoutePerm.DayOfWeek, time.Time(expectedRoutePerm.TimeDep),
-					time.Time(expectedRoutePerm.TimeArr)))
+					time.Time(expectedRoutePerm.TimeArr), expectedRoutePerm.LocDepPoint,
+					expectedRoutePerm.LocArrPoint, expectedRoutePerm.LocDepPlaceId, expectedRoutePerm.LocArrPlaceId,
+					expectedRoutePerm.Version))
 
 	resultRoutePerm, resultErr := userRepository.SelectRoutePerm(expectedRoutePerm.Id)
 	assert.Nil(t, resultErr)
@@ -637,7 +722,7 @@ func TestUserRepository_SelectRoutePerm_notFound(t *testing.T) {
 	const routePermId uint32 = 1
 
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr FROM view_route_perm").
+		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_perm").
 		WithArgs(routePermId).
 		WillReturnError(sql.ErrNoRows)
 
@@ -668,7 +753,7 @@ func TestUserRepository_UpdateRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -677,14 +762,19 @@ func TestUserRepository_UpdateRoutePerm(t *testing.T) {
 		ExpectQuery("UPDATE view_route_perm").
 		WithArgs(expectedRoutePerm.Id, expectedRoutePerm.LocDep, expectedRoutePerm.LocArr, expectedRoutePerm.MinPrice,
 			expectedRoutePerm.EvenWeek, expectedRoutePerm.OddWeek, expectedRoutePerm.DayOfWeek,
-			time.Time(expectedRoutePerm.TimeDep), time.Time(expectedRoutePerm.TimeArr)).
+			time.Time(expectedRoutePerm.TimeDep), time.Time(expectedRoutePerm.TimeArr), expectedRoutePerm.LocDepPoint,
+			expectedRoutePerm.LocArrPoint, expectedRoutePerm.LocDepPlaceId, expectedRoutePerm.LocArrPlaceId,
+			expectedRoutePerm.Version).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "even_week",
-				"odd_week", "day_of_week", "time_dep", "time_arr"}).
+				"odd_week", "day_of_week", "time_dep", "time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id",
+				"loc_arr_place_id", "version"}).
 				AddRow(expectedRoutePerm.Id, expectedRoutePerm.UserAuthorId, expectedRoutePerm.LocDep,
 					expectedRoutePerm.LocArr, expectedRoutePerm.MinPrice, expectedRoutePerm.EvenWeek,
 					expectedRoutePerm.OddWeek, expectedRoutePerm.DayOfWeek, time.Time(expectedRoutePerm.TimeDep),
-					time.Time(expectedRoutePerm.TimeArr)))
+					time.Time(expectedRoutePerm.TimeArr), expectedRoutePerm.LocDepPoint,
+					expectedRoutePerm.LocArrPoint, expectedRoutePerm.LocDepPlaceId, expectedRoutePerm.LocArrPlaceId,
+					expectedRoutePerm.Version))
 
 	resultRoutePerm, resultErr := userRepository.UpdateRoutePerm(expectedRoutePerm)
 	assert.Nil(t, resultErr)
@@ -693,7 +783,7 @@ func TestUserRepository_UpdateRoutePerm(t *testing.T) {
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestUserRepository_UpdateRoutePerm_notFound(t *testing.T) {
+func TestUserRepository_UpdateRoutePerm_preconditionFailed(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -713,7 +803,7 @@ func TestUserRepository_UpdateRoutePerm_notFound(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -721,16 +811,49 @@ func TestUserRepository_UpdateRoutePerm_notFound(t *testing.T) {
 	sqlmock_.
 		ExpectQuery("UPDATE view_route_perm").
 		WithArgs(routePerm.Id, routePerm.LocDep, routePerm.LocArr, routePerm.MinPrice, routePerm.EvenWeek,
-			routePerm.OddWeek, routePerm.DayOfWeek, time.Time(routePerm.TimeDep), time.Time(routePerm.TimeArr)).
+			routePerm.OddWeek, routePerm.DayOfWeek, time.Time(routePerm.TimeDep), time.Time(routePerm.TimeArr),
+			routePerm.LocDepPoint,
+			routePerm.LocArrPoint, routePerm.LocDepPlaceId, routePerm.LocArrPlaceId, routePerm.Version).
 		WillReturnError(sql.ErrNoRows)
 
 	resultRouteTmp, resultErr := userRepository.UpdateRoutePerm(routePerm)
-	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	assert.Equal(t, resultErr, consts.RepErrPreconditionFailed)
 	assert.Nil(t, resultRouteTmp)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
+func TestUserRepository_PatchRoutePerm_preconditionFailed(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	oddWeek := false
+	dayOfWeek := uint32(3)
+	routePermPatch := &models.RoutePermPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		Version:      2,
+		OddWeek:      &oddWeek,
+		DayOfWeek:    &dayOfWeek,
+	}
+
+	sqlmock_.
+		ExpectQuery("UPDATE view_route_perm SET odd_week = \\$3, day_of_week = \\$4 WHERE id = \\$1 AND \\(version = \\$2 OR \\$2 = 0\\)").
+		WithArgs(routePermPatch.Id, routePermPatch.Version, oddWeek, dayOfWeek).
+		WillReturnError(sql.ErrNoRows)
+
+	resultRoutePerm, resultErr := userRepository.PatchRoutePerm(routePermPatch)
+	assert.Nil(t, resultRoutePerm)
+	assert.Equal(t, consts.RepErrPreconditionFailed, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
 func TestUserRepository_DeleteRoutePerm(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
@@ -751,30 +874,33 @@ func TestUserRepository_DeleteRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
 
 	sqlmock_.
 		ExpectQuery("DELETE FROM view_route_perm").
-		WithArgs(expectedRoutePerm.Id).
+		WithArgs(expectedRoutePerm.Id, expectedRoutePerm.Version).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "even_week",
-				"odd_week", "day_of_week", "time_dep", "time_arr"}).
+				"odd_week", "day_of_week", "time_dep", "time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id",
+				"loc_arr_place_id", "version"}).
 				AddRow(expectedRoutePerm.Id, expectedRoutePerm.UserAuthorId, expectedRoutePerm.LocDep,
 					expectedRoutePerm.LocArr, expectedRoutePerm.MinPrice, expectedRoutePerm.EvenWeek,
 					expectedRoutePerm.OddWeek, expectedRoutePerm.DayOfWeek, time.Time(expectedRoutePerm.TimeDep),
-					time.Time(expectedRoutePerm.TimeArr)))
+					time.Time(expectedRoutePerm.TimeArr), expectedRoutePerm.LocDepPoint,
+					expectedRoutePerm.LocArrPoint, expectedRoutePerm.LocDepPlaceId, expectedRoutePerm.LocArrPlaceId,
+					expectedRoutePerm.Version))
 
-	resultRoutePerm, resultErr := userRepository.DeleteRoutePerm(expectedRoutePerm.Id)
+	resultRoutePerm, resultErr := userRepository.DeleteRoutePerm(expectedRoutePerm.Id, expectedRoutePerm.Version)
 	assert.Nil(t, resultErr)
 	assert.Equal(t, expectedRoutePerm, resultRoutePerm)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
 
-func TestUserRepository_DeleteRoutePerm_notFound(t *testing.T) {
+func TestUserRepository_DeleteRoutePerm_preconditionFailed(t *testing.T) {
 	db, sqlmock_, err := sqlmock.New()
 	assert.Nil(t, err)
 	defer func(db *sql.DB) {
@@ -784,14 +910,15 @@ func TestUserRepository_DeleteRoutePerm_notFound(t *testing.T) {
 	userRepository := repository.NewUserRepositoryImpl(db)
 
 	const routePermId uint32 = 1
+	const version uint32 = 2
 
 	sqlmock_.
 		ExpectQuery("DELETE FROM view_route_perm").
-		WithArgs(routePermId).
+		WithArgs(routePermId, version).
 		WillReturnError(sql.ErrNoRows)
 
-	resultRoutePerm, resultErr := userRepository.DeleteRoutePerm(routePermId)
-	assert.Equal(t, resultErr, consts.RepErrNotFound)
+	resultRoutePerm, resultErr := userRepository.DeleteRoutePerm(routePermId, version)
+	assert.Equal(t, resultErr, consts.RepErrPreconditionFailed)
 	assert.Nil(t, resultRoutePerm)
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
@@ -824,7 +951,7 @@ func TestUserRepository_SelectRoutePermArray(t *testing.T) {
 			MinPrice:     500,
 			EvenWeek:     true,
 			OddWeek:      false,
-			DayOfWeek:    timestamps.DayOfWeekWednesday,
+			DayOfWeek:    3,
 			TimeDep:      *timeDep1,
 			TimeArr:      *timeArr1,
 		},
@@ -836,22 +963,24 @@ func TestUserRepository_SelectRoutePermArray(t *testing.T) {
 			MinPrice:     600,
 			EvenWeek:     false,
 			OddWeek:      true,
-			DayOfWeek:    timestamps.DayOfWeekSaturday,
+			DayOfWeek:    6,
 			TimeDep:      *timeDep2,
 			TimeArr:      *timeArr2,
 		},
 	}
 
 	rows := sqlmock.NewRows([]string{"id", "user_author_id", "loc_dep", "loc_arr", "min_price", "even_week",
-		"odd_week", "day_of_week", "time_dep", "time_arr"})
+		"odd_week", "day_of_week", "time_dep", "time_arr", "loc_dep_point", "loc_arr_point", "loc_dep_place_id",
+		"loc_arr_place_id", "version"})
 	for _, expectedRoutePerm := range *expectedRoutesPerm {
 		rows.AddRow(expectedRoutePerm.Id, expectedRoutePerm.UserAuthorId, expectedRoutePerm.LocDep,
 			expectedRoutePerm.LocArr, expectedRoutePerm.MinPrice, expectedRoutePerm.EvenWeek,
 			expectedRoutePerm.OddWeek, expectedRoutePerm.DayOfWeek, time.Time(expectedRoutePerm.TimeDep),
-			time.Time(expectedRoutePerm.TimeArr))
+			time.Time(expectedRoutePerm.TimeArr), expectedRoutePerm.LocDepPoint, expectedRoutePerm.LocArrPoint,
+			expectedRoutePerm.LocDepPlaceId, expectedRoutePerm.LocArrPlaceId, expectedRoutePerm.Version)
 	}
 	sqlmock_.
-		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr FROM view_route_perm").
+		ExpectQuery("SELECT id, user_author_id, loc_dep, loc_arr, min_price, even_week, odd_week, day_of_week, time_dep, time_arr, loc_dep_point, loc_arr_point, loc_dep_place_id, loc_arr_place_id, version FROM view_route_perm").
 		WillReturnRows(rows)
 
 	resultRoutesPerm, resultErr := userRepository.SelectRoutePermArrayByUserAuthorId(userId)
@@ -860,3 +989,322 @@ func TestUserRepository_SelectRoutePermArray(t *testing.T) {
 
 	assert.Nil(t, sqlmock_.ExpectationsWereMet())
 }
+
+func TestUserRepository_InsertSavedSearch(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	locDep := "Общежитие №10"
+	var maxPrice uint32 = 700
+	order := models.AdsSearchOrderMinPriceAsc
+	savedSearch := &models.SavedSearch{
+		UserAuthorId: 101,
+		Name:         "До УЛК",
+		LocDep:       &locDep,
+		MaxPrice:     &maxPrice,
+		Order:        &order,
+	}
+	expectedSavedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: savedSearch.UserAuthorId,
+		Name:         savedSearch.Name,
+		LocDep:       savedSearch.LocDep,
+		MaxPrice:     savedSearch.MaxPrice,
+		Order:        savedSearch.Order,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO saved_search").
+		WithArgs(savedSearch.UserAuthorId, savedSearch.Name, savedSearch.LocDep, savedSearch.LocArr,
+			savedSearch.MaxPrice, savedSearch.Order).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_author_id", "name", "loc_dep", "loc_arr", "max_price", "order_"}).
+				AddRow(expectedSavedSearch.Id, savedSearch.UserAuthorId, savedSearch.Name, *savedSearch.LocDep, nil,
+					*savedSearch.MaxPrice, *savedSearch.Order))
+
+	resultSavedSearch, resultErr := userRepository.InsertSavedSearch(savedSearch)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedSavedSearch, resultSavedSearch)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_InsertSavedSearch_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	savedSearch := &models.SavedSearch{
+		UserAuthorId: 101,
+		Name:         "До УЛК",
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO saved_search").
+		WithArgs(savedSearch.UserAuthorId, savedSearch.Name, savedSearch.LocDep, savedSearch.LocArr,
+			savedSearch.MaxPrice, savedSearch.Order).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	resultSavedSearch, resultErr := userRepository.InsertSavedSearch(savedSearch)
+	assert.Nil(t, resultSavedSearch)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_UpdateSavedSearchName(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	locArr := "УЛК"
+	expectedSavedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: 101,
+		Name:         "Вечером до УЛК",
+		LocArr:       &locArr,
+	}
+
+	sqlmock_.
+		ExpectQuery("UPDATE saved_search SET name").
+		WithArgs(expectedSavedSearch.Id, expectedSavedSearch.Name).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_author_id", "name", "loc_dep", "loc_arr", "max_price", "order_"}).
+				AddRow(expectedSavedSearch.Id, expectedSavedSearch.UserAuthorId, expectedSavedSearch.Name, nil,
+					*expectedSavedSearch.LocArr, nil, nil))
+
+	resultSavedSearch, resultErr := userRepository.UpdateSavedSearchName(expectedSavedSearch.Id,
+		expectedSavedSearch.Name)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedSavedSearch, resultSavedSearch)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_SelectAdMatchArrayByRouteTmpId(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	var routeTmpId uint32 = 1
+	var limit uint32 = 10
+	dateTimeArr, err := timestamps.NewDateTime("10.11.2021 18:12")
+	assert.Nil(t, err)
+	expectedAdMatches := &models.AdMatches{
+		&models.AdMatch{
+			Ad: &models.Ad{
+				Id:               2,
+				UserAuthorId:     102,
+				UserAuthorVkId:   202,
+				UserAuthorName:   "Pupok Vasiliev",
+				UserAuthorAvatar: "https://yandex.ru/logo2.png",
+				LocDep:           "Корпус Энерго",
+				LocArr:           "Корпус УЛК",
+				DateTimeArr:      *dateTimeArr,
+				Item:             "Зачётная книжка",
+				MinPrice:         600,
+				Comment:          "",
+				Status:           models.AdStatusOpen,
+			},
+			Fit: 0.5,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+		"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_arr", "date_time_arr", "item", "min_price",
+		"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "fit"})
+	for _, expectedAdMatch := range *expectedAdMatches {
+		rows.AddRow(expectedAdMatch.Id, expectedAdMatch.UserAuthorId, expectedAdMatch.UserAuthorVkId,
+			expectedAdMatch.UserAuthorName, expectedAdMatch.UserAuthorAvatar, expectedAdMatch.UserExecutorVkId,
+			expectedAdMatch.LocDep, expectedAdMatch.LocArr, time.Time(expectedAdMatch.DateTimeArr), expectedAdMatch.Item,
+			expectedAdMatch.MinPrice, expectedAdMatch.Comment, expectedAdMatch.Status, expectedAdMatch.LocDepPoint,
+			expectedAdMatch.LocArrPoint, expectedAdMatch.LocDepPlaceId, expectedAdMatch.LocArrPlaceId,
+			(*time.Time)(expectedAdMatch.DateTimeDep), expectedAdMatch.Fit)
+	}
+	sqlmock_.
+		ExpectQuery("JOIN view_route_tmp AS route ON route.id = \\$1.+coalesce\\(ad.date_time_dep, ad.date_time_arr\\) <= route.date_time_arr.+ORDER BY fit DESC, id DESC").
+		WithArgs(routeTmpId, 1000, nil, nil, limit).
+		WillReturnRows(rows)
+
+	resultAdMatches, resultErr := userRepository.SelectAdMatchArrayByRouteTmpId(routeTmpId, nil, limit)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdMatches, resultAdMatches)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_SelectAdMatchArrayByRoutePermId_cursor(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	var routePermId uint32 = 1
+	var limit uint32 = 10
+	cursor := &models.AdMatchesCursor{
+		Fit: 0.75,
+		Id:  5,
+	}
+
+	sqlmock_.
+		ExpectQuery("JOIN view_route_perm AS route ON route.id = \\$1.+extract\\(ISODOW FROM ad.date_time_arr\\) = route.day_of_week").
+		WithArgs(routePermId, 1000, cursor.Fit, cursor.Id, limit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_author_id", "user_author_vk_id", "user_author_name",
+			"user_author_avatar", "user_executor_vk_id", "loc_dep", "loc_arr", "date_time_arr", "item", "min_price",
+			"comment", "status", "loc_dep_point", "loc_arr_point", "loc_dep_place_id", "loc_arr_place_id", "date_time_dep", "fit"}))
+
+	resultAdMatches, resultErr := userRepository.SelectAdMatchArrayByRoutePermId(routePermId, cursor, limit)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, &models.AdMatches{}, resultAdMatches)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_SelectAdReviewArrayByUserTargetId(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	const userTargetId uint32 = 102
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedAdReviews := &models.AdReviews{
+		&models.AdReview{
+			Id:           1,
+			AdId:         1,
+			UserAuthorId: 101,
+			UserTargetId: userTargetId,
+			Rating:       5,
+			Comment:      "Доставил вовремя",
+			DateTime:     *dateTime,
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "ad_id", "user_author_id", "user_target_id", "rating", "comment",
+		"date_time"})
+	for _, expectedAdReview := range *expectedAdReviews {
+		rows.AddRow(expectedAdReview.Id, expectedAdReview.AdId, expectedAdReview.UserAuthorId,
+			expectedAdReview.UserTargetId, expectedAdReview.Rating, expectedAdReview.Comment,
+			time.Time(expectedAdReview.DateTime))
+	}
+	sqlmock_.
+		ExpectQuery("SELECT id, ad_id, user_author_id, user_target_id, rating, comment, date_time FROM ad_review").
+		WithArgs(userTargetId).
+		WillReturnRows(rows)
+
+	resultAdReviews, resultErr := userRepository.SelectAdReviewArrayByUserTargetId(userTargetId)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedAdReviews, resultAdReviews)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_InsertUserBlock(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	userBlock := &models.UserBlock{
+		UserId:        101,
+		UserBlockedId: 102,
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedUserBlock := &models.UserBlock{
+		UserId:            userBlock.UserId,
+		UserBlockedId:     userBlock.UserBlockedId,
+		UserBlockedName:   "Петр Васильев",
+		UserBlockedAvatar: "https://mail.ru/petr_vasiliev_avatar.jpg",
+		DateTime:          *dateTime,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO user_block").
+		WithArgs(userBlock.UserId, userBlock.UserBlockedId).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_blocked_id", "name", "avatar", "date_time"}).
+			AddRow(expectedUserBlock.UserId, expectedUserBlock.UserBlockedId, expectedUserBlock.UserBlockedName,
+				expectedUserBlock.UserBlockedAvatar, time.Time(expectedUserBlock.DateTime)))
+
+	resultUserBlock, resultErr := userRepository.InsertUserBlock(userBlock)
+	assert.Nil(t, resultErr)
+	assert.Equal(t, expectedUserBlock, resultUserBlock)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_InsertUserBlock_conflict(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	userBlock := &models.UserBlock{
+		UserId:        101,
+		UserBlockedId: 102,
+	}
+
+	sqlmock_.
+		ExpectQuery("INSERT INTO user_block").
+		WithArgs(userBlock.UserId, userBlock.UserBlockedId).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	resultUserBlock, resultErr := userRepository.InsertUserBlock(userBlock)
+	assert.Equal(t, consts.RepErrConflict, resultErr)
+	assert.Nil(t, resultUserBlock)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
+
+func TestUserRepository_DeleteUserBlock_notFound(t *testing.T) {
+	db, sqlmock_, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	userRepository := repository.NewUserRepositoryImpl(db)
+
+	const userId, userBlockedId uint32 = 101, 102
+
+	sqlmock_.
+		ExpectQuery("DELETE FROM user_block WHERE user_id = \\$1 AND user_blocked_id = \\$2").
+		WithArgs(userId, userBlockedId).
+		WillReturnError(sql.ErrNoRows)
+
+	resultUserBlock, resultErr := userRepository.DeleteUserBlock(userId, userBlockedId)
+	assert.Equal(t, consts.RepErrNotFound, resultErr)
+	assert.Nil(t, resultUserBlock)
+
+	assert.Nil(t, sqlmock_.ExpectationsWereMet())
+}
@@ -11,11 +11,23 @@ type Usecase interface {
 	CreateRouteTmp(routeTmp *models.RouteTmp) *response.Response
 	GetRouteTmp(userId uint32, routeTmpId uint32) *response.Response
 	UpdateRouteTmp(routeTmp *models.RouteTmp) *response.Response
-	DeleteRouteTmp(userId uint32, routeTmpId uint32) *response.Response
+	PatchRouteTmp(routeTmpPatch *models.RouteTmpPatch) *response.Response
+	DeleteRouteTmp(userId uint32, routeTmpId uint32, version uint32) *response.Response
 	ListRouteTmp(userId uint32) *response.Response
 	CreateRoutePerm(routePerm *models.RoutePerm) *response.Response
 	GetRoutePerm(userId uint32, routePermId uint32) *response.Response
 	UpdateRoutePerm(routePerm *models.RoutePerm) *response.Response
-	DeleteRoutePerm(userId uint32, routePermId uint32) *response.Response
+	PatchRoutePerm(routePermPatch *models.RoutePermPatch) *response.Response
+	DeleteRoutePerm(userId uint32, routePermId uint32, version uint32) *response.Response
 	ListRoutePerm(userId uint32) *response.Response
+	ListRouteTmpAds(userId uint32, routeTmpId uint32, cursor *models.AdMatchesCursor, limit *uint32) *response.Response
+	ListRoutePermAds(userId uint32, routePermId uint32, cursor *models.AdMatchesCursor, limit *uint32) *response.Response
+	CreateSavedSearch(savedSearch *models.SavedSearch) *response.Response
+	RenameSavedSearch(savedSearch *models.SavedSearch) *response.Response
+	DeleteSavedSearch(userId uint32, savedSearchId uint32) *response.Response
+	ListSavedSearch(userId uint32) *response.Response
+	ListUserReviews(userId uint32) *response.Response
+	BlockUser(userBlock *models.UserBlock) *response.Response
+	UnblockUser(userId uint32, userBlockedId uint32) *response.Response
+	ListUserBlocks(userId uint32) *response.Response
 }
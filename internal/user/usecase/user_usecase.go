@@ -3,10 +3,14 @@ package usecase
 import (
 	"github.com/TechnoHandOver/backend/internal/consts"
 	"github.com/TechnoHandOver/backend/internal/models"
+	"github.com/TechnoHandOver/backend/internal/tools/etag"
+	"github.com/TechnoHandOver/backend/internal/tools/parser"
 	"github.com/TechnoHandOver/backend/internal/tools/response"
 	"github.com/TechnoHandOver/backend/internal/user"
 )
 
+const routeAdsDefaultLimit uint32 = 20
+
 type UserUsecase struct {
 	userRepository user.Repository
 }
@@ -57,6 +61,10 @@ func (userUsecase *UserUsecase) Get(id uint32) *response.Response {
 func (userUsecase *UserUsecase) CreateRouteTmp(routeTmp *models.RouteTmp) *response.Response {
 	routeTmp, err := userUsecase.userRepository.InsertRouteTmp(routeTmp)
 	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
@@ -77,7 +85,7 @@ func (userUsecase *UserUsecase) GetRouteTmp(userId uint32, routeTmpId uint32) *r
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
-	return response.NewResponse(consts.OK, routeTmp)
+	return response.NewVersionedResponse(consts.OK, routeTmp, routeTmp.Version)
 }
 
 func (userUsecase *UserUsecase) UpdateRouteTmp(routeTmp *models.RouteTmp) *response.Response {
@@ -94,7 +102,27 @@ func (userUsecase *UserUsecase) UpdateRouteTmp(routeTmp *models.RouteTmp) *respo
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
+	if !etag.Matches(routeTmp.Version, existingRouteTmp.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
 	routeTmp, err = userUsecase.userRepository.UpdateRouteTmp(routeTmp)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewVersionedResponse(consts.OK, routeTmp, routeTmp.Version)
+}
+
+func (userUsecase *UserUsecase) PatchRouteTmp(routeTmpPatch *models.RouteTmpPatch) *response.Response {
+	existingRouteTmp, err := userUsecase.userRepository.SelectRouteTmp(routeTmpPatch.Id)
 	if err != nil {
 		if err == consts.RepErrNotFound {
 			return response.NewEmptyResponse(consts.NotFound)
@@ -103,10 +131,30 @@ func (userUsecase *UserUsecase) UpdateRouteTmp(routeTmp *models.RouteTmp) *respo
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
-	return response.NewResponse(consts.OK, routeTmp)
+	if routeTmpPatch.UserAuthorId != existingRouteTmp.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	if !etag.Matches(routeTmpPatch.Version, existingRouteTmp.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
+	routeTmp, err := userUsecase.userRepository.PatchRouteTmp(routeTmpPatch)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewVersionedResponse(consts.OK, routeTmp, routeTmp.Version)
 }
 
-func (userUsecase *UserUsecase) DeleteRouteTmp(userId uint32, routeTmpId uint32) *response.Response {
+func (userUsecase *UserUsecase) DeleteRouteTmp(userId uint32, routeTmpId uint32, version uint32) *response.Response {
 	existingRouteTmp, err := userUsecase.userRepository.SelectRouteTmp(routeTmpId)
 	if err != nil {
 		if err == consts.RepErrNotFound {
@@ -120,10 +168,17 @@ func (userUsecase *UserUsecase) DeleteRouteTmp(userId uint32, routeTmpId uint32)
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
-	routeTmp, err := userUsecase.userRepository.DeleteRouteTmp(routeTmpId)
+	if !etag.Matches(version, existingRouteTmp.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
+	routeTmp, err := userUsecase.userRepository.DeleteRouteTmp(routeTmpId, version)
 	if err != nil {
-		if err == consts.RepErrNotFound {
+		switch err {
+		case consts.RepErrNotFound:
 			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
 		}
 
 		return response.NewErrorResponse(consts.InternalError, err)
@@ -144,6 +199,10 @@ func (userUsecase *UserUsecase) ListRouteTmp(userId uint32) *response.Response {
 func (userUsecase *UserUsecase) CreateRoutePerm(routePerm *models.RoutePerm) *response.Response {
 	routePerm, err := userUsecase.userRepository.InsertRoutePerm(routePerm)
 	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
@@ -164,7 +223,7 @@ func (userUsecase *UserUsecase) GetRoutePerm(userId uint32, routePermId uint32)
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
-	return response.NewResponse(consts.OK, routePerm)
+	return response.NewVersionedResponse(consts.OK, routePerm, routePerm.Version)
 }
 
 func (userUsecase *UserUsecase) UpdateRoutePerm(routePerm *models.RoutePerm) *response.Response {
@@ -181,7 +240,27 @@ func (userUsecase *UserUsecase) UpdateRoutePerm(routePerm *models.RoutePerm) *re
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
+	if !etag.Matches(routePerm.Version, existingRoutePerm.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
 	routePerm, err = userUsecase.userRepository.UpdateRoutePerm(routePerm)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewVersionedResponse(consts.OK, routePerm, routePerm.Version)
+}
+
+func (userUsecase *UserUsecase) PatchRoutePerm(routePermPatch *models.RoutePermPatch) *response.Response {
+	existingRoutePerm, err := userUsecase.userRepository.SelectRoutePerm(routePermPatch.Id)
 	if err != nil {
 		if err == consts.RepErrNotFound {
 			return response.NewEmptyResponse(consts.NotFound)
@@ -190,10 +269,30 @@ func (userUsecase *UserUsecase) UpdateRoutePerm(routePerm *models.RoutePerm) *re
 		return response.NewErrorResponse(consts.InternalError, err)
 	}
 
-	return response.NewResponse(consts.OK, routePerm)
+	if routePermPatch.UserAuthorId != existingRoutePerm.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	if !etag.Matches(routePermPatch.Version, existingRoutePerm.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
+	routePerm, err := userUsecase.userRepository.PatchRoutePerm(routePermPatch)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewVersionedResponse(consts.OK, routePerm, routePerm.Version)
 }
 
-func (userUsecase *UserUsecase) DeleteRoutePerm(userId uint32, routePermId uint32) *response.Response {
+func (userUsecase *UserUsecase) DeleteRoutePerm(userId uint32, routePermId uint32, version uint32) *response.Response {
 	existingRoutePerm, err := userUsecase.userRepository.SelectRoutePerm(routePermId)
 	if err != nil {
 		if err == consts.RepErrNotFound {
@@ -207,10 +306,17 @@ func (userUsecase *UserUsecase) DeleteRoutePerm(userId uint32, routePermId uint3
 		return response.NewEmptyResponse(consts.Forbidden)
 	}
 
-	routePerm, err := userUsecase.userRepository.DeleteRoutePerm(routePermId)
+	if !etag.Matches(version, existingRoutePerm.Version) {
+		return response.NewEmptyResponse(consts.PreconditionFailed)
+	}
+
+	routePerm, err := userUsecase.userRepository.DeleteRoutePerm(routePermId, version)
 	if err != nil {
-		if err == consts.RepErrNotFound {
+		switch err {
+		case consts.RepErrNotFound:
 			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrPreconditionFailed:
+			return response.NewEmptyResponse(consts.PreconditionFailed)
 		}
 
 		return response.NewErrorResponse(consts.InternalError, err)
@@ -227,3 +333,197 @@ func (userUsecase *UserUsecase) ListRoutePerm(userId uint32) *response.Response
 
 	return response.NewResponse(consts.OK, routesPerm)
 }
+
+func (userUsecase *UserUsecase) ListRouteTmpAds(userId uint32, routeTmpId uint32, cursor *models.AdMatchesCursor,
+	limit *uint32) *response.Response {
+	routeTmp, err := userUsecase.userRepository.SelectRouteTmp(routeTmpId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if userId != routeTmp.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	limit_ := parser.GetOrDefault(limit, routeAdsDefaultLimit).(uint32)
+	adMatches, err := userUsecase.userRepository.SelectAdMatchArrayByRouteTmpId(routeTmpId, cursor, limit_+1)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return newAdMatchesPageResponse(adMatches, limit_)
+}
+
+func (userUsecase *UserUsecase) ListRoutePermAds(userId uint32, routePermId uint32, cursor *models.AdMatchesCursor,
+	limit *uint32) *response.Response {
+	routePerm, err := userUsecase.userRepository.SelectRoutePerm(routePermId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if userId != routePerm.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	limit_ := parser.GetOrDefault(limit, routeAdsDefaultLimit).(uint32)
+	adMatches, err := userUsecase.userRepository.SelectAdMatchArrayByRoutePermId(routePermId, cursor, limit_+1)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return newAdMatchesPageResponse(adMatches, limit_)
+}
+
+func newAdMatchesPageResponse(adMatches *models.AdMatches, limit uint32) *response.Response {
+	var nextCursor string
+	if uint32(len(*adMatches)) > limit {
+		*adMatches = (*adMatches)[:limit]
+		nextCursor = models.NewAdMatchesCursor((*adMatches)[limit-1]).String()
+	}
+
+	return response.NewPageResponse(consts.OK, adMatches, nextCursor)
+}
+
+func (userUsecase *UserUsecase) CreateSavedSearch(savedSearch *models.SavedSearch) *response.Response {
+	savedSearch, err := userUsecase.userRepository.InsertSavedSearch(savedSearch)
+	if err != nil {
+		if err == consts.RepErrConflict {
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, savedSearch)
+}
+
+func (userUsecase *UserUsecase) RenameSavedSearch(savedSearch *models.SavedSearch) *response.Response {
+	existingSavedSearch, err := userUsecase.userRepository.SelectSavedSearch(savedSearch.Id)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if savedSearch.UserAuthorId != existingSavedSearch.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	savedSearch, err = userUsecase.userRepository.UpdateSavedSearchName(savedSearch.Id, savedSearch.Name)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, savedSearch)
+}
+
+func (userUsecase *UserUsecase) DeleteSavedSearch(userId uint32, savedSearchId uint32) *response.Response {
+	existingSavedSearch, err := userUsecase.userRepository.SelectSavedSearch(savedSearchId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	if userId != existingSavedSearch.UserAuthorId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	savedSearch, err := userUsecase.userRepository.DeleteSavedSearch(savedSearchId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, savedSearch)
+}
+
+func (userUsecase *UserUsecase) ListSavedSearch(userId uint32) *response.Response {
+	savedSearches, err := userUsecase.userRepository.SelectSavedSearchArrayByUserAuthorId(userId)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, savedSearches)
+}
+
+func (userUsecase *UserUsecase) ListUserReviews(userId uint32) *response.Response {
+	if _, err := userUsecase.userRepository.Select(userId); err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	adReviews, err := userUsecase.userRepository.SelectAdReviewArrayByUserTargetId(userId)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, adReviews)
+}
+
+func (userUsecase *UserUsecase) BlockUser(userBlock *models.UserBlock) *response.Response {
+	if userBlock.UserId == userBlock.UserBlockedId {
+		return response.NewEmptyResponse(consts.Forbidden)
+	}
+
+	userBlock, err := userUsecase.userRepository.InsertUserBlock(userBlock)
+	if err != nil {
+		switch err {
+		case consts.RepErrNotFound:
+			return response.NewEmptyResponse(consts.NotFound)
+		case consts.RepErrConflict:
+			return response.NewEmptyResponse(consts.Conflict)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.Created, userBlock)
+}
+
+func (userUsecase *UserUsecase) UnblockUser(userId uint32, userBlockedId uint32) *response.Response {
+	userBlock, err := userUsecase.userRepository.DeleteUserBlock(userId, userBlockedId)
+	if err != nil {
+		if err == consts.RepErrNotFound {
+			return response.NewEmptyResponse(consts.NotFound)
+		}
+
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, userBlock)
+}
+
+func (userUsecase *UserUsecase) ListUserBlocks(userId uint32) *response.Response {
+	userBlocks, err := userUsecase.userRepository.SelectUserBlockArrayByUserId(userId)
+	if err != nil {
+		return response.NewErrorResponse(consts.InternalError, err)
+	}
+
+	return response.NewResponse(consts.OK, userBlocks)
+}
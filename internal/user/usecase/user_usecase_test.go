@@ -226,7 +226,7 @@ func TestUserUsecase_GetRouteTmp(t *testing.T) {
 		Return(expectedRouteTmp, nil)
 
 	response_ := userUsecase.GetRouteTmp(expectedRouteTmp.UserAuthorId, expectedRouteTmp.Id)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedRouteTmp), response_)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, expectedRouteTmp, expectedRouteTmp.Version), response_)
 }
 
 func TestUserUsecase_GetRouteTmp_forbidden(t *testing.T) {
@@ -324,7 +324,7 @@ func TestUserUsecase_UpdateRouteTmp(t *testing.T) {
 		After(call)
 
 	response_ := userUsecase.UpdateRouteTmp(expectedRouteTmp)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedRouteTmp), response_)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, expectedRouteTmp, expectedRouteTmp.Version), response_)
 }
 
 func TestUserUsecase_UpdateRouteTmp_forbidden(t *testing.T) {
@@ -400,6 +400,84 @@ func TestUserUsecase_UpdateRouteTmp_notFound(t *testing.T) {
 	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
 }
 
+func TestUserUsecase_UpdateRouteTmp_preconditionFailed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	dateTimeDep, err := timestamps.NewDateTime("13.11.2021 13:55")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("13.11.2021 14:00")
+	assert.Nil(t, err)
+	routeTmp := &models.RouteTmp{
+		Id:           1,
+		UserAuthorId: 101,
+		LocDep:       "Корпус Энерго",
+		LocArr:       "Корпус УЛК",
+		MinPrice:     500,
+		DateTimeDep:  *dateTimeDep,
+		DateTimeArr:  *dateTimeArr,
+		Version:      2,
+	}
+	existingRouteTmp := *routeTmp
+	existingRouteTmp.MinPrice = 400
+	existingRouteTmp.Version = 3
+
+	mockUserRepository.
+		EXPECT().
+		SelectRouteTmp(gomock.Eq(routeTmp.Id)).
+		Return(&existingRouteTmp, nil)
+
+	response_ := userUsecase.UpdateRouteTmp(routeTmp)
+	assert.Equal(t, response.NewEmptyResponse(consts.PreconditionFailed), response_)
+}
+
+func TestUserUsecase_PatchRouteTmp(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	dateTimeDep, err := timestamps.NewDateTime("13.11.2021 13:50")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("13.11.2021 13:55")
+	assert.Nil(t, err)
+	minPrice := uint32(600)
+	routeTmpPatch := &models.RouteTmpPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		MinPrice:     &minPrice,
+	}
+	routeTmp := &models.RouteTmp{
+		Id:           routeTmpPatch.Id,
+		UserAuthorId: routeTmpPatch.UserAuthorId,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		MinPrice:     500,
+		DateTimeDep:  *dateTimeDep,
+		DateTimeArr:  *dateTimeArr,
+	}
+	expectedRouteTmp := *routeTmp
+	expectedRouteTmp.MinPrice = minPrice
+
+	call := mockUserRepository.
+		EXPECT().
+		SelectRouteTmp(gomock.Eq(routeTmpPatch.Id)).
+		Return(routeTmp, nil)
+
+	mockUserRepository.
+		EXPECT().
+		PatchRouteTmp(gomock.Eq(routeTmpPatch)).
+		Return(&expectedRouteTmp, nil).
+		After(call)
+
+	response_ := userUsecase.PatchRouteTmp(routeTmpPatch)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, &expectedRouteTmp, expectedRouteTmp.Version), response_)
+}
+
 func TestUserUsecase_DeleteRouteTmp(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -428,11 +506,11 @@ func TestUserUsecase_DeleteRouteTmp(t *testing.T) {
 
 	mockUserRepository.
 		EXPECT().
-		DeleteRouteTmp(gomock.Eq(expectedRouteTmp.Id)).
+		DeleteRouteTmp(gomock.Eq(expectedRouteTmp.Id), gomock.Eq(expectedRouteTmp.Version)).
 		Return(expectedRouteTmp, nil).
 		After(call)
 
-	response_ := userUsecase.DeleteRouteTmp(expectedRouteTmp.UserAuthorId, expectedRouteTmp.Id)
+	response_ := userUsecase.DeleteRouteTmp(expectedRouteTmp.UserAuthorId, expectedRouteTmp.Id, expectedRouteTmp.Version)
 	assert.Equal(t, response.NewResponse(consts.OK, expectedRouteTmp), response_)
 }
 
@@ -462,7 +540,7 @@ func TestUserUsecase_DeleteRouteTmp_forbidden(t *testing.T) {
 		SelectRouteTmp(gomock.Eq(expectedRouteTmp.Id)).
 		Return(expectedRouteTmp, nil)
 
-	response_ := userUsecase.DeleteRouteTmp(expectedRouteTmp.UserAuthorId+1, expectedRouteTmp.Id)
+	response_ := userUsecase.DeleteRouteTmp(expectedRouteTmp.UserAuthorId+1, expectedRouteTmp.Id, expectedRouteTmp.Version)
 	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
 }
 
@@ -475,13 +553,14 @@ func TestUserUsecase_DeleteRouteTmp_notFound(t *testing.T) {
 
 	const routeTmpId uint32 = 1
 	const userAuthorId uint32 = 101
+	const version uint32 = 1
 
 	mockUserRepository.
 		EXPECT().
 		SelectRouteTmp(gomock.Eq(routeTmpId)).
 		Return(nil, consts.RepErrNotFound)
 
-	response_ := userUsecase.DeleteRouteTmp(userAuthorId, routeTmpId)
+	response_ := userUsecase.DeleteRouteTmp(userAuthorId, routeTmpId, version)
 	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
 }
 
@@ -549,7 +628,7 @@ func TestUserUsecase_CreateRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -597,7 +676,7 @@ func TestUserUsecase_GetRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -608,7 +687,7 @@ func TestUserUsecase_GetRoutePerm(t *testing.T) {
 		Return(expectedRoutePerm, nil)
 
 	response_ := userUsecase.GetRoutePerm(expectedRoutePerm.UserAuthorId, expectedRoutePerm.Id)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedRoutePerm), response_)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, expectedRoutePerm, expectedRoutePerm.Version), response_)
 }
 
 func TestUserUsecase_GetRoutePerm_forbidden(t *testing.T) {
@@ -630,7 +709,7 @@ func TestUserUsecase_GetRoutePerm_forbidden(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -682,7 +761,7 @@ func TestUserUsecase_UpdateRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep1,
 		TimeArr:      *timeArr1,
 	}
@@ -698,7 +777,7 @@ func TestUserUsecase_UpdateRoutePerm(t *testing.T) {
 		MinPrice:     600,
 		EvenWeek:     false,
 		OddWeek:      true,
-		DayOfWeek:    timestamps.DayOfWeekSaturday,
+		DayOfWeek:    6,
 		TimeDep:      *timeDep2,
 		TimeArr:      *timeArr2,
 	}
@@ -715,7 +794,7 @@ func TestUserUsecase_UpdateRoutePerm(t *testing.T) {
 		After(call)
 
 	response_ := userUsecase.UpdateRoutePerm(expectedRoutePerm)
-	assert.Equal(t, response.NewResponse(consts.OK, expectedRoutePerm), response_)
+	assert.Equal(t, response.NewVersionedResponse(consts.OK, expectedRoutePerm, expectedRoutePerm.Version), response_)
 }
 
 func TestUserUsecase_UpdateRoutePerm_forbidden(t *testing.T) {
@@ -737,7 +816,7 @@ func TestUserUsecase_UpdateRoutePerm_forbidden(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep1,
 		TimeArr:      *timeArr1,
 	}
@@ -753,7 +832,7 @@ func TestUserUsecase_UpdateRoutePerm_forbidden(t *testing.T) {
 		MinPrice:     600,
 		EvenWeek:     false,
 		OddWeek:      true,
-		DayOfWeek:    timestamps.DayOfWeekSaturday,
+		DayOfWeek:    6,
 		TimeDep:      *timeDep2,
 		TimeArr:      *timeArr2,
 	}
@@ -786,7 +865,7 @@ func TestUserUsecase_UpdateRoutePerm_notFound(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -800,6 +879,45 @@ func TestUserUsecase_UpdateRoutePerm_notFound(t *testing.T) {
 	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
 }
 
+func TestUserUsecase_PatchRoutePerm_forbidden(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	timeDep, err := timestamps.NewTime("13:50")
+	assert.Nil(t, err)
+	timeArr, err := timestamps.NewTime("13:55")
+	assert.Nil(t, err)
+	evenWeek := false
+	routePermPatch := &models.RoutePermPatch{
+		Id:           1,
+		UserAuthorId: 101,
+		EvenWeek:     &evenWeek,
+	}
+	routePerm := &models.RoutePerm{
+		Id:           routePermPatch.Id,
+		UserAuthorId: 102,
+		LocDep:       "Общежитие №10",
+		LocArr:       "УЛК",
+		MinPrice:     500,
+		EvenWeek:     true,
+		OddWeek:      true,
+		DayOfWeek:    1,
+		TimeDep:      *timeDep,
+		TimeArr:      *timeArr,
+	}
+
+	mockUserRepository.
+		EXPECT().
+		SelectRoutePerm(gomock.Eq(routePermPatch.Id)).
+		Return(routePerm, nil)
+
+	response_ := userUsecase.PatchRoutePerm(routePermPatch)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
 func TestUserUsecase_DeleteRoutePerm(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -819,7 +937,7 @@ func TestUserUsecase_DeleteRoutePerm(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -831,11 +949,11 @@ func TestUserUsecase_DeleteRoutePerm(t *testing.T) {
 
 	mockUserRepository.
 		EXPECT().
-		DeleteRoutePerm(gomock.Eq(expectedRoutePerm.Id)).
+		DeleteRoutePerm(gomock.Eq(expectedRoutePerm.Id), gomock.Eq(expectedRoutePerm.Version)).
 		Return(expectedRoutePerm, nil).
 		After(call)
 
-	response_ := userUsecase.DeleteRoutePerm(expectedRoutePerm.UserAuthorId, expectedRoutePerm.Id)
+	response_ := userUsecase.DeleteRoutePerm(expectedRoutePerm.UserAuthorId, expectedRoutePerm.Id, expectedRoutePerm.Version)
 	assert.Equal(t, response.NewResponse(consts.OK, expectedRoutePerm), response_)
 }
 
@@ -858,7 +976,7 @@ func TestUserUsecase_DeleteRoutePerm_forbidden(t *testing.T) {
 		MinPrice:     500,
 		EvenWeek:     true,
 		OddWeek:      false,
-		DayOfWeek:    timestamps.DayOfWeekWednesday,
+		DayOfWeek:    3,
 		TimeDep:      *timeDep,
 		TimeArr:      *timeArr,
 	}
@@ -868,7 +986,7 @@ func TestUserUsecase_DeleteRoutePerm_forbidden(t *testing.T) {
 		SelectRoutePerm(gomock.Eq(expectedRoutePerm.Id)).
 		Return(expectedRoutePerm, nil)
 
-	response_ := userUsecase.DeleteRoutePerm(expectedRoutePerm.UserAuthorId+1, expectedRoutePerm.Id)
+	response_ := userUsecase.DeleteRoutePerm(expectedRoutePerm.UserAuthorId+1, expectedRoutePerm.Id, expectedRoutePerm.Version)
 	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
 }
 
@@ -881,13 +999,14 @@ func TestUserUsecase_DeleteRoutePerm_notFound(t *testing.T) {
 
 	const routePermId uint32 = 1
 	const userAuthorId uint32 = 101
+	const version uint32 = 1
 
 	mockUserRepository.
 		EXPECT().
 		SelectRoutePerm(gomock.Eq(routePermId)).
 		Return(nil, consts.RepErrNotFound)
 
-	response_ := userUsecase.DeleteRoutePerm(userAuthorId, routePermId)
+	response_ := userUsecase.DeleteRoutePerm(userAuthorId, routePermId, version)
 	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
 }
 
@@ -916,7 +1035,7 @@ func TestUserUsecase_ListRoutePerm(t *testing.T) {
 			MinPrice:     500,
 			EvenWeek:     true,
 			OddWeek:      false,
-			DayOfWeek:    timestamps.DayOfWeekWednesday,
+			DayOfWeek:    3,
 			TimeDep:      *timeDep1,
 			TimeArr:      *timeArr1,
 		},
@@ -928,7 +1047,7 @@ func TestUserUsecase_ListRoutePerm(t *testing.T) {
 			MinPrice:     600,
 			EvenWeek:     false,
 			OddWeek:      true,
-			DayOfWeek:    timestamps.DayOfWeekSaturday,
+			DayOfWeek:    6,
 			TimeDep:      *timeDep2,
 			TimeArr:      *timeArr2,
 		},
@@ -942,3 +1061,372 @@ func TestUserUsecase_ListRoutePerm(t *testing.T) {
 	response_ := userUsecase.ListRoutePerm(userId)
 	assert.Equal(t, response.NewResponse(consts.OK, expectedRoutesPerm), response_)
 }
+
+func TestUserUsecase_CreateSavedSearch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	locDep := "Общежитие №10"
+	savedSearch := &models.SavedSearch{
+		UserAuthorId: 101,
+		Name:         "Из общежития",
+		LocDep:       &locDep,
+	}
+	expectedSavedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: savedSearch.UserAuthorId,
+		Name:         savedSearch.Name,
+		LocDep:       savedSearch.LocDep,
+	}
+
+	mockUserRepository.
+		EXPECT().
+		InsertSavedSearch(gomock.Eq(savedSearch)).
+		DoAndReturn(func(savedSearch *models.SavedSearch) (*models.SavedSearch, error) {
+			savedSearch.Id = expectedSavedSearch.Id
+			return savedSearch, nil
+		})
+
+	response_ := userUsecase.CreateSavedSearch(savedSearch)
+	assert.Equal(t, response.NewResponse(consts.Created, expectedSavedSearch), response_)
+}
+
+func TestUserUsecase_CreateSavedSearch_conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	savedSearch := &models.SavedSearch{
+		UserAuthorId: 101,
+		Name:         "Из общежития",
+	}
+
+	mockUserRepository.
+		EXPECT().
+		InsertSavedSearch(gomock.Eq(savedSearch)).
+		Return(nil, consts.RepErrConflict)
+
+	response_ := userUsecase.CreateSavedSearch(savedSearch)
+	assert.Equal(t, response.NewEmptyResponse(consts.Conflict), response_)
+}
+
+func TestUserUsecase_RenameSavedSearch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	locDep := "Общежитие №10"
+	existingSavedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: 101,
+		Name:         "Из общежития",
+		LocDep:       &locDep,
+	}
+	savedSearch := &models.SavedSearch{
+		Id:           existingSavedSearch.Id,
+		UserAuthorId: existingSavedSearch.UserAuthorId,
+		Name:         "Из десятки",
+	}
+	expectedSavedSearch := &models.SavedSearch{
+		Id:           existingSavedSearch.Id,
+		UserAuthorId: existingSavedSearch.UserAuthorId,
+		Name:         savedSearch.Name,
+		LocDep:       existingSavedSearch.LocDep,
+	}
+
+	call := mockUserRepository.
+		EXPECT().
+		SelectSavedSearch(gomock.Eq(savedSearch.Id)).
+		Return(existingSavedSearch, nil)
+
+	mockUserRepository.
+		EXPECT().
+		UpdateSavedSearchName(gomock.Eq(savedSearch.Id), gomock.Eq(savedSearch.Name)).
+		Return(expectedSavedSearch, nil).
+		After(call)
+
+	response_ := userUsecase.RenameSavedSearch(savedSearch)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedSavedSearch), response_)
+}
+
+func TestUserUsecase_RenameSavedSearch_forbidden(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	existingSavedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: 101,
+		Name:         "Из общежития",
+	}
+	savedSearch := &models.SavedSearch{
+		Id:           existingSavedSearch.Id,
+		UserAuthorId: 102,
+		Name:         "Из десятки",
+	}
+
+	mockUserRepository.
+		EXPECT().
+		SelectSavedSearch(gomock.Eq(savedSearch.Id)).
+		Return(existingSavedSearch, nil)
+
+	response_ := userUsecase.RenameSavedSearch(savedSearch)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestUserUsecase_DeleteSavedSearch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	expectedSavedSearch := &models.SavedSearch{
+		Id:           1,
+		UserAuthorId: 101,
+		Name:         "Из общежития",
+	}
+
+	call := mockUserRepository.
+		EXPECT().
+		SelectSavedSearch(gomock.Eq(expectedSavedSearch.Id)).
+		Return(expectedSavedSearch, nil)
+
+	mockUserRepository.
+		EXPECT().
+		DeleteSavedSearch(gomock.Eq(expectedSavedSearch.Id)).
+		Return(expectedSavedSearch, nil).
+		After(call)
+
+	response_ := userUsecase.DeleteSavedSearch(expectedSavedSearch.UserAuthorId, expectedSavedSearch.Id)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedSavedSearch), response_)
+}
+
+func TestUserUsecase_ListSavedSearch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	var userId uint32 = 101
+	expectedSavedSearches := &models.SavedSearches{
+		&models.SavedSearch{
+			Id:           1,
+			UserAuthorId: userId,
+			Name:         "Из общежития",
+		},
+	}
+
+	mockUserRepository.
+		EXPECT().
+		SelectSavedSearchArrayByUserAuthorId(gomock.Eq(userId)).
+		Return(expectedSavedSearches, nil)
+
+	response_ := userUsecase.ListSavedSearch(userId)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedSavedSearches), response_)
+}
+
+func TestUserUsecase_ListUserReviews(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	user := &models.User{
+		Id:          102,
+		VkId:        202,
+		Name:        "Pupok Vasiliev",
+		Avatar:      "https://yandex.ru/logo2.png",
+		Rating:      5,
+		RatingCount: 1,
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedAdReviews := &models.AdReviews{
+		&models.AdReview{
+			Id:           1,
+			AdId:         1,
+			UserAuthorId: 101,
+			UserTargetId: user.Id,
+			Rating:       5,
+			Comment:      "Доставил вовремя",
+			DateTime:     *dateTime,
+		},
+	}
+
+	callSelect := mockUserRepository.
+		EXPECT().
+		Select(gomock.Eq(user.Id)).
+		Return(user, nil)
+	mockUserRepository.
+		EXPECT().
+		SelectAdReviewArrayByUserTargetId(gomock.Eq(user.Id)).
+		Return(expectedAdReviews, nil).
+		After(callSelect)
+
+	response_ := userUsecase.ListUserReviews(user.Id)
+	assert.Equal(t, response.NewResponse(consts.OK, expectedAdReviews), response_)
+}
+
+func TestUserUsecase_ListUserReviews_notFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	const userId uint32 = 102
+
+	mockUserRepository.
+		EXPECT().
+		Select(gomock.Eq(userId)).
+		Return(nil, consts.RepErrNotFound)
+
+	response_ := userUsecase.ListUserReviews(userId)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}
+
+func TestUserUsecase_ListRouteTmpAds(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	dateTimeDep, err := timestamps.NewDateTime("13.11.2021 17:20")
+	assert.Nil(t, err)
+	dateTimeArr, err := timestamps.NewDateTime("13.11.2021 17:25")
+	assert.Nil(t, err)
+	routeTmp := &models.RouteTmp{
+		Id:           1,
+		UserAuthorId: 101,
+		LocDep:       "Корпус Энерго",
+		LocArr:       "Корпус УЛК",
+		MinPrice:     500,
+		DateTimeDep:  *dateTimeDep,
+		DateTimeArr:  *dateTimeArr,
+	}
+	var limit uint32 = 1
+	adMatches := &models.AdMatches{
+		&models.AdMatch{
+			Ad:  &models.Ad{Id: 3, UserAuthorId: 102, LocDep: routeTmp.LocDep, LocArr: routeTmp.LocArr},
+			Fit: 1,
+		},
+		&models.AdMatch{
+			Ad:  &models.Ad{Id: 2, UserAuthorId: 103, LocDep: routeTmp.LocDep, LocArr: routeTmp.LocArr},
+			Fit: 0.5,
+		},
+	}
+	expectedAdMatches := &models.AdMatches{(*adMatches)[0]}
+
+	call := mockUserRepository.
+		EXPECT().
+		SelectRouteTmp(gomock.Eq(routeTmp.Id)).
+		Return(routeTmp, nil)
+
+	mockUserRepository.
+		EXPECT().
+		SelectAdMatchArrayByRouteTmpId(gomock.Eq(routeTmp.Id), gomock.Nil(), gomock.Eq(limit+1)).
+		Return(adMatches, nil).
+		After(call)
+
+	response_ := userUsecase.ListRouteTmpAds(routeTmp.UserAuthorId, routeTmp.Id, nil, &limit)
+	assert.Equal(t, response.NewPageResponse(consts.OK, expectedAdMatches,
+		models.NewAdMatchesCursor((*expectedAdMatches)[0]).String()), response_)
+}
+
+func TestUserUsecase_ListRoutePermAds_forbidden(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	routePerm := &models.RoutePerm{
+		Id:           1,
+		UserAuthorId: 101,
+	}
+
+	mockUserRepository.
+		EXPECT().
+		SelectRoutePerm(gomock.Eq(routePerm.Id)).
+		Return(routePerm, nil)
+
+	response_ := userUsecase.ListRoutePermAds(102, routePerm.Id, nil, nil)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestUserUsecase_BlockUser(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	userBlock := &models.UserBlock{
+		UserId:        101,
+		UserBlockedId: 102,
+	}
+	dateTime, err := timestamps.NewDateTime("05.12.2021 21:00")
+	assert.Nil(t, err)
+	expectedUserBlock := &models.UserBlock{
+		UserId:            userBlock.UserId,
+		UserBlockedId:     userBlock.UserBlockedId,
+		UserBlockedName:   "Петр Васильев",
+		UserBlockedAvatar: "https://mail.ru/petr_vasiliev_avatar.jpg",
+		DateTime:          *dateTime,
+	}
+
+	mockUserRepository.
+		EXPECT().
+		InsertUserBlock(gomock.Eq(userBlock)).
+		Return(expectedUserBlock, nil)
+
+	response_ := userUsecase.BlockUser(userBlock)
+	assert.Equal(t, response.NewResponse(consts.Created, expectedUserBlock), response_)
+}
+
+func TestUserUsecase_BlockUser_self(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	userBlock := &models.UserBlock{
+		UserId:        101,
+		UserBlockedId: 101,
+	}
+
+	response_ := userUsecase.BlockUser(userBlock)
+	assert.Equal(t, response.NewEmptyResponse(consts.Forbidden), response_)
+}
+
+func TestUserUsecase_UnblockUser_notFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUserRepository := mock_user.NewMockRepository(controller)
+	userUsecase := usecase.NewUserUsecaseImpl(mockUserRepository)
+
+	const userId, userBlockedId uint32 = 101, 102
+
+	mockUserRepository.
+		EXPECT().
+		DeleteUserBlock(gomock.Eq(userId), gomock.Eq(userBlockedId)).
+		Return(nil, consts.RepErrNotFound)
+
+	response_ := userUsecase.UnblockUser(userId, userBlockedId)
+	assert.Equal(t, response.NewEmptyResponse(consts.NotFound), response_)
+}